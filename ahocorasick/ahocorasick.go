@@ -0,0 +1,167 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"sort"
+
+	"fsm-modulo-three/fsm"
+)
+
+const rootState fsm.State = "ε"
+
+type Match struct {
+	Pattern string
+	Index   int
+	Start   int
+	End     int
+}
+
+type Matcher struct {
+	patterns  []string
+	automaton *fsm.FiniteAutomaton
+	outputs   map[fsm.State][]int
+}
+
+type trieNode struct {
+	state    fsm.State
+	children map[rune]*trieNode
+	fail     *trieNode
+	outputs  []int
+}
+
+func New(patterns []string) (*Matcher, error) {
+	root := &trieNode{state: rootState, children: make(map[rune]*trieNode)}
+	nodes := []*trieNode{root}
+	byState := map[fsm.State]*trieNode{rootState: root}
+	symbols := make(map[rune]bool)
+
+	for i, pattern := range patterns {
+		if pattern == "" {
+			return nil, fmt.Errorf("pattern %d is empty", i)
+		}
+
+		node := root
+		prefix := []rune{}
+		for _, char := range pattern {
+			symbols[char] = true
+			prefix = append(prefix, char)
+			child, ok := node.children[char]
+			if !ok {
+				child = &trieNode{state: fsm.State(string(prefix)), children: make(map[rune]*trieNode)}
+				node.children[char] = child
+				nodes = append(nodes, child)
+				byState[child.state] = child
+			}
+			node = child
+		}
+		node.outputs = append(node.outputs, i)
+	}
+
+	alphabetRunes := make([]rune, 0, len(symbols))
+	for char := range symbols {
+		alphabetRunes = append(alphabetRunes, char)
+	}
+	sort.Slice(alphabetRunes, func(i, j int) bool { return alphabetRunes[i] < alphabetRunes[j] })
+
+	alphabet := make([]fsm.Symbol, len(alphabetRunes))
+	for i, char := range alphabetRunes {
+		alphabet[i] = fsm.Symbol(string(char))
+	}
+
+	table := make(fsm.TransitionTable)
+	queue := []*trieNode{root}
+	root.fail = root
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		if node != root {
+			node.outputs = append(node.outputs, node.fail.outputs...)
+		}
+
+		for _, char := range alphabetRunes {
+			symbol := fsm.Symbol(string(char))
+			if child, ok := node.children[char]; ok {
+				if node == root {
+					child.fail = root
+				} else {
+					child.fail = byState[table.Next(node.fail.state, symbol)]
+				}
+				table.Set(node.state, symbol, child.state)
+				queue = append(queue, child)
+				continue
+			}
+
+			if node == root {
+				table.Set(node.state, symbol, root.state)
+			} else {
+				table.Set(node.state, symbol, table.Next(node.fail.state, symbol))
+			}
+		}
+	}
+
+	states := make([]fsm.State, len(nodes))
+	outputs := make(map[fsm.State][]int)
+	var accepting []fsm.State
+	for i, node := range nodes {
+		states[i] = node.state
+		if len(node.outputs) > 0 {
+			outputs[node.state] = node.outputs
+			accepting = append(accepting, node.state)
+		}
+	}
+
+	return &Matcher{
+		patterns:  patterns,
+		automaton: fsm.NewTableAutomaton(states, alphabet, rootState, accepting, table),
+		outputs:   outputs,
+	}, nil
+}
+
+func (m *Matcher) Automaton() *fsm.FiniteAutomaton {
+	return m.automaton
+}
+
+func (m *Matcher) FindAll(text string) []Match {
+	var matches []Match
+	state := rootState
+
+	for i, char := range text {
+		next, ok := m.automaton.Table[state][fsm.Symbol(string(char))]
+		if !ok {
+			state = rootState
+			continue
+		}
+		state = next
+
+		end := i + len(string(char))
+		for _, index := range m.outputs[state] {
+			pattern := m.patterns[index]
+			matches = append(matches, Match{
+				Pattern: pattern,
+				Index:   index,
+				Start:   end - len(pattern),
+				End:     end,
+			})
+		}
+	}
+
+	return matches
+}
+
+func (m *Matcher) Contains(text string) bool {
+	state := rootState
+	for _, char := range text {
+		next, ok := m.automaton.Table[state][fsm.Symbol(string(char))]
+		if !ok {
+			state = rootState
+			continue
+		}
+		state = next
+		if len(m.outputs[state]) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,103 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestFindAll_ClassicExample(t *testing.T) {
+	m, err := New([]string{"he", "she", "his", "hers"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	matches := m.FindAll("ushers")
+
+	var rendered []string
+	for _, match := range matches {
+		rendered = append(rendered, fmt.Sprintf("%s@%d-%d", match.Pattern, match.Start, match.End))
+	}
+
+	expected := []string{"she@1-4", "he@2-4", "hers@2-6"}
+	if strings.Join(rendered, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected matches %v, got %v", expected, rendered)
+	}
+}
+
+func TestFindAll_MatchesNaiveSearch(t *testing.T) {
+	patterns := []string{"ab", "b", "abc", "bca", "c", "aa"}
+	m, err := New(patterns)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, text := range []string{"abcabca", "aaab", "xxabcxbca", "", "zzz"} {
+		naive := 0
+		for _, pattern := range patterns {
+			for i := 0; i+len(pattern) <= len(text); i++ {
+				if text[i:i+len(pattern)] == pattern {
+					naive++
+				}
+			}
+		}
+
+		matches := m.FindAll(text)
+		if len(matches) != naive {
+			t.Errorf("For text '%s': expected %d matches, got %d: %v", text, naive, len(matches), matches)
+		}
+		for _, match := range matches {
+			if text[match.Start:match.End] != match.Pattern {
+				t.Errorf("For text '%s': match %+v does not cover its pattern", text, match)
+			}
+			if patterns[match.Index] != match.Pattern {
+				t.Errorf("Match index %d does not point at pattern '%s'", match.Index, match.Pattern)
+			}
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	m, err := New([]string{"needle", "pin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !m.Contains("a haystack with a pin") {
+		t.Error("Expected to find 'pin'")
+	}
+	if m.Contains("a haystack with a needl") {
+		t.Error("Expected no match")
+	}
+}
+
+func TestAutomaton_Inspection(t *testing.T) {
+	m, err := New([]string{"ab", "b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	automaton := m.Automaton()
+	if !strings.Contains(automaton.DOT(), `"ab" [shape=doublecircle]`) {
+		t.Errorf("Expected 'ab' to be an accepting state in DOT output:\n%s", automaton.DOT())
+	}
+
+	runner := fsm.NewRunner(automaton, fsm.WithHistory())
+	if _, err := runner.Feed("aab"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if runner.CurrentState() != "ab" || !runner.IsAccepting() {
+		t.Errorf("Expected to end in accepting state 'ab', got %s", runner.CurrentState())
+	}
+	if len(runner.History()) != 3 {
+		t.Errorf("Expected 3 trace entries, got %d", len(runner.History()))
+	}
+}
+
+func TestNew_EmptyPattern(t *testing.T) {
+	if _, err := New([]string{"a", ""}); err == nil {
+		t.Error("Expected error for empty pattern, but got none")
+	}
+}
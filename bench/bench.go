@@ -0,0 +1,182 @@
+package bench
+
+//go:generate go run ../cmd/fsmgen -in testdata/mod7_base2.json -package bench -out mod7_base2_gen.go
+//go:generate go run ../cmd/fsmgen -in testdata/mod7_base10.json -package bench -out mod7_base10_gen.go
+//go:generate go run ../cmd/fsmgen -in testdata/mod7_base36.json -package bench -out mod7_base36_gen.go
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"testing"
+	"text/tabwriter"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modulo"
+)
+
+// Strategy is a way of executing the same automaton: a transition closure
+// (NewFiniteAutomaton), a TransitionTable (NewTableAutomaton), a SparseTable
+// (ToSparse), or Go source generated by fsmgen.
+type Strategy string
+
+const (
+	Closure  Strategy = "closure"
+	Table    Strategy = "table"
+	Sparse   Strategy = "sparse"
+	Compiled Strategy = "compiled"
+)
+
+// Modulus is the remainder machine measured for every alphabet size.
+const Modulus = 7
+
+var (
+	Strategies    = []Strategy{Closure, Table, Sparse, Compiled}
+	AlphabetSizes = []int{2, 10, 36}
+)
+
+type Result struct {
+	Strategy     Strategy
+	AlphabetSize int
+	Length       int
+	NsPerSymbol  float64
+	AllocsPerOp  int64
+	BytesPerOp   int64
+}
+
+// Runner returns a function that runs the mod-7 machine over the given
+// alphabet size with the given strategy and returns its final state.
+func Runner(strategy Strategy, alphabetSize int) (func(string) (fsm.State, error), error) {
+	switch strategy {
+	case Closure:
+		if alphabetSize < 2 || alphabetSize > 36 {
+			return nil, fmt.Errorf("alphabet size must be between 2 and 36, got %d", alphabetSize)
+		}
+		return closureAutomaton(alphabetSize).ProcessInput, nil
+	case Table:
+		m, err := modulo.NewModFSM(Modulus, alphabetSize)
+		if err != nil {
+			return nil, err
+		}
+		return m.GetAutomaton().ProcessInput, nil
+	case Sparse:
+		m, err := modulo.NewModFSM(Modulus, alphabetSize)
+		if err != nil {
+			return nil, err
+		}
+		fa, err := m.GetAutomaton().ToSparse()
+		if err != nil {
+			return nil, err
+		}
+		return fa.ProcessInput, nil
+	case Compiled:
+		return compiledRunner(alphabetSize)
+	}
+	return nil, fmt.Errorf("unknown strategy '%s'", strategy)
+}
+
+func closureAutomaton(base int) *fsm.FiniteAutomaton {
+	states := make([]fsm.State, Modulus)
+	remainders := make(map[fsm.State]int, Modulus)
+	for r := range states {
+		states[r] = fsm.State("S" + strconv.Itoa(r))
+		remainders[states[r]] = r
+	}
+	alphabet := make([]fsm.Symbol, base)
+	for digit := range alphabet {
+		alphabet[digit] = fsm.Symbol(strconv.FormatInt(int64(digit), base))
+	}
+
+	return fsm.NewFiniteAutomaton(states, alphabet, states[0], states, func(currentState fsm.State, symbol fsm.Symbol) fsm.State {
+		digit, _ := strconv.ParseInt(string(symbol), base, 64)
+		return states[(remainders[currentState]*base+int(digit))%Modulus]
+	})
+}
+
+func compiledRunner(alphabetSize int) (func(string) (fsm.State, error), error) {
+	switch alphabetSize {
+	case 2:
+		return func(input string) (fsm.State, error) {
+			state, err := Mod7Base2Run(input)
+			return fsm.State(state), err
+		}, nil
+	case 10:
+		return func(input string) (fsm.State, error) {
+			state, err := Mod7Base10Run(input)
+			return fsm.State(state), err
+		}, nil
+	case 36:
+		return func(input string) (fsm.State, error) {
+			state, err := Mod7Base36Run(input)
+			return fsm.State(state), err
+		}, nil
+	}
+	return nil, fmt.Errorf("no compiled machine for alphabet size %d", alphabetSize)
+}
+
+// Input returns a reproducible random string of base-alphabetSize digits.
+func Input(alphabetSize, length int, seed int64) string {
+	r := rand.New(rand.NewSource(seed))
+	input := make([]byte, length)
+	for i := range input {
+		input[i] = strconv.FormatInt(int64(r.Intn(alphabetSize)), alphabetSize)[0]
+	}
+	return string(input)
+}
+
+// Measure benchmarks one strategy on an input of the given length using
+// testing.Benchmark, so it takes about a second.
+func Measure(strategy Strategy, alphabetSize, length int) (Result, error) {
+	if length < 1 {
+		return Result{}, fmt.Errorf("input length must be positive, got %d", length)
+	}
+	run, err := Runner(strategy, alphabetSize)
+	if err != nil {
+		return Result{}, err
+	}
+	input := Input(alphabetSize, length, 1)
+	if _, err := run(input); err != nil {
+		return Result{}, err
+	}
+
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			run(input)
+		}
+	})
+	return Result{
+		Strategy:     strategy,
+		AlphabetSize: alphabetSize,
+		Length:       length,
+		NsPerSymbol:  float64(r.T.Nanoseconds()) / float64(r.N) / float64(length),
+		AllocsPerOp:  r.AllocsPerOp(),
+		BytesPerOp:   r.AllocedBytesPerOp(),
+	}, nil
+}
+
+// MeasureAll measures every strategy at every alphabet size.
+func MeasureAll(length int) ([]Result, error) {
+	var results []Result
+	for _, alphabetSize := range AlphabetSizes {
+		for _, strategy := range Strategies {
+			result, err := Measure(strategy, alphabetSize, length)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func WriteResults(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STRATEGY\tALPHABET\tLENGTH\tNS/SYMBOL\tALLOCS/OP\tB/OP")
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%d\t%d\n",
+			result.Strategy, result.AlphabetSize, result.Length, result.NsPerSymbol, result.AllocsPerOp, result.BytesPerOp)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,129 @@
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestRunner_StrategiesAgree(t *testing.T) {
+	for _, alphabetSize := range AlphabetSizes {
+		runners := make(map[Strategy]func(string) (fsm.State, error))
+		for _, strategy := range Strategies {
+			run, err := Runner(strategy, alphabetSize)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			runners[strategy] = run
+		}
+
+		for seed := int64(0); seed < 20; seed++ {
+			input := Input(alphabetSize, int(seed)+1, seed)
+			expected, err := runners[Table](input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, strategy := range []Strategy{Closure, Sparse, Compiled} {
+				state, err := runners[strategy](input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if state != expected {
+					t.Errorf("%s base %d on '%s': expected %s, got %s", strategy, alphabetSize, input, expected, state)
+				}
+			}
+		}
+	}
+}
+
+func TestRunner_InvalidSymbol(t *testing.T) {
+	for _, strategy := range Strategies {
+		run, err := Runner(strategy, 2)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, err := run("012"); err == nil {
+			t.Errorf("Expected error for invalid symbol with %s, but got none", strategy)
+		}
+	}
+}
+
+func TestRunner_Errors(t *testing.T) {
+	tests := []struct {
+		strategy     Strategy
+		alphabetSize int
+	}{
+		{"interpreted", 2},
+		{Closure, 1},
+		{Table, 37},
+		{Compiled, 16},
+	}
+
+	for _, test := range tests {
+		if _, err := Runner(test.strategy, test.alphabetSize); err == nil {
+			t.Errorf("Expected error for %s with alphabet size %d, but got none", test.strategy, test.alphabetSize)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs a full benchmark")
+	}
+
+	result, err := Measure(Compiled, 2, 64)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Strategy != Compiled || result.AlphabetSize != 2 || result.Length != 64 {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if result.NsPerSymbol <= 0 {
+		t.Errorf("Expected positive ns/symbol, got %f", result.NsPerSymbol)
+	}
+
+	if _, err := Measure(Compiled, 2, 0); err == nil {
+		t.Error("Expected error for zero length, but got none")
+	}
+}
+
+func TestWriteResults(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteResults(&buf, []Result{
+		{Strategy: Table, AlphabetSize: 10, Length: 1024, NsPerSymbol: 12.345, AllocsPerOp: 0, BytesPerOp: 0},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header and one row, got:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "table 10 1024 12.35 0 0" {
+		t.Errorf("Unexpected row: %q", lines[1])
+	}
+}
+
+func BenchmarkStrategies(b *testing.B) {
+	const length = 1024
+	for _, alphabetSize := range AlphabetSizes {
+		input := Input(alphabetSize, length, 1)
+		for _, strategy := range Strategies {
+			run, err := Runner(strategy, alphabetSize)
+			if err != nil {
+				b.Fatalf("Unexpected error: %v", err)
+			}
+			b.Run(fmt.Sprintf("%s/alphabet=%d", strategy, alphabetSize), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					run(input)
+				}
+				b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/length, "ns/symbol")
+			})
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Code generated by fsmgen. DO NOT EDIT.
+
+package bench
+
+import "fmt"
+
+type Mod7Base10State string
+
+const Mod7Base10InitialState Mod7Base10State = "S0"
+
+func Mod7Base10Transition(state Mod7Base10State, symbol string) (Mod7Base10State, bool) {
+	switch state {
+	case "S0":
+		switch symbol {
+		case "0":
+			return "S0", true
+		case "1":
+			return "S1", true
+		case "2":
+			return "S2", true
+		case "3":
+			return "S3", true
+		case "4":
+			return "S4", true
+		case "5":
+			return "S5", true
+		case "6":
+			return "S6", true
+		case "7":
+			return "S0", true
+		case "8":
+			return "S1", true
+		case "9":
+			return "S2", true
+		}
+	case "S1":
+		switch symbol {
+		case "0":
+			return "S3", true
+		case "1":
+			return "S4", true
+		case "2":
+			return "S5", true
+		case "3":
+			return "S6", true
+		case "4":
+			return "S0", true
+		case "5":
+			return "S1", true
+		case "6":
+			return "S2", true
+		case "7":
+			return "S3", true
+		case "8":
+			return "S4", true
+		case "9":
+			return "S5", true
+		}
+	case "S2":
+		switch symbol {
+		case "0":
+			return "S6", true
+		case "1":
+			return "S0", true
+		case "2":
+			return "S1", true
+		case "3":
+			return "S2", true
+		case "4":
+			return "S3", true
+		case "5":
+			return "S4", true
+		case "6":
+			return "S5", true
+		case "7":
+			return "S6", true
+		case "8":
+			return "S0", true
+		case "9":
+			return "S1", true
+		}
+	case "S3":
+		switch symbol {
+		case "0":
+			return "S2", true
+		case "1":
+			return "S3", true
+		case "2":
+			return "S4", true
+		case "3":
+			return "S5", true
+		case "4":
+			return "S6", true
+		case "5":
+			return "S0", true
+		case "6":
+			return "S1", true
+		case "7":
+			return "S2", true
+		case "8":
+			return "S3", true
+		case "9":
+			return "S4", true
+		}
+	case "S4":
+		switch symbol {
+		case "0":
+			return "S5", true
+		case "1":
+			return "S6", true
+		case "2":
+			return "S0", true
+		case "3":
+			return "S1", true
+		case "4":
+			return "S2", true
+		case "5":
+			return "S3", true
+		case "6":
+			return "S4", true
+		case "7":
+			return "S5", true
+		case "8":
+			return "S6", true
+		case "9":
+			return "S0", true
+		}
+	case "S5":
+		switch symbol {
+		case "0":
+			return "S1", true
+		case "1":
+			return "S2", true
+		case "2":
+			return "S3", true
+		case "3":
+			return "S4", true
+		case "4":
+			return "S5", true
+		case "5":
+			return "S6", true
+		case "6":
+			return "S0", true
+		case "7":
+			return "S1", true
+		case "8":
+			return "S2", true
+		case "9":
+			return "S3", true
+		}
+	case "S6":
+		switch symbol {
+		case "0":
+			return "S4", true
+		case "1":
+			return "S5", true
+		case "2":
+			return "S6", true
+		case "3":
+			return "S0", true
+		case "4":
+			return "S1", true
+		case "5":
+			return "S2", true
+		case "6":
+			return "S3", true
+		case "7":
+			return "S4", true
+		case "8":
+			return "S5", true
+		case "9":
+			return "S6", true
+		}
+	}
+	return state, false
+}
+
+func Mod7Base10IsAccepting(state Mod7Base10State) bool {
+	switch state {
+	case "S0", "S1", "S2", "S3", "S4", "S5", "S6":
+		return true
+	}
+	return false
+}
+
+func Mod7Base10Run(input string) (Mod7Base10State, error) {
+	state := Mod7Base10InitialState
+	for i, char := range input {
+		next, ok := Mod7Base10Transition(state, string(char))
+		if !ok {
+			return state, fmt.Errorf("invalid symbol '%c' at position %d in state %s", char, i, state)
+		}
+		state = next
+	}
+	return state, nil
+}
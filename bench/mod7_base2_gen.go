@@ -0,0 +1,84 @@
+// Code generated by fsmgen. DO NOT EDIT.
+
+package bench
+
+import "fmt"
+
+type Mod7Base2State string
+
+const Mod7Base2InitialState Mod7Base2State = "S0"
+
+func Mod7Base2Transition(state Mod7Base2State, symbol string) (Mod7Base2State, bool) {
+	switch state {
+	case "S0":
+		switch symbol {
+		case "0":
+			return "S0", true
+		case "1":
+			return "S1", true
+		}
+	case "S1":
+		switch symbol {
+		case "0":
+			return "S2", true
+		case "1":
+			return "S3", true
+		}
+	case "S2":
+		switch symbol {
+		case "0":
+			return "S4", true
+		case "1":
+			return "S5", true
+		}
+	case "S3":
+		switch symbol {
+		case "0":
+			return "S6", true
+		case "1":
+			return "S0", true
+		}
+	case "S4":
+		switch symbol {
+		case "0":
+			return "S1", true
+		case "1":
+			return "S2", true
+		}
+	case "S5":
+		switch symbol {
+		case "0":
+			return "S3", true
+		case "1":
+			return "S4", true
+		}
+	case "S6":
+		switch symbol {
+		case "0":
+			return "S5", true
+		case "1":
+			return "S6", true
+		}
+	}
+	return state, false
+}
+
+func Mod7Base2IsAccepting(state Mod7Base2State) bool {
+	switch state {
+	case "S0", "S1", "S2", "S3", "S4", "S5", "S6":
+		return true
+	}
+	return false
+}
+
+func Mod7Base2Run(input string) (Mod7Base2State, error) {
+	state := Mod7Base2InitialState
+	for i, char := range input {
+		next, ok := Mod7Base2Transition(state, string(char))
+		if !ok {
+			return state, fmt.Errorf("invalid symbol '%c' at position %d in state %s", char, i, state)
+		}
+		state = next
+	}
+	return state, nil
+}
@@ -0,0 +1,560 @@
+// Code generated by fsmgen. DO NOT EDIT.
+
+package bench
+
+import "fmt"
+
+type Mod7Base36State string
+
+const Mod7Base36InitialState Mod7Base36State = "S0"
+
+func Mod7Base36Transition(state Mod7Base36State, symbol string) (Mod7Base36State, bool) {
+	switch state {
+	case "S0":
+		switch symbol {
+		case "0":
+			return "S0", true
+		case "1":
+			return "S1", true
+		case "2":
+			return "S2", true
+		case "3":
+			return "S3", true
+		case "4":
+			return "S4", true
+		case "5":
+			return "S5", true
+		case "6":
+			return "S6", true
+		case "7":
+			return "S0", true
+		case "8":
+			return "S1", true
+		case "9":
+			return "S2", true
+		case "a":
+			return "S3", true
+		case "b":
+			return "S4", true
+		case "c":
+			return "S5", true
+		case "d":
+			return "S6", true
+		case "e":
+			return "S0", true
+		case "f":
+			return "S1", true
+		case "g":
+			return "S2", true
+		case "h":
+			return "S3", true
+		case "i":
+			return "S4", true
+		case "j":
+			return "S5", true
+		case "k":
+			return "S6", true
+		case "l":
+			return "S0", true
+		case "m":
+			return "S1", true
+		case "n":
+			return "S2", true
+		case "o":
+			return "S3", true
+		case "p":
+			return "S4", true
+		case "q":
+			return "S5", true
+		case "r":
+			return "S6", true
+		case "s":
+			return "S0", true
+		case "t":
+			return "S1", true
+		case "u":
+			return "S2", true
+		case "v":
+			return "S3", true
+		case "w":
+			return "S4", true
+		case "x":
+			return "S5", true
+		case "y":
+			return "S6", true
+		case "z":
+			return "S0", true
+		}
+	case "S1":
+		switch symbol {
+		case "0":
+			return "S1", true
+		case "1":
+			return "S2", true
+		case "2":
+			return "S3", true
+		case "3":
+			return "S4", true
+		case "4":
+			return "S5", true
+		case "5":
+			return "S6", true
+		case "6":
+			return "S0", true
+		case "7":
+			return "S1", true
+		case "8":
+			return "S2", true
+		case "9":
+			return "S3", true
+		case "a":
+			return "S4", true
+		case "b":
+			return "S5", true
+		case "c":
+			return "S6", true
+		case "d":
+			return "S0", true
+		case "e":
+			return "S1", true
+		case "f":
+			return "S2", true
+		case "g":
+			return "S3", true
+		case "h":
+			return "S4", true
+		case "i":
+			return "S5", true
+		case "j":
+			return "S6", true
+		case "k":
+			return "S0", true
+		case "l":
+			return "S1", true
+		case "m":
+			return "S2", true
+		case "n":
+			return "S3", true
+		case "o":
+			return "S4", true
+		case "p":
+			return "S5", true
+		case "q":
+			return "S6", true
+		case "r":
+			return "S0", true
+		case "s":
+			return "S1", true
+		case "t":
+			return "S2", true
+		case "u":
+			return "S3", true
+		case "v":
+			return "S4", true
+		case "w":
+			return "S5", true
+		case "x":
+			return "S6", true
+		case "y":
+			return "S0", true
+		case "z":
+			return "S1", true
+		}
+	case "S2":
+		switch symbol {
+		case "0":
+			return "S2", true
+		case "1":
+			return "S3", true
+		case "2":
+			return "S4", true
+		case "3":
+			return "S5", true
+		case "4":
+			return "S6", true
+		case "5":
+			return "S0", true
+		case "6":
+			return "S1", true
+		case "7":
+			return "S2", true
+		case "8":
+			return "S3", true
+		case "9":
+			return "S4", true
+		case "a":
+			return "S5", true
+		case "b":
+			return "S6", true
+		case "c":
+			return "S0", true
+		case "d":
+			return "S1", true
+		case "e":
+			return "S2", true
+		case "f":
+			return "S3", true
+		case "g":
+			return "S4", true
+		case "h":
+			return "S5", true
+		case "i":
+			return "S6", true
+		case "j":
+			return "S0", true
+		case "k":
+			return "S1", true
+		case "l":
+			return "S2", true
+		case "m":
+			return "S3", true
+		case "n":
+			return "S4", true
+		case "o":
+			return "S5", true
+		case "p":
+			return "S6", true
+		case "q":
+			return "S0", true
+		case "r":
+			return "S1", true
+		case "s":
+			return "S2", true
+		case "t":
+			return "S3", true
+		case "u":
+			return "S4", true
+		case "v":
+			return "S5", true
+		case "w":
+			return "S6", true
+		case "x":
+			return "S0", true
+		case "y":
+			return "S1", true
+		case "z":
+			return "S2", true
+		}
+	case "S3":
+		switch symbol {
+		case "0":
+			return "S3", true
+		case "1":
+			return "S4", true
+		case "2":
+			return "S5", true
+		case "3":
+			return "S6", true
+		case "4":
+			return "S0", true
+		case "5":
+			return "S1", true
+		case "6":
+			return "S2", true
+		case "7":
+			return "S3", true
+		case "8":
+			return "S4", true
+		case "9":
+			return "S5", true
+		case "a":
+			return "S6", true
+		case "b":
+			return "S0", true
+		case "c":
+			return "S1", true
+		case "d":
+			return "S2", true
+		case "e":
+			return "S3", true
+		case "f":
+			return "S4", true
+		case "g":
+			return "S5", true
+		case "h":
+			return "S6", true
+		case "i":
+			return "S0", true
+		case "j":
+			return "S1", true
+		case "k":
+			return "S2", true
+		case "l":
+			return "S3", true
+		case "m":
+			return "S4", true
+		case "n":
+			return "S5", true
+		case "o":
+			return "S6", true
+		case "p":
+			return "S0", true
+		case "q":
+			return "S1", true
+		case "r":
+			return "S2", true
+		case "s":
+			return "S3", true
+		case "t":
+			return "S4", true
+		case "u":
+			return "S5", true
+		case "v":
+			return "S6", true
+		case "w":
+			return "S0", true
+		case "x":
+			return "S1", true
+		case "y":
+			return "S2", true
+		case "z":
+			return "S3", true
+		}
+	case "S4":
+		switch symbol {
+		case "0":
+			return "S4", true
+		case "1":
+			return "S5", true
+		case "2":
+			return "S6", true
+		case "3":
+			return "S0", true
+		case "4":
+			return "S1", true
+		case "5":
+			return "S2", true
+		case "6":
+			return "S3", true
+		case "7":
+			return "S4", true
+		case "8":
+			return "S5", true
+		case "9":
+			return "S6", true
+		case "a":
+			return "S0", true
+		case "b":
+			return "S1", true
+		case "c":
+			return "S2", true
+		case "d":
+			return "S3", true
+		case "e":
+			return "S4", true
+		case "f":
+			return "S5", true
+		case "g":
+			return "S6", true
+		case "h":
+			return "S0", true
+		case "i":
+			return "S1", true
+		case "j":
+			return "S2", true
+		case "k":
+			return "S3", true
+		case "l":
+			return "S4", true
+		case "m":
+			return "S5", true
+		case "n":
+			return "S6", true
+		case "o":
+			return "S0", true
+		case "p":
+			return "S1", true
+		case "q":
+			return "S2", true
+		case "r":
+			return "S3", true
+		case "s":
+			return "S4", true
+		case "t":
+			return "S5", true
+		case "u":
+			return "S6", true
+		case "v":
+			return "S0", true
+		case "w":
+			return "S1", true
+		case "x":
+			return "S2", true
+		case "y":
+			return "S3", true
+		case "z":
+			return "S4", true
+		}
+	case "S5":
+		switch symbol {
+		case "0":
+			return "S5", true
+		case "1":
+			return "S6", true
+		case "2":
+			return "S0", true
+		case "3":
+			return "S1", true
+		case "4":
+			return "S2", true
+		case "5":
+			return "S3", true
+		case "6":
+			return "S4", true
+		case "7":
+			return "S5", true
+		case "8":
+			return "S6", true
+		case "9":
+			return "S0", true
+		case "a":
+			return "S1", true
+		case "b":
+			return "S2", true
+		case "c":
+			return "S3", true
+		case "d":
+			return "S4", true
+		case "e":
+			return "S5", true
+		case "f":
+			return "S6", true
+		case "g":
+			return "S0", true
+		case "h":
+			return "S1", true
+		case "i":
+			return "S2", true
+		case "j":
+			return "S3", true
+		case "k":
+			return "S4", true
+		case "l":
+			return "S5", true
+		case "m":
+			return "S6", true
+		case "n":
+			return "S0", true
+		case "o":
+			return "S1", true
+		case "p":
+			return "S2", true
+		case "q":
+			return "S3", true
+		case "r":
+			return "S4", true
+		case "s":
+			return "S5", true
+		case "t":
+			return "S6", true
+		case "u":
+			return "S0", true
+		case "v":
+			return "S1", true
+		case "w":
+			return "S2", true
+		case "x":
+			return "S3", true
+		case "y":
+			return "S4", true
+		case "z":
+			return "S5", true
+		}
+	case "S6":
+		switch symbol {
+		case "0":
+			return "S6", true
+		case "1":
+			return "S0", true
+		case "2":
+			return "S1", true
+		case "3":
+			return "S2", true
+		case "4":
+			return "S3", true
+		case "5":
+			return "S4", true
+		case "6":
+			return "S5", true
+		case "7":
+			return "S6", true
+		case "8":
+			return "S0", true
+		case "9":
+			return "S1", true
+		case "a":
+			return "S2", true
+		case "b":
+			return "S3", true
+		case "c":
+			return "S4", true
+		case "d":
+			return "S5", true
+		case "e":
+			return "S6", true
+		case "f":
+			return "S0", true
+		case "g":
+			return "S1", true
+		case "h":
+			return "S2", true
+		case "i":
+			return "S3", true
+		case "j":
+			return "S4", true
+		case "k":
+			return "S5", true
+		case "l":
+			return "S6", true
+		case "m":
+			return "S0", true
+		case "n":
+			return "S1", true
+		case "o":
+			return "S2", true
+		case "p":
+			return "S3", true
+		case "q":
+			return "S4", true
+		case "r":
+			return "S5", true
+		case "s":
+			return "S6", true
+		case "t":
+			return "S0", true
+		case "u":
+			return "S1", true
+		case "v":
+			return "S2", true
+		case "w":
+			return "S3", true
+		case "x":
+			return "S4", true
+		case "y":
+			return "S5", true
+		case "z":
+			return "S6", true
+		}
+	}
+	return state, false
+}
+
+func Mod7Base36IsAccepting(state Mod7Base36State) bool {
+	switch state {
+	case "S0", "S1", "S2", "S3", "S4", "S5", "S6":
+		return true
+	}
+	return false
+}
+
+func Mod7Base36Run(input string) (Mod7Base36State, error) {
+	state := Mod7Base36InitialState
+	for i, char := range input {
+		next, ok := Mod7Base36Transition(state, string(char))
+		if !ok {
+			return state, fmt.Errorf("invalid symbol '%c' at position %d in state %s", char, i, state)
+		}
+		state = next
+	}
+	return state, nil
+}
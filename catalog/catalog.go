@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+
+	"fsm-modulo-three/fsm"
+)
+
+var binaryAlphabet = []fsm.Symbol{"0", "1"}
+
+func EvenOnes() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("Even", "0", "Even")
+	table.Set("Even", "1", "Odd")
+	table.Set("Odd", "0", "Odd")
+	table.Set("Odd", "1", "Even")
+
+	return fsm.NewTableAutomaton([]fsm.State{"Even", "Odd"}, binaryAlphabet, "Even", []fsm.State{"Even"}, table)
+}
+
+func OddParity() *fsm.FiniteAutomaton {
+	fa := EvenOnes()
+	fa.AcceptingStates = []fsm.State{"Odd"}
+	return fa
+}
+
+func EvenLength() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	for _, symbol := range binaryAlphabet {
+		table.Set("Even", symbol, "Odd")
+		table.Set("Odd", symbol, "Even")
+	}
+
+	return fsm.NewTableAutomaton([]fsm.State{"Even", "Odd"}, binaryAlphabet, "Even", []fsm.State{"Even"}, table)
+}
+
+func EndsWith01() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("Start", "0", "Zero")
+	table.Set("Start", "1", "Start")
+	table.Set("Zero", "0", "Zero")
+	table.Set("Zero", "1", "ZeroOne")
+	table.Set("ZeroOne", "0", "Zero")
+	table.Set("ZeroOne", "1", "Start")
+
+	return fsm.NewTableAutomaton([]fsm.State{"Start", "Zero", "ZeroOne"}, binaryAlphabet, "Start", []fsm.State{"ZeroOne"}, table)
+}
+
+func NoConsecutiveOnes() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("LastZero", "0", "LastZero")
+	table.Set("LastZero", "1", "LastOne")
+	table.Set("LastOne", "0", "LastZero")
+	table.Set("LastOne", "1", "Dead")
+	table.Set("Dead", "0", "Dead")
+	table.Set("Dead", "1", "Dead")
+
+	return fsm.NewTableAutomaton(
+		[]fsm.State{"LastZero", "LastOne", "Dead"},
+		binaryAlphabet,
+		"LastZero",
+		[]fsm.State{"LastZero", "LastOne"},
+		table,
+	)
+}
+
+func ContainsBinary(pattern string) (*fsm.FiniteAutomaton, error) {
+	return fsm.SubstringAutomaton(pattern, binaryAlphabet)
+}
+
+func DivisibleBy(k, base int) (*fsm.FiniteAutomaton, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("divisor must be positive, got %d", k)
+	}
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("base must be between 2 and 36, got %d", base)
+	}
+
+	states := make([]fsm.State, k)
+	for r := range states {
+		states[r] = fsm.State(fmt.Sprintf("R%d", r))
+	}
+
+	alphabet := make([]fsm.Symbol, base)
+	for digit := range alphabet {
+		alphabet[digit] = fsm.Symbol(strconv.FormatInt(int64(digit), base))
+	}
+
+	table := fsm.TransitionTable{}
+	for r := range states {
+		for digit, symbol := range alphabet {
+			table.Set(states[r], symbol, states[(r*base+digit)%k])
+		}
+	}
+
+	return fsm.NewTableAutomaton(states, alphabet, states[0], []fsm.State{states[0]}, table), nil
+}
@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmtest"
+)
+
+func checkAgainst(t *testing.T, fa *fsm.FiniteAutomaton, maxLen int, expected func(string) bool) {
+	t.Helper()
+
+	for _, input := range fsm.SeedCorpus(fa.Alphabet, maxLen) {
+		accepted, err := fa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != expected(input) {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, expected(input), accepted)
+		}
+	}
+}
+
+func TestEvenOnes(t *testing.T) {
+	checkAgainst(t, EvenOnes(), 8, func(input string) bool {
+		return strings.Count(input, "1")%2 == 0
+	})
+}
+
+func TestOddParity(t *testing.T) {
+	checkAgainst(t, OddParity(), 8, func(input string) bool {
+		return strings.Count(input, "1")%2 == 1
+	})
+}
+
+func TestEvenLength(t *testing.T) {
+	checkAgainst(t, EvenLength(), 8, func(input string) bool {
+		return len(input)%2 == 0
+	})
+}
+
+func TestEndsWith01(t *testing.T) {
+	checkAgainst(t, EndsWith01(), 8, func(input string) bool {
+		return strings.HasSuffix(input, "01")
+	})
+}
+
+func TestNoConsecutiveOnes(t *testing.T) {
+	checkAgainst(t, NoConsecutiveOnes(), 8, func(input string) bool {
+		return !strings.Contains(input, "11")
+	})
+}
+
+func TestContainsBinary(t *testing.T) {
+	fa, err := ContainsBinary("101")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkAgainst(t, fa, 8, func(input string) bool {
+		return strings.Contains(input, "101")
+	})
+
+	if _, err := ContainsBinary("102"); err == nil {
+		t.Error("Expected error for non-binary pattern, but got none")
+	}
+}
+
+func TestDivisibleBy(t *testing.T) {
+	tests := []struct {
+		k    int
+		base int
+	}{
+		{1, 2},
+		{3, 2},
+		{5, 2},
+		{7, 10},
+		{4, 16},
+		{6, 3},
+	}
+
+	for _, test := range tests {
+		fa, err := DivisibleBy(test.k, test.base)
+		if err != nil {
+			t.Fatalf("Unexpected error for k=%d base=%d: %v", test.k, test.base, err)
+		}
+
+		checkAgainst(t, fa, 4, func(input string) bool {
+			if input == "" {
+				return true
+			}
+			value, _ := new(big.Int).SetString(input, test.base)
+			return new(big.Int).Mod(value, big.NewInt(int64(test.k))).Sign() == 0
+		})
+	}
+}
+
+func TestDivisibleBy_ThreeMatchesModThreeDiagram(t *testing.T) {
+	fa, err := DivisibleBy(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fsmtest.AssertFinalState(t, fa, "1101", "R1")
+	fsmtest.AssertFinalState(t, fa, "1110", "R2")
+	fsmtest.AssertFinalState(t, fa, "1111", "R0")
+}
+
+func TestDivisibleBy_InvalidArguments(t *testing.T) {
+	if _, err := DivisibleBy(0, 2); err == nil {
+		t.Error("Expected error for zero divisor, but got none")
+	}
+	if _, err := DivisibleBy(3, 1); err == nil {
+		t.Error("Expected error for base 1, but got none")
+	}
+	if _, err := DivisibleBy(3, 37); err == nil {
+		t.Error("Expected error for base 37, but got none")
+	}
+}
@@ -0,0 +1,133 @@
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+
+	"fsm-modulo-three/fsm"
+)
+
+func ParityFrames(dataBits int, odd bool) (*fsm.FiniteAutomaton, error) {
+	if dataBits < 1 {
+		return nil, fmt.Errorf("frames need at least one data bit, got %d", dataBits)
+	}
+
+	name := func(position, ones int) fsm.State {
+		parity := "Even"
+		if ones == 1 {
+			parity = "Odd"
+		}
+		return fsm.State(fmt.Sprintf("B%d%s", position, parity))
+	}
+	expected := 0
+	if odd {
+		expected = 1
+	}
+
+	var states []fsm.State
+	table := fsm.TransitionTable{}
+	for position := 0; position < dataBits; position++ {
+		for ones := 0; ones < 2; ones++ {
+			states = append(states, name(position, ones))
+			table.Set(name(position, ones), "0", name(position+1, ones))
+			table.Set(name(position, ones), "1", name(position+1, 1-ones))
+		}
+	}
+	for ones := 0; ones < 2; ones++ {
+		states = append(states, name(dataBits, ones))
+		for bit, symbol := range binaryAlphabet {
+			if (ones+bit)%2 == expected {
+				table.Set(name(dataBits, ones), symbol, name(0, 0))
+			} else {
+				table.Set(name(dataBits, ones), symbol, "Bad")
+			}
+		}
+	}
+	states = append(states, "Bad")
+	for _, symbol := range binaryAlphabet {
+		table.Set("Bad", symbol, "Bad")
+	}
+
+	return fsm.NewTableAutomaton(states, binaryAlphabet, name(0, 0), []fsm.State{name(0, 0)}, table), nil
+}
+
+func Luhn() *fsm.FiniteAutomaton {
+	fa, _ := LuhnModN(10)
+	return fa
+}
+
+// LuhnModN accepts non-empty digit strings whose Luhn mod N checksum is zero.
+// Reading left to right, the position of a digit relative to the end is not
+// yet known, so each state tracks the sum both ways: A as if the last digit
+// read is undoubled, B as if it is doubled.
+func LuhnModN(base int) (*fsm.FiniteAutomaton, error) {
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("base must be between 2 and 36, got %d", base)
+	}
+
+	alphabet := make([]fsm.Symbol, base)
+	for digit := range alphabet {
+		alphabet[digit] = fsm.Symbol(strconv.FormatInt(int64(digit), base))
+	}
+	double := func(digit int) int {
+		doubled := 2 * digit
+		return doubled/base + doubled%base
+	}
+	name := func(a, b int) fsm.State {
+		return fsm.State(fmt.Sprintf("L%d_%d", a, b))
+	}
+
+	states := []fsm.State{"Start"}
+	var accepting []fsm.State
+	table := fsm.TransitionTable{}
+	for digit, symbol := range alphabet {
+		table.Set("Start", symbol, name(digit%base, double(digit)%base))
+	}
+	for a := 0; a < base; a++ {
+		for b := 0; b < base; b++ {
+			states = append(states, name(a, b))
+			if a == 0 {
+				accepting = append(accepting, name(a, b))
+			}
+			for digit, symbol := range alphabet {
+				table.Set(name(a, b), symbol, name((b+digit)%base, (a+double(digit))%base))
+			}
+		}
+	}
+
+	return fsm.NewTableAutomaton(states, alphabet, "Start", accepting, table), nil
+}
+
+// CRC accepts bit strings, most significant bit first, that leave a zero
+// remainder when divided by the generator polynomial: a message followed by
+// its CRC, computed with a zero initial value and no reflection or final XOR.
+// poly is written without its implicit x^width term, so CRC-8 is CRC(8, 0x07).
+func CRC(width int, poly uint64) (*fsm.FiniteAutomaton, error) {
+	if width < 1 || width > 16 {
+		return nil, fmt.Errorf("register width must be between 1 and 16, got %d", width)
+	}
+	mask := uint64(1)<<width - 1
+	if poly == 0 || poly&^mask != 0 {
+		return nil, fmt.Errorf("polynomial %#x does not fit a %d-bit register", poly, width)
+	}
+
+	digits := (width + 3) / 4
+	name := func(register uint64) fsm.State {
+		return fsm.State(fmt.Sprintf("R%0*x", digits, register))
+	}
+
+	states := make([]fsm.State, 0, 1<<width)
+	table := fsm.TransitionTable{}
+	for register := uint64(0); register <= mask; register++ {
+		states = append(states, name(register))
+		for bit, symbol := range binaryAlphabet {
+			next := (register<<1 | uint64(bit)) & mask
+			if register>>(width-1)&1 == 1 {
+				next ^= poly
+			}
+			table.Set(name(register), symbol, name(next))
+		}
+	}
+
+	return fsm.NewTableAutomaton(states, binaryAlphabet, name(0), []fsm.State{name(0)}, table), nil
+}
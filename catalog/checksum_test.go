@@ -0,0 +1,162 @@
+package catalog
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsmtest"
+)
+
+func TestParityFrames(t *testing.T) {
+	for _, odd := range []bool{false, true} {
+		fa, err := ParityFrames(3, odd)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		checkAgainst(t, fa, 8, func(input string) bool {
+			if len(input)%4 != 0 {
+				return false
+			}
+			for i := 0; i < len(input); i += 4 {
+				if (strings.Count(input[i:i+4], "1")%2 == 1) != odd {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	if _, err := ParityFrames(0, false); err == nil {
+		t.Error("Expected error for zero data bits, but got none")
+	}
+}
+
+func luhnValid(input string, base int) bool {
+	if input == "" {
+		return false
+	}
+	sum := 0
+	for i := len(input) - 1; i >= 0; i-- {
+		digit, _ := strconv.ParseInt(input[i:i+1], base, 64)
+		d := int(digit)
+		if (len(input)-1-i)%2 == 1 {
+			d *= 2
+			d = d/base + d%base
+		}
+		sum += d
+	}
+	return sum%base == 0
+}
+
+func TestLuhn(t *testing.T) {
+	fa := Luhn()
+
+	fsmtest.AssertAccepts(t, fa, "79927398713", "4539578763621486", "0", "18")
+	fsmtest.AssertRejects(t, fa, "", "79927398710", "4539578763621487", "81")
+
+	checkAgainst(t, fa, 4, func(input string) bool {
+		return luhnValid(input, 10)
+	})
+}
+
+func TestLuhnModN(t *testing.T) {
+	for _, base := range []int{2, 3, 16} {
+		fa, err := LuhnModN(base)
+		if err != nil {
+			t.Fatalf("Unexpected error for base %d: %v", base, err)
+		}
+		checkAgainst(t, fa, 4, func(input string) bool {
+			return luhnValid(input, base)
+		})
+	}
+
+	if _, err := LuhnModN(37); err == nil {
+		t.Error("Expected error for base 37, but got none")
+	}
+}
+
+func crcBits(width int, poly uint64, message string) string {
+	mask := uint64(1)<<width - 1
+	var crc uint64
+	for _, char := range message {
+		top := crc>>(width-1)&1 ^ uint64(char-'0')
+		crc = crc << 1 & mask
+		if top == 1 {
+			crc ^= poly
+		}
+	}
+	return fmt.Sprintf("%0*b", width, crc)
+}
+
+func TestCRC(t *testing.T) {
+	tests := []struct {
+		width int
+		poly  uint64
+	}{
+		{1, 0x1},
+		{3, 0x3},
+		{8, 0x07},
+		{16, 0x1021},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d_%#x", test.width, test.poly), func(t *testing.T) {
+			fa, err := CRC(test.width, test.poly)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(fa.States) != 1<<test.width {
+				t.Errorf("Expected %d states, got %d", 1<<test.width, len(fa.States))
+			}
+
+			for _, message := range []string{"", "1", "1101", "100000001", "1011001110001111"} {
+				framed := message + crcBits(test.width, test.poly, message)
+				fsmtest.AssertAccepts(t, fa, framed)
+				if test.width > 1 {
+					for i := range framed {
+						flipped := []byte(framed)
+						flipped[i] ^= 1
+						fsmtest.AssertRejects(t, fa, string(flipped))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCRC_CheckValue(t *testing.T) {
+	fa, err := CRC(8, 0x07)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var message strings.Builder
+	for _, b := range []byte("123456789") {
+		fmt.Fprintf(&message, "%08b", b)
+	}
+	if crc := crcBits(8, 0x07, message.String()); crc != "11110100" {
+		t.Fatalf("Expected CRC-8 check value 0xf4, got %s", crc)
+	}
+	fsmtest.AssertAccepts(t, fa, message.String()+"11110100")
+}
+
+func TestCRC_InvalidArguments(t *testing.T) {
+	tests := []struct {
+		width int
+		poly  uint64
+	}{
+		{0, 0x1},
+		{17, 0x1},
+		{8, 0},
+		{8, 0x107},
+	}
+
+	for _, test := range tests {
+		if _, err := CRC(test.width, test.poly); err == nil {
+			t.Errorf("Expected error for width %d poly %#x, but got none", test.width, test.poly)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package catalog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+var nucleotides = []fsm.Symbol{"A", "C", "G", "T"}
+
+// nucleotideNormalizer accepts lower-case bases and reads RNA uracil as
+// thymine, so the same machines run over DNA and RNA sequences.
+var nucleotideNormalizer = fsm.Aliases(map[fsm.Symbol]fsm.Symbol{
+	"a": "A", "c": "C", "g": "G", "t": "T", "u": "T", "U": "T",
+})
+
+func ContainsMotif(motif string) (*fsm.FiniteAutomaton, error) {
+	if motif == "" {
+		return nil, fmt.Errorf("motif must not be empty")
+	}
+	fa, err := fsm.SubstringAutomaton(strings.ReplaceAll(strings.ToUpper(motif), "U", "T"), nucleotides)
+	if err != nil {
+		return nil, err
+	}
+	fa.Normalizer = nucleotideNormalizer
+	return fa, nil
+}
+
+// MooreMachine emits the output of every state it enters. States without an
+// entry in Outputs emit the empty string.
+type MooreMachine struct {
+	Automaton *fsm.FiniteAutomaton
+	Outputs   map[fsm.State]string
+}
+
+// Run returns one output per input symbol, for the state entered after it.
+func (m *MooreMachine) Run(input string) ([]string, error) {
+	runner := fsm.NewRunner(m.Automaton)
+	outputs := make([]string, 0, len(input))
+	for i, char := range input {
+		state, err := runner.Step(fsm.Symbol(string(char)))
+		if err != nil {
+			return outputs, fmt.Errorf("at position %d: %w", i, err)
+		}
+		outputs = append(outputs, m.Outputs[state])
+	}
+	return outputs, nil
+}
+
+// OpenReadingFrame reads codons in a single frame and outputs "start" on the
+// last base of an ATG that opens a reading frame and "stop" on the last base
+// of the TAA, TAG or TGA that closes it. Start codons inside an open frame are
+// ordinary codons. The machine accepts when the last codon closed a frame.
+func OpenReadingFrame() *MooreMachine {
+	table := fsm.TransitionTable{}
+	for _, boundary := range []fsm.State{"Out", "Stop"} {
+		table.Set(boundary, "A", "OutA")
+		table.SetDefault(boundary, "Out1")
+	}
+	table.Set("OutA", "T", "OutAT")
+	table.SetDefault("OutA", "Out2")
+	table.SetDefault("Out1", "Out2")
+	table.Set("OutAT", "G", "Start")
+	table.SetDefault("OutAT", "Out")
+	table.SetDefault("Out2", "Out")
+
+	for _, boundary := range []fsm.State{"Start", "Orf"} {
+		table.Set(boundary, "T", "OrfT")
+		table.SetDefault(boundary, "Orf1")
+	}
+	table.Set("OrfT", "A", "OrfTA")
+	table.Set("OrfT", "G", "OrfTG")
+	table.SetDefault("OrfT", "Orf2")
+	table.SetDefault("Orf1", "Orf2")
+	table.Set("OrfTA", "A", "Stop")
+	table.Set("OrfTA", "G", "Stop")
+	table.SetDefault("OrfTA", "Orf")
+	table.Set("OrfTG", "A", "Stop")
+	table.SetDefault("OrfTG", "Orf")
+	table.SetDefault("Orf2", "Orf")
+
+	fa := fsm.NewTableAutomaton(
+		[]fsm.State{"Out", "OutA", "Out1", "OutAT", "Out2", "Start", "OrfT", "Orf1", "OrfTA", "OrfTG", "Orf2", "Orf", "Stop"},
+		nucleotides,
+		"Out",
+		[]fsm.State{"Stop"},
+		table,
+	)
+	fa.Normalizer = nucleotideNormalizer
+	return &MooreMachine{
+		Automaton: fa,
+		Outputs:   map[fsm.State]string{"Start": "start", "Stop": "stop"},
+	}
+}
+
+type FASTAPosition struct {
+	Record int
+	Header string
+	Offset int
+}
+
+// ScanFASTA streams the bases of a FASTA file to visit one at a time, so
+// sequences never need to fit in memory. Offsets restart at zero for each
+// record; lines starting with ';' are comments. Errors from visit are
+// reported with the line they occurred on.
+func ScanFASTA(r io.Reader, visit func(position FASTAPosition, base fsm.Symbol) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	position := FASTAPosition{Record: -1}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || strings.HasPrefix(text, ";"):
+			continue
+		case strings.HasPrefix(text, ">"):
+			position = FASTAPosition{Record: position.Record + 1, Header: strings.TrimSpace(text[1:])}
+			continue
+		case position.Record < 0:
+			return fmt.Errorf("line %d: sequence data before the first '>' header", line)
+		}
+
+		for _, char := range text {
+			if err := visit(position, fsm.Symbol(string(char))); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			position.Offset++
+		}
+	}
+	return scanner.Err()
+}
+
+type MotifMatch struct {
+	Header string
+	Offset int
+}
+
+// FindMotif reports the first occurrence of motif in each FASTA record that
+// contains it.
+func FindMotif(r io.Reader, motif string) ([]MotifMatch, error) {
+	fa, err := ContainsMotif(motif)
+	if err != nil {
+		return nil, err
+	}
+
+	runner := fsm.NewRunner(fa)
+	found := false
+	var matches []MotifMatch
+	err = ScanFASTA(r, func(position FASTAPosition, base fsm.Symbol) error {
+		if position.Offset == 0 {
+			runner.Reset()
+			found = false
+		}
+		if _, err := runner.Step(base); err != nil {
+			return err
+		}
+		if !found && runner.IsAccepting() {
+			found = true
+			matches = append(matches, MotifMatch{Header: position.Header, Offset: position.Offset - len(motif) + 1})
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// ORF is an open reading frame on the forward strand. Start and End are base
+// offsets within the record; End is exclusive and includes the stop codon.
+type ORF struct {
+	Header string
+	Frame  int
+	Start  int
+	End    int
+}
+
+func (o ORF) Codons() int {
+	return (o.End - o.Start) / 3
+}
+
+// FindORFs runs OpenReadingFrame in each of the three forward frames of every
+// FASTA record and reports the frames of at least minCodons codons, counting
+// the start and stop codons. Frames still open at the end of a record are
+// dropped.
+func FindORFs(r io.Reader, minCodons int) ([]ORF, error) {
+	machine := OpenReadingFrame()
+	runners := make([]*fsm.Runner, 3)
+	starts := make([]int, 3)
+	for frame := range runners {
+		runners[frame] = fsm.NewRunner(machine.Automaton)
+	}
+
+	var orfs []ORF
+	err := ScanFASTA(r, func(position FASTAPosition, base fsm.Symbol) error {
+		if position.Offset == 0 {
+			for _, runner := range runners {
+				runner.Reset()
+			}
+		}
+		for frame, runner := range runners {
+			if position.Offset < frame {
+				continue
+			}
+			state, err := runner.Step(base)
+			if err != nil {
+				return err
+			}
+			switch machine.Outputs[state] {
+			case "start":
+				starts[frame] = position.Offset - 2
+			case "stop":
+				orf := ORF{Header: position.Header, Frame: frame, Start: starts[frame], End: position.Offset + 1}
+				if orf.Codons() >= minCodons {
+					orfs = append(orfs, orf)
+				}
+			}
+		}
+		return nil
+	})
+	return orfs, err
+}
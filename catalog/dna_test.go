@@ -0,0 +1,159 @@
+package catalog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmtest"
+)
+
+func TestContainsMotif(t *testing.T) {
+	fa, err := ContainsMotif("GATA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkAgainst(t, fa, 6, func(input string) bool {
+		return strings.Contains(input, "GATA")
+	})
+	fsmtest.AssertAccepts(t, fa, "ccgata", "GAUA")
+
+	rna, err := ContainsMotif("augg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fsmtest.AssertAccepts(t, rna, "CCAUGG", "ccatgg")
+	fsmtest.AssertRejects(t, rna, "AUGA")
+
+	for _, motif := range []string{"", "GANTC"} {
+		if _, err := ContainsMotif(motif); err == nil {
+			t.Errorf("Expected error for motif %q, but got none", motif)
+		}
+	}
+}
+
+func TestOpenReadingFrame(t *testing.T) {
+	machine := OpenReadingFrame()
+
+	outputs, err := machine.Run("CCATGAAATGTAGTAA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := make([]string, 16)
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("Expected no ORF in frame 0, got %q", outputs)
+	}
+
+	outputs, err = machine.Run("ATGAAATGGTAGATG")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []string{"", "", "start", "", "", "", "", "", "", "", "", "stop", "", "", "start"}
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("Expected %q, got %q", expected, outputs)
+	}
+
+	fsmtest.AssertAccepts(t, machine.Automaton, "ATGTAA", "augccuuga", "CCCATGTGA")
+	fsmtest.AssertRejects(t, machine.Automaton, "", "ATG", "ATGTAAC", "CATGTAA", "TAA")
+
+	if _, err := machine.Run("ATGN"); err == nil || !strings.Contains(err.Error(), "at position 3") {
+		t.Errorf("Expected invalid symbol error at position 3, got %v", err)
+	}
+}
+
+const fastaSample = `;sample records
+>seq1 first record
+CCGATAATG
+AAATAG
+
+>seq2
+atgccc
+uga
+>seq3
+TTTTTT
+`
+
+func TestScanFASTA(t *testing.T) {
+	var bases []string
+	var positions []FASTAPosition
+	err := ScanFASTA(strings.NewReader(fastaSample), func(position FASTAPosition, base fsm.Symbol) error {
+		if position.Offset == 0 {
+			positions = append(positions, position)
+			bases = append(bases, "")
+		}
+		bases[len(bases)-1] += string(base)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedBases := []string{"CCGATAATGAAATAG", "atgcccuga", "TTTTTT"}
+	if !reflect.DeepEqual(bases, expectedBases) {
+		t.Errorf("Expected bases %q, got %q", expectedBases, bases)
+	}
+	expectedPositions := []FASTAPosition{
+		{Record: 0, Header: "seq1 first record"},
+		{Record: 1, Header: "seq2"},
+		{Record: 2, Header: "seq3"},
+	}
+	if !reflect.DeepEqual(positions, expectedPositions) {
+		t.Errorf("Expected positions %+v, got %+v", expectedPositions, positions)
+	}
+
+	if err := ScanFASTA(strings.NewReader("ACGT\n"), func(FASTAPosition, fsm.Symbol) error { return nil }); err == nil {
+		t.Error("Expected error for sequence without header, but got none")
+	}
+}
+
+func TestFindMotif(t *testing.T) {
+	matches, err := FindMotif(strings.NewReader(fastaSample), "ATGA")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []MotifMatch{{Header: "seq1 first record", Offset: 6}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+
+	matches, err = FindMotif(strings.NewReader(fastaSample), "CCUG")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []MotifMatch{{Header: "seq2", Offset: 4}}
+	if !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, matches)
+	}
+
+	_, err = FindMotif(strings.NewReader(">bad\nACGN\n"), "ACG")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected invalid symbol error on line 2, got %v", err)
+	}
+}
+
+func TestFindORFs(t *testing.T) {
+	orfs, err := FindORFs(strings.NewReader(fastaSample), 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []ORF{
+		{Header: "seq1 first record", Frame: 0, Start: 6, End: 15},
+		{Header: "seq2", Frame: 0, Start: 0, End: 9},
+	}
+	if !reflect.DeepEqual(orfs, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, orfs)
+	}
+
+	orfs, err = FindORFs(strings.NewReader(">frames\nCATGTAAGATGCCCTAG\n"), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []ORF{{Header: "frames", Frame: 2, Start: 8, End: 17}}
+	if !reflect.DeepEqual(orfs, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, orfs)
+	}
+	if expected[0].Codons() != 3 {
+		t.Errorf("Expected 3 codons, got %d", expected[0].Codons())
+	}
+}
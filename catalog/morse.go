@@ -0,0 +1,153 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+var morseCodes = map[fsm.Symbol]string{
+	"A": ".-", "B": "-...", "C": "-.-.", "D": "-..", "E": ".", "F": "..-.",
+	"G": "--.", "H": "....", "I": "..", "J": ".---", "K": "-.-", "L": ".-..",
+	"M": "--", "N": "-.", "O": "---", "P": ".--.", "Q": "--.-", "R": ".-.",
+	"S": "...", "T": "-", "U": "..-", "V": "...-", "W": ".--", "X": "-..-",
+	"Y": "-.--", "Z": "--..",
+	"0": "-----", "1": ".----", "2": "..---", "3": "...--", "4": "....-",
+	"5": ".....", "6": "-....", "7": "--...", "8": "---..", "9": "----.",
+}
+
+func morseLetters() []fsm.Symbol {
+	letters := make([]fsm.Symbol, 0, len(morseCodes))
+	for letter := range morseCodes {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return letters
+}
+
+// MorseEncoderDefinition is a one-state transducer: each letter or digit
+// outputs its code and a space outputs the word gap "/".
+func MorseEncoderDefinition() *fsm.Definition {
+	letters := morseLetters()
+	definition := &fsm.Definition{
+		Name:            "morse-encoder",
+		States:          []fsm.State{"Ready"},
+		Alphabet:        append(letters, " "),
+		InitialState:    "Ready",
+		AcceptingStates: []fsm.State{"Ready"},
+	}
+	for _, letter := range letters {
+		definition.Transitions = append(definition.Transitions, fsm.TransitionDefinition{
+			From: "Ready", Symbol: letter, To: "Ready", Output: "'" + morseCodes[letter] + "'",
+		})
+	}
+	definition.Transitions = append(definition.Transitions, fsm.TransitionDefinition{
+		From: "Ready", Symbol: " ", To: "Ready", Output: "'/'",
+	})
+	return definition
+}
+
+// MorseDecoderDefinition walks a trie of codes over ".", "-", " " (letter gap)
+// and "/" (word gap). A gap outputs the letter for the code read so far;
+// codes that are not letters lead to the rejecting Invalid state.
+func MorseDecoderDefinition() *fsm.Definition {
+	node := func(code string) fsm.State {
+		if code == "" {
+			return "Start"
+		}
+		return fsm.State("M" + code)
+	}
+
+	prefixes := map[string]bool{"": true}
+	letterFor := make(map[string]fsm.Symbol, len(morseCodes))
+	for letter, code := range morseCodes {
+		letterFor[code] = letter
+		for i := 1; i <= len(code); i++ {
+			prefixes[code[:i]] = true
+		}
+	}
+	codes := make([]string, 0, len(prefixes))
+	for code := range prefixes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	definition := &fsm.Definition{
+		Name:            "morse-decoder",
+		Alphabet:        []fsm.Symbol{".", "-", " ", "/"},
+		InitialState:    "Start",
+		AcceptingStates: []fsm.State{"Start"},
+	}
+	add := func(from fsm.State, symbol fsm.Symbol, to fsm.State, output string) {
+		definition.Transitions = append(definition.Transitions, fsm.TransitionDefinition{
+			From: from, Symbol: symbol, To: to, Output: output,
+		})
+	}
+
+	for _, code := range codes {
+		definition.States = append(definition.States, node(code))
+		for _, mark := range []string{".", "-"} {
+			if prefixes[code+mark] {
+				add(node(code), fsm.Symbol(mark), node(code+mark), "")
+			}
+		}
+		if code == "" {
+			add("Start", " ", "Start", "")
+			add("Start", "/", "Start", "' '")
+		} else if letter, ok := letterFor[code]; ok {
+			add(node(code), " ", "Start", "'"+string(letter)+"'")
+			add(node(code), "/", "Start", "'"+string(letter)+" '")
+		}
+		definition.Transitions = append(definition.Transitions, fsm.TransitionDefinition{
+			From: node(code), Default: true, To: "Invalid",
+		})
+	}
+	definition.States = append(definition.States, "Invalid")
+	definition.Transitions = append(definition.Transitions, fsm.TransitionDefinition{
+		From: "Invalid", Default: true, To: "Invalid",
+	})
+	return definition
+}
+
+func MorseEncoder() *fsm.ScriptedMachine {
+	machine, _ := MorseEncoderDefinition().Script(nil)
+	return machine
+}
+
+func MorseDecoder() *fsm.ScriptedMachine {
+	machine, _ := MorseDecoderDefinition().Script(nil)
+	return machine
+}
+
+func MorseEncode(text string) (string, error) {
+	result, err := MorseEncoder().Run(strings.ToUpper(text))
+	if err != nil {
+		return "", err
+	}
+	return joinOutputs(result.Outputs, " "), nil
+}
+
+func MorseDecode(code string) (string, error) {
+	result, err := MorseDecoder().Run(code + " ")
+	if err != nil {
+		return "", err
+	}
+	if !result.Accepted {
+		for position, step := range result.Path {
+			if step.To == "Invalid" {
+				return "", fmt.Errorf("invalid Morse code at position %d", position)
+			}
+		}
+	}
+	return joinOutputs(result.Outputs, ""), nil
+}
+
+func joinOutputs(outputs []any, separator string) string {
+	parts := make([]string, len(outputs))
+	for i, output := range outputs {
+		parts[i] = fmt.Sprint(output)
+	}
+	return strings.Join(parts, separator)
+}
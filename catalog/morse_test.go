@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestMorseEncode(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"SOS", "... --- ..."},
+		{"hello world", ".... . .-.. .-.. --- / .-- --- .-. .-.. -.."},
+		{"R2 D2", ".-. ..--- / -.. ..---"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		encoded, err := MorseEncode(test.text)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", test.text, err)
+		}
+		if encoded != test.expected {
+			t.Errorf("For %q: expected %q, got %q", test.text, test.expected, encoded)
+		}
+	}
+
+	if _, err := MorseEncode("hi!"); err == nil || !strings.Contains(err.Error(), "invalid symbol '!'") {
+		t.Errorf("Expected invalid symbol error, got %v", err)
+	}
+}
+
+func TestMorseDecode(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+	}{
+		{"... --- ...", "SOS"},
+		{".... .. / - .... . .-. .", "HI THERE"},
+		{"-----  .----", "01"},
+		{"", ""},
+	}
+
+	for _, test := range tests {
+		decoded, err := MorseDecode(test.code)
+		if err != nil {
+			t.Fatalf("Unexpected error for %q: %v", test.code, err)
+		}
+		if decoded != test.expected {
+			t.Errorf("For %q: expected %q, got %q", test.code, test.expected, decoded)
+		}
+	}
+
+	invalid := []struct {
+		code          string
+		expectedError string
+	}{
+		{"... ......", "invalid Morse code at position 9"},
+		{"..--", "invalid Morse code at position 4"},
+		{".-x", "invalid symbol 'x'"},
+	}
+	for _, test := range invalid {
+		if _, err := MorseDecode(test.code); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("For %q: expected error containing %q, got %v", test.code, test.expectedError, err)
+		}
+	}
+}
+
+func TestMorse_RoundTrip(t *testing.T) {
+	text := "THE QUICK BROWN FOX JUMPS OVER THE LAZY DOG 0123456789"
+	encoded, err := MorseEncode(text)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	decoded, err := MorseDecode(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded != text {
+		t.Errorf("Expected %q, got %q", text, decoded)
+	}
+}
+
+func TestMorseDefinitions(t *testing.T) {
+	for _, definition := range []*fsm.Definition{MorseEncoderDefinition(), MorseDecoderDefinition()} {
+		if err := definition.Validate(); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	decoder := MorseDecoderDefinition()
+	if len(decoder.States) != 41 {
+		t.Errorf("Expected 39 code prefixes plus Start and Invalid, got %d states", len(decoder.States))
+	}
+}
@@ -0,0 +1,72 @@
+package catalog
+
+import (
+	"fmt"
+
+	"fsm-modulo-three/fsm"
+)
+
+var romanAlphabet = []fsm.Symbol{"I", "V", "X", "L", "C", "D", "M"}
+
+// romanPlace holds the numerals used to write one decimal digit: one and five
+// of the place, and ten, which is the one of the next place up.
+type romanPlace struct {
+	prefix         string
+	one, five, ten fsm.Symbol
+	maxDigit       int
+}
+
+var romanPlaces = []romanPlace{
+	{"M", "M", "", "", 3},
+	{"C", "C", "D", "M", 9},
+	{"X", "X", "L", "C", 9},
+	{"I", "I", "V", "X", 9},
+}
+
+// RomanNumerals accepts the canonical Roman numerals from I to MMMCMXCIX.
+// Numerals are read one decimal place at a time, from thousands down; a state
+// such as C4 means the hundreds digit written so far is 4 (CD). A place may
+// be skipped, but never revisited, and anything else falls into Dead.
+func RomanNumerals() *fsm.FiniteAutomaton {
+	name := func(place romanPlace, digit int) fsm.State {
+		return fsm.State(fmt.Sprintf("%s%d", place.prefix, digit))
+	}
+
+	states := []fsm.State{"Start"}
+	var accepting []fsm.State
+	table := fsm.TransitionTable{}
+	enterBelow := func(from fsm.State, place int) {
+		for _, lower := range romanPlaces[place+1:] {
+			table.Set(from, lower.one, name(lower, 1))
+			if lower.five != "" {
+				table.Set(from, lower.five, name(lower, 5))
+			}
+		}
+		table.SetDefault(from, "Dead")
+	}
+
+	enterBelow("Start", -1)
+	for i, place := range romanPlaces {
+		for digit := 1; digit <= place.maxDigit; digit++ {
+			state := name(place, digit)
+			states = append(states, state)
+			accepting = append(accepting, state)
+
+			switch digit {
+			case 1:
+				table.Set(state, place.one, name(place, 2))
+				if place.five != "" {
+					table.Set(state, place.five, name(place, 4))
+					table.Set(state, place.ten, name(place, 9))
+				}
+			case 2, 5, 6, 7:
+				table.Set(state, place.one, name(place, digit+1))
+			}
+			enterBelow(state, i)
+		}
+	}
+	states = append(states, "Dead")
+	table.SetDefault("Dead", "Dead")
+
+	return fsm.NewTableAutomaton(states, romanAlphabet, "Start", accepting, table)
+}
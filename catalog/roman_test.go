@@ -0,0 +1,53 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmtest"
+)
+
+func toRoman(n int) string {
+	values := []int{1000, 900, 500, 400, 100, 90, 50, 40, 10, 9, 5, 4, 1}
+	numerals := []string{"M", "CM", "D", "CD", "C", "XC", "L", "XL", "X", "IX", "V", "IV", "I"}
+
+	var sb strings.Builder
+	for i, value := range values {
+		for n >= value {
+			sb.WriteString(numerals[i])
+			n -= value
+		}
+	}
+	return sb.String()
+}
+
+func TestRomanNumerals(t *testing.T) {
+	fa := RomanNumerals()
+
+	canonical := make(map[string]bool, 3999)
+	for n := 1; n <= 3999; n++ {
+		canonical[toRoman(n)] = true
+		fsmtest.AssertAccepts(t, fa, toRoman(n))
+	}
+	checkAgainst(t, fa, 5, func(input string) bool {
+		return canonical[input]
+	})
+
+	fsmtest.AssertRejects(t, fa, "", "IIII", "VV", "IL", "IC", "XM", "VX", "MMMM", "CMC", "IVI", "XCX", "DCD")
+	fsmtest.AssertFinalState(t, fa, "MCMXC", "X9")
+}
+
+func TestRomanNumerals_Definition(t *testing.T) {
+	definition, err := fsm.LoadDefinition("testdata/roman_numerals.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fa, err := definition.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fsmtest.AssertEquivalent(t, fa, RomanNumerals())
+	fsmtest.AssertGoldenDOT(t, RomanNumerals(), "testdata/roman_numerals.dot")
+}
@@ -0,0 +1,163 @@
+package cellular
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+const (
+	Dead  fsm.Symbol = "0"
+	Alive fsm.Symbol = "1"
+)
+
+// Row is one generation of cells, each Dead or Alive.
+type Row []fsm.Symbol
+
+// ParseRow reads a row written with 0/1 or ./# characters.
+func ParseRow(text string) (Row, error) {
+	row := make(Row, 0, len(text))
+	for i, char := range text {
+		switch char {
+		case '0', '.':
+			row = append(row, Dead)
+		case '1', '#':
+			row = append(row, Alive)
+		default:
+			return nil, fmt.Errorf("invalid cell %q at position %d: use 0/1 or ./#", char, i)
+		}
+	}
+	return row, nil
+}
+
+// SingleCell is a row of width cells with only the centre one alive.
+func SingleCell(width int) Row {
+	row := make(Row, width)
+	for i := range row {
+		row[i] = Dead
+	}
+	if width > 0 {
+		row[width/2] = Alive
+	}
+	return row
+}
+
+func (r Row) String() string {
+	var sb strings.Builder
+	for _, cell := range r {
+		if cell == Alive {
+			sb.WriteByte('#')
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
+
+// Automaton is an elementary (two-state, radius one) cellular automaton with
+// Wolfram rule numbering: bit l<<2|c<<1|r of Rule is the next state of a cell
+// whose left neighbour, itself and right neighbour are l, c and r. Cells past
+// the edges are dead unless Wrap joins the ends into a ring.
+type Automaton struct {
+	Rule  uint8
+	Width int
+	Wrap  bool
+}
+
+func New(rule, width int) (*Automaton, error) {
+	if rule < 0 || rule > 255 {
+		return nil, fmt.Errorf("rule must be between 0 and 255, got %d", rule)
+	}
+	if width < 1 {
+		return nil, fmt.Errorf("width must be positive, got %d", width)
+	}
+	return &Automaton{Rule: uint8(rule), Width: width}, nil
+}
+
+func (a *Automaton) cell(row Row, i int) uint8 {
+	if a.Wrap {
+		i = (i + len(row)) % len(row)
+	} else if i < 0 || i >= len(row) {
+		return 0
+	}
+	if row[i] == Alive {
+		return 1
+	}
+	return 0
+}
+
+func (a *Automaton) Step(row Row) (Row, error) {
+	if len(row) != a.Width {
+		return nil, fmt.Errorf("row has %d cells, automaton width is %d", len(row), a.Width)
+	}
+
+	next := make(Row, len(row))
+	for i := range row {
+		neighbourhood := a.cell(row, i-1)<<2 | a.cell(row, i)<<1 | a.cell(row, i+1)
+		next[i] = Dead
+		if a.Rule>>neighbourhood&1 == 1 {
+			next[i] = Alive
+		}
+	}
+	return next, nil
+}
+
+// Run returns initial followed by the next steps generations.
+func (a *Automaton) Run(initial Row, steps int) ([]Row, error) {
+	rows := []Row{initial}
+	for step := 0; step < steps; step++ {
+		next, err := a.Step(rows[len(rows)-1])
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, next)
+	}
+	return rows, nil
+}
+
+// Text renders generations one per line, alive cells as '#'.
+func Text(rows []Row) string {
+	var sb strings.Builder
+	for _, row := range rows {
+		sb.WriteString(row.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// WritePNG renders generations top to bottom as a black-on-white image with
+// each cell scale pixels square.
+func WritePNG(w io.Writer, rows []Row, scale int) error {
+	if scale < 1 {
+		return fmt.Errorf("scale must be positive, got %d", scale)
+	}
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width*scale, len(rows)*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xFF
+	}
+	for y, row := range rows {
+		for x, cell := range row {
+			if cell != Alive {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetGray(x*scale+dx, y*scale+dy, color.Gray{Y: 0})
+				}
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
@@ -0,0 +1,99 @@
+package cellular
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestAutomaton_Rule90(t *testing.T) {
+	a, err := New(90, 9)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rows, err := a.Run(SingleCell(9), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "....#....\n" +
+		"...#.#...\n" +
+		"..#...#..\n" +
+		".#.#.#.#.\n"
+	if text := Text(rows); text != expected {
+		t.Errorf("Expected\n%sgot\n%s", expected, text)
+	}
+}
+
+func TestAutomaton_Rule30(t *testing.T) {
+	a, _ := New(30, 7)
+	rows, err := a.Run(SingleCell(7), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if last := rows[3].String(); last != "##.####" {
+		t.Errorf("Expected ##.#### after 3 steps, got %s", last)
+	}
+}
+
+func TestAutomaton_Wrap(t *testing.T) {
+	a, _ := New(2, 4) // every cell copies its right neighbour
+	row, _ := ParseRow("1000")
+
+	fixed, _ := a.Step(row)
+	if fixed.String() != "...." {
+		t.Errorf("Expected the live cell to leave the edge, got %s", fixed)
+	}
+
+	a.Wrap = true
+	wrapped, _ := a.Step(row)
+	if wrapped.String() != "...#" {
+		t.Errorf("Expected the live cell to wrap around, got %s", wrapped)
+	}
+}
+
+func TestAutomaton_Errors(t *testing.T) {
+	if _, err := New(256, 4); err == nil {
+		t.Error("Expected error for rule 256, but got none")
+	}
+	if _, err := New(30, 0); err == nil {
+		t.Error("Expected error for width 0, but got none")
+	}
+
+	a, _ := New(30, 4)
+	if _, err := a.Run(SingleCell(5), 1); err == nil {
+		t.Error("Expected error for a row of the wrong width, but got none")
+	}
+	if _, err := ParseRow("01x"); err == nil {
+		t.Error("Expected error for an invalid cell, but got none")
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	a, _ := New(90, 5)
+	rows, _ := a.Run(SingleCell(5), 1)
+
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, rows, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Failed to decode PNG: %v", err)
+	}
+
+	if bounds := img.Bounds(); bounds.Dx() != 10 || bounds.Dy() != 4 {
+		t.Fatalf("Expected a 10x4 image, got %v", bounds)
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+	if !dark(5, 1) || dark(0, 0) || !dark(2, 3) || dark(4, 3) {
+		t.Error("Expected live cells black and dead cells white")
+	}
+
+	if err := WritePNG(&buf, rows, 0); err == nil {
+		t.Error("Expected error for scale 0, but got none")
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func runAnimate(args []string) int {
+	flags := flag.NewFlagSet("animate", flag.ContinueOnError)
+	definitionPath := flags.String("def", "", "path to a JSON automaton definition (default: built-in mod-three)")
+	input := flags.String("input", "", "input string to simulate")
+	format := flags.String("format", "svg", "frame format: dot or svg")
+	output := flags.String("out", "frames", "directory to write frames into")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "dot" && *format != "svg" {
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected dot or svg)\n", *format)
+		return 2
+	}
+
+	_, automaton, err := loadAutomaton(*definitionPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	frames, err := automaton.Frames(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	if err := os.MkdirAll(*output, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, frame := range frames {
+		content := automaton.FrameSVG(frame)
+		if *format == "dot" {
+			content = automaton.FrameDOT(frame)
+		}
+
+		path := filepath.Join(*output, fmt.Sprintf("frame_%03d.%s", frame.Step, *format))
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	fmt.Printf("Wrote %d frames to %s\n", len(frames), *output)
+	return 0
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modulo"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	exitOK       = 0
+	exitInvalid  = 1
+	exitInternal = 2
+)
+
+type outputMode int
+
+const (
+	outputDefault outputMode = iota
+	outputTrace
+	outputQuiet
+)
+
+func runBatch(in io.Reader, out, errOut io.Writer, machine *modulo.ModFSM, mode outputMode) int {
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	status := exitOK
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+
+		if !writeResult(w, errOut, machine, mode, input, fmt.Sprintf("line %d", line)) {
+			status = exitInvalid
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(errOut, "Error: reading input: %v\n", err)
+		return exitInternal
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(errOut, "Error: writing output: %v\n", err)
+		return exitInternal
+	}
+
+	return status
+}
+
+func runArgs(inputs []string, out, errOut io.Writer, machine *modulo.ModFSM, mode outputMode) int {
+	w := bufio.NewWriter(out)
+
+	status := exitOK
+	for i, input := range inputs {
+		if !writeResult(w, errOut, machine, mode, input, fmt.Sprintf("argument %d", i+1)) {
+			status = exitInvalid
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(errOut, "Error: writing output: %v\n", err)
+		return exitInternal
+	}
+	return status
+}
+
+func writeResult(w, errOut io.Writer, machine *modulo.ModFSM, mode outputMode, input, location string) bool {
+	result, err := machine.Mod(input)
+	if err != nil {
+		writeError(w, errOut, mode, input, location, err)
+		return false
+	}
+
+	if mode == outputQuiet {
+		fmt.Fprintln(w, result.Remainder)
+		return true
+	}
+
+	if mode == outputTrace {
+		trace := fsm.NewRunResult()
+		if err := machine.GetAutomaton().RunInto(input, trace); err != nil {
+			writeError(w, errOut, mode, input, location, fmt.Errorf("tracing: %w", err))
+			return false
+		}
+		defer trace.Release()
+
+		fmt.Fprintf(w, "%s\t%d\t%s\n", result.Input, result.Remainder, result.FinalState)
+		for _, transition := range trace.Trace {
+			fmt.Fprintf(w, "  %s --%s--> %s\n", transition.From, transition.Symbol, transition.To)
+		}
+		return true
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%s\n", result.Input, result.Remainder, result.FinalState)
+	return true
+}
+
+func writeError(w, errOut io.Writer, mode outputMode, input, location string, err error) {
+	if mode == outputQuiet {
+		fmt.Fprintln(w, "error")
+	} else {
+		fmt.Fprintf(w, "%s\terror\t%v\n", input, err)
+	}
+	fmt.Fprintf(errOut, "%s: %v\n", location, err)
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
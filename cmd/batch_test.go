@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fsm-modulo-three/modulo"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func newModThree(t *testing.T) *modulo.ModFSM {
+	t.Helper()
+
+	machine, err := modulo.NewModFSM(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return machine
+}
+
+func TestRunBatch(t *testing.T) {
+	tests := []struct {
+		name           string
+		input          string
+		expectedOutput string
+		expectedStatus int
+	}{
+		{"all valid", "1101\n1110\n1111\n", "1101\t1\tS1\n1110\t2\tS2\n1111\t0\tS0\n", exitOK},
+		{"blank lines skipped", "\n  1101  \n\n", "1101\t1\tS1\n", exitOK},
+		{"empty input", "", "", exitOK},
+		{"no trailing newline", "110", "110\t0\tS0\n", exitOK},
+		{"crlf", "11\r\n", "11\t0\tS0\n", exitOK},
+		{"invalid line", "1101\n012\n1\n", "1101\t1\tS1\n012\terror\tinvalid character '2' at position 2: not a base-2 digit\n1\t1\tS1\n", exitInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			status := runBatch(strings.NewReader(test.input), &out, &errOut, newModThree(t), outputDefault)
+
+			if status != test.expectedStatus {
+				t.Errorf("Expected exit status %d, got %d", test.expectedStatus, status)
+			}
+			if out.String() != test.expectedOutput {
+				t.Errorf("Expected output %q, got %q", test.expectedOutput, out.String())
+			}
+			if (test.expectedStatus == exitOK) != (errOut.Len() == 0) {
+				t.Errorf("Unexpected stderr output %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunBatch_ReportsLineNumbers(t *testing.T) {
+	var errOut bytes.Buffer
+	runBatch(strings.NewReader("1\n\nx\n"), io.Discard, &errOut, newModThree(t), outputDefault)
+
+	if !strings.HasPrefix(errOut.String(), "line 3:") {
+		t.Errorf("Expected error for line 3, got %q", errOut.String())
+	}
+}
+
+func TestRunBatch_ReadError(t *testing.T) {
+	var errOut bytes.Buffer
+	reader := io.MultiReader(strings.NewReader("1101\n"), iotest.ErrReader(errors.New("broken pipe")))
+
+	if status := runBatch(reader, io.Discard, &errOut, newModThree(t), outputDefault); status != exitInternal {
+		t.Errorf("Expected exit status %d on read error, got %d", exitInternal, status)
+	}
+	if !strings.Contains(errOut.String(), "broken pipe") {
+		t.Errorf("Expected read error to be reported, got %q", errOut.String())
+	}
+}
+
+func TestRunArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedStderr string
+		expectedStatus int
+	}{
+		{"single", []string{"1101"}, "1101\t1\tS1\n", "", exitOK},
+		{"multiple", []string{"1101", "1110", "1111"}, "1101\t1\tS1\n1110\t2\tS2\n1111\t0\tS0\n", "", exitOK},
+		{"invalid argument", []string{"1", "2", "10"}, "1\t1\tS1\n2\terror\tinvalid character '2' at position 0: not a base-2 digit\n10\t2\tS2\n", "argument 2: ", exitInvalid},
+		{"empty argument", []string{""}, "\terror\tinput string cannot be empty\n", "argument 1: ", exitInvalid},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			status := runArgs(test.args, &out, &errOut, newModThree(t), outputDefault)
+
+			if status != test.expectedStatus {
+				t.Errorf("Expected exit status %d, got %d", test.expectedStatus, status)
+			}
+			if out.String() != test.expectedOutput {
+				t.Errorf("Expected output %q, got %q", test.expectedOutput, out.String())
+			}
+			if !strings.HasPrefix(errOut.String(), test.expectedStderr) || (test.expectedStderr == "") != (errOut.Len() == 0) {
+				t.Errorf("Expected stderr starting with %q, got %q", test.expectedStderr, errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunArgs_ModulusAndBase(t *testing.T) {
+	tests := []struct {
+		modulus        int
+		base           int
+		args           []string
+		expectedOutput string
+	}{
+		{5, 2, []string{"1010", "111"}, "1010\t0\tS0\n111\t2\tS2\n"},
+		{7, 10, []string{"100", "49"}, "100\t2\tS2\n49\t0\tS0\n"},
+		{16, 16, []string{"ff"}, "ff\t15\tS15\n"},
+	}
+
+	for _, test := range tests {
+		machine, err := modulo.NewModFSM(test.modulus, test.base)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var out bytes.Buffer
+		if status := runArgs(test.args, &out, io.Discard, machine, outputDefault); status != exitOK {
+			t.Errorf("Expected exit status %d, got %d", exitOK, status)
+		}
+		if out.String() != test.expectedOutput {
+			t.Errorf("Mod %d base %d: expected output %q, got %q", test.modulus, test.base, test.expectedOutput, out.String())
+		}
+	}
+}
+
+func TestRunArgs_OutputModes(t *testing.T) {
+	tests := []struct {
+		name           string
+		mode           outputMode
+		args           []string
+		expectedOutput string
+	}{
+		{"trace", outputTrace, []string{"110"}, "110\t0\tS0\n  S0 --1--> S1\n  S1 --1--> S0\n  S0 --0--> S0\n"},
+		{"trace invalid", outputTrace, []string{"2"}, "2\terror\tinvalid character '2' at position 0: not a base-2 digit\n"},
+		{"quiet", outputQuiet, []string{"1101", "1110", "1111"}, "1\n2\n0\n"},
+		{"quiet invalid", outputQuiet, []string{"1", "x"}, "1\nerror\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			runArgs(test.args, &out, io.Discard, newModThree(t), test.mode)
+			if out.String() != test.expectedOutput {
+				t.Errorf("Expected output %q, got %q", test.expectedOutput, out.String())
+			}
+		})
+	}
+}
+
+func TestRunArgs_TraceError(t *testing.T) {
+	machine, err := modulo.NewModFSM(3, 2, modulo.HexInput())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	if status := runArgs([]string{"f0"}, &out, &errOut, machine, outputTrace); status != exitInvalid {
+		t.Errorf("Expected exit status %d, got %d", exitInvalid, status)
+	}
+	if !strings.HasPrefix(out.String(), "f0\terror\ttracing: ") || strings.Contains(out.String(), "-->") {
+		t.Errorf("Expected a trace error instead of a partial trace, got %q", out.String())
+	}
+	if !strings.HasPrefix(errOut.String(), "argument 1: tracing: ") {
+		t.Errorf("Expected the error on stderr, got %q", errOut.String())
+	}
+}
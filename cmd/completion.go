@@ -0,0 +1,254 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const programName = "fsm-demo"
+
+type flagSpec struct {
+	name   string
+	usage  string
+	file   bool
+	values []string
+}
+
+type commandSpec struct {
+	name  string
+	usage string
+	flags []flagSpec
+}
+
+var rootFlags = []flagSpec{
+	{name: "config", usage: "config file", file: true},
+	{name: "q", usage: "batch mode"},
+	{name: "mod", usage: "modulus"},
+	{name: "base", usage: "input base"},
+	{name: "trace", usage: "print transitions"},
+	{name: "quiet", usage: "print only remainders"},
+	{name: "color", usage: "colorize interactive output"},
+	{name: "cpuprofile", usage: "CPU profile output", file: true},
+	{name: "memprofile", usage: "heap profile output", file: true},
+	{name: "log-level", usage: "log level", values: []string{"debug", "info", "warn", "error", "off"}},
+	{name: "log-format", usage: "log format", values: []string{"text", "json"}},
+	{name: "watch", usage: "re-process a file when it changes", file: true},
+}
+
+var commands = []commandSpec{
+	{name: "doc", usage: "render a documentation report", flags: []flagSpec{
+		{name: "def", usage: "JSON definition", file: true},
+		{name: "format", usage: "report format", values: []string{"markdown", "html", "svg"}},
+		{name: "out", usage: "output file", file: true},
+	}},
+	{name: "animate", usage: "write simulation frames", flags: []flagSpec{
+		{name: "def", usage: "JSON definition", file: true},
+		{name: "input", usage: "input to simulate"},
+		{name: "format", usage: "frame format", values: []string{"dot", "svg"}},
+		{name: "out", usage: "output directory", file: true},
+	}},
+	{name: "debug", usage: "step through an input interactively", flags: []flagSpec{
+		{name: "def", usage: "JSON definition", file: true},
+		{name: "input", usage: "input to debug"},
+		{name: "no-clear", usage: "do not clear the screen"},
+	}},
+	{name: "completion", usage: "generate shell completion scripts", flags: nil},
+	{name: "version", usage: "print version and build information", flags: nil},
+	{name: "selftest", usage: "verify a mod-N machine against arithmetic", flags: []flagSpec{
+		{name: "config", usage: "config file", file: true},
+		{name: "mod", usage: "modulus"},
+		{name: "base", usage: "input base"},
+		{name: "max-len", usage: "maximum input length"},
+	}},
+	{name: "serve", usage: "run the HTTP service", flags: []flagSpec{
+		{name: "config", usage: "config file", file: true},
+		{name: "mod", usage: "modulus"},
+		{name: "base", usage: "input base"},
+		{name: "port", usage: "listen port"},
+		{name: "host", usage: "listen interface"},
+		{name: "rate", usage: "requests per second per client"},
+		{name: "burst", usage: "rate limit burst"},
+		{name: "max-len", usage: "maximum input length"},
+		{name: "api-keys", usage: "API key file", file: true},
+		{name: "namespace-quota", usage: "machines per namespace"},
+		{name: "definitions", usage: "definitions directory", file: true},
+		{name: "log-level", usage: "log level", values: []string{"debug", "info", "warn", "error", "off"}},
+		{name: "log-format", usage: "log format", values: []string{"text", "json"}},
+	}},
+	{name: "verify", usage: "check a CSV of expected remainders", flags: []flagSpec{
+		{name: "config", usage: "config file", file: true},
+		{name: "mod", usage: "modulus"},
+		{name: "base", usage: "input base"},
+	}},
+	{name: "match", usage: "print lines matching a regular expression", flags: []flagSpec{
+		{name: "v", usage: "select non-matching lines"},
+		{name: "c", usage: "count selected lines"},
+		{name: "n", usage: "print line numbers"},
+		{name: "x", usage: "match whole lines"},
+		{name: "i", usage: "ignore case"},
+	}},
+	{name: "stats", usage: "print size and complexity statistics", flags: []flagSpec{
+		{name: "def", usage: "JSON definition", file: true},
+		{name: "json", usage: "print JSON"},
+	}},
+}
+
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func runCompletion(args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s completion %s\n", programName, strings.Join(completionShells, "|"))
+		return 2
+	}
+
+	if err := writeCompletion(os.Stdout, args[0]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	return 0
+}
+
+func writeCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		writeBashCompletion(w)
+	case "zsh":
+		writeZshCompletion(w)
+	case "fish":
+		writeFishCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q (expected %s)", shell, strings.Join(completionShells, ", "))
+	}
+	return nil
+}
+
+func flagNames(flags []flagSpec) string {
+	names := make([]string, len(flags))
+	for i, f := range flags {
+		names[i] = "-" + f.name
+	}
+	return strings.Join(names, " ")
+}
+
+func commandNames() string {
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = c.name
+	}
+	return strings.Join(names, " ")
+}
+
+func writeBashFlagValues(w io.Writer, flags []flagSpec, indent string) {
+	fmt.Fprintf(w, "%scase \"${prev}\" in\n", indent)
+	for _, f := range flags {
+		switch {
+		case f.file:
+			fmt.Fprintf(w, "%s-%s) COMPREPLY=( $(compgen -f -- \"${cur}\") ); return ;;\n", indent, f.name)
+		case len(f.values) > 0:
+			fmt.Fprintf(w, "%s-%s) COMPREPLY=( $(compgen -W %q -- \"${cur}\") ); return ;;\n", indent, f.name, strings.Join(f.values, " "))
+		}
+	}
+	fmt.Fprintf(w, "%sesac\n", indent)
+}
+
+func writeBashCompletion(w io.Writer) {
+	fmt.Fprintf(w, "# bash completion for %s\n", programName)
+	fmt.Fprintln(w, "_fsm_demo() {")
+	fmt.Fprintln(w, `    local cur="${COMP_WORDS[COMP_CWORD]}" prev="${COMP_WORDS[COMP_CWORD-1]}" opts`)
+	fmt.Fprintln(w, `    if [ "${COMP_CWORD}" -eq 1 ]; then`)
+	fmt.Fprintf(w, "        COMPREPLY=( $(compgen -W %q -- \"${cur}\") )\n", commandNames()+" "+flagNames(rootFlags))
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    case "${COMP_WORDS[1]}" in`)
+	for _, c := range commands {
+		if c.name == "completion" {
+			fmt.Fprintf(w, "    completion)\n        opts=%q ;;\n", strings.Join(completionShells, " "))
+			continue
+		}
+		fmt.Fprintf(w, "    %s)\n", c.name)
+		writeBashFlagValues(w, c.flags, "        ")
+		fmt.Fprintf(w, "        opts=%q ;;\n", flagNames(c.flags))
+	}
+	fmt.Fprintln(w, "    *)")
+	writeBashFlagValues(w, rootFlags, "        ")
+	fmt.Fprintf(w, "        opts=%q ;;\n", flagNames(rootFlags))
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, `    COMPREPLY=( $(compgen -W "${opts}" -- "${cur}") )`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintf(w, "complete -F _fsm_demo %s\n", programName)
+}
+
+func zshArguments(flags []flagSpec) string {
+	specs := make([]string, len(flags))
+	for i, f := range flags {
+		spec := fmt.Sprintf("'-%s[%s]", f.name, f.usage)
+		switch {
+		case f.file:
+			spec += ":file:_files"
+		case len(f.values) > 0:
+			spec += fmt.Sprintf(":%s:(%s)", f.name, strings.Join(f.values, " "))
+		}
+		specs[i] = spec + "'"
+	}
+	return strings.Join(specs, " ")
+}
+
+func writeZshCompletion(w io.Writer) {
+	fmt.Fprintf(w, "#compdef %s\n\n", programName)
+	fmt.Fprintln(w, "_fsm_demo() {")
+	fmt.Fprintln(w, "    local -a commands")
+	fmt.Fprintln(w, "    commands=(")
+	for _, c := range commands {
+		fmt.Fprintf(w, "        '%s:%s'\n", c.name, c.usage)
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, `    if (( CURRENT == 2 )) && [[ "${words[2]}" != -* ]]; then`)
+	fmt.Fprintln(w, "        _describe -t commands 'command' commands")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    fi")
+	fmt.Fprintln(w, `    case "${words[2]}" in`)
+	for _, c := range commands {
+		if c.name == "completion" {
+			fmt.Fprintf(w, "    completion)\n        _values 'shell' %s ;;\n", strings.Join(completionShells, " "))
+			continue
+		}
+		fmt.Fprintf(w, "    %s)\n        _arguments %s ;;\n", c.name, zshArguments(c.flags))
+	}
+	fmt.Fprintf(w, "    *)\n        _arguments %s ;;\n", zshArguments(rootFlags))
+	fmt.Fprintln(w, "    esac")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `_fsm_demo "$@"`)
+}
+
+func writeFishFlags(w io.Writer, condition string, flags []flagSpec) {
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c %s -n '%s' -o %s -d '%s'", programName, condition, f.name, f.usage)
+		switch {
+		case f.file:
+			line += " -r -F"
+		case len(f.values) > 0:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(f.values, " "))
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func writeFishCompletion(w io.Writer) {
+	fmt.Fprintf(w, "# fish completion for %s\n", programName)
+	fmt.Fprintf(w, "complete -c %s -f\n", programName)
+	for _, c := range commands {
+		fmt.Fprintf(w, "complete -c %s -n '__fish_use_subcommand' -a %s -d '%s'\n", programName, c.name, c.usage)
+	}
+	writeFishFlags(w, "__fish_use_subcommand", rootFlags)
+	for _, c := range commands {
+		condition := "__fish_seen_subcommand_from " + c.name
+		if c.name == "completion" {
+			fmt.Fprintf(w, "complete -c %s -n '%s' -a '%s'\n", programName, condition, strings.Join(completionShells, " "))
+			continue
+		}
+		writeFishFlags(w, condition, c.flags)
+	}
+}
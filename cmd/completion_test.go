@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := writeCompletion(&out, shell); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			script := out.String()
+			for _, c := range commands {
+				if !strings.Contains(script, c.name) {
+					t.Errorf("%s script should mention command %q", shell, c.name)
+				}
+				for _, f := range c.flags {
+					if !strings.Contains(script, f.name) {
+						t.Errorf("%s script should mention flag -%s of %s", shell, f.name, c.name)
+					}
+				}
+			}
+			for _, f := range rootFlags {
+				if !strings.Contains(script, f.name) {
+					t.Errorf("%s script should mention root flag -%s", shell, f.name)
+				}
+			}
+		})
+	}
+
+	if err := writeCompletion(&bytes.Buffer{}, "powershell"); err == nil {
+		t.Error("Expected error for unsupported shell, but got none")
+	}
+}
+
+func TestBashCompletion(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+
+	var script bytes.Buffer
+	writeCompletion(&script, "bash")
+	path := filepath.Join(t.TempDir(), "completion.bash")
+	if err := os.WriteFile(path, script.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		words    string
+		expected string
+	}{
+		{"fsm-demo d", "doc debug"},
+		{"fsm-demo doc -format ''", "markdown html svg"},
+		{"fsm-demo animate -f", "-format"},
+		{"fsm-demo completion ''", "bash zsh fish"},
+		{"fsm-demo -mod 5 -qu", "-quiet"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.words, func(t *testing.T) {
+			program := "source " + path + "; COMP_WORDS=(" + test.words + "); COMP_CWORD=$(( ${#COMP_WORDS[@]} - 1 )); _fsm_demo; echo \"${COMPREPLY[@]}\""
+			output, err := exec.Command(bash, "-c", program).CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash failed: %v\n%s", err, output)
+			}
+			if strings.TrimSpace(string(output)) != test.expected {
+				t.Errorf("Expected completions %q, got %q", test.expected, strings.TrimSpace(string(output)))
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const configFileName = ".fsmrc"
+
+type config struct {
+	Output outputMode
+	Mod    int
+	Base   int
+	Color  bool
+	Port   int
+}
+
+func defaultConfig() config {
+	return config{
+		Output: outputDefault,
+		Mod:    3,
+		Base:   2,
+		Port:   8080,
+	}
+}
+
+func loadConfig(path string, getenv func(string) string) (config, error) {
+	cfg := defaultConfig()
+
+	explicit := path != ""
+	if !explicit {
+		path = getenv("FSM_CONFIG")
+		explicit = path != ""
+	}
+	if !explicit {
+		if home := getenv("HOME"); home != "" {
+			path = filepath.Join(home, configFileName)
+		}
+	}
+
+	if path != "" {
+		file, err := os.Open(path)
+		switch {
+		case err == nil:
+			defer file.Close()
+			if err := cfg.read(file); err != nil {
+				return cfg, fmt.Errorf("%s: %w", path, err)
+			}
+		case explicit || !errors.Is(err, os.ErrNotExist):
+			return cfg, err
+		}
+	}
+
+	for _, key := range []string{"output", "mod", "base", "color", "port"} {
+		if value := getenv("FSM_" + strings.ToUpper(key)); value != "" {
+			if err := cfg.set(key, value); err != nil {
+				return cfg, fmt.Errorf("FSM_%s: %w", strings.ToUpper(key), err)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+func (c *config) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected key = value", line)
+		}
+		if err := c.set(strings.TrimSpace(key), strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *config) set(key, value string) error {
+	var err error
+	switch key {
+	case "output":
+		c.Output, err = parseOutputMode(value)
+	case "mod":
+		c.Mod, err = strconv.Atoi(value)
+	case "base":
+		c.Base, err = strconv.Atoi(value)
+	case "color":
+		c.Color, err = strconv.ParseBool(value)
+	case "port":
+		c.Port, err = strconv.Atoi(value)
+		if err == nil && (c.Port < 1 || c.Port > 65535) {
+			err = fmt.Errorf("port must be between 1 and 65535")
+		}
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", key, value, err)
+	}
+	return nil
+}
+
+func parseOutputMode(value string) (outputMode, error) {
+	switch value {
+	case "default", "tsv":
+		return outputDefault, nil
+	case "trace":
+		return outputTrace, nil
+	case "quiet":
+		return outputQuiet, nil
+	}
+	return outputDefault, fmt.Errorf("expected default, trace or quiet")
+}
+
+func explicitFlags(flags *flag.FlagSet) map[string]bool {
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	return explicit
+}
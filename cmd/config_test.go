@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, configFileName)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func envOf(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	home := t.TempDir()
+	writeConfig(t, home, "# defaults\nmod = 5\nbase=10\n\noutput = quiet\ncolor = true\nport = 9000\n")
+
+	tests := []struct {
+		name     string
+		path     string
+		env      map[string]string
+		expected config
+	}{
+		{"no config", "", map[string]string{"HOME": t.TempDir()}, defaultConfig()},
+		{"home file", "", map[string]string{"HOME": home}, config{Output: outputQuiet, Mod: 5, Base: 10, Color: true, Port: 9000}},
+		{"env overrides file", "", map[string]string{"HOME": home, "FSM_MOD": "7", "FSM_OUTPUT": "trace", "FSM_COLOR": "false"}, config{Output: outputTrace, Mod: 7, Base: 10, Color: false, Port: 9000}},
+		{"env only", "", map[string]string{"FSM_BASE": "16", "FSM_PORT": "80"}, config{Output: outputDefault, Mod: 3, Base: 16, Port: 80}},
+		{"explicit path", filepath.Join(home, configFileName), nil, config{Output: outputQuiet, Mod: 5, Base: 10, Color: true, Port: 9000}},
+		{"FSM_CONFIG path", "", map[string]string{"FSM_CONFIG": filepath.Join(home, configFileName)}, config{Output: outputQuiet, Mod: 5, Base: 10, Color: true, Port: 9000}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := loadConfig(test.path, envOf(test.env))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if cfg != test.expected {
+				t.Errorf("Expected config %+v, got %+v", test.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		content  string
+		env      map[string]string
+		expected string
+	}{
+		{"missing equals", "mod 5\n", nil, "line 1: expected key = value"},
+		{"unknown key", "\nshape = round\n", nil, `line 2: unknown setting "shape"`},
+		{"bad number", "mod = five\n", nil, `invalid mod "five"`},
+		{"bad output", "output = loud\n", nil, "expected default, trace or quiet"},
+		{"bad port", "port = 70000\n", nil, "port must be between 1 and 65535"},
+		{"bad env", "", map[string]string{"FSM_COLOR": "maybe"}, "FSM_COLOR: invalid color"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := writeConfig(t, dir, test.content)
+			_, err := loadConfig(path, envOf(test.env))
+			if err == nil || !strings.Contains(err.Error(), test.expected) {
+				t.Errorf("Expected error containing %q, got %v", test.expected, err)
+			}
+		})
+	}
+
+	if _, err := loadConfig(filepath.Join(dir, "missing"), envOf(nil)); err == nil {
+		t.Error("Expected error for a missing explicit config file, but got none")
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+const clearScreen = "\033[H\033[2J"
+
+func runDebug(args []string) int {
+	flags := flag.NewFlagSet("debug", flag.ContinueOnError)
+	definitionPath := flags.String("def", "", "path to a JSON automaton definition (default: built-in mod-three)")
+	input := flags.String("input", "", "input string to debug")
+	noClear := flags.Bool("no-clear", false, "do not clear the screen between steps")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	_, automaton, err := loadAutomaton(*definitionPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	d := newDebugger(automaton, os.Stdout)
+	d.clear = !*noClear
+	if err := d.load(*input); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	d.run(os.Stdin)
+	return 0
+}
+
+type debugger struct {
+	automaton   *fsm.FiniteAutomaton
+	runner      *fsm.Runner
+	input       []fsm.Symbol
+	position    int
+	breakpoints map[fsm.State]bool
+	message     string
+	clear       bool
+	out         io.Writer
+}
+
+func newDebugger(automaton *fsm.FiniteAutomaton, out io.Writer) *debugger {
+	return &debugger{
+		automaton:   automaton,
+		runner:      fsm.NewRunner(automaton, fsm.WithHistory()),
+		breakpoints: make(map[fsm.State]bool),
+		out:         out,
+	}
+}
+
+func (d *debugger) load(input string) error {
+	symbols := make([]fsm.Symbol, 0, len(input))
+	for i, char := range input {
+		symbol := fsm.Symbol(string(char))
+		if !d.isSymbol(symbol) {
+			return fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, d.automaton.Alphabet)
+		}
+		symbols = append(symbols, symbol)
+	}
+
+	d.input = symbols
+	d.position = 0
+	d.runner.Reset()
+	return nil
+}
+
+func (d *debugger) isSymbol(symbol fsm.Symbol) bool {
+	for _, s := range d.automaton.Alphabet {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *debugger) run(in io.Reader) {
+	d.render()
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(d.out, "(debug) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(d.out)
+			return
+		}
+
+		if quit := d.execute(strings.Fields(scanner.Text())); quit {
+			return
+		}
+		d.render()
+	}
+}
+
+func (d *debugger) execute(fields []string) bool {
+	d.message = ""
+	if len(fields) == 0 {
+		fields = []string{"step"}
+	}
+
+	command, args := fields[0], fields[1:]
+	switch command {
+	case "s", "step":
+		n, err := countArg(args)
+		if err != nil {
+			d.message = err.Error()
+			return false
+		}
+		d.step(n)
+	case "c", "continue":
+		d.continueRun()
+	case "b", "break":
+		d.setBreakpoints(args, true)
+	case "d", "delete":
+		d.setBreakpoints(args, false)
+	case "r", "rollback", "back":
+		n, err := countArg(args)
+		if err != nil {
+			d.message = err.Error()
+			return false
+		}
+		if n > d.position {
+			n = d.position
+		}
+		if err := d.runner.Rollback(n); err != nil {
+			d.message = err.Error()
+			return false
+		}
+		d.position -= n
+	case "reset":
+		d.position = 0
+		d.runner.Reset()
+	case "i", "input":
+		if err := d.load(strings.Join(args, "")); err != nil {
+			d.message = err.Error()
+		}
+	case "h", "help":
+		d.message = debugHelp
+	case "q", "quit", "exit":
+		return true
+	default:
+		d.message = fmt.Sprintf("unknown command %q (type 'help' for a list)", command)
+	}
+
+	return false
+}
+
+const debugHelp = `commands:
+  s, step [n]        consume the next n symbols (default 1, empty line steps)
+  c, continue        run until a breakpoint or the end of the input
+  b, break STATE...  set breakpoints on states
+  d, delete STATE... remove breakpoints
+  r, rollback [n]    undo the last n steps (default 1)
+  reset              return to the initial state
+  i, input STRING    load a new input string
+  q, quit            leave the debugger`
+
+func countArg(args []string) (int, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid count %q", args[0])
+	}
+	return n, nil
+}
+
+func (d *debugger) step(n int) {
+	for i := 0; i < n; i++ {
+		if d.position >= len(d.input) {
+			d.message = "end of input"
+			return
+		}
+
+		if _, err := d.runner.Step(d.input[d.position]); err != nil {
+			d.message = err.Error()
+			return
+		}
+		d.position++
+	}
+}
+
+func (d *debugger) continueRun() {
+	for d.position < len(d.input) {
+		d.step(1)
+		if state := d.runner.CurrentState(); d.breakpoints[state] {
+			d.message = fmt.Sprintf("breakpoint hit: %s at position %d", state, d.position)
+			return
+		}
+	}
+	d.message = "end of input"
+}
+
+func (d *debugger) setBreakpoints(states []string, enabled bool) {
+	for _, name := range states {
+		state := fsm.State(name)
+		if !d.isState(state) {
+			d.message = fmt.Sprintf("unknown state %q", name)
+			return
+		}
+
+		if enabled {
+			d.breakpoints[state] = true
+		} else {
+			delete(d.breakpoints, state)
+		}
+	}
+}
+
+func (d *debugger) isState(state fsm.State) bool {
+	for _, s := range d.automaton.States {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *debugger) render() {
+	if d.clear {
+		fmt.Fprint(d.out, clearScreen)
+	}
+
+	current := d.runner.CurrentState()
+	var next fsm.Symbol
+	if d.position < len(d.input) {
+		next = d.input[d.position]
+	}
+
+	w := tabwriter.NewWriter(d.out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(w, "  \tstate")
+	for _, symbol := range d.automaton.Alphabet {
+		fmt.Fprintf(w, "\t%s", symbol)
+	}
+	fmt.Fprintln(w)
+
+	for _, state := range d.automaton.States {
+		marker := " "
+		if state == current {
+			marker = ">"
+		}
+		name := string(state)
+		if d.automaton.IsAcceptingState(state) {
+			name += " (accept)"
+		}
+		if d.breakpoints[state] {
+			name += " [break]"
+		}
+
+		fmt.Fprintf(w, "%s\t%s", marker, name)
+		for _, symbol := range d.automaton.Alphabet {
+			target := string(d.automaton.TransitionFunction(state, symbol))
+			if state == current && symbol == next {
+				target = "[" + target + "]"
+			}
+			fmt.Fprintf(w, "\t%s", target)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	fmt.Fprintln(d.out)
+	fmt.Fprintf(d.out, "Input:    %s|%s\n", joinInput(d.input[:d.position]), joinInput(d.input[d.position:]))
+	fmt.Fprintf(d.out, "Position: %d/%d\n", d.position, len(d.input))
+	fmt.Fprintf(d.out, "State:    %s (accepting: %v)\n", current, d.runner.IsAccepting())
+	if len(d.breakpoints) > 0 {
+		fmt.Fprintf(d.out, "Breaks:   %s\n", strings.Join(d.breakpointNames(), ", "))
+	}
+	if d.message != "" {
+		fmt.Fprintln(d.out)
+		fmt.Fprintln(d.out, d.message)
+	}
+	fmt.Fprintln(d.out)
+}
+
+func (d *debugger) breakpointNames() []string {
+	names := make([]string, 0, len(d.breakpoints))
+	for state := range d.breakpoints {
+		names = append(names, string(state))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func joinInput(symbols []fsm.Symbol) string {
+	var sb strings.Builder
+	for _, symbol := range symbols {
+		sb.WriteString(string(symbol))
+	}
+	return sb.String()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fsm-modulo-three/modthree"
+	"strings"
+	"testing"
+)
+
+func newTestDebugger(t *testing.T, input string) (*debugger, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+	d := newDebugger(modthree.NewModThreeFSM().GetAutomaton(), &out)
+	if err := d.load(input); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return d, &out
+}
+
+func TestDebugger_Commands(t *testing.T) {
+	tests := []struct {
+		name             string
+		commands         []string
+		expectedState    string
+		expectedPosition int
+	}{
+		{"single step", []string{"step"}, "S1", 1},
+		{"empty line steps", []string{""}, "S1", 1},
+		{"step count", []string{"s 3"}, "S1", 3},
+		{"step past end", []string{"s 10"}, "S0", 4},
+		{"continue", []string{"c"}, "S0", 4},
+		{"breakpoint", []string{"b S2", "c"}, "S2", 2},
+		{"continue after breakpoint", []string{"b S1", "c", "c"}, "S1", 3},
+		{"delete breakpoint", []string{"b S2", "d S2", "c"}, "S0", 4},
+		{"rollback", []string{"s 3", "r"}, "S2", 2},
+		{"rollback past start", []string{"s 2", "r 5"}, "S0", 0},
+		{"reset", []string{"c", "reset"}, "S0", 0},
+		{"new input", []string{"c", "input 11 10"}, "S0", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, _ := newTestDebugger(t, "1001")
+			for _, command := range test.commands {
+				if d.execute(strings.Fields(command)) {
+					t.Fatalf("Command %q should not quit", command)
+				}
+			}
+
+			if string(d.runner.CurrentState()) != test.expectedState {
+				t.Errorf("Expected state %s, got %s", test.expectedState, d.runner.CurrentState())
+			}
+			if d.position != test.expectedPosition {
+				t.Errorf("Expected position %d, got %d", test.expectedPosition, d.position)
+			}
+		})
+	}
+}
+
+func TestDebugger_Errors(t *testing.T) {
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"bogus", "unknown command"},
+		{"b S9", "unknown state"},
+		{"s x", "invalid count"},
+		{"input 012", "invalid symbol"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.command, func(t *testing.T) {
+			d, _ := newTestDebugger(t, "1001")
+			d.execute(strings.Fields(test.command))
+			if !strings.Contains(d.message, test.expected) {
+				t.Errorf("Expected message containing %q, got %q", test.expected, d.message)
+			}
+		})
+	}
+}
+
+func TestDebugger_Run(t *testing.T) {
+	d, out := newTestDebugger(t, "1001")
+	d.run(strings.NewReader("b S2\nc\nq\n"))
+
+	output := out.String()
+	expected := []string{
+		"Input:    10|01",
+		"Position: 2/4",
+		"State:    S2 (accepting: true)",
+		"Breaks:   S2",
+		"breakpoint hit: S2 at position 2",
+		"> ",
+	}
+	for _, component := range expected {
+		if !strings.Contains(output, component) {
+			t.Errorf("Debugger output should contain %q:\n%s", component, output)
+		}
+	}
+
+	if strings.Contains(output, clearScreen) {
+		t.Error("Debugger should not clear the screen unless enabled")
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmdoc"
+	"fsm-modulo-three/modthree"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func runDoc(args []string) int {
+	flags := flag.NewFlagSet("doc", flag.ContinueOnError)
+	definitionPath := flags.String("def", "", "path to a JSON automaton definition (default: built-in mod-three)")
+	format := flags.String("format", "markdown", "report format: markdown, html or svg")
+	output := flags.String("out", "", "output file (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	name, automaton, err := loadAutomaton(*definitionPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	description := fsmdoc.Describe(name, automaton)
+
+	var report string
+	switch *format {
+	case "markdown", "md":
+		report = description.Markdown()
+	case "html":
+		if report, err = description.HTML(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	case "svg":
+		report = automaton.SVG()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q (expected markdown, html or svg)\n", *format)
+		return 2
+	}
+
+	if *output == "" {
+		fmt.Print(report)
+		return 0
+	}
+
+	if err := os.WriteFile(*output, []byte(report), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func loadAutomaton(definitionPath string) (string, *fsm.FiniteAutomaton, error) {
+	if definitionPath == "" {
+		return "ModThree", modthree.NewModThreeFSM().GetAutomaton(), nil
+	}
+
+	if filepath.Ext(definitionPath) == ".fa" {
+		return loadGrail(definitionPath)
+	}
+
+	definition, err := fsm.LoadDefinition(definitionPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	automaton, err := definition.Automaton()
+	if err != nil {
+		return "", nil, err
+	}
+
+	name := definition.Name
+	if name == "" {
+		name = definitionPath
+	}
+	return name, automaton, nil
+}
+
+func loadGrail(path string) (string, *fsm.FiniteAutomaton, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer file.Close()
+
+	nfa, err := fsm.ReadGrail(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	automaton, err := nfa.AsDFA()
+	if err != nil {
+		automaton = nfa.ToDFA()
+	}
+	return strings.TrimSuffix(filepath.Base(path), ".fa"), automaton, nil
+}
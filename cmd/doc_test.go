@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadAutomaton_Grail(t *testing.T) {
+	dir := t.TempDir()
+	deterministic := filepath.Join(dir, "ends_ba.fa")
+	nondeterministic := filepath.Join(dir, "either.fa")
+	os.WriteFile(deterministic, []byte("(START) |- 0\n0 a 0\n0 b 1\n1 a 2\n2 -| (FINAL)\n"), 0o644)
+	os.WriteFile(nondeterministic, []byte("(START) |- 0\n0 a 1\n0 a 2\n2 b 1\n1 -| (FINAL)\n"), 0o644)
+
+	name, automaton, err := loadAutomaton(deterministic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name != "ends_ba" || automaton.States[0] != "0" {
+		t.Errorf("Expected states to keep their Grail names, got %s %v", name, automaton.States)
+	}
+	if accepted, _ := automaton.Accepts("aba"); !accepted {
+		t.Error("Expected 'aba' to be accepted")
+	}
+
+	_, automaton, err = loadAutomaton(nondeterministic)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if accepted, _ := automaton.Accepts("ab"); !accepted {
+		t.Error("Expected the determinized machine to accept 'ab'")
+	}
+
+	if _, _, err := loadAutomaton(filepath.Join(dir, "missing.fa")); err == nil {
+		t.Error("Expected error for a missing file, but got none")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmgen"
+	"os"
+)
+
+func main() {
+	input := flag.String("in", "", "path to the JSON automaton definition")
+	output := flag.String("out", "", "path of the generated Go file (default stdout)")
+	packageName := flag.String("package", "main", "package name of the generated file")
+	name := flag.String("name", "", "identifier prefix for generated symbols (default: definition name)")
+	flag.Parse()
+
+	if *input == "" {
+		fmt.Fprintln(os.Stderr, "usage: fsmgen -in definition.json [-out file.go] [-package name] [-name Prefix]")
+		os.Exit(2)
+	}
+
+	definition, err := fsm.LoadDefinition(*input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	source, err := fsmgen.Generate(definition, fsmgen.Options{Package: *packageName, Name: *name})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		os.Stdout.Write(source)
+		return
+	}
+
+	if err := os.WriteFile(*output, source, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var errInterrupted = errors.New("interrupted")
+
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyTab       = 9
+	keyNewline   = 10
+	keyReturn    = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyBackspace = 127
+	keyCtrlH     = 8
+)
+
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	raw      bool
+	history  []string
+	commands []string
+}
+
+func newLineEditor(in io.Reader, out io.Writer, raw bool, commands []string) *lineEditor {
+	return &lineEditor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		raw:      raw,
+		commands: commands,
+	}
+}
+
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	fmt.Fprint(e.out, prompt)
+	if !e.raw {
+		return e.readCooked()
+	}
+
+	var line []rune
+	cursor := 0
+	historyIndex := len(e.history)
+	var draft []rune
+
+	redraw := func() {
+		fmt.Fprintf(e.out, "\r\033[K%s%s", prompt, string(line))
+		if back := len(line) - cursor; back > 0 {
+			fmt.Fprintf(e.out, "\033[%dD", back)
+		}
+	}
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch r {
+		case keyReturn, keyNewline:
+			fmt.Fprint(e.out, "\r\n")
+			text := string(line)
+			e.remember(text)
+			return text, nil
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errInterrupted
+		case keyCtrlD:
+			if len(line) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+		case keyBackspace, keyCtrlH:
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+		case keyCtrlA:
+			cursor = 0
+		case keyCtrlE:
+			cursor = len(line)
+		case keyCtrlU:
+			line = line[cursor:]
+			cursor = 0
+		case keyTab:
+			line, cursor = e.complete(prompt, line, cursor)
+		case keyEscape:
+			switch e.readEscape() {
+			case 'A':
+				if historyIndex > 0 {
+					if historyIndex == len(e.history) {
+						draft = line
+					}
+					historyIndex--
+					line = []rune(e.history[historyIndex])
+					cursor = len(line)
+				}
+			case 'B':
+				if historyIndex < len(e.history) {
+					historyIndex++
+					if historyIndex == len(e.history) {
+						line = draft
+					} else {
+						line = []rune(e.history[historyIndex])
+					}
+					cursor = len(line)
+				}
+			case 'C':
+				if cursor < len(line) {
+					cursor++
+				}
+			case 'D':
+				if cursor > 0 {
+					cursor--
+				}
+			}
+		default:
+			if r < 32 {
+				continue
+			}
+			line = append(line[:cursor], append([]rune{r}, line[cursor:]...)...)
+			cursor++
+		}
+
+		redraw()
+	}
+}
+
+func (e *lineEditor) readCooked() (string, error) {
+	text, err := e.in.ReadString('\n')
+	if err != nil && (err != io.EOF || text == "") {
+		return "", err
+	}
+
+	text = strings.TrimRight(text, "\r\n")
+	e.remember(text)
+	return text, nil
+}
+
+func (e *lineEditor) readEscape() rune {
+	r, _, err := e.in.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+
+	r, _, err = e.in.ReadRune()
+	if err != nil {
+		return 0
+	}
+	return r
+}
+
+func (e *lineEditor) remember(text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == text {
+		return
+	}
+	e.history = append(e.history, text)
+}
+
+func (e *lineEditor) complete(prompt string, line []rune, cursor int) ([]rune, int) {
+	prefix := string(line[:cursor])
+	if strings.ContainsRune(prefix, ' ') {
+		return line, cursor
+	}
+
+	matches := completions(e.commands, prefix)
+	switch len(matches) {
+	case 0:
+		return line, cursor
+	case 1:
+		completed := []rune(matches[0] + " ")
+		return append(completed, line[cursor:]...), len(completed)
+	}
+
+	common := []rune(commonPrefix(matches))
+	if len(common) > cursor {
+		return append(common, line[cursor:]...), len(common)
+	}
+
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(matches, "  "))
+	return line, cursor
+}
+
+func completions(commands []string, prefix string) []string {
+	var matches []string
+	for _, command := range commands {
+		if strings.HasPrefix(command, prefix) {
+			matches = append(matches, command)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLineEditor_Raw(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		expected string
+	}{
+		{"plain", "1101\r", "1101"},
+		{"newline", "1101\n", "1101"},
+		{"backspace", "1102\x7f1\r", "1101"},
+		{"ctrl-h", "11x\x08\r", "11"},
+		{"cursor left insert", "111\x1b[D0\r", "1101"},
+		{"cursor right", "11\x1b[D\x1b[C0\r", "110"},
+		{"home and end", "01\x011\x050\r", "1010"},
+		{"kill line", "abc\x15101\r", "101"},
+		{"complete unique", "tr\t110\r", "trace 110"},
+		{"complete common prefix", "t\ta\t\r", "table "},
+		{"complete after space ignored", "x t\t\r", "x t"},
+		{"control characters ignored", "1\x021\r", "11"},
+		{"unicode", "é\r", "é"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			editor := newLineEditor(strings.NewReader(test.keys), &out, true, interactiveCommands)
+
+			line, err := editor.readLine("> ")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if line != test.expected {
+				t.Errorf("Expected line %q, got %q", test.expected, line)
+			}
+		})
+	}
+}
+
+func TestLineEditor_History(t *testing.T) {
+	var out bytes.Buffer
+	keys := "first\rsecond\rsecond\r\x1b[A\x1b[A\rdr\x1b[A\x1b[A\x1b[B\x1b[B\r"
+	editor := newLineEditor(strings.NewReader(keys), &out, true, nil)
+
+	expected := []string{"first", "second", "second", "first", "dr"}
+	for i, want := range expected {
+		line, err := editor.readLine("> ")
+		if err != nil {
+			t.Fatalf("Line %d: unexpected error: %v", i, err)
+		}
+		if line != want {
+			t.Errorf("Line %d: expected %q, got %q", i, want, line)
+		}
+	}
+
+	if len(editor.history) != 4 {
+		t.Errorf("Expected consecutive duplicates to be collapsed into 4 history entries, got %v", editor.history)
+	}
+}
+
+func TestLineEditor_Interrupts(t *testing.T) {
+	editor := newLineEditor(strings.NewReader("11\x03\x04"), &bytes.Buffer{}, true, nil)
+
+	if _, err := editor.readLine("> "); !errors.Is(err, errInterrupted) {
+		t.Errorf("Expected errInterrupted for Ctrl-C, got %v", err)
+	}
+	if _, err := editor.readLine("> "); err != io.EOF {
+		t.Errorf("Expected io.EOF for Ctrl-D on an empty line, got %v", err)
+	}
+}
+
+func TestLineEditor_Cooked(t *testing.T) {
+	editor := newLineEditor(strings.NewReader("1101\r\nlast"), &bytes.Buffer{}, false, nil)
+
+	for _, expected := range []string{"1101", "last"} {
+		line, err := editor.readLine("> ")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if line != expected {
+			t.Errorf("Expected line %q, got %q", expected, line)
+		}
+	}
+
+	if _, err := editor.readLine("> "); err != io.EOF {
+		t.Errorf("Expected io.EOF at end of input, got %v", err)
+	}
+}
+
+func TestCompletions(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"t", []string{"table", "trace"}},
+		{":d", []string{":define", ":dot"}},
+		{"tr", []string{"trace"}},
+		{"z", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.prefix, func(t *testing.T) {
+			matches := completions(interactiveCommands, test.prefix)
+			if strings.Join(matches, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("Expected %v, got %v", test.expected, matches)
+			}
+		})
+	}
+}
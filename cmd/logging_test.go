@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	tests := []struct {
+		level    string
+		format   string
+		expected string
+	}{
+		{"debug", "text", "level=DEBUG msg=hello"},
+		{"info", "", "level=INFO msg=world"},
+		{"warn", "json", ""},
+		{"DEBUG", "JSON", `"msg":"hello"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.level+"_"+test.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := newLogger(&buf, test.level, test.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			logger.Debug("hello")
+			logger.Info("world")
+			if test.expected == "" {
+				if buf.Len() != 0 {
+					t.Errorf("Expected no output, got %q", buf.String())
+				}
+				return
+			}
+			if !strings.Contains(buf.String(), test.expected) {
+				t.Errorf("Expected output containing %q, got %q", test.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestNewLogger_Disabled(t *testing.T) {
+	for _, level := range []string{"", "off"} {
+		logger, err := newLogger(&bytes.Buffer{}, level, "text")
+		if err != nil || logger != nil {
+			t.Errorf("Expected no logger for level %q, got %v, %v", level, logger, err)
+		}
+	}
+}
+
+func TestNewLogger_Errors(t *testing.T) {
+	if _, err := newLogger(&bytes.Buffer{}, "loud", "text"); err == nil {
+		t.Error("Expected error for an invalid level, but got none")
+	}
+	if _, err := newLogger(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Error("Expected error for an invalid format, but got none")
+	}
+}
@@ -1,14 +1,145 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"fsm-modulo-three/modthree"
+	"fsm-modulo-three/modulo"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "doc":
+			os.Exit(runDoc(os.Args[2:]))
+		case "animate":
+			os.Exit(runAnimate(os.Args[2:]))
+		case "debug":
+			os.Exit(runDebug(os.Args[2:]))
+		case "completion":
+			os.Exit(runCompletion(os.Args[2:]))
+		case "version":
+			os.Exit(runVersion(os.Args[2:]))
+		case "selftest":
+			os.Exit(runSelftest(os.Args[2:]))
+		case "serve":
+			os.Exit(runServe(os.Args[2:]))
+		case "verify":
+			os.Exit(runVerify(os.Args[2:]))
+		case "match":
+			os.Exit(runMatch(os.Args[2:]))
+		case "stats":
+			os.Exit(runStats(os.Args[2:]))
+		}
+	}
+
+	os.Exit(runMain(os.Args[1:]))
+}
+
+func runMain(args []string) (status int) {
+	defaults := defaultConfig()
+	flags := flag.NewFlagSet("fsm-demo", flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file (default $FSM_CONFIG or ~/"+configFileName+")")
+	batch := flags.Bool("q", false, "batch mode: read one input per stdin line and print tab-separated results (default when stdin is not a terminal)")
+	modulus := flags.Int("mod", defaults.Mod, "modulus to compute remainders for")
+	base := flags.Int("base", defaults.Base, "base of the input numbers (2-36)")
+	trace := flags.Bool("trace", false, "print the state transitions taken for each input")
+	quiet := flags.Bool("quiet", false, "print only the remainder for each input")
+	color := flags.Bool("color", defaults.Color, "colorize interactive output")
+	cpuProfile := flags.String("cpuprofile", "", "write a CPU profile to this file")
+	memProfile := flags.String("memprofile", "", "write a heap profile to this file on exit")
+	logLevel := flags.String("log-level", "off", "log to stderr at this level: debug, info, warn, error or off")
+	logFormat := flags.String("log-format", "text", "log format: text or json")
+	watch := flags.String("watch", "", "re-process this file of inputs whenever it changes and print what changed")
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+
+	cfg, err := loadConfig(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: loading config: %v\n", err)
+		return exitInternal
+	}
+
+	explicit := explicitFlags(flags)
+	if !explicit["mod"] {
+		*modulus = cfg.Mod
+	}
+	if !explicit["base"] {
+		*base = cfg.Base
+	}
+	if !explicit["color"] {
+		*color = cfg.Color
+	}
+
+	mode := cfg.Output
+	switch {
+	case *trace && *quiet:
+		fmt.Fprintln(os.Stderr, "Error: -trace and -quiet cannot be combined")
+		return exitInternal
+	case *trace:
+		mode = outputTrace
+	case *quiet:
+		mode = outputQuiet
+	}
+
+	machine, err := modulo.NewModFSM(*modulus, *base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+	machine.GetAutomaton().Logger = logger
+
+	stopProfiling, err := startProfiling(*cpuProfile, *memProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+	defer func() {
+		if err := stopProfiling(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			status = exitInternal
+		}
+	}()
+
+	if *watch != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return runWatch(ctx, &watcher{path: *watch, machine: machine, mode: mode, out: os.Stdout, errOut: os.Stderr}, watchInterval)
+	}
+
+	if flags.NArg() > 0 {
+		return runArgs(flags.Args(), os.Stdout, os.Stderr, machine, mode)
+	}
+
+	if *batch || !stdinIsTerminal() {
+		return runBatch(os.Stdin, os.Stdout, os.Stderr, machine, mode)
+	}
+
+	if *modulus == 3 && *base == 2 {
+		machine = nil
+	}
+	runInteractive(machine, *color)
+	return exitOK
+}
+
+func runInteractive(machine *modulo.ModFSM, color bool) {
+	if machine != nil {
+		runInteractiveModN(machine, color)
+		return
+	}
+
 	fmt.Println("=== Finite State Machine Modulo Three Implementation ===")
 	fmt.Println("This program demonstrates the FSM library and mod-three functionality.")
 	fmt.Println()
@@ -36,34 +167,49 @@ func main() {
 
 	fmt.Println("=== Interactive Mode ===")
 	fmt.Println("Enter binary strings to compute their modulo three remainder.")
-	fmt.Println("Enter 'quit' to exit.")
+	fmt.Println("Commands: 'trace <binary>' shows each transition, 'table' prints the transition table,")
+	fmt.Println("':use mod5' or ':load file.json' switches automata, ':help' lists everything.")
+	fmt.Println("Enter 'quit' to exit. Use Tab to complete commands and the arrow keys for history.")
 	fmt.Println()
 
-	scanner := bufio.NewScanner(os.Stdin)
-	for {
-		fmt.Print("Enter binary string: ")
-		if !scanner.Scan() {
-			break
-		}
+	runPrompt(nil, color)
+}
 
-		input := strings.TrimSpace(scanner.Text())
-		if input == "quit" || input == "exit" {
-			break
-		}
+func runInteractiveModN(machine *modulo.ModFSM, color bool) {
+	fmt.Printf("=== Mod %d (base %d) ===\n", machine.Modulus(), machine.Base())
+	fmt.Println(machine.String())
+	fmt.Println()
+	fmt.Printf("Enter base-%d numbers to compute their remainder modulo %d.\n", machine.Base(), machine.Modulus())
+	fmt.Println("Type ':help' for commands and 'quit' to exit.")
+	fmt.Println()
+
+	runPrompt(machine, color)
+}
 
-		if input == "" {
+func runPrompt(machine *modulo.ModFSM, color bool) {
+	editor := newLineEditor(os.Stdin, os.Stdout, false, interactiveCommands)
+	if restore, err := enableRawMode(os.Stdin.Fd()); err == nil {
+		defer restore()
+		editor.raw = true
+	}
+
+	session := newSession(editor.out, editor.readLine)
+	session.color = color
+	if machine != nil {
+		session.useModN(machine)
+	}
+	for {
+		line, err := editor.readLine(session.prompt())
+		if errors.Is(err, errInterrupted) {
 			continue
 		}
-
-		result, err := fsm.ModThree(input)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			continue
+			break
 		}
 
-		fmt.Printf("Result: %s (decimal: %d) %% 3 = %d (Final State: %s)\n",
-			result.Input, result.DecimalValue, result.Remainder, result.FinalState)
-		fmt.Println()
+		if quit := session.handle(line); quit {
+			break
+		}
 	}
 
 	fmt.Println("Goodbye!")
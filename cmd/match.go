@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"os"
+)
+
+type matchOptions struct {
+	invert      bool
+	count       bool
+	lineNumbers bool
+	prefix      string
+}
+
+func runMatch(args []string) int {
+	flags := flag.NewFlagSet("match", flag.ContinueOnError)
+	invert := flags.Bool("v", false, "select lines the automaton rejects")
+	count := flags.Bool("c", false, "print only the number of selected lines")
+	lineNumbers := flags.Bool("n", false, "prefix each line with its line number")
+	wholeLine := flags.Bool("x", false, "match whole lines only instead of any substring")
+	ignoreCase := flags.Bool("i", false, "ignore ASCII case distinctions")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s match [flags] PATTERN [FILE...]\n", programName)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+	if flags.NArg() < 1 {
+		flags.Usage()
+		return exitInternal
+	}
+
+	automaton, err := compileMatcher(flags.Arg(0), *wholeLine, *ignoreCase)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	files := flags.Args()[1:]
+	options := matchOptions{invert: *invert, count: *count, lineNumbers: *lineNumbers}
+	if len(files) == 0 {
+		return matchStatus(matchLines(os.Stdin, os.Stdout, automaton, options))
+	}
+
+	status, selected := exitOK, 0
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			status = exitInternal
+			continue
+		}
+
+		if len(files) > 1 {
+			options.prefix = path + ":"
+		}
+		n, err := matchLines(file, os.Stdout, automaton, options)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			status = exitInternal
+		}
+		selected += n
+	}
+
+	if status != exitOK {
+		return status
+	}
+	return matchStatus(selected, nil)
+}
+
+func compileMatcher(pattern string, wholeLine, ignoreCase bool) (*fsm.FiniteAutomaton, error) {
+	var options []fsm.CompileOption
+	if ignoreCase {
+		options = append(options, fsm.FoldCase())
+	}
+
+	// Wildcard stands for every non-ASCII rune, so `.` and negated classes
+	// match them instead of the line failing to scan.
+	matcher, err := fsm.NewMatcher(pattern, append(fsm.ASCIIAlphabet(), fsm.Wildcard), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if wholeLine {
+		return matcher.DFA(fsm.MatchFull)
+	}
+	return matcher.DFA(fsm.MatchSubstring)
+}
+
+func matchLines(in io.Reader, out io.Writer, automaton *fsm.FiniteAutomaton, options matchOptions) (int, error) {
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	selected := 0
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		accepted, err := automaton.Accepts(text)
+		if err != nil {
+			return selected, fmt.Errorf("line %d: %w", line, err)
+		}
+		if accepted == options.invert {
+			continue
+		}
+
+		selected++
+		if options.count {
+			continue
+		}
+		fmt.Fprint(w, options.prefix)
+		if options.lineNumbers {
+			fmt.Fprintf(w, "%d:", line)
+		}
+		fmt.Fprintln(w, text)
+	}
+
+	if options.count {
+		fmt.Fprintf(w, "%s%d\n", options.prefix, selected)
+	}
+	if err := scanner.Err(); err != nil {
+		return selected, err
+	}
+	return selected, w.Flush()
+}
+
+func matchStatus(selected int, err error) int {
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	case selected == 0:
+		return exitInvalid
+	}
+	return exitOK
+}
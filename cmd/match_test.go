@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var errTestRead = errors.New("broken pipe")
+
+func TestMatchLines(t *testing.T) {
+	input := "hello world\nfoo bar\nfoobar\nbaz\ncafé foo\n"
+
+	tests := []struct {
+		name             string
+		pattern          string
+		wholeLine        bool
+		ignoreCase       bool
+		options          matchOptions
+		expectedOutput   string
+		expectedSelected int
+	}{
+		{"substring", "fo+", false, false, matchOptions{}, "foo bar\nfoobar\ncafé foo\n", 3},
+		{"whole line", "fo+bar", true, false, matchOptions{}, "foobar\n", 1},
+		{"invert", "o", false, false, matchOptions{invert: true}, "baz\n", 1},
+		{"count", "ba[rz]", false, false, matchOptions{count: true}, "3\n", 3},
+		{"line numbers", "world|baz", false, false, matchOptions{lineNumbers: true}, "1:hello world\n4:baz\n", 2},
+		{"prefix", "baz", false, false, matchOptions{prefix: "in.txt:"}, "in.txt:baz\n", 1},
+		{"no match", "qux", false, false, matchOptions{}, "", 0},
+		{"ignore case", "HELLO|BAZ", false, true, matchOptions{}, "hello world\nbaz\n", 2},
+		{"utf-8 dot", "caf. foo", true, false, matchOptions{}, "café foo\n", 1},
+		{"utf-8 negated class", "f[^a-z]", false, false, matchOptions{}, "café foo\n", 1},
+		{"utf-8 word class", `caf\w`, false, false, matchOptions{}, "", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			automaton, err := compileMatcher(test.pattern, test.wholeLine, test.ignoreCase)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var out bytes.Buffer
+			selected, err := matchLines(strings.NewReader(input), &out, automaton, test.options)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if out.String() != test.expectedOutput {
+				t.Errorf("Expected output %q, got %q", test.expectedOutput, out.String())
+			}
+			if selected != test.expectedSelected {
+				t.Errorf("Expected %d selected lines, got %d", test.expectedSelected, selected)
+			}
+		})
+	}
+}
+
+func TestCompileMatcher_Errors(t *testing.T) {
+	for _, pattern := range []string{"(", "a)", "*"} {
+		if _, err := compileMatcher(pattern, false, false); err == nil {
+			t.Errorf("Expected error for pattern %q, but got none", pattern)
+		}
+	}
+}
+
+func TestMatchLines_ReadError(t *testing.T) {
+	automaton, _ := compileMatcher("a", false, false)
+	if _, err := matchLines(iotest.ErrReader(errTestRead), &bytes.Buffer{}, automaton, matchOptions{}); err == nil {
+		t.Error("Expected read error, but got none")
+	}
+}
+
+func TestMatchStatus(t *testing.T) {
+	tests := []struct {
+		selected int
+		err      error
+		expected int
+	}{
+		{2, nil, exitOK},
+		{0, nil, exitInvalid},
+		{1, errTestRead, exitInternal},
+	}
+
+	for _, test := range tests {
+		if status := matchStatus(test.selected, test.err); status != test.expected {
+			t.Errorf("Expected status %d for %d selected (err %v), got %d", test.expected, test.selected, test.err, status)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+func startProfiling(cpuProfile, memProfile string) (func() error, error) {
+	var cpuFile *os.File
+	if cpuProfile != "" {
+		file, err := os.Create(cpuProfile)
+		if err != nil {
+			return nil, fmt.Errorf("creating CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("starting CPU profile: %w", err)
+		}
+		cpuFile = file
+	}
+
+	stop := func() error {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return fmt.Errorf("writing CPU profile: %w", err)
+			}
+		}
+
+		if memProfile == "" {
+			return nil
+		}
+
+		file, err := os.Create(memProfile)
+		if err != nil {
+			return fmt.Errorf("creating memory profile: %w", err)
+		}
+		defer file.Close()
+
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			return fmt.Errorf("writing memory profile: %w", err)
+		}
+		return file.Close()
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStartProfiling(t *testing.T) {
+	dir := t.TempDir()
+	cpuProfile := filepath.Join(dir, "cpu.pprof")
+	memProfile := filepath.Join(dir, "mem.pprof")
+
+	stop, err := startProfiling(cpuProfile, memProfile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	runArgs([]string{strings.Repeat("10", 1000)}, &strings.Builder{}, &strings.Builder{}, newModThree(t), outputDefault)
+
+	if err := stop(); err != nil {
+		t.Fatalf("Unexpected error stopping profiles: %v", err)
+	}
+
+	for _, path := range []string{cpuProfile, memProfile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Errorf("Expected profile %s to exist: %v", path, err)
+			continue
+		}
+		if info.Size() == 0 {
+			t.Errorf("Expected profile %s to be non-empty", path)
+		}
+	}
+}
+
+func TestStartProfiling_Disabled(t *testing.T) {
+	stop, err := startProfiling("", "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestStartProfiling_BadPath(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing", "cpu.pprof")
+	if _, err := startProfiling(missing, ""); err == nil {
+		t.Error("Expected error for an unwritable CPU profile path, but got none")
+	}
+
+	stop, _ := startProfiling("", filepath.Join(t.TempDir(), "missing", "mem.pprof"))
+	if err := stop(); err == nil {
+		t.Error("Expected error for an unwritable memory profile path, but got none")
+	}
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"fsm-modulo-three/catalog"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modthree"
+	"fsm-modulo-three/modulo"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var interactiveCommands = []string{
+	"quit", "exit", "trace", "table",
+	":load", ":define", ":use", ":table", ":dot", ":help",
+}
+
+var builtinMachines = map[string]func() *fsm.FiniteAutomaton{
+	"even-ones":           catalog.EvenOnes,
+	"odd-parity":          catalog.OddParity,
+	"even-length":         catalog.EvenLength,
+	"ends-with-01":        catalog.EndsWith01,
+	"no-consecutive-ones": catalog.NoConsecutiveOnes,
+	"luhn":                catalog.Luhn,
+	"roman-numerals":      catalog.RomanNumerals,
+}
+
+// maxUseModulus bounds :use modN. Building the machine takes time and memory
+// proportional to N, so a typo must not stall the session.
+const maxUseModulus = 1 << 16
+
+const replHelp = `Enter an input string to run it through the current automaton.
+  trace INPUT      show each transition taken for INPUT
+  table, :table    print the transition table
+  :dot             print the automaton in Graphviz DOT format
+  :load FILE       load a JSON automaton definition
+  :define [JSON]   define an automaton inline (multi-line JSON ends with an empty line)
+  :use NAME        switch to a built-in machine: mod3, modN, ` + "%s" + `
+  quit, exit       leave interactive mode`
+
+type session struct {
+	name      string
+	automaton *fsm.FiniteAutomaton
+	modThree  *modthree.ModThreeFSM
+	modN      *modulo.ModFSM
+	out       io.Writer
+	readLine  func(prompt string) (string, error)
+	color     bool
+}
+
+func newSession(out io.Writer, readLine func(prompt string) (string, error)) *session {
+	s := &session{out: out, readLine: readLine}
+	s.useModThree()
+	return s
+}
+
+func (s *session) useModThree() {
+	s.modThree = modthree.NewModThreeFSM()
+	s.modN = nil
+	s.name = "mod3"
+	s.automaton = s.modThree.GetAutomaton()
+}
+
+func (s *session) useModN(machine *modulo.ModFSM) {
+	s.modThree = nil
+	s.modN = machine
+	s.name = fmt.Sprintf("mod%d", machine.Modulus())
+	if machine.Base() != 2 {
+		s.name += fmt.Sprintf("-base%d", machine.Base())
+	}
+	s.automaton = machine.GetAutomaton()
+}
+
+func (s *session) use(name string, automaton *fsm.FiniteAutomaton) {
+	s.modThree = nil
+	s.modN = nil
+	s.name = name
+	s.automaton = automaton
+	fmt.Fprintf(s.out, "Using %s (%d states, alphabet %v)\n", name, len(automaton.States), automaton.Alphabet)
+}
+
+func (s *session) prompt() string {
+	if s.modThree != nil {
+		return "Enter binary string: "
+	}
+	return s.name + "> "
+}
+
+func (s *session) handle(line string) bool {
+	input := strings.TrimSpace(line)
+	if input == "quit" || input == "exit" {
+		return true
+	}
+
+	if input == "" {
+		return false
+	}
+
+	if strings.HasPrefix(input, ":") {
+		if err := s.command(input); err != nil {
+			s.printError(err)
+		}
+		return false
+	}
+
+	if input == "table" {
+		writeTable(s.out, s.automaton)
+		return false
+	}
+
+	if rest, ok := strings.CutPrefix(input, "trace"); ok && (rest == "" || rest[0] == ' ') {
+		if err := writeTrace(s.out, s.automaton, strings.TrimSpace(rest)); err != nil {
+			s.printError(err)
+		}
+		return false
+	}
+
+	s.evaluate(input)
+	return false
+}
+
+func (s *session) evaluate(input string) {
+	if s.modThree != nil {
+		result, err := s.modThree.ModThree(input)
+		if err != nil {
+			s.printError(err)
+			return
+		}
+
+		fmt.Fprintf(s.out, "Result: %s (decimal: %d) %% 3 = %d (Final State: %s)\n",
+			result.Input, result.DecimalValue, result.Remainder, result.FinalState)
+		fmt.Fprintln(s.out)
+		return
+	}
+
+	if s.modN != nil {
+		result, err := s.modN.Mod(input)
+		if err != nil {
+			s.printError(err)
+			return
+		}
+
+		fmt.Fprintf(s.out, "Result: %s %% %d = %d (Final State: %s)\n",
+			result.Input, s.modN.Modulus(), result.Remainder, result.FinalState)
+		return
+	}
+
+	finalState, err := s.automaton.ProcessInput(input)
+	if err != nil {
+		s.printError(err)
+		return
+	}
+
+	verdict := "rejected"
+	if s.automaton.IsAcceptingState(finalState) {
+		verdict = "accepted"
+	}
+	fmt.Fprintf(s.out, "Result: %s %s (Final State: %s)\n", input, verdict, finalState)
+}
+
+func (s *session) printError(err error) {
+	if s.color {
+		fmt.Fprintf(s.out, "\033[31mError: %v\033[0m\n", err)
+		return
+	}
+	fmt.Fprintf(s.out, "Error: %v\n", err)
+}
+
+func (s *session) command(input string) error {
+	command, args, _ := strings.Cut(input, " ")
+	args = strings.TrimSpace(args)
+
+	switch command {
+	case ":help":
+		fmt.Fprintf(s.out, replHelp+"\n", strings.Join(builtinNames(), ", "))
+	case ":table":
+		writeTable(s.out, s.automaton)
+	case ":dot":
+		fmt.Fprint(s.out, s.automaton.DOT())
+	case ":load":
+		if args == "" {
+			return fmt.Errorf("usage: :load FILE")
+		}
+		name, automaton, err := loadAutomaton(args)
+		if err != nil {
+			return err
+		}
+		s.use(name, automaton)
+	case ":define":
+		return s.define(args)
+	case ":use":
+		return s.useBuiltin(args)
+	default:
+		return fmt.Errorf("unknown command %q (type :help for a list)", command)
+	}
+
+	return nil
+}
+
+func (s *session) define(source string) error {
+	if source == "" {
+		fmt.Fprintln(s.out, "Enter a JSON definition; finish with an empty line.")
+
+		var lines []string
+		for {
+			line, err := s.readLine("... ")
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(line) == "" {
+				break
+			}
+			lines = append(lines, line)
+		}
+		source = strings.Join(lines, "\n")
+	}
+
+	definition, err := fsm.ReadDefinition(strings.NewReader(source))
+	if err != nil {
+		return err
+	}
+
+	automaton, err := definition.Automaton()
+	if err != nil {
+		return err
+	}
+
+	name := definition.Name
+	if name == "" {
+		name = "defined"
+	}
+	s.use(name, automaton)
+	return nil
+}
+
+func (s *session) useBuiltin(name string) error {
+	if name == "mod3" {
+		s.useModThree()
+		fmt.Fprintln(s.out, "Using mod3 (built-in mod-three machine)")
+		return nil
+	}
+
+	if constructor, ok := builtinMachines[name]; ok {
+		s.use(name, constructor())
+		return nil
+	}
+
+	if digits, ok := strings.CutPrefix(name, "mod"); ok {
+		k, err := strconv.Atoi(digits)
+		if err != nil {
+			return fmt.Errorf("invalid modulus in %q", name)
+		}
+		if k > maxUseModulus {
+			return fmt.Errorf("modulus %d is too large: :use supports up to mod%d", k, maxUseModulus)
+		}
+		machine, err := modulo.NewModFSM(k, 2)
+		if err != nil {
+			return err
+		}
+		s.useModN(machine)
+		fmt.Fprintf(s.out, "Using %s (remainders modulo %d)\n", s.name, k)
+		return nil
+	}
+
+	return fmt.Errorf("unknown machine %q (available: mod3, modN, %s)", name, strings.Join(builtinNames(), ", "))
+}
+
+func builtinNames() []string {
+	names := make([]string, 0, len(builtinMachines))
+	for name := range builtinMachines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const singleStateDefinition = `{"name":"all-a","states":["A"],"alphabet":["a"],"initial":"A","accepting":["A"],"transitions":[{"from":"A","symbol":"a","to":"A"}]}`
+
+func newTestSession(lines ...string) (*session, *bytes.Buffer) {
+	var out bytes.Buffer
+	readLine := func(prompt string) (string, error) {
+		if len(lines) == 0 {
+			return "", fmt.Errorf("no more input")
+		}
+		line := lines[0]
+		lines = lines[1:]
+		return line, nil
+	}
+	return newSession(&out, readLine), &out
+}
+
+func TestSession_Evaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{"mod-three default", []string{"1101"}, "Result: 1101 (decimal: 13) % 3 = 1 (Final State: S1)"},
+		{"use modN", []string{":use mod5", "1010"}, "Result: 1010 % 5 = 0 (Final State: S0)"},
+		{"use modN remainder", []string{":use mod5", "111"}, "Result: 111 % 5 = 2 (Final State: S2)"},
+		{"use catalog", []string{":use even-ones", "11"}, "Result: 11 accepted"},
+		{"back to mod3", []string{":use mod5", ":use mod3", "11"}, "% 3 = 0"},
+		{"define inline", []string{":define " + singleStateDefinition, "aa"}, "Result: aa accepted (Final State: A)"},
+		{"trace", []string{":use even-ones", "trace 1"}, "1: Even --1--> Odd"},
+		{"table", []string{":table"}, "-> S0 *"},
+		{"dot", []string{":dot"}, "digraph FiniteAutomaton {"},
+		{"help", []string{":help"}, ":use NAME"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, out := newTestSession()
+			for _, line := range test.lines {
+				if s.handle(line) {
+					t.Fatalf("Line %q should not quit", line)
+				}
+			}
+
+			if !strings.Contains(out.String(), test.expected) {
+				t.Errorf("Expected output containing %q, got:\n%s", test.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestSession_DefineMultiline(t *testing.T) {
+	s, out := newTestSession(`{"name": "multi",`, `"states":["A"],"alphabet":["a"],"initial":"A",`, `"accepting":[],"transitions":[]}`, "")
+
+	s.handle(":define")
+	if s.name != "multi" {
+		t.Fatalf("Expected automaton 'multi' to be active, got %q:\n%s", s.name, out.String())
+	}
+	if s.prompt() != "multi> " {
+		t.Errorf("Expected prompt 'multi> ', got %q", s.prompt())
+	}
+
+	s.handle("a")
+	if !strings.Contains(out.String(), "Result: a rejected") {
+		t.Errorf("Expected input to be rejected:\n%s", out.String())
+	}
+}
+
+func TestSession_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "machine.json")
+	if err := os.WriteFile(path, []byte(singleStateDefinition), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, out := newTestSession()
+	s.handle(":load " + path)
+	if s.name != "all-a" || s.modThree != nil {
+		t.Errorf("Expected loaded automaton to be active:\n%s", out.String())
+	}
+}
+
+func TestSession_Errors(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{":bogus", "unknown command"},
+		{":load", "usage: :load FILE"},
+		{":load /nonexistent.json", "Error:"},
+		{":use nope", "unknown machine"},
+		{":use modx", "invalid modulus"},
+		{":use mod0", "modulus must be positive"},
+		{":use mod65537", "too large: :use supports up to mod65536"},
+		{":use mod99999999999999", "too large"},
+		{":define {", "Error:"},
+		{"12", "Error:"},
+		{"trace", "usage: trace"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			s, out := newTestSession()
+			s.handle(test.line)
+			if !strings.Contains(out.String(), test.expected) {
+				t.Errorf("Expected output containing %q, got:\n%s", test.expected, out.String())
+			}
+			if s.name != "mod3" {
+				t.Errorf("Failed command should keep mod3 active, got %q", s.name)
+			}
+		})
+	}
+}
+
+func TestSession_Quit(t *testing.T) {
+	s, _ := newTestSession()
+	for _, line := range []string{"quit", " exit "} {
+		if !s.handle(line) {
+			t.Errorf("Expected %q to quit", line)
+		}
+	}
+}
+
+func TestSession_UseModN(t *testing.T) {
+	machine, _ := modulo.NewModFSM(7, 10)
+
+	s, out := newTestSession()
+	s.useModN(machine)
+	if s.prompt() != "mod7-base10> " {
+		t.Errorf("Expected prompt 'mod7-base10> ', got %q", s.prompt())
+	}
+
+	s.handle("100")
+	if !strings.Contains(out.String(), "Result: 100 % 7 = 2 (Final State: S2)") {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+}
+
+func TestSession_ColorErrors(t *testing.T) {
+	s, out := newTestSession()
+	s.color = true
+	s.handle(":bogus")
+
+	if !strings.HasPrefix(out.String(), "\033[31mError:") {
+		t.Errorf("Expected colored error, got %q", out.String())
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	selftestAutoBudget = 1 << 20
+	selftestMaxInputs  = 1 << 26
+	selftestMaxReports = 10
+)
+
+type selftestReport struct {
+	Modulus  int
+	Base     int
+	MaxLen   int
+	Checked  int
+	Failures []string
+	Elapsed  time.Duration
+}
+
+func (r *selftestReport) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+func runSelftest(args []string) int {
+	defaults := defaultConfig()
+	flags := flag.NewFlagSet("selftest", flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file (default $FSM_CONFIG or ~/"+configFileName+")")
+	modulus := flags.Int("mod", defaults.Mod, "modulus of the machine to verify")
+	base := flags.Int("base", defaults.Base, "base of the machine to verify (2-36)")
+	maxLen := flags.Int("max-len", 0, "verify every input up to this length (default: as long as ~1M inputs allow)")
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+
+	cfg, err := loadConfig(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: loading config: %v\n", err)
+		return exitInternal
+	}
+
+	explicit := explicitFlags(flags)
+	if !explicit["mod"] {
+		*modulus = cfg.Mod
+	}
+	if !explicit["base"] {
+		*base = cfg.Base
+	}
+
+	machine, err := modulo.NewModFSM(*modulus, *base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	report, err := selftest(machine, *maxLen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	writeSelftestReport(os.Stdout, report)
+	if !report.Passed() {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+func selftestLength(base, maxLen int) (int, error) {
+	total, count := 0, 1
+	for length := 1; ; length++ {
+		count *= base
+		total += count
+
+		if maxLen > 0 {
+			if total > selftestMaxInputs {
+				return 0, fmt.Errorf("length %d needs more than %d inputs in base %d; choose a smaller -max-len", maxLen, selftestMaxInputs, base)
+			}
+			if length == maxLen {
+				return maxLen, nil
+			}
+		} else if total > selftestAutoBudget {
+			return max(length-1, 1), nil
+		}
+	}
+}
+
+func selftest(machine *modulo.ModFSM, maxLen int) (*selftestReport, error) {
+	length, err := selftestLength(machine.Base(), maxLen)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &selftestReport{Modulus: machine.Modulus(), Base: machine.Base(), MaxLen: length}
+	start := time.Now()
+	modulus := big.NewInt(int64(machine.Modulus()))
+	value := new(big.Int)
+
+	for n := 1; n <= length; n++ {
+		digits := make([]int, n)
+		input := make([]byte, n)
+		for {
+			for i, digit := range digits {
+				input[i] = strconv.FormatInt(int64(digit), machine.Base())[0]
+			}
+
+			report.Checked++
+			value.SetString(string(input), machine.Base())
+			expected := int(new(big.Int).Mod(value, modulus).Int64())
+
+			result, err := machine.Mod(string(input))
+			switch {
+			case err != nil:
+				report.Failures = append(report.Failures, fmt.Sprintf("%s: %v", input, err))
+			case result.Remainder != expected:
+				report.Failures = append(report.Failures, fmt.Sprintf("%s: expected remainder %d, got %d", input, expected, result.Remainder))
+			}
+
+			if !increment(digits, machine.Base()) {
+				break
+			}
+		}
+	}
+
+	report.Elapsed = time.Since(start)
+	return report, nil
+}
+
+func increment(digits []int, base int) bool {
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i]++
+		if digits[i] < base {
+			return true
+		}
+		digits[i] = 0
+	}
+	return false
+}
+
+func writeSelftestReport(w io.Writer, report *selftestReport) {
+	fmt.Fprintf(w, "Self-test: mod %d, base %d\n", report.Modulus, report.Base)
+	fmt.Fprintf(w, "Checked %d inputs of length 1-%d in %s\n", report.Checked, report.MaxLen, report.Elapsed.Round(time.Millisecond))
+
+	if report.Passed() {
+		fmt.Fprintln(w, "PASS")
+		return
+	}
+
+	fmt.Fprintf(w, "FAIL: %d mismatches\n", len(report.Failures))
+	for _, failure := range report.Failures[:min(len(report.Failures), selftestMaxReports)] {
+		fmt.Fprintf(w, "  %s\n", failure)
+	}
+	if hidden := len(report.Failures) - selftestMaxReports; hidden > 0 {
+		fmt.Fprintf(w, "  ... and %d more\n", hidden)
+	}
+}
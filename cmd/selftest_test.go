@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"strings"
+	"testing"
+)
+
+func TestSelftestLength(t *testing.T) {
+	tests := []struct {
+		base     int
+		maxLen   int
+		expected int
+	}{
+		{2, 5, 5},
+		{10, 3, 3},
+		{2, 0, 19},
+		{10, 0, 5},
+		{36, 0, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("base%d_len%d", test.base, test.maxLen), func(t *testing.T) {
+			length, err := selftestLength(test.base, test.maxLen)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if length != test.expected {
+				t.Errorf("Expected length %d, got %d", test.expected, length)
+			}
+		})
+	}
+
+	if _, err := selftestLength(10, 12); err == nil {
+		t.Error("Expected error for an infeasible length, but got none")
+	}
+}
+
+func TestSelftest(t *testing.T) {
+	tests := []struct {
+		modulus         int
+		base            int
+		maxLen          int
+		expectedChecked int
+	}{
+		{3, 2, 4, 2 + 4 + 8 + 16},
+		{5, 2, 8, 510},
+		{7, 10, 3, 1110},
+		{1, 16, 2, 16 + 256},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("mod%d_base%d", test.modulus, test.base), func(t *testing.T) {
+			machine, _ := modulo.NewModFSM(test.modulus, test.base)
+			report, err := selftest(machine, test.maxLen)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !report.Passed() {
+				t.Errorf("Expected self-test to pass, got failures: %v", report.Failures)
+			}
+			if report.Checked != test.expectedChecked {
+				t.Errorf("Expected %d inputs checked, got %d", test.expectedChecked, report.Checked)
+			}
+		})
+	}
+}
+
+func TestWriteSelftestReport(t *testing.T) {
+	var passed bytes.Buffer
+	writeSelftestReport(&passed, &selftestReport{Modulus: 3, Base: 2, MaxLen: 4, Checked: 30})
+	if !strings.Contains(passed.String(), "Checked 30 inputs of length 1-4") || !strings.HasSuffix(passed.String(), "PASS\n") {
+		t.Errorf("Unexpected passing report:\n%s", passed.String())
+	}
+
+	failures := make([]string, 12)
+	for i := range failures {
+		failures[i] = fmt.Sprintf("input%d: mismatch", i)
+	}
+
+	var failed bytes.Buffer
+	writeSelftestReport(&failed, &selftestReport{Modulus: 3, Base: 2, MaxLen: 4, Checked: 30, Failures: failures})
+	expected := []string{"FAIL: 12 mismatches", "input0: mismatch", "input9: mismatch", "... and 2 more"}
+	for _, component := range expected {
+		if !strings.Contains(failed.String(), component) {
+			t.Errorf("Failing report should contain %q:\n%s", component, failed.String())
+		}
+	}
+	if strings.Contains(failed.String(), "input10") {
+		t.Errorf("Failing report should truncate the failure list:\n%s", failed.String())
+	}
+}
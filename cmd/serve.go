@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"fsm-modulo-three/server"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const reloadInterval = time.Second
+
+func runServe(args []string) int {
+	defaults := defaultConfig()
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file (default $FSM_CONFIG or ~/"+configFileName+")")
+	modulus := flags.Int("mod", defaults.Mod, "modulus to compute remainders for")
+	base := flags.Int("base", defaults.Base, "base of the input numbers (2-36)")
+	port := flags.Int("port", defaults.Port, "port to listen on")
+	host := flags.String("host", "", "interface to listen on (default all)")
+	rate := flags.Float64("rate", 0, "requests per second allowed per client IP (0 disables rate limiting)")
+	burst := flags.Int("burst", 0, "requests a client may make at once before -rate applies (default: -rate rounded up)")
+	maxLen := flags.Int("max-len", 0, "maximum input length in characters (0 for unlimited)")
+	maxDefinition := flags.Int64("max-definition-size", server.DefaultMaxDefinitionSize, "maximum machine definition upload in bytes (0 for unlimited)")
+	apiKeys := flags.String("api-keys", "", "file of \"principal key [namespace,...]\" lines; registry requests then require an X-API-Key header")
+	definitions := flags.String("definitions", "", "directory of JSON definitions to serve in the registry, reloaded on change")
+	quota := flags.Int("namespace-quota", 0, "maximum machines per registry namespace (0 for unlimited)")
+	logLevel := flags.String("log-level", "info", "log to stderr at this level: debug, info, warn, error or off")
+	logFormat := flags.String("log-format", "text", "log format: text or json")
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+
+	cfg, err := loadConfig(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: loading config: %v\n", err)
+		return exitInternal
+	}
+
+	explicit := explicitFlags(flags)
+	if !explicit["mod"] {
+		*modulus = cfg.Mod
+	}
+	if !explicit["base"] {
+		*base = cfg.Base
+	}
+	if !explicit["port"] {
+		*port = cfg.Port
+	}
+
+	machine, err := modulo.NewModFSM(*modulus, *base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	logger, err := newLogger(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+	machine.GetAutomaton().Logger = logger
+
+	if *rate < 0 || *burst < 0 || *maxLen < 0 || *maxDefinition < 0 || *quota < 0 {
+		fmt.Fprintln(os.Stderr, "Error: -rate, -burst, -max-len, -max-definition-size and -namespace-quota must not be negative")
+		return exitInternal
+	}
+
+	registry := server.NewRegistry()
+	registry.SetDefaultQuota(*quota)
+	options := []server.Option{
+		server.WithRateLimit(*rate, *burst),
+		server.WithMaxInputLength(*maxLen),
+		server.WithMaxDefinitionSize(*maxDefinition),
+		server.WithRegistry(registry),
+	}
+	if logger != nil {
+		options = append(options, server.WithLogger(logger))
+	}
+	if *apiKeys != "" {
+		authenticator, err := loadAPIKeys(*apiKeys)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitInternal
+		}
+		options = append(options, server.WithAuthenticator(authenticator))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *definitions != "" {
+		reloader := server.NewReloader(registry, *definitions, logger)
+		if _, err := reloader.Reload(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: loading definitions: %v\n", err)
+			return exitInternal
+		}
+		go reloader.Run(ctx, reloadInterval)
+	}
+
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf("%s:%d", *host, *port),
+		Handler:           server.New(machine, options...),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		if logger != nil {
+			logger.Info("serving", "modulus", *modulus, "base", *base, "addr", httpServer.Addr)
+		} else {
+			fmt.Fprintf(os.Stderr, "Serving mod %d (base %d) on %s\n", *modulus, *base, httpServer.Addr)
+		}
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+	return exitOK
+}
+
+func loadAPIKeys(path string) (server.Authenticator, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading API keys: %w", err)
+	}
+	defer file.Close()
+
+	keys, err := server.ReadAPIKeys(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return server.APIKeyAuthenticator(server.DefaultAPIKeyHeader, keys), nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+func runStats(args []string) int {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	definitionPath := flags.String("def", "", "path to a JSON automaton definition (default: built-in mod-three)")
+	asJSON := flags.Bool("json", false, "print the statistics as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+
+	name, automaton, err := loadAutomaton(*definitionPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInvalid
+	}
+
+	if err := writeStats(os.Stdout, name, automaton.Stats(), *asJSON); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+	return exitOK
+}
+
+func writeStats(w io.Writer, name string, stats fsm.Stats, asJSON bool) error {
+	if asJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Name string `json:"name"`
+			fsm.Stats
+		}{name, stats})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "name\t%s\n", name)
+	fmt.Fprintf(tw, "states\t%d\n", stats.States)
+	fmt.Fprintf(tw, "accepting states\t%d\n", stats.AcceptingStates)
+	fmt.Fprintf(tw, "reachable states\t%d\n", stats.Reachable)
+	fmt.Fprintf(tw, "alphabet size\t%d\n", stats.AlphabetSize)
+	fmt.Fprintf(tw, "transitions\t%d\n", stats.Transitions)
+	fmt.Fprintf(tw, "edges\t%d\n", stats.Edges)
+	fmt.Fprintf(tw, "density\t%.3f\n", stats.Density)
+	fmt.Fprintf(tw, "depth\t%d\n", stats.Depth)
+	fmt.Fprintf(tw, "diameter\t%d\n", stats.Diameter)
+	return tw.Flush()
+}
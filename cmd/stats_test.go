@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fsm-modulo-three/modthree"
+	"strings"
+	"testing"
+)
+
+func TestWriteStats(t *testing.T) {
+	stats := modthree.NewModThreeFSM().GetAutomaton().Stats()
+
+	var text bytes.Buffer
+	if err := writeStats(&text, "ModThree", stats, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, expected := range []string{"name              ModThree\n", "states            3\n", "transitions       6\n", "density           0.667\n", "diameter          2\n"} {
+		if !strings.Contains(text.String(), expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, text.String())
+		}
+	}
+
+	var encoded bytes.Buffer
+	if err := writeStats(&encoded, "ModThree", stats, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil {
+		t.Fatalf("Unexpected error decoding JSON: %v", err)
+	}
+	if decoded["name"] != "ModThree" || decoded["states"] != float64(3) || decoded["alphabet_size"] != float64(2) {
+		t.Errorf("Unexpected JSON output: %v", decoded)
+	}
+}
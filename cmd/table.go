@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"text/tabwriter"
+)
+
+func writeTable(w io.Writer, automaton *fsm.FiniteAutomaton) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "state")
+	for _, symbol := range automaton.Alphabet {
+		fmt.Fprintf(tw, "\t%s", symbol)
+	}
+	fmt.Fprintln(tw)
+
+	for _, state := range automaton.States {
+		name := string(state)
+		if state == automaton.InitialState {
+			name = "-> " + name
+		}
+		if automaton.IsAcceptingState(state) {
+			name += " *"
+		}
+
+		fmt.Fprint(tw, name)
+		for _, symbol := range automaton.Alphabet {
+			fmt.Fprintf(tw, "\t%s", automaton.TransitionFunction(state, symbol))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
+
+func writeTrace(w io.Writer, automaton *fsm.FiniteAutomaton, input string) error {
+	if input == "" {
+		return fmt.Errorf("usage: trace <input>")
+	}
+
+	runner := fsm.NewRunner(automaton, fsm.WithHistory())
+	if _, err := runner.Feed(input); err != nil {
+		return err
+	}
+
+	for i, transition := range runner.History() {
+		fmt.Fprintf(w, "%3d: %s --%s--> %s\n", i+1, transition.From, transition.Symbol, transition.To)
+	}
+	fmt.Fprintf(w, "Final state: %s (accepting: %v)\n", runner.CurrentState(), runner.IsAccepting())
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"fsm-modulo-three/modthree"
+	"strings"
+	"testing"
+)
+
+func TestWriteTable(t *testing.T) {
+	var out bytes.Buffer
+	writeTable(&out, modthree.NewModThreeFSM().GetAutomaton())
+
+	expected := []string{
+		"state",
+		"-> S0 *  S0  S1",
+		"S1 *     S2  S0",
+		"S2 *     S1  S2",
+	}
+	for _, component := range expected {
+		if !strings.Contains(out.String(), component) {
+			t.Errorf("Table should contain %q:\n%s", component, out.String())
+		}
+	}
+}
+
+func TestWriteTrace(t *testing.T) {
+	automaton := modthree.NewModThreeFSM().GetAutomaton()
+
+	var out bytes.Buffer
+	if err := writeTrace(&out, automaton, "110"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"1: S0 --1--> S1",
+		"2: S1 --1--> S0",
+		"3: S0 --0--> S0",
+		"Final state: S0",
+	}
+	for _, component := range expected {
+		if !strings.Contains(out.String(), component) {
+			t.Errorf("Trace should contain %q:\n%s", component, out.String())
+		}
+	}
+
+	for _, input := range []string{"", "12"} {
+		if err := writeTrace(&bytes.Buffer{}, automaton, input); err == nil {
+			t.Errorf("Expected error for input '%s', but got none", input)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+)
+
+func enableRawMode(fd uintptr) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const verifyMaxReports = 20
+
+type verifyReport struct {
+	Rows       int
+	Mismatches []string
+	Errors     []string
+}
+
+func (r *verifyReport) Passed() bool {
+	return len(r.Mismatches) == 0 && len(r.Errors) == 0
+}
+
+func runVerify(args []string) int {
+	defaults := defaultConfig()
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	configPath := flags.String("config", "", "config file (default $FSM_CONFIG or ~/"+configFileName+")")
+	modulus := flags.Int("mod", defaults.Mod, "modulus of the machine to verify")
+	base := flags.Int("base", defaults.Base, "base of the machine to verify (2-36)")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s verify [flags] [FILE.csv]\n", programName)
+		fmt.Fprintln(flags.Output(), "Reads input,expected_remainder rows from FILE or stdin.")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitInternal
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return exitInternal
+	}
+
+	cfg, err := loadConfig(*configPath, os.Getenv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: loading config: %v\n", err)
+		return exitInternal
+	}
+
+	explicit := explicitFlags(flags)
+	if !explicit["mod"] {
+		*modulus = cfg.Mod
+	}
+	if !explicit["base"] {
+		*base = cfg.Base
+	}
+
+	machine, err := modulo.NewModFSM(*modulus, *base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	in := io.Reader(os.Stdin)
+	if flags.NArg() == 1 {
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitInternal
+		}
+		defer file.Close()
+		in = file
+	}
+
+	report, err := verify(in, machine)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	writeVerifyReport(os.Stdout, report)
+	if !report.Passed() {
+		return exitInvalid
+	}
+	return exitOK
+}
+
+func verify(in io.Reader, machine *modulo.ModFSM) (*verifyReport, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	report := &verifyReport{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return report, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) != 2 {
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: expected 2 fields, got %d", line, len(record)))
+			continue
+		}
+
+		input := strings.TrimSpace(record[0])
+		expected, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			if report.Rows == 0 && len(report.Errors) == 0 {
+				continue
+			}
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: invalid expected remainder %q", line, record[1]))
+			continue
+		}
+
+		report.Rows++
+		result, err := machine.Mod(input)
+		switch {
+		case err != nil:
+			report.Errors = append(report.Errors, fmt.Sprintf("line %d: %s: %v", line, input, err))
+		case result.Remainder != expected:
+			report.Mismatches = append(report.Mismatches, fmt.Sprintf("line %d: %s: expected %d, got %d", line, input, expected, result.Remainder))
+		}
+	}
+}
+
+func writeVerifyReport(w io.Writer, report *verifyReport) {
+	fmt.Fprintf(w, "Verified %d rows: %d mismatches, %d errors\n", report.Rows, len(report.Mismatches), len(report.Errors))
+	if report.Passed() {
+		fmt.Fprintln(w, "PASS")
+		return
+	}
+
+	fmt.Fprintln(w, "FAIL")
+	problems := append(append([]string{}, report.Mismatches...), report.Errors...)
+	for _, problem := range problems[:min(len(problems), verifyMaxReports)] {
+		fmt.Fprintf(w, "  %s\n", problem)
+	}
+	if hidden := len(problems) - verifyMaxReports; hidden > 0 {
+		fmt.Fprintf(w, "  ... and %d more\n", hidden)
+	}
+}
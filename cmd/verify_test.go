@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	tests := []struct {
+		name               string
+		csv                string
+		expectedRows       int
+		expectedMismatches int
+		expectedErrors     int
+	}{
+		{"all match", "1101,1\n1110,2\n1111,0\n", 3, 0, 0},
+		{"header", "input,expected\n1101,1\n", 1, 0, 0},
+		{"comments and spaces", "# fixtures\n1101, 1\n\n 110 , 0\n", 2, 0, 0},
+		{"mismatch", "1101,2\n1110,2\n", 2, 1, 0},
+		{"invalid input", "12,0\n", 1, 0, 1},
+		{"bad expected", "1101,1\n1110,two\n", 1, 0, 1},
+		{"wrong field count", "1101\n1110,2,extra\n", 0, 0, 2},
+		{"empty", "", 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := verify(strings.NewReader(test.csv), newModThree(t))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if report.Rows != test.expectedRows {
+				t.Errorf("Expected %d rows, got %d", test.expectedRows, report.Rows)
+			}
+			if len(report.Mismatches) != test.expectedMismatches {
+				t.Errorf("Expected %d mismatches, got %v", test.expectedMismatches, report.Mismatches)
+			}
+			if len(report.Errors) != test.expectedErrors {
+				t.Errorf("Expected %d errors, got %v", test.expectedErrors, report.Errors)
+			}
+			if report.Passed() != (test.expectedMismatches == 0 && test.expectedErrors == 0) {
+				t.Errorf("Unexpected Passed() = %v", report.Passed())
+			}
+		})
+	}
+}
+
+func TestVerify_MalformedCSV(t *testing.T) {
+	if _, err := verify(strings.NewReader("\"1101,1\n"), newModThree(t)); err == nil {
+		t.Error("Expected error for malformed CSV, but got none")
+	}
+}
+
+func TestWriteVerifyReport(t *testing.T) {
+	report, _ := verify(strings.NewReader("1101,2\n12,0\n1110,2\n"), newModThree(t))
+
+	var out bytes.Buffer
+	writeVerifyReport(&out, report)
+
+	expected := []string{
+		"Verified 3 rows: 1 mismatches, 1 errors",
+		"FAIL",
+		"line 1: 1101: expected 2, got 1",
+		"line 2: 12: invalid character '2'",
+	}
+	for _, component := range expected {
+		if !strings.Contains(out.String(), component) {
+			t.Errorf("Report should contain %q:\n%s", component, out.String())
+		}
+	}
+
+	out.Reset()
+	passing, _ := verify(strings.NewReader("1101,1\n"), newModThree(t))
+	writeVerifyReport(&out, passing)
+	if !strings.HasSuffix(out.String(), "PASS\n") {
+		t.Errorf("Expected PASS, got %q", out.String())
+	}
+}
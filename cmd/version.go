@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+var version = ""
+
+func runVersion(args []string) int {
+	if len(args) != 0 {
+		fmt.Fprintf(os.Stderr, "Usage: %s version\n", programName)
+		return 2
+	}
+
+	info, _ := debug.ReadBuildInfo()
+	writeVersion(os.Stdout, info)
+	return 0
+}
+
+func writeVersion(w io.Writer, info *debug.BuildInfo) {
+	moduleVersion := version
+	module := "fsm-modulo-three"
+	goVersion := runtime.Version()
+	settings := map[string]string{}
+
+	if info != nil {
+		if info.Main.Path != "" {
+			module = info.Main.Path
+		}
+		if moduleVersion == "" {
+			moduleVersion = info.Main.Version
+		}
+		if info.GoVersion != "" {
+			goVersion = info.GoVersion
+		}
+		for _, setting := range info.Settings {
+			settings[setting.Key] = setting.Value
+		}
+	}
+
+	if moduleVersion == "" {
+		moduleVersion = "(devel)"
+	}
+
+	revision := settings["vcs.revision"]
+	if revision == "" {
+		revision = "unknown"
+	}
+	if settings["vcs.modified"] == "true" {
+		revision += " (modified)"
+	}
+
+	fmt.Fprintf(w, "%s %s\n", programName, moduleVersion)
+	fmt.Fprintf(w, "Module:   %s\n", module)
+	fmt.Fprintf(w, "Revision: %s\n", revision)
+	if buildTime := settings["vcs.time"]; buildTime != "" {
+		fmt.Fprintf(w, "Date:     %s\n", buildTime)
+	}
+	fmt.Fprintf(w, "Go:       %s %s/%s\n", goVersion, runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestWriteVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     *debug.BuildInfo
+		expected []string
+	}{
+		{
+			name: "full build info",
+			info: &debug.BuildInfo{
+				GoVersion: "go1.99.0",
+				Main:      debug.Module{Path: "example.com/fsm", Version: "v1.2.3"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "abc123"},
+					{Key: "vcs.time", Value: "2026-01-02T03:04:05Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			expected: []string{"fsm-demo v1.2.3", "Module:   example.com/fsm", "Revision: abc123 (modified)", "Date:     2026-01-02T03:04:05Z", "Go:       go1.99.0"},
+		},
+		{
+			name:     "no build info",
+			info:     nil,
+			expected: []string{"fsm-demo (devel)", "Module:   fsm-modulo-three", "Revision: unknown", "Go:       go"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var out bytes.Buffer
+			writeVersion(&out, test.info)
+			for _, component := range test.expected {
+				if !strings.Contains(out.String(), component) {
+					t.Errorf("Version output should contain %q:\n%s", component, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestWriteVersion_LinkerOverride(t *testing.T) {
+	defer func(previous string) { version = previous }(version)
+	version = "v9.9.9"
+
+	var out bytes.Buffer
+	writeVersion(&out, &debug.BuildInfo{Main: debug.Module{Version: "v1.0.0"}})
+	if !strings.HasPrefix(out.String(), "fsm-demo v9.9.9\n") {
+		t.Errorf("Expected linker-provided version to win, got:\n%s", out.String())
+	}
+}
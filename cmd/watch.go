@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const watchInterval = 500 * time.Millisecond
+
+type watcher struct {
+	path    string
+	machine *modulo.ModFSM
+	mode    outputMode
+	out     io.Writer
+	errOut  io.Writer
+
+	loaded  bool
+	modTime time.Time
+	size    int64
+	results []string
+}
+
+func runWatch(ctx context.Context, w *watcher, interval time.Duration) int {
+	if err := w.poll(); err != nil {
+		fmt.Fprintf(w.errOut, "Error: %v\n", err)
+		return exitInternal
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return exitOK
+		case <-ticker.C:
+			if err := w.poll(); err != nil {
+				fmt.Fprintf(w.errOut, "Error: %v\n", err)
+			}
+		}
+	}
+}
+
+func (w *watcher) poll() error {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return err
+	}
+	if w.loaded && info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return err
+	}
+	results, err := w.evaluate(data)
+	if err != nil {
+		// Remember the bad version so it is reported once, not on every poll.
+		w.modTime, w.size = info.ModTime(), info.Size()
+		return fmt.Errorf("%s: %w", w.path, err)
+	}
+
+	if !w.loaded {
+		for _, line := range results {
+			fmt.Fprintln(w.out, line)
+		}
+	} else {
+		changes := diffResults(w.results, results)
+		fmt.Fprintf(w.out, "--- %s changed: %d result(s) differ\n", w.path, len(changes))
+		for _, change := range changes {
+			fmt.Fprintln(w.out, change)
+		}
+	}
+
+	w.loaded = true
+	w.modTime = info.ModTime()
+	w.size = info.Size()
+	w.results = results
+	return nil
+}
+
+// evaluate returns one record per input: its result line and, in trace
+// mode, the trace lines that follow it. Records are what diffs compare, so a
+// changed trace shows up with the input it belongs to.
+func (w *watcher) evaluate(data []byte) ([]string, error) {
+	var records []string
+	var record bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 1
+	for ; scanner.Scan(); line++ {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			continue
+		}
+		record.Reset()
+		writeResult(&record, io.Discard, w.machine, w.mode, input, fmt.Sprintf("line %d", line))
+		records = append(records, strings.TrimSuffix(record.String(), "\n"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %w", line, err)
+	}
+	return records, nil
+}
+
+func diffResults(previous, current []string) []string {
+	counts := make(map[string]int)
+	for _, line := range previous {
+		counts[line]++
+	}
+
+	var added []string
+	for _, line := range current {
+		if counts[line] > 0 {
+			counts[line]--
+			continue
+		}
+		added = append(added, markRecord("+ ", line))
+	}
+
+	var removed []string
+	for _, line := range previous {
+		if counts[line] > 0 {
+			counts[line]--
+			removed = append(removed, markRecord("- ", line))
+		}
+	}
+
+	return append(removed, added...)
+}
+
+func markRecord(mark, record string) string {
+	return mark + strings.ReplaceAll(record, "\n", "\n"+mark)
+}
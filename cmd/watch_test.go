@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeWatchedFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestWatcher_Poll(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.txt")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, "1101\n1110\n", start)
+
+	var out bytes.Buffer
+	w := &watcher{path: path, machine: newModThree(t), out: &out, errOut: &bytes.Buffer{}}
+
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.String() != "1101\t1\tS1\n1110\t2\tS2\n" {
+		t.Errorf("Expected initial results, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output for an unchanged file, got %q", out.String())
+	}
+
+	writeWatchedFile(t, path, "1101\n1111\n12\n", start.Add(time.Minute))
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"changed: 3 result(s) differ",
+		"- 1110\t2\tS2",
+		"+ 1111\t0\tS0",
+		"+ 12\terror\t",
+	}
+	for _, component := range expected {
+		if !strings.Contains(out.String(), component) {
+			t.Errorf("Diff should contain %q:\n%s", component, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "1101") {
+		t.Errorf("Diff should not repeat unchanged results:\n%s", out.String())
+	}
+}
+
+func TestWatcher_TraceDiffsPerInput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.txt")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, "110\n11\n", start)
+
+	var out bytes.Buffer
+	w := &watcher{path: path, machine: newModThree(t), mode: outputTrace, out: &out, errOut: &bytes.Buffer{}}
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out.Reset()
+	writeWatchedFile(t, path, "110\n111\n", start.Add(time.Minute))
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "changed: 2 result(s) differ\n" +
+		"- 11\t0\tS0\n-   S0 --1--> S1\n-   S1 --1--> S0\n" +
+		"+ 111\t1\tS1\n+   S0 --1--> S1\n+   S1 --1--> S0\n+   S0 --1--> S1\n"
+	if !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("Expected each changed input with its whole trace, got:\n%s", out.String())
+	}
+}
+
+func TestWatcher_LineTooLong(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.txt")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, "1101\n", start)
+
+	var out bytes.Buffer
+	w := &watcher{path: path, machine: newModThree(t), out: &out, errOut: &bytes.Buffer{}}
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	out.Reset()
+	writeWatchedFile(t, path, "1101\n"+strings.Repeat("1", 2<<20)+"\n", start.Add(time.Minute))
+	if err := w.poll(); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("Expected an error for the over-long line 2, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no diff for a file that failed to read, got %q", out.String())
+	}
+	if err := w.poll(); err != nil {
+		t.Errorf("Expected the same bad file to be reported once, got %v", err)
+	}
+
+	writeWatchedFile(t, path, "1101\n1111\n", start.Add(2*time.Minute))
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "changed: 1 result(s) differ") {
+		t.Errorf("Expected a diff against the last good results, got %q", out.String())
+	}
+}
+
+func TestWatcher_MissingFile(t *testing.T) {
+	w := &watcher{path: filepath.Join(t.TempDir(), "missing.txt"), machine: newModThree(t), out: &bytes.Buffer{}, errOut: &bytes.Buffer{}}
+	if status := runWatch(context.Background(), w, time.Millisecond); status != exitInternal {
+		t.Errorf("Expected exit status %d, got %d", exitInternal, status)
+	}
+}
+
+func TestRunWatch_StopsOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.txt")
+	writeWatchedFile(t, path, "1101\n", time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	w := &watcher{path: path, machine: newModThree(t), out: &bytes.Buffer{}, errOut: &bytes.Buffer{}}
+	if status := runWatch(ctx, w, time.Millisecond); status != exitOK {
+		t.Errorf("Expected exit status %d, got %d", exitOK, status)
+	}
+}
+
+func TestDiffResults(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []string
+		current  []string
+		expected []string
+	}{
+		{"unchanged", []string{"a", "b"}, []string{"b", "a"}, nil},
+		{"added", []string{"a"}, []string{"a", "b"}, []string{"+ b"}},
+		{"removed", []string{"a", "b"}, []string{"b"}, []string{"- a"}},
+		{"duplicates", []string{"a", "a"}, []string{"a"}, []string{"- a"}},
+		{"replaced", []string{"a"}, []string{"c"}, []string{"- a", "+ c"}},
+		{"multi-line record", []string{"a\n  t"}, nil, []string{"- a\n-   t"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes := diffResults(test.previous, test.current)
+			if strings.Join(changes, ",") != strings.Join(test.expected, ",") {
+				t.Errorf("Expected %v, got %v", test.expected, changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,536 @@
+package expr
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxSourceLength = 1024
+	maxDepth        = 64
+)
+
+type Func func(args ...any) (any, error)
+
+type Env map[string]any
+
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+type nodeKind int
+
+const (
+	nodeLiteral nodeKind = iota
+	nodeIdent
+	nodeUnary
+	nodeBinary
+	nodeCall
+)
+
+type node struct {
+	kind     nodeKind
+	pos      int
+	op       string
+	value    any
+	name     string
+	children []*node
+}
+
+type Program struct {
+	source string
+	root   *node
+}
+
+func Compile(source string) (*Program, error) {
+	if len(source) > maxSourceLength {
+		return nil, &Error{Pos: 0, Msg: fmt.Sprintf("expression longer than %d bytes", maxSourceLength)}
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpression(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &Error{Pos: next.pos, Msg: fmt.Sprintf("unexpected %q", next.text)}
+	}
+	return &Program{source: source, root: root}, nil
+}
+
+func (p *Program) String() string {
+	return p.source
+}
+
+func (p *Program) Identifiers() []string {
+	seen := make(map[string]bool)
+	var walk func(n *node)
+	walk = func(n *node) {
+		if n.kind == nodeIdent || n.kind == nodeCall {
+			seen[n.name] = true
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(p.root)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		if _, builtin := builtins[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *Program) Eval(env Env) (any, error) {
+	return eval(p.root, env)
+}
+
+func (p *Program) EvalBool(env Env) (bool, error) {
+	value, err := p.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, &Error{Pos: p.root.pos, Msg: fmt.Sprintf("expected a bool result, got %s", typeName(value))}
+	}
+	return result, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenInt
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(source) && source[i] >= '0' && source[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenInt, pos: start, text: source[start:i]})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(source) && (source[i] == '_' || source[i] >= 'a' && source[i] <= 'z' || source[i] >= 'A' && source[i] <= 'Z' || source[i] >= '0' && source[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, pos: start, text: source[start:i]})
+		case c == '"' || c == '\'':
+			start := i
+			i++
+			for i < len(source) && source[i] != c {
+				if source[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(source) {
+				return nil, &Error{Pos: start, Msg: "unterminated string"}
+			}
+			i++
+			literal := source[start:i]
+			if c == '\'' {
+				literal = `"` + strings.ReplaceAll(strings.ReplaceAll(literal[1:len(literal)-1], `"`, `\"`), `\'`, `'`) + `"`
+			}
+			text, err := strconv.Unquote(literal)
+			if err != nil {
+				return nil, &Error{Pos: start, Msg: "invalid string literal"}
+			}
+			tokens = append(tokens, token{kind: tokenString, pos: start, text: text})
+		default:
+			operator := ""
+			for _, candidate := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "+", "-", "*", "/", "%", "!", "(", ")", ","} {
+				if strings.HasPrefix(source[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, &Error{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, pos: i, text: operator})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source), text: "end of expression"}), nil
+}
+
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); t.kind != tokenOperator || t.text != text {
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("expected %q, got %q", text, t.text)}
+	}
+	return nil
+}
+
+func (p *parser) parseExpression(minPrecedence, depth int) (*node, error) {
+	if depth > maxDepth {
+		return nil, &Error{Pos: p.peek().pos, Msg: fmt.Sprintf("expression nested deeper than %d levels", maxDepth)}
+	}
+
+	left, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if t.kind != tokenOperator || !ok || prec <= minPrecedence {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseExpression(prec, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		left = &node{kind: nodeBinary, pos: t.pos, op: t.text, children: []*node{left, right}}
+	}
+}
+
+func (p *parser) parseUnary(depth int) (*node, error) {
+	t := p.peek()
+	if t.kind == tokenOperator && (t.text == "!" || t.text == "-") {
+		p.next()
+		if depth > maxDepth {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expression nested deeper than %d levels", maxDepth)}
+		}
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &node{kind: nodeUnary, pos: t.pos, op: t.text, children: []*node{operand}}, nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func (p *parser) parsePrimary(depth int) (*node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenInt:
+		value, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, &Error{Pos: t.pos, Msg: "integer out of range"}
+		}
+		return &node{kind: nodeLiteral, pos: t.pos, value: value}, nil
+	case tokenString:
+		return &node{kind: nodeLiteral, pos: t.pos, value: t.text}, nil
+	case tokenIdent:
+		switch t.text {
+		case "true", "false":
+			return &node{kind: nodeLiteral, pos: t.pos, value: t.text == "true"}, nil
+		}
+		if next := p.peek(); next.kind != tokenOperator || next.text != "(" {
+			return &node{kind: nodeIdent, pos: t.pos, name: t.text}, nil
+		}
+		p.next()
+		call := &node{kind: nodeCall, pos: t.pos, name: t.text}
+		if next := p.peek(); next.kind == tokenOperator && next.text == ")" {
+			p.next()
+			return call, nil
+		}
+		for {
+			arg, err := p.parseExpression(0, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			call.children = append(call.children, arg)
+			if next := p.peek(); next.kind == tokenOperator && next.text == "," {
+				p.next()
+				continue
+			}
+			return call, p.expect(")")
+		}
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseExpression(0, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+}
+
+func eval(n *node, env Env) (any, error) {
+	switch n.kind {
+	case nodeLiteral:
+		return n.value, nil
+	case nodeIdent:
+		value, ok := env[n.name]
+		if !ok {
+			return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("unknown identifier '%s'", n.name)}
+		}
+		return normalize(n.pos, value)
+	case nodeUnary:
+		operand, err := eval(n.children[0], env)
+		if err != nil {
+			return nil, err
+		}
+		switch value := operand.(type) {
+		case bool:
+			if n.op == "!" {
+				return !value, nil
+			}
+		case int64:
+			if n.op == "-" {
+				return -value, nil
+			}
+		}
+		return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("operator %s does not apply to %s", n.op, typeName(operand))}
+	case nodeCall:
+		return call(n, env)
+	}
+
+	left, err := eval(n.children[0], env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("operator %s needs bool operands, got %s", n.op, typeName(left))}
+		}
+		if l == (n.op == "||") {
+			return l, nil
+		}
+		right, err := eval(n.children[1], env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("operator %s needs bool operands, got %s", n.op, typeName(right))}
+		}
+		return r, nil
+	}
+
+	right, err := eval(n.children[1], env)
+	if err != nil {
+		return nil, err
+	}
+	return binary(n, left, right)
+}
+
+func binary(n *node, left, right any) (any, error) {
+	mismatch := &Error{Pos: n.pos, Msg: fmt.Sprintf("operator %s does not apply to %s and %s", n.op, typeName(left), typeName(right))}
+
+	switch l := left.(type) {
+	case int64:
+		r, ok := right.(int64)
+		if !ok {
+			return nil, mismatch
+		}
+		switch n.op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/", "%":
+			if r == 0 {
+				return nil, &Error{Pos: n.pos, Msg: "division by zero"}
+			}
+			if n.op == "/" {
+				return l / r, nil
+			}
+			return l % r, nil
+		}
+		return compare(n.op, l < r, l == r, mismatch)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, mismatch
+		}
+		if n.op == "+" {
+			return l + r, nil
+		}
+		return compare(n.op, l < r, l == r, mismatch)
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			return nil, mismatch
+		}
+		switch n.op {
+		case "==":
+			return l == r, nil
+		case "!=":
+			return l != r, nil
+		}
+	}
+	return nil, mismatch
+}
+
+func compare(op string, less, equal bool, mismatch error) (any, error) {
+	switch op {
+	case "==":
+		return equal, nil
+	case "!=":
+		return !equal, nil
+	case "<":
+		return less, nil
+	case "<=":
+		return less || equal, nil
+	case ">":
+		return !less && !equal, nil
+	case ">=":
+		return !less, nil
+	}
+	return nil, mismatch
+}
+
+func call(n *node, env Env) (any, error) {
+	fn, ok := builtins[n.name]
+	if value, defined := env[n.name]; defined {
+		fn, ok = value.(Func)
+		if !ok {
+			return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("'%s' is not a function", n.name)}
+		}
+	}
+	if !ok {
+		return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("unknown function '%s'", n.name)}
+	}
+
+	args := make([]any, len(n.children))
+	for i, child := range n.children {
+		value, err := eval(child, env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+
+	result, err := fn(args...)
+	if err != nil {
+		return nil, &Error{Pos: n.pos, Msg: fmt.Sprintf("%s: %v", n.name, err)}
+	}
+	return normalize(n.pos, result)
+}
+
+func normalize(pos int, value any) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return int64(v), nil
+	case int64, string, bool:
+		return v, nil
+	}
+	return nil, &Error{Pos: pos, Msg: fmt.Sprintf("unsupported value of type %T", value)}
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case int64:
+		return "int"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+var builtins = map[string]Func{
+	"len": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %s", typeName(args[0]))
+		}
+		return int64(len([]rune(s))), nil
+	},
+	"str": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		return fmt.Sprint(args[0]), nil
+	},
+	"int": func(args ...any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		switch v := args[0].(type) {
+		case int64:
+			return v, nil
+		case string:
+			return strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		}
+		return nil, fmt.Errorf("cannot convert %s to int", typeName(args[0]))
+	},
+	"contains":   stringPredicate(strings.Contains),
+	"startsWith": stringPredicate(strings.HasPrefix),
+	"endsWith":   stringPredicate(strings.HasSuffix),
+}
+
+func stringPredicate(predicate func(s, sub string) bool) Func {
+	return func(args ...any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments, got %d", len(args))
+		}
+		s, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("expected string arguments, got %s and %s", typeName(args[0]), typeName(args[1]))
+		}
+		return predicate(s, sub), nil
+	}
+}
@@ -0,0 +1,165 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	env := Env{
+		"symbol": "a",
+		"count":  3,
+		"limit":  int64(10),
+		"strict": true,
+		"double": Func(func(args ...any) (any, error) {
+			return args[0].(int64) * 2, nil
+		}),
+	}
+
+	tests := []struct {
+		source   string
+		expected any
+	}{
+		{"1 + 2 * 3", int64(7)},
+		{"(1 + 2) * 3", int64(9)},
+		{"10 - 4 - 3", int64(3)},
+		{"17 % 5", int64(2)},
+		{"-count + 1", int64(-2)},
+		{"count < limit && strict", true},
+		{"!strict || count == 3", true},
+		{`symbol == "a"`, true},
+		{`symbol == 'a'`, true},
+		{`symbol + "b" + 'c'`, "abc"},
+		{`"abc" < "abd"`, true},
+		{"count >= 3 && count <= 3 && count != 4 && count > 2", true},
+		{"double(count) == 6", true},
+		{`len("héllo")`, int64(5)},
+		{`int("42") + 1`, int64(43)},
+		{`str(count) + "x"`, "3x"},
+		{`contains("payload", "load") && startsWith("payload", "pay") && endsWith("payload", "ad")`, true},
+		{`'it\'s'`, "it's"},
+		{`"tab\tnewline\n"`, "tab\tnewline\n"},
+		{"false && missing", false},
+		{"true || missing", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			program, err := Compile(test.source)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			value, err := program.Eval(env)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if value != test.expected {
+				t.Errorf("Expected %v (%T), got %v (%T)", test.expected, test.expected, value, value)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		source        string
+		expectedPos   int
+		expectedError string
+	}{
+		{"1 +", 3, "unexpected"},
+		{"(1 + 2", 6, `expected ")"`},
+		{"1 2", 2, "unexpected"},
+		{`"open`, 0, "unterminated string"},
+		{"a # b", 2, "unexpected character"},
+		{"f(1,", 4, "unexpected"},
+		{"99999999999999999999", 0, "integer out of range"},
+		{strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), 65, "nested deeper"},
+		{strings.Repeat("1+", 600) + "1", 0, "longer than"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source[:min(len(test.source), 20)], func(t *testing.T) {
+			_, err := Compile(test.source)
+			var exprErr *Error
+			if !errors.As(err, &exprErr) {
+				t.Fatalf("Expected *Error, got %v", err)
+			}
+			if exprErr.Pos != test.expectedPos || !strings.Contains(exprErr.Msg, test.expectedError) {
+				t.Errorf("Expected error at %d containing %q, got %v", test.expectedPos, test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	env := Env{
+		"count":  int64(1),
+		"name":   "x",
+		"nested": []int{1},
+		"fail": Func(func(args ...any) (any, error) {
+			return nil, fmt.Errorf("boom")
+		}),
+	}
+
+	tests := []struct {
+		source        string
+		expectedError string
+	}{
+		{"missing", "unknown identifier 'missing'"},
+		{"count + name", "does not apply to int and string"},
+		{"count / 0", "division by zero"},
+		{"count % 0", "division by zero"},
+		{"!count", "does not apply to int"},
+		{"-name", "does not apply to string"},
+		{"count && true", "needs bool operands"},
+		{"true && count", "needs bool operands"},
+		{"true < false", "does not apply to bool and bool"},
+		{"nested", "unsupported value"},
+		{"nope()", "unknown function 'nope'"},
+		{"count()", "'count' is not a function"},
+		{"fail()", "fail: boom"},
+		{"len(1)", "expected a string"},
+		{"len()", "expected 1 argument"},
+		{`int("x")`, "invalid syntax"},
+		{"contains(1, 2)", "expected string arguments"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.source, func(t *testing.T) {
+			program, err := Compile(test.source)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := program.Eval(env); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", test.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestProgram_EvalBool(t *testing.T) {
+	program, _ := Compile("count > 1")
+	if ok, err := program.EvalBool(Env{"count": 2}); err != nil || !ok {
+		t.Errorf("Expected true, got %v, %v", ok, err)
+	}
+
+	program, _ = Compile("count + 1")
+	if _, err := program.EvalBool(Env{"count": 2}); err == nil || !strings.Contains(err.Error(), "expected a bool result, got int") {
+		t.Errorf("Expected bool result error, got %v", err)
+	}
+}
+
+func TestProgram_Identifiers(t *testing.T) {
+	program, err := Compile(`len(symbol) > limit && check(state, "x") || symbol == "y"`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if identifiers := strings.Join(program.Identifiers(), ","); identifiers != "check,limit,state,symbol" {
+		t.Errorf("Expected check,limit,state,symbol, got %s", identifiers)
+	}
+	if program.String() != `len(symbol) > limit && check(state, "x") || symbol == "y"` {
+		t.Errorf("Expected String to return the source, got %s", program.String())
+	}
+}
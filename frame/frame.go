@@ -0,0 +1,163 @@
+package frame
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"fsm-modulo-three/fsm"
+)
+
+// SyncByte starts every frame. A frame is the sync byte, a length byte, that
+// many payload bytes and a checksum byte: the XOR of the length and payload.
+const SyncByte byte = 0x7E
+
+var ErrChecksum = errors.New("frame checksum mismatch")
+
+type Frame struct {
+	Offset  int64
+	Payload []byte
+}
+
+// Encode frames payload for the decoder.
+func Encode(payload []byte) ([]byte, error) {
+	if len(payload) > 255 {
+		return nil, fmt.Errorf("payload of %d bytes does not fit in a frame", len(payload))
+	}
+
+	checksum := byte(len(payload))
+	for _, b := range payload {
+		checksum ^= b
+	}
+	encoded := append([]byte{SyncByte, byte(len(payload))}, payload...)
+	return append(encoded, checksum), nil
+}
+
+func payloadState(remaining int) fsm.State {
+	return fsm.State(fmt.Sprintf("Payload%d", remaining))
+}
+
+// Automaton recognises the frame layout for payloads of up to maxPayload
+// bytes. Counting is done by the states themselves: the length byte moves to
+// Payload<n>, and each payload byte moves one state closer to Checksum.
+// Bytes outside a frame and lengths above maxPayload are skipped, except that
+// a sync byte read as an oversized length starts a new frame.
+func Automaton(maxPayload int) (*fsm.FiniteAutomaton, error) {
+	if maxPayload < 0 || maxPayload > 255 {
+		return nil, fmt.Errorf("maximum payload must be between 0 and 255, got %d", maxPayload)
+	}
+
+	sync := fsm.SymbolSet{fsm.ByteSymbol(SyncByte)}
+	states := []fsm.State{"Sync", "Length", "Checksum", "Frame"}
+	transitions := []fsm.ClassTransition{
+		{From: "Sync", Class: sync, To: "Length"},
+		{From: "Sync", Default: true, To: "Sync"},
+		{From: "Length", Class: fsm.SymbolSet{fsm.ByteSymbol(0)}, To: "Checksum"},
+		{From: "Checksum", Default: true, To: "Frame"},
+		{From: "Frame", Class: sync, To: "Length"},
+		{From: "Frame", Default: true, To: "Sync"},
+	}
+	for n := 1; n <= maxPayload; n++ {
+		states = append(states, payloadState(n))
+		next := payloadState(n - 1)
+		if n == 1 {
+			next = "Checksum"
+		}
+		transitions = append(transitions,
+			fsm.ClassTransition{From: "Length", Class: fsm.SymbolSet{fsm.ByteSymbol(byte(n))}, To: payloadState(n)},
+			fsm.ClassTransition{From: payloadState(n), Default: true, To: next},
+		)
+	}
+	if maxPayload < int(SyncByte) {
+		transitions = append(transitions, fsm.ClassTransition{From: "Length", Class: sync, To: "Length"})
+	}
+	transitions = append(transitions, fsm.ClassTransition{From: "Length", Default: true, To: "Sync"})
+
+	return fsm.NewByteAutomaton(states, "Sync", []fsm.State{"Frame"}, transitions)
+}
+
+// action runs after the byte b moved the decoder out of a state.
+type action func(d *Decoder, b byte) (*Frame, error)
+
+type Decoder struct {
+	reader   *bufio.Reader
+	runner   *fsm.Runner
+	actions  map[fsm.State]action
+	offset   int64
+	start    int64
+	payload  []byte
+	checksum byte
+}
+
+func NewDecoder(r io.Reader, maxPayload int) (*Decoder, error) {
+	fa, err := Automaton(maxPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Decoder{
+		reader:  bufio.NewReader(r),
+		runner:  fsm.NewRunner(fa),
+		actions: map[fsm.State]action{"Length": begin, "Checksum": verify},
+	}
+	for n := 1; n <= maxPayload; n++ {
+		d.actions[payloadState(n)] = collect
+	}
+	return d, nil
+}
+
+func begin(d *Decoder, b byte) (*Frame, error) {
+	d.start = d.offset - 2
+	d.payload = make([]byte, 0, b)
+	d.checksum = b
+	return nil, nil
+}
+
+func collect(d *Decoder, b byte) (*Frame, error) {
+	d.payload = append(d.payload, b)
+	d.checksum ^= b
+	return nil, nil
+}
+
+func verify(d *Decoder, b byte) (*Frame, error) {
+	if b != d.checksum {
+		return nil, fmt.Errorf("frame at offset %d: %w: got %#02x, expected %#02x", d.start, ErrChecksum, b, d.checksum)
+	}
+	return &Frame{Offset: d.start, Payload: d.payload}, nil
+}
+
+// Next returns the next valid frame. A frame with a bad checksum is reported
+// as an error wrapping ErrChecksum; decoding can continue with the following
+// call. At the end of input Next returns io.EOF, or io.ErrUnexpectedEOF when
+// the input stops inside a frame.
+func (d *Decoder) Next() (Frame, error) {
+	for {
+		b, err := d.reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			if state := d.runner.CurrentState(); state != "Sync" && state != "Frame" {
+				return Frame{}, io.ErrUnexpectedEOF
+			}
+			return Frame{}, io.EOF
+		}
+		if err != nil {
+			return Frame{}, err
+		}
+
+		from := d.runner.CurrentState()
+		if _, err := d.runner.StepByte(b); err != nil {
+			return Frame{}, err
+		}
+		d.offset++
+
+		if run, ok := d.actions[from]; ok {
+			frame, err := run(d, b)
+			if err != nil {
+				return Frame{}, err
+			}
+			if frame != nil {
+				return *frame, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package frame
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"fsm-modulo-three/fsmtest"
+)
+
+func encode(t *testing.T, payloads ...string) []byte {
+	t.Helper()
+
+	var stream []byte
+	for _, payload := range payloads {
+		encoded, err := Encode([]byte(payload))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		stream = append(stream, encoded...)
+	}
+	return stream
+}
+
+func decodeAll(t *testing.T, stream []byte, maxPayload int) ([]Frame, []error) {
+	t.Helper()
+
+	decoder, err := NewDecoder(bytes.NewReader(stream), maxPayload)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var frames []Frame
+	var errs []error
+	for {
+		frame, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return frames, errs
+		}
+		if err != nil {
+			errs = append(errs, err)
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				return frames, errs
+			}
+			continue
+		}
+		frames = append(frames, frame)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	encoded, err := Encode([]byte{0x01, 0x02})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := []byte{SyncByte, 0x02, 0x01, 0x02, 0x01}; !bytes.Equal(encoded, expected) {
+		t.Errorf("Expected %x, got %x", expected, encoded)
+	}
+
+	if _, err := Encode(make([]byte, 256)); err == nil {
+		t.Error("Expected error for oversized payload, but got none")
+	}
+}
+
+func TestAutomaton(t *testing.T) {
+	fa, err := Automaton(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fsmtest.AssertAccepts(t, fa, string(encode(t, "")), string(encode(t, "ab", "abcd")))
+	fsmtest.AssertRejects(t, fa, "", string(encode(t, "ab")[:3]), string(encode(t, "abcde")))
+
+	if _, err := Automaton(256); err == nil {
+		t.Error("Expected error for maximum payload 256, but got none")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	stream := append([]byte("noise"), encode(t, "hello", "", "frame")...)
+	frames, errs := decodeAll(t, stream, 16)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	expected := []Frame{
+		{Offset: 5, Payload: []byte("hello")},
+		{Offset: 13, Payload: []byte{}},
+		{Offset: 16, Payload: []byte("frame")},
+	}
+	if !reflect.DeepEqual(frames, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, frames)
+	}
+}
+
+func TestDecoder_Checksum(t *testing.T) {
+	stream := encode(t, "bad", "good")
+	stream[5] ^= 0xFF
+
+	frames, errs := decodeAll(t, stream, 16)
+	if len(errs) != 1 || !errors.Is(errs[0], ErrChecksum) {
+		t.Fatalf("Expected one checksum error, got %v", errs)
+	}
+	if len(frames) != 1 || string(frames[0].Payload) != "good" {
+		t.Errorf("Expected the good frame after the bad one, got %+v", frames)
+	}
+}
+
+func TestDecoder_Resync(t *testing.T) {
+	stream := append([]byte{SyncByte, 0x40, SyncByte}, encode(t, "ok")[1:]...)
+	stream = append(stream, SyncByte, 0x20, 'x')
+	stream = append(stream, encode(t, "again")...)
+
+	frames, errs := decodeAll(t, stream, 8)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+	if len(frames) != 2 || frames[0].Offset != 2 || string(frames[0].Payload) != "ok" || string(frames[1].Payload) != "again" {
+		t.Errorf("Expected frames 'ok' at offset 2 and 'again', got %+v", frames)
+	}
+}
+
+func TestDecoder_Truncated(t *testing.T) {
+	stream := encode(t, "whole", "cut")
+	_, errs := decodeAll(t, stream[:len(stream)-2], 16)
+	if len(errs) != 1 || !errors.Is(errs[0], io.ErrUnexpectedEOF) {
+		t.Errorf("Expected unexpected EOF, got %v", errs)
+	}
+}
+
+func TestNewDecoder_InvalidMaxPayload(t *testing.T) {
+	if _, err := NewDecoder(bytes.NewReader(nil), -1); err == nil {
+		t.Error("Expected error for negative maximum payload, but got none")
+	}
+}
@@ -0,0 +1,51 @@
+package fsm
+
+import "strings"
+
+type SymbolNormalizer func(Symbol) Symbol
+
+func Aliases(aliases map[Symbol]Symbol) SymbolNormalizer {
+	return func(symbol Symbol) Symbol {
+		if canonical, ok := aliases[symbol]; ok {
+			return canonical
+		}
+		return symbol
+	}
+}
+
+func CaseInsensitive(alphabet []Symbol) SymbolNormalizer {
+	aliases := make(map[Symbol]Symbol)
+	for _, symbol := range alphabet {
+		for _, spelling := range []Symbol{Symbol(strings.ToLower(string(symbol))), Symbol(strings.ToUpper(string(symbol)))} {
+			if _, ok := aliases[spelling]; !ok {
+				aliases[spelling] = symbol
+			}
+		}
+	}
+	for _, symbol := range alphabet {
+		aliases[symbol] = symbol
+	}
+	return Aliases(aliases)
+}
+
+// OtherSymbols maps every symbol outside alphabet to Wildcard, for automata
+// whose alphabet uses Wildcard to stand for the rest of the input.
+func OtherSymbols(alphabet []Symbol) SymbolNormalizer {
+	known := make(map[Symbol]bool, len(alphabet))
+	for _, symbol := range alphabet {
+		known[symbol] = true
+	}
+	return func(symbol Symbol) Symbol {
+		if known[symbol] {
+			return symbol
+		}
+		return Wildcard
+	}
+}
+
+func (fa *FiniteAutomaton) normalize(symbol Symbol) Symbol {
+	if fa.Normalizer == nil {
+		return symbol
+	}
+	return fa.Normalizer(symbol)
+}
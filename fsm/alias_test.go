@@ -0,0 +1,96 @@
+package fsm
+
+import "testing"
+
+func newGCCounter() *FiniteAutomaton {
+	return NewTableAutomaton(
+		[]State{"even", "odd"},
+		[]Symbol{"A", "C", "G", "T"},
+		"even",
+		[]State{"even"},
+		TransitionTable{
+			"even": {"A": "even", "C": "odd", "G": "odd", "T": "even"},
+			"odd":  {"A": "odd", "C": "even", "G": "even", "T": "odd"},
+		},
+	)
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	fa := newGCCounter()
+	fa.Normalizer = CaseInsensitive(fa.Alphabet)
+
+	tests := []struct {
+		input    string
+		expected State
+	}{
+		{"GATTACAG", "odd"},
+		{"gattacag", "odd"},
+		{"GaTtAcAg", "odd"},
+		{"cg", "even"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			state, err := fa.ProcessInput(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if state != test.expected {
+				t.Errorf("Expected state %s, got %s", test.expected, state)
+			}
+		})
+	}
+
+	if _, err := fa.ProcessInput("gatu"); err == nil {
+		t.Error("Expected error for symbol outside the alphabet, but got none")
+	}
+}
+
+func TestAliases(t *testing.T) {
+	fa := newGCCounter()
+	fa.Normalizer = Aliases(map[Symbol]Symbol{"U": "T", "u": "T", "g": "G"})
+
+	state, err := fa.ProcessInput("UuGg")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "even" {
+		t.Errorf("Expected state even, got %s", state)
+	}
+
+	if _, err := fa.ProcessInput("a"); err == nil {
+		t.Error("Expected error for unaliased lowercase symbol, but got none")
+	}
+}
+
+func TestNormalizer_Runner(t *testing.T) {
+	fa := newGCCounter()
+	fa.Normalizer = CaseInsensitive(fa.Alphabet)
+	runner := NewRunner(fa, WithHistory())
+
+	if _, err := runner.Feed("gc"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	history := runner.History()
+	if len(history) != 2 || history[0].Symbol != "G" || history[1].Symbol != "C" {
+		t.Errorf("Expected history to record canonical symbols, got %v", history)
+	}
+}
+
+func TestNormalizer_Explain(t *testing.T) {
+	fa := newGCCounter()
+	fa.Normalizer = CaseInsensitive(fa.Alphabet)
+
+	if length, _ := fa.LongestAcceptedPrefix("cgx"); length != 2 {
+		t.Errorf("Expected longest accepted prefix 2, got %d", length)
+	}
+
+	frames, err := fa.Frames("ac")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if frames[2].Transition.Symbol != "C" {
+		t.Errorf("Expected canonical symbol C in frame, got %s", frames[2].Transition.Symbol)
+	}
+}
@@ -0,0 +1,43 @@
+package fsm
+
+import (
+	"fmt"
+)
+
+type Frame struct {
+	Step       int
+	Consumed   string
+	State      State
+	Transition *Transition
+}
+
+func (fa *FiniteAutomaton) Frames(input string) ([]Frame, error) {
+	frames := []Frame{{Step: 0, State: fa.InitialState}}
+	currentState := fa.InitialState
+
+	for i, char := range input {
+		symbol := fa.normalize(Symbol(string(char)))
+		if !fa.isValidSymbol(symbol) {
+			return nil, fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, fa.Alphabet)
+		}
+
+		next := fa.TransitionFunction(currentState, symbol)
+		frames = append(frames, Frame{
+			Step:       len(frames),
+			Consumed:   input[:i+len(string(char))],
+			State:      next,
+			Transition: &Transition{From: currentState, Symbol: symbol, To: next},
+		})
+		currentState = next
+	}
+
+	return frames, nil
+}
+
+func (fa *FiniteAutomaton) FrameDOT(frame Frame) string {
+	return fa.dot(&highlight{state: frame.State, transition: frame.Transition})
+}
+
+func (fa *FiniteAutomaton) FrameSVG(frame Frame) string {
+	return fa.svg(&highlight{state: frame.State, transition: frame.Transition})
+}
@@ -0,0 +1,81 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFrames(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	frames, err := fa.Frames("110")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		state    State
+		consumed string
+	}{
+		{"S0", ""},
+		{"S1", "1"},
+		{"S0", "11"},
+		{"S0", "110"},
+	}
+
+	if len(frames) != len(expected) {
+		t.Fatalf("Expected %d frames, got %d", len(expected), len(frames))
+	}
+	for i, frame := range frames {
+		if frame.Step != i || frame.State != expected[i].state || frame.Consumed != expected[i].consumed {
+			t.Errorf("Frame %d: expected step %d in %s after '%s', got %+v", i, i, expected[i].state, expected[i].consumed, frame)
+		}
+	}
+
+	if frames[0].Transition != nil {
+		t.Error("Initial frame should not have a transition")
+	}
+	if frames[2].Transition.From != "S1" || frames[2].Transition.Symbol != "1" {
+		t.Errorf("Unexpected transition in frame 2: %+v", frames[2].Transition)
+	}
+
+	if _, err := fa.Frames("12"); err == nil {
+		t.Error("Expected error for invalid input, but got none")
+	}
+}
+
+func TestFrameDOT(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	frames, _ := fa.Frames("10")
+
+	dot := fa.FrameDOT(frames[2])
+	if !strings.Contains(dot, `"S2" [shape=circle, style=filled, fillcolor=lightblue];`) {
+		t.Errorf("Expected active state S2 to be highlighted:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"S1" -> "S2" [label="0", color=red, penwidth=2];`) {
+		t.Errorf("Expected transition S1 -> S2 to be highlighted:\n%s", dot)
+	}
+	if strings.Count(dot, "fillcolor") != 1 || strings.Count(dot, "color=red") != 1 {
+		t.Errorf("Expected exactly one highlighted state and edge:\n%s", dot)
+	}
+
+	if fa.FrameDOT(frames[0]) == fa.DOT() {
+		t.Error("Initial frame should highlight the initial state")
+	}
+}
+
+func TestFrameSVG(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	frames, _ := fa.Frames("1")
+
+	svg := fa.FrameSVG(frames[1])
+	if strings.Count(svg, `fill="#a6d8ff"`) != 1 {
+		t.Errorf("Expected one highlighted state in SVG frame")
+	}
+	if strings.Count(svg, `stroke="red"`) != 1 {
+		t.Errorf("Expected one highlighted edge in SVG frame")
+	}
+	if strings.Contains(fa.SVG(), `stroke="red"`) {
+		t.Error("Plain SVG should not contain highlights")
+	}
+}
@@ -0,0 +1,245 @@
+package fsm
+
+import "fmt"
+
+// BuchiAutomaton is a nondeterministic automaton over infinite words. A word
+// is accepted if some run visits an accepting state infinitely often.
+type BuchiAutomaton struct {
+	States          []State
+	Alphabet        []Symbol
+	InitialState    State
+	AcceptingStates []State
+	Transitions     map[State]map[Symbol][]State
+
+	alphabetSet *symbolSet
+}
+
+func NewBuchiAutomaton(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+) *BuchiAutomaton {
+	return &BuchiAutomaton{
+		States:          states,
+		Alphabet:        alphabet,
+		InitialState:    initialState,
+		AcceptingStates: acceptingStates,
+		Transitions:     make(map[State]map[Symbol][]State),
+		alphabetSet:     newSymbolSet(alphabet),
+	}
+}
+
+func (b *BuchiAutomaton) AddTransition(from State, symbol Symbol, to State) {
+	if b.Transitions[from] == nil {
+		b.Transitions[from] = make(map[Symbol][]State)
+	}
+	for _, existing := range b.Transitions[from][symbol] {
+		if existing == to {
+			return
+		}
+	}
+	b.Transitions[from][symbol] = append(b.Transitions[from][symbol], to)
+}
+
+// AcceptsLasso reports whether the infinite word prefix·loop^ω is accepted.
+func (b *BuchiAutomaton) AcceptsLasso(prefix, loop string) (bool, error) {
+	if loop == "" {
+		return false, fmt.Errorf("loop of an infinite word must not be empty")
+	}
+	prefixSymbols, err := b.symbols(prefix)
+	if err != nil {
+		return false, err
+	}
+	loopSymbols, err := b.symbols(loop)
+	if err != nil {
+		return false, fmt.Errorf("loop: %w", err)
+	}
+
+	current := []State{b.InitialState}
+	for _, symbol := range prefixSymbols {
+		current = b.move(current, symbol)
+	}
+
+	// Runs over loop^ω are paths in the graph of (state, position in loop).
+	type node struct {
+		state    State
+		position int
+	}
+	successors := func(n node) []node {
+		var next []node
+		for _, to := range b.Transitions[n.state][loopSymbols[n.position]] {
+			next = append(next, node{to, (n.position + 1) % len(loopSymbols)})
+		}
+		return next
+	}
+	reachable := func(from []node) map[node]bool {
+		seen := make(map[node]bool)
+		stack := append([]node(nil), from...)
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, next := range successors(n) {
+				if !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		return seen
+	}
+
+	starts := make([]node, len(current))
+	for i, state := range current {
+		starts[i] = node{state, 0}
+	}
+	candidates := reachable(starts)
+	for _, start := range starts {
+		candidates[start] = true
+	}
+	for n := range candidates {
+		if b.isAccepting(n.state) && reachable([]node{n})[n] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// liveStates returns the states from which some run visits an accepting
+// state infinitely often on some input.
+func (b *BuchiAutomaton) liveStates() map[State]bool {
+	successors := func(state State) []State {
+		var next []State
+		for _, symbol := range b.Alphabet {
+			next = append(next, b.Transitions[state][symbol]...)
+		}
+		return next
+	}
+	reaches := func(from State, goal func(State) bool) bool {
+		seen := map[State]bool{}
+		stack := successors(from)
+		for len(stack) > 0 {
+			state := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if goal(state) {
+				return true
+			}
+			if !seen[state] {
+				seen[state] = true
+				stack = append(stack, successors(state)...)
+			}
+		}
+		return false
+	}
+
+	recurrent := map[State]bool{}
+	for _, state := range b.AcceptingStates {
+		target := state
+		if reaches(target, func(s State) bool { return s == target }) {
+			recurrent[target] = true
+		}
+	}
+
+	live := map[State]bool{}
+	for _, state := range b.States {
+		if recurrent[state] || reaches(state, func(s State) bool { return recurrent[s] }) {
+			live[state] = true
+		}
+	}
+	return live
+}
+
+func (b *BuchiAutomaton) move(states []State, symbol Symbol) []State {
+	seen := make(map[State]bool)
+	for _, state := range states {
+		for _, next := range b.Transitions[state][symbol] {
+			seen[next] = true
+		}
+	}
+
+	result := make([]State, 0, len(seen))
+	for _, state := range b.States {
+		if seen[state] {
+			result = append(result, state)
+		}
+	}
+	return result
+}
+
+func (b *BuchiAutomaton) symbols(input string) ([]Symbol, error) {
+	var symbols []Symbol
+	for i, char := range input {
+		symbol := Symbol(string(char))
+		if !b.isValidSymbol(symbol) {
+			return nil, fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, b.Alphabet)
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, nil
+}
+
+func (b *BuchiAutomaton) isAccepting(state State) bool {
+	for _, accepting := range b.AcceptingStates {
+		if state == accepting {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BuchiAutomaton) isValidSymbol(symbol Symbol) bool {
+	return containsSymbol(b.alphabetSet, b.Alphabet, symbol)
+}
+
+// BuchiMonitor follows every run of a Büchi automaton over an event stream
+// that is observed one symbol at a time.
+type BuchiMonitor struct {
+	automaton *BuchiAutomaton
+	live      map[State]bool
+	current   []State
+	observed  int
+}
+
+func (b *BuchiAutomaton) Monitor() *BuchiMonitor {
+	return &BuchiMonitor{
+		automaton: b,
+		live:      b.liveStates(),
+		current:   []State{b.InitialState},
+	}
+}
+
+func (m *BuchiMonitor) Observe(symbol Symbol) error {
+	if !m.automaton.isValidSymbol(symbol) {
+		return fmt.Errorf("invalid symbol '%s' at event %d: not in alphabet %v", symbol, m.observed, m.automaton.Alphabet)
+	}
+	m.current = m.automaton.move(m.current, symbol)
+	m.observed++
+	return nil
+}
+
+// Violated reports whether no run survives the events so far: some event had
+// no transition from any current state.
+func (m *BuchiMonitor) Violated() bool {
+	return len(m.current) == 0
+}
+
+// CannotAccept reports whether no continuation of the events so far can be
+// accepted, because no surviving run can still visit an accepting state
+// infinitely often. It is true whenever Violated is.
+func (m *BuchiMonitor) CannotAccept() bool {
+	for _, state := range m.current {
+		if m.live[state] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *BuchiMonitor) States() []State {
+	return append([]State(nil), m.current...)
+}
+
+func (m *BuchiMonitor) Reset() {
+	m.current = []State{m.automaton.InitialState}
+	m.observed = 0
+}
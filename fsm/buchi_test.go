@@ -0,0 +1,113 @@
+package fsm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuchiAutomaton_AcceptsLasso(t *testing.T) {
+	// Infinitely many a.
+	infinitelyOften := NewBuchiAutomaton([]State{"Other", "SawA"}, []Symbol{"a", "b"}, "Other", []State{"SawA"})
+	for _, from := range infinitelyOften.States {
+		infinitelyOften.AddTransition(from, "a", "SawA")
+		infinitelyOften.AddTransition(from, "b", "Other")
+	}
+
+	// Finitely many b: guess when the last b has been read.
+	eventuallyAlways := NewBuchiAutomaton([]State{"Any", "OnlyA"}, []Symbol{"a", "b"}, "Any", []State{"OnlyA"})
+	eventuallyAlways.AddTransition("Any", "a", "Any")
+	eventuallyAlways.AddTransition("Any", "b", "Any")
+	eventuallyAlways.AddTransition("Any", "a", "OnlyA")
+	eventuallyAlways.AddTransition("OnlyA", "a", "OnlyA")
+
+	tests := []struct {
+		automaton *BuchiAutomaton
+		prefix    string
+		loop      string
+		expected  bool
+	}{
+		{infinitelyOften, "", "ab", true},
+		{infinitelyOften, "aaa", "b", false},
+		{infinitelyOften, "bb", "bba", true},
+		{eventuallyAlways, "babb", "a", true},
+		{eventuallyAlways, "", "ab", false},
+		{eventuallyAlways, "a", "aab", false},
+	}
+
+	for _, test := range tests {
+		accepted, err := test.automaton.AcceptsLasso(test.prefix, test.loop)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s(%s)^ω: %v", test.prefix, test.loop, err)
+		}
+		if accepted != test.expected {
+			t.Errorf("For %s(%s)^ω: expected accepted=%v, got %v", test.prefix, test.loop, test.expected, accepted)
+		}
+	}
+
+	if _, err := infinitelyOften.AcceptsLasso("a", ""); err == nil {
+		t.Error("Expected error for empty loop, but got none")
+	}
+	if _, err := infinitelyOften.AcceptsLasso("", "ac"); err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+}
+
+// newRequestGrant accepts streams where grants only follow requests and every
+// request is eventually granted. With trap set, a stray grant leads to a
+// non-accepting sink instead of killing the run.
+func newRequestGrant(trap bool) *BuchiAutomaton {
+	b := NewBuchiAutomaton([]State{"Idle", "Pending", "Error"}, []Symbol{"r", "g", "i"}, "Idle", []State{"Idle"})
+	b.AddTransition("Idle", "i", "Idle")
+	b.AddTransition("Idle", "r", "Pending")
+	b.AddTransition("Pending", "r", "Pending")
+	b.AddTransition("Pending", "i", "Pending")
+	b.AddTransition("Pending", "g", "Idle")
+	if trap {
+		b.AddTransition("Idle", "g", "Error")
+		for _, symbol := range b.Alphabet {
+			b.AddTransition("Error", symbol, "Error")
+		}
+	}
+	return b
+}
+
+func TestBuchiMonitor(t *testing.T) {
+	monitor := newRequestGrant(false).Monitor()
+
+	for _, symbol := range []Symbol{"i", "r", "i", "g"} {
+		if err := monitor.Observe(symbol); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if monitor.Violated() || monitor.CannotAccept() {
+		t.Errorf("Expected no verdict yet, got violated=%v cannotAccept=%v", monitor.Violated(), monitor.CannotAccept())
+	}
+	if !reflect.DeepEqual(monitor.States(), []State{"Idle"}) {
+		t.Errorf("Expected current states [Idle], got %v", monitor.States())
+	}
+
+	monitor.Observe("g")
+	if !monitor.Violated() || !monitor.CannotAccept() {
+		t.Errorf("Expected a stray grant to violate, got violated=%v cannotAccept=%v", monitor.Violated(), monitor.CannotAccept())
+	}
+
+	monitor.Reset()
+	if monitor.Violated() {
+		t.Error("Expected reset monitor not to be violated")
+	}
+	if err := monitor.Observe("x"); err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+}
+
+func TestBuchiMonitor_CannotAcceptBeforeViolation(t *testing.T) {
+	monitor := newRequestGrant(true).Monitor()
+
+	monitor.Observe("g")
+	if monitor.Violated() {
+		t.Error("Expected the run to survive in the trap state")
+	}
+	if !monitor.CannotAccept() {
+		t.Error("Expected no accepting continuation from the trap state")
+	}
+}
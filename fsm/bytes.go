@@ -0,0 +1,86 @@
+package fsm
+
+import "fmt"
+
+func AnyByte() SymbolSet {
+	return Range(0, 255)
+}
+
+func ByteSymbol(b byte) Symbol {
+	return Symbol(string(rune(b)))
+}
+
+func ExtendedByteAlphabet() []Symbol {
+	alphabet := make([]Symbol, 256)
+	for b := range alphabet {
+		alphabet[b] = ByteSymbol(byte(b))
+	}
+	return alphabet
+}
+
+func NewByteAutomaton(states []State, initialState State, acceptingStates []State, transitions []ClassTransition) (*FiniteAutomaton, error) {
+	return NewClassAutomaton(states, ExtendedByteAlphabet(), initialState, acceptingStates, transitions)
+}
+
+func (fa *FiniteAutomaton) ProcessBytes(data []byte) (State, error) {
+	currentState := fa.InitialState
+	debug := debugEnabled(fa.Logger)
+	if run, ok := fa.fastPath(debug); ok {
+		if finalState, ok := run.processBytes(data); ok {
+			return finalState, nil
+		}
+	}
+
+	for i, b := range data {
+		symbol := fa.normalize(ByteSymbol(b))
+
+		if !fa.isValidSymbol(symbol) {
+			logInvalidSymbol(fa.Logger, currentState, symbol, i)
+			return "", fmt.Errorf("invalid byte %q at position %d: not in alphabet", b, i)
+		}
+
+		next := fa.TransitionFunction(currentState, symbol)
+		if debug {
+			logTransition(fa.Logger, Transition{From: currentState, Symbol: symbol, To: next}, i)
+		}
+		currentState = next
+	}
+
+	return currentState, nil
+}
+
+func (fa *FiniteAutomaton) AcceptsBytes(data []byte) (bool, error) {
+	finalState, err := fa.ProcessBytes(data)
+	if err != nil {
+		return false, err
+	}
+	return fa.IsAcceptingState(finalState), nil
+}
+
+// StepByte advances the runner on a single byte. When the automaton is a
+// table over every byte value, as built by NewByteAutomaton, it looks the
+// byte up directly instead of searching the alphabet like Step does.
+func (r *Runner) StepByte(b byte) (State, error) {
+	symbol := ByteSymbol(b)
+	if r.automaton.Table == nil || r.automaton.Normalizer != nil || !r.coversBytes() {
+		return r.Step(symbol)
+	}
+
+	r.advance(symbol, r.automaton.Table.Next(r.currentState, symbol))
+	return r.currentState, nil
+}
+
+func (r *Runner) coversBytes() bool {
+	if r.byteAlphabet == nil {
+		inAlphabet := make(map[Symbol]bool, len(r.automaton.Alphabet))
+		for _, symbol := range r.automaton.Alphabet {
+			inAlphabet[symbol] = true
+		}
+		covers := true
+		for b := 0; b < 256 && covers; b++ {
+			covers = inAlphabet[ByteSymbol(byte(b))]
+		}
+		r.byteAlphabet = &covers
+	}
+	return *r.byteAlphabet
+}
@@ -0,0 +1,137 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func newNumberScanner(t *testing.T) *FiniteAutomaton {
+	t.Helper()
+
+	fa, err := NewByteAutomaton(
+		[]State{"Start", "Int", "Dot", "Frac", "Reject"},
+		"Start",
+		[]State{"Int", "Frac"},
+		[]ClassTransition{
+			{From: "Start", Class: Digits(), To: "Int"},
+			{From: "Int", Class: Digits(), To: "Int"},
+			{From: "Int", Class: Chars("."), To: "Dot"},
+			{From: "Dot", Class: Digits(), To: "Frac"},
+			{From: "Frac", Class: Digits(), To: "Frac"},
+			{From: "Start", Default: true, To: "Reject"},
+			{From: "Int", Default: true, To: "Reject"},
+			{From: "Dot", Default: true, To: "Reject"},
+			{From: "Frac", Default: true, To: "Reject"},
+			{From: "Reject", Default: true, To: "Reject"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return fa
+}
+
+func TestNewByteAutomaton(t *testing.T) {
+	fa := newNumberScanner(t)
+
+	if len(fa.Alphabet) != 256 || !AnyByte().Contains(ByteSymbol(0xFF)) {
+		t.Errorf("Expected 256 symbols, got %d", len(fa.Alphabet))
+	}
+
+	tests := []struct {
+		input         []byte
+		expectedState State
+		accepted      bool
+	}{
+		{[]byte("42"), "Int", true},
+		{[]byte("3.14"), "Frac", true},
+		{[]byte("3."), "Dot", false},
+		{[]byte("3.1.4"), "Reject", false},
+		{[]byte("x1"), "Reject", false},
+		{[]byte{'1', 0xFF}, "Reject", false},
+		{[]byte{}, "Start", false},
+	}
+
+	for _, test := range tests {
+		finalState, err := fa.ProcessBytes(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if finalState != test.expectedState {
+			t.Errorf("For input %q: expected final state %s, got %s", test.input, test.expectedState, finalState)
+		}
+		if accepted, _ := fa.AcceptsBytes(test.input); accepted != test.accepted {
+			t.Errorf("For input %q: expected accepted=%v, got %v", test.input, test.accepted, accepted)
+		}
+	}
+
+	if finalState, err := fa.ProcessInput("2.5"); err != nil || finalState != "Frac" {
+		t.Errorf("Expected ASCII strings to work with ProcessInput, got %s (%v)", finalState, err)
+	}
+}
+
+func TestNewByteAutomaton_Errors(t *testing.T) {
+	states := []State{"A", "B"}
+	tests := []struct {
+		description string
+		initial     State
+		accepting   []State
+		transitions []ClassTransition
+	}{
+		{"unknown initial", "Z", nil, nil},
+		{"unknown accepting", "A", []State{"Z"}, nil},
+		{"unknown source", "A", nil, []ClassTransition{{From: "Z", Class: Digits(), To: "A"}}},
+		{"unknown target", "A", nil, []ClassTransition{{From: "A", Class: Digits(), To: "Z"}}},
+		{"empty class", "A", nil, []ClassTransition{{From: "A", Class: Range('9', '0'), To: "B"}}},
+		{"symbol outside alphabet", "A", nil, []ClassTransition{{From: "A", Class: Chars("é€"), To: "B"}}},
+		{"default with class", "A", nil, []ClassTransition{{From: "A", Class: Digits(), Default: true, To: "B"}}},
+		{"overlapping classes", "A", nil, []ClassTransition{
+			{From: "A", Class: Letters(), To: "A"},
+			{From: "A", Class: Range('x', 'z'), To: "B"},
+		}},
+		{"conflicting defaults", "A", nil, []ClassTransition{{From: "A", Default: true, To: "A"}, {From: "A", Default: true, To: "B"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if _, err := NewByteAutomaton(states, test.initial, test.accepting, test.transitions); err == nil {
+				t.Errorf("Expected error for %s, but got none", test.description)
+			}
+		})
+	}
+}
+
+func TestRunner_StepByte(t *testing.T) {
+	fa := newNumberScanner(t)
+	runner := NewRunner(fa, WithHistory())
+
+	for _, b := range []byte("12.5") {
+		if _, err := runner.StepByte(b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if runner.CurrentState() != "Frac" {
+		t.Errorf("Expected state Frac, got %s", runner.CurrentState())
+	}
+	if len(runner.History()) != 4 || runner.History()[2].Symbol != "." {
+		t.Errorf("Expected 4 recorded transitions, got %v", runner.History())
+	}
+
+	runner.StepByte(0xFF)
+	if runner.CurrentState() != "Reject" {
+		t.Errorf("Expected state Reject, got %s", runner.CurrentState())
+	}
+}
+
+func TestRunner_StepByteFallsBackToStep(t *testing.T) {
+	table := TransitionTable{}
+	table.SetDefault("A", "A")
+	fa := NewTableAutomaton([]State{"A"}, []Symbol{"a"}, "A", []State{"A"}, table)
+	runner := NewRunner(fa)
+
+	if _, err := runner.StepByte('a'); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := runner.StepByte('b'); err == nil {
+		t.Error("Expected error for byte outside the alphabet, but got none")
+	}
+}
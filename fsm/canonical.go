@@ -0,0 +1,51 @@
+package fsm
+
+import "fmt"
+
+func (fa *FiniteAutomaton) Canonicalize() *FiniteAutomaton {
+	names := make(map[State]State, len(fa.States))
+	var order []State
+	visit := func(state State) {
+		if _, seen := names[state]; !seen {
+			names[state] = State(fmt.Sprintf("Q%d", len(order)))
+			order = append(order, state)
+		}
+	}
+
+	explored := 0
+	explore := func(root State) {
+		visit(root)
+		for ; explored < len(order); explored++ {
+			for _, symbol := range fa.Alphabet {
+				visit(fa.TransitionFunction(order[explored], symbol))
+			}
+		}
+	}
+
+	explore(fa.InitialState)
+	for _, state := range fa.States {
+		explore(state)
+	}
+
+	states := make([]State, len(order))
+	table := make(TransitionTable, len(order))
+	for i, state := range order {
+		states[i] = names[state]
+		for _, symbol := range fa.Alphabet {
+			table.Set(names[state], symbol, names[fa.TransitionFunction(state, symbol)])
+		}
+	}
+
+	var accepting []State
+	for _, state := range order {
+		if fa.IsAcceptingState(state) {
+			accepting = append(accepting, names[state])
+		}
+	}
+
+	canonical := NewTableAutomaton(states, fa.Alphabet, names[fa.InitialState], accepting, table)
+	canonical.Logger = fa.Logger
+	canonical.Normalizer = fa.Normalizer
+	canonical.Decoder = fa.Decoder
+	return canonical
+}
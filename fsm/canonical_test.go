@@ -0,0 +1,70 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func TestFiniteAutomaton_Canonicalize(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("zero", "0", "zero")
+	table.Set("zero", "1", "one")
+	table.Set("one", "0", "two")
+	table.Set("one", "1", "zero")
+	table.Set("two", "0", "one")
+	table.Set("two", "1", "two")
+	table.Set("orphan", "0", "stray")
+	table.Set("orphan", "1", "orphan")
+	table.Set("stray", "0", "stray")
+	table.Set("stray", "1", "stray")
+
+	original := NewTableAutomaton([]State{"orphan", "two", "one", "zero", "stray"}, []Symbol{"0", "1"}, "zero", []State{"zero"}, table)
+	canonical := original.Canonicalize()
+
+	expectedStates := []State{"Q0", "Q1", "Q2", "Q3", "Q4"}
+	for i, state := range canonical.States {
+		if state != expectedStates[i] {
+			t.Errorf("Expected state %d to be %s, got %s", i, expectedStates[i], state)
+		}
+	}
+	if canonical.InitialState != "Q0" || len(canonical.AcceptingStates) != 1 || canonical.AcceptingStates[0] != "Q0" {
+		t.Errorf("Expected Q0 to be initial and accepting, got %s %v", canonical.InitialState, canonical.AcceptingStates)
+	}
+
+	tests := []struct {
+		from     State
+		symbol   Symbol
+		expected State
+	}{
+		{"Q0", "1", "Q1"},
+		{"Q1", "0", "Q2"},
+		{"Q3", "0", "Q4"},
+		{"Q4", "1", "Q4"},
+	}
+	for _, test := range tests {
+		if next := canonical.TransitionFunction(test.from, test.symbol); next != test.expected {
+			t.Errorf("Expected %s --%s--> %s, got %s", test.from, test.symbol, test.expected, next)
+		}
+	}
+
+	if equal, _, err := Equivalent(original, canonical); err != nil || !equal {
+		t.Error("Expected the canonical automaton to be equivalent")
+	}
+}
+
+func TestFiniteAutomaton_CanonicalizeIsStable(t *testing.T) {
+	a := NewTableAutomaton([]State{"A", "B"}, []Symbol{"x"}, "A", []State{"B"}, TransitionTable{
+		"A": {"x": "B"},
+		"B": {"x": "A"},
+	})
+	b := NewTableAutomaton([]State{"second", "first"}, []Symbol{"x"}, "first", []State{"second"}, TransitionTable{
+		"first":  {"x": "second"},
+		"second": {"x": "first"},
+	})
+
+	if a.Canonicalize().DOT() != b.Canonicalize().DOT() {
+		t.Error("Expected renamed automata to canonicalize identically")
+	}
+	if a.Canonicalize().DOT() != a.Canonicalize().Canonicalize().DOT() {
+		t.Error("Expected canonicalization to be idempotent")
+	}
+}
@@ -0,0 +1,145 @@
+package fsm
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	lowBytes   = 0x0101010101010101
+	packBits   = 0x0102040810204080
+	chunkWidth = 8
+)
+
+// BinaryChunker runs an automaton over a two-symbol alphabet of single ASCII
+// characters eight symbols at a time. It precomputes, for every state and
+// every 8-bit pattern, the state reached after those eight symbols, so a long
+// bitstring costs one table lookup per eight input bytes.
+type BinaryChunker struct {
+	fa      *FiniteAutomaton
+	symbols [2]byte
+	states  []State
+	initial int32
+
+	// single[state*2+bit] is one step; chunks[state*256+pattern] is eight,
+	// where bit k of pattern is the k-th symbol of the chunk.
+	single []int32
+	chunks []int32
+
+	// SWAR validation: input XOR zeros leaves each byte 0 or diff, and diff
+	// is a single bit shifted down by shift.
+	zeros uint64
+	diff  byte
+	shift uint
+}
+
+// NewBinaryChunker builds the chunk tables for fa. The alphabet must be two
+// single ASCII characters, every transition must stay within the declared
+// states, and states*256 must not exceed the interned table cap.
+func NewBinaryChunker(fa *FiniteAutomaton) (*BinaryChunker, error) {
+	if len(fa.Alphabet) != 2 {
+		return nil, fmt.Errorf("chunked processing needs a two-symbol alphabet, got %v", fa.Alphabet)
+	}
+	if fa.Normalizer != nil {
+		return nil, fmt.Errorf("chunked processing does not support symbol normalizers")
+	}
+	if len(fa.States)*256 > maxInternedTransitions {
+		return nil, fmt.Errorf("too many states for chunked processing: %d", len(fa.States))
+	}
+
+	c := &BinaryChunker{fa: fa, states: fa.States}
+	for i, symbol := range fa.Alphabet {
+		if len(symbol) != 1 || symbol[0] >= 0x80 {
+			return nil, fmt.Errorf("chunked processing needs single ASCII symbols, got '%s'", symbol)
+		}
+		c.symbols[i] = symbol[0]
+	}
+	if c.symbols[0] == c.symbols[1] {
+		return nil, fmt.Errorf("chunked processing needs two distinct symbols, got %v", fa.Alphabet)
+	}
+
+	ids := make(map[State]int32, len(fa.States))
+	for i, state := range fa.States {
+		ids[state] = int32(i)
+	}
+	initial, ok := ids[fa.InitialState]
+	if !ok {
+		return nil, fmt.Errorf("initial state '%s' is not declared", fa.InitialState)
+	}
+	c.initial = initial
+
+	c.single = make([]int32, 2*len(fa.States))
+	for s, state := range fa.States {
+		for bit, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			id, ok := ids[next]
+			if !ok {
+				return nil, fmt.Errorf("transition from '%s' on '%s' leads to undeclared state '%s'", state, symbol, next)
+			}
+			c.single[2*s+bit] = id
+		}
+	}
+
+	// Double the block length three times: 1, 2, 4, then 8 symbols.
+	table, width := c.single, 2
+	for width < 256 {
+		doubled := make([]int32, len(fa.States)*width*width)
+		for s := range fa.States {
+			for pattern := 0; pattern < width*width; pattern++ {
+				mid := table[s*width+pattern%width]
+				doubled[s*width*width+pattern] = table[int(mid)*width+pattern/width]
+			}
+		}
+		table, width = doubled, width*width
+	}
+	c.chunks = table
+
+	c.diff = c.symbols[0] ^ c.symbols[1]
+	c.zeros = uint64(c.symbols[0]) * lowBytes
+	c.shift = uint(bits.TrailingZeros8(c.diff))
+	return c, nil
+}
+
+// Process returns the final state for input. Invalid input is handed to the
+// automaton's ProcessInput, so the error is the one it reports.
+func (c *BinaryChunker) Process(input string) (State, error) {
+	state, ok := c.run(input)
+	if !ok {
+		return c.fa.ProcessInput(input)
+	}
+	return c.states[state], nil
+}
+
+func (c *BinaryChunker) run(input string) (int32, bool) {
+	state := c.initial
+	i := 0
+	if bits.OnesCount8(c.diff) == 1 {
+		for ; i+chunkWidth <= len(input); i += chunkWidth {
+			x := load64(input[i:i+chunkWidth]) ^ c.zeros
+			if x&^(uint64(c.diff)*lowBytes) != 0 {
+				return 0, false
+			}
+			state = c.chunks[int(state)<<8|int((x>>c.shift)*packBits>>56)]
+		}
+	}
+
+	for ; i < len(input); i++ {
+		switch input[i] {
+		case c.symbols[0]:
+			state = c.single[2*state]
+		case c.symbols[1]:
+			state = c.single[2*state+1]
+		default:
+			return 0, false
+		}
+	}
+	return state, true
+}
+
+// load64 reads eight bytes little-endian; the compiler merges it into a
+// single load.
+func load64(b string) uint64 {
+	_ = b[7]
+	return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16 | uint64(b[3])<<24 |
+		uint64(b[4])<<32 | uint64(b[5])<<40 | uint64(b[6])<<48 | uint64(b[7])<<56
+}
@@ -0,0 +1,132 @@
+package fsm
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func newModTableAutomaton(modulus int, alphabet []Symbol) *FiniteAutomaton {
+	states := make([]State, modulus)
+	for r := range states {
+		states[r] = State("R" + string(rune('a'+r)))
+	}
+	table := TransitionTable{}
+	for r, state := range states {
+		for digit, symbol := range alphabet {
+			table.Set(state, symbol, states[(2*r+digit)%modulus])
+		}
+	}
+	return NewTableAutomaton(states, alphabet, states[0], states[:1], table)
+}
+
+func randomInput(r *rand.Rand, alphabet []Symbol, length int) string {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteString(string(alphabet[r.Intn(len(alphabet))]))
+	}
+	return sb.String()
+}
+
+func TestBinaryChunker_MatchesProcessInput(t *testing.T) {
+	alphabets := map[string][]Symbol{
+		"binary":   {"0", "1"},
+		"reversed": {"1", "0"},
+		"letters":  {"x", "y"},
+		"no_swar":  {"a", "b"},
+	}
+	r := rand.New(rand.NewSource(1))
+
+	for name, alphabet := range alphabets {
+		t.Run(name, func(t *testing.T) {
+			fa := newModTableAutomaton(7, alphabet)
+			chunker, err := NewBinaryChunker(fa)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for length := 0; length <= 40; length++ {
+				input := randomInput(r, alphabet, length)
+				expected, err := fa.ProcessInput(input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				state, err := chunker.Process(input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if state != expected {
+					t.Errorf("Input '%s': expected %s, got %s", input, expected, state)
+				}
+			}
+		})
+	}
+}
+
+func TestBinaryChunker_InvalidInput(t *testing.T) {
+	fa := newModTableAutomaton(3, []Symbol{"0", "1"})
+	chunker, err := NewBinaryChunker(fa)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, input := range []string{"0101010121010101", "01010101010101010101012", "0000000é"} {
+		_, expected := fa.ProcessInput(input)
+		_, err := chunker.Process(input)
+		if err == nil {
+			t.Fatalf("Expected error for '%s', but got none", input)
+		}
+		if err.Error() != expected.Error() {
+			t.Errorf("Expected error %q, got %q", expected, err)
+		}
+	}
+}
+
+func TestNewBinaryChunker_Errors(t *testing.T) {
+	normalized := newModTableAutomaton(3, []Symbol{"0", "1"})
+	normalized.Normalizer = CaseInsensitive(normalized.Alphabet)
+
+	undeclared := newModTableAutomaton(3, []Symbol{"0", "1"})
+	undeclared.Table.Set(undeclared.States[1], "1", "Elsewhere")
+
+	huge := newModTableAutomaton(2, []Symbol{"0", "1"})
+	huge.States = make([]State, maxInternedTransitions/256+1)
+
+	tests := map[string]*FiniteAutomaton{
+		"three_symbols": newModTableAutomaton(3, []Symbol{"0", "1", "2"}),
+		"multi_byte":    newModTableAutomaton(3, []Symbol{"0", "→"}),
+		"normalizer":    normalized,
+		"undeclared":    undeclared,
+		"too_many":      huge,
+	}
+
+	for name, fa := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewBinaryChunker(fa); err == nil {
+				t.Errorf("Expected error for %s, but got none", name)
+			}
+		})
+	}
+}
+
+func BenchmarkBinaryChunker(b *testing.B) {
+	fa := newModTableAutomaton(7, []Symbol{"0", "1"})
+	input := randomInput(rand.New(rand.NewSource(1)), fa.Alphabet, 1<<16)
+	chunker, err := NewBinaryChunker(fa)
+	if err != nil {
+		b.Fatalf("Unexpected error: %v", err)
+	}
+
+	b.Run("ProcessInput", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			fa.ProcessInput(input)
+		}
+	})
+	b.Run("chunked", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			chunker.Process(input)
+		}
+	})
+}
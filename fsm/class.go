@@ -0,0 +1,147 @@
+package fsm
+
+import "fmt"
+
+type SymbolSet []Symbol
+
+type ClassTransition struct {
+	From    State
+	Class   SymbolSet
+	Default bool
+	To      State
+}
+
+func Range(lo, hi rune) SymbolSet {
+	var set SymbolSet
+	for r := lo; r <= hi; r++ {
+		set = append(set, Symbol(string(r)))
+	}
+	return set
+}
+
+func Chars(chars string) SymbolSet {
+	var set SymbolSet
+	for _, r := range chars {
+		set = append(set, Symbol(string(r)))
+	}
+	return Union(set)
+}
+
+func Union(sets ...SymbolSet) SymbolSet {
+	seen := make(map[Symbol]bool)
+	var union SymbolSet
+	for _, set := range sets {
+		for _, symbol := range set {
+			if !seen[symbol] {
+				seen[symbol] = true
+				union = append(union, symbol)
+			}
+		}
+	}
+	return union
+}
+
+func Digits() SymbolSet {
+	return Range('0', '9')
+}
+
+func Letters() SymbolSet {
+	return Union(Range('a', 'z'), Range('A', 'Z'))
+}
+
+func Whitespace() SymbolSet {
+	return Chars(" \t\n\r\f\v")
+}
+
+func (s SymbolSet) Contains(symbol Symbol) bool {
+	for _, member := range s {
+		if member == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func ParseClass(class string, alphabet []Symbol) (SymbolSet, error) {
+	p := &regexParser{pattern: []rune(class), alphabet: alphabet}
+	if len(p.pattern) == 0 || p.pattern[0] != '[' {
+		return nil, fmt.Errorf("class %q must start with '['", class)
+	}
+
+	node, err := p.parseClass()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.pattern) {
+		return nil, fmt.Errorf("unexpected %q after class %q", string(p.pattern[p.pos:]), class)
+	}
+	return SymbolSet(node.symbols), nil
+}
+
+func (t TransitionTable) SetClass(from State, class SymbolSet, to State) {
+	for _, symbol := range class {
+		t.Set(from, symbol, to)
+	}
+}
+
+func NewClassAutomaton(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+	transitions []ClassTransition,
+) (*FiniteAutomaton, error) {
+	known := make(map[State]bool, len(states))
+	for _, state := range states {
+		known[state] = true
+	}
+	if !known[initialState] {
+		return nil, fmt.Errorf("initial state '%s' is not declared", initialState)
+	}
+	for _, state := range acceptingStates {
+		if !known[state] {
+			return nil, fmt.Errorf("accepting state '%s' is not declared", state)
+		}
+	}
+
+	inAlphabet := make(map[Symbol]bool, len(alphabet))
+	for _, symbol := range alphabet {
+		inAlphabet[symbol] = true
+	}
+
+	table := make(TransitionTable)
+	for _, transition := range transitions {
+		if !known[transition.From] {
+			return nil, fmt.Errorf("transition from undeclared state '%s'", transition.From)
+		}
+		if !known[transition.To] {
+			return nil, fmt.Errorf("transition to undeclared state '%s'", transition.To)
+		}
+
+		if transition.Default {
+			if len(transition.Class) > 0 {
+				return nil, fmt.Errorf("default transition from '%s' must not have a class", transition.From)
+			}
+			if existing, ok := table.Default(transition.From); ok && existing != transition.To {
+				return nil, fmt.Errorf("conflicting default transitions from '%s' to '%s' and '%s'", transition.From, existing, transition.To)
+			}
+			table.SetDefault(transition.From, transition.To)
+			continue
+		}
+
+		if len(transition.Class) == 0 {
+			return nil, fmt.Errorf("transition from '%s' to '%s' has an empty class", transition.From, transition.To)
+		}
+		for _, symbol := range transition.Class {
+			if !inAlphabet[symbol] {
+				return nil, fmt.Errorf("transition from '%s' on symbol %q not in alphabet", transition.From, symbol)
+			}
+			if existing, ok := table[transition.From][symbol]; ok && existing != transition.To {
+				return nil, fmt.Errorf("conflicting transitions from '%s' on %q to '%s' and '%s'", transition.From, symbol, existing, transition.To)
+			}
+		}
+		table.SetClass(transition.From, transition.Class, transition.To)
+	}
+
+	return NewTableAutomaton(states, alphabet, initialState, acceptingStates, table), nil
+}
@@ -0,0 +1,107 @@
+package fsm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSymbolSet(t *testing.T) {
+	tests := []struct {
+		set      SymbolSet
+		symbol   Symbol
+		expected bool
+	}{
+		{Digits(), "7", true},
+		{Digits(), "a", false},
+		{Letters(), "Q", true},
+		{Letters(), "_", false},
+		{Whitespace(), "\t", true},
+		{Range('α', 'ω'), "λ", true},
+		{Union(Letters(), Chars("_"), Digits()), "_", true},
+	}
+
+	for _, test := range tests {
+		if got := test.set.Contains(test.symbol); got != test.expected {
+			t.Errorf("Expected %v.Contains(%q) to be %v, got %v", test.set, test.symbol, test.expected, got)
+		}
+	}
+
+	if union := Union(Chars("abca"), Range('b', 'd')); !reflect.DeepEqual(union, SymbolSet{"a", "b", "c", "d"}) {
+		t.Errorf("Expected deduplicated union [a b c d], got %v", union)
+	}
+}
+
+func TestParseClass(t *testing.T) {
+	alphabet := ASCIIAlphabet()
+
+	tests := []struct {
+		class    string
+		expected SymbolSet
+	}{
+		{"[0-3]", SymbolSet{"0", "1", "2", "3"}},
+		{"[xa-c]", SymbolSet{"a", "b", "c", "x"}},
+		{`[\d]`, Digits()},
+	}
+
+	for _, test := range tests {
+		set, err := ParseClass(test.class, alphabet)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.class, err)
+		}
+		if !reflect.DeepEqual(set, test.expected) {
+			t.Errorf("For class %s: expected %v, got %v", test.class, test.expected, set)
+		}
+	}
+
+	negated, err := ParseClass("[^0-9]", []Symbol{"0", "5", "a", "b"})
+	if err != nil || !reflect.DeepEqual(negated, SymbolSet{"a", "b"}) {
+		t.Errorf("Expected negation relative to the alphabet, got %v (%v)", negated, err)
+	}
+
+	for _, class := range []string{"0-9", "[0-9", "[9-0]", "[0-9]x", ""} {
+		if _, err := ParseClass(class, alphabet); err == nil {
+			t.Errorf("Expected error for class %q, but got none", class)
+		}
+	}
+}
+
+func TestNewClassAutomaton(t *testing.T) {
+	fa, err := NewClassAutomaton(
+		[]State{"Start", "Ident", "Reject"},
+		ASCIIAlphabet(),
+		"Start",
+		[]State{"Ident"},
+		[]ClassTransition{
+			{From: "Start", Class: Union(Letters(), Chars("_")), To: "Ident"},
+			{From: "Ident", Class: Union(Letters(), Digits(), Chars("_")), To: "Ident"},
+			{From: "Start", Default: true, To: "Reject"},
+			{From: "Ident", Default: true, To: "Reject"},
+			{From: "Reject", Default: true, To: "Reject"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"snake_case", true},
+		{"_private", true},
+		{"x9", true},
+		{"9x", false},
+		{"has space", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		accepted, err := fa.Accepts(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accepted != test.expected {
+			t.Errorf("For input %q: expected %v, got %v", test.input, test.expected, accepted)
+		}
+	}
+}
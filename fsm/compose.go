@@ -0,0 +1,79 @@
+package fsm
+
+import "fmt"
+
+// transformation is the effect of a block of input on the automaton's state:
+// a table over the declared states, and run for any other state.
+type transformation struct {
+	table map[State]State
+	run   func(State) State
+}
+
+func (t transformation) apply(state State) State {
+	if next, ok := t.table[state]; ok {
+		return next
+	}
+	return t.run(state)
+}
+
+// then returns the transformation that applies t and then u.
+func (t transformation) then(u transformation) transformation {
+	table := make(map[State]State, len(t.table))
+	for state, mid := range t.table {
+		table[state] = u.apply(mid)
+	}
+	return transformation{
+		table: table,
+		run:   func(state State) State { return u.apply(t.apply(state)) },
+	}
+}
+
+func (fa *FiniteAutomaton) identity() transformation {
+	table := make(map[State]State, len(fa.States))
+	for _, state := range fa.States {
+		table[state] = state
+	}
+	return transformation{table: table, run: func(state State) State { return state }}
+}
+
+func (fa *FiniteAutomaton) transformationOf(block []Symbol) transformation {
+	run := func(state State) State {
+		for _, symbol := range block {
+			state = fa.TransitionFunction(state, symbol)
+		}
+		return state
+	}
+
+	table := make(map[State]State, len(fa.States))
+	for _, state := range fa.States {
+		table[state] = run(state)
+	}
+	return transformation{table: table, run: run}
+}
+
+// ComposeTransitions precomputes the state reached from every declared state
+// after reading block, so applying the block later is a single lookup. States
+// outside fa.States are run through the block on demand. Symbols are not
+// checked against the alphabet.
+func (fa *FiniteAutomaton) ComposeTransitions(block []Symbol) func(State) State {
+	return fa.transformationOf(block).apply
+}
+
+// ComposeRepeated is ComposeTransitions for block repeated n times. It takes
+// O(|States| log n) to build by repeated squaring.
+func (fa *FiniteAutomaton) ComposeRepeated(block []Symbol, n int) (func(State) State, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("cannot repeat a block %d times: count must not be negative", n)
+	}
+
+	result, power := fa.identity(), fa.transformationOf(block)
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			result = result.then(power)
+		}
+		if n > 1 {
+			power = power.then(power)
+		}
+	}
+	return result.apply, nil
+}
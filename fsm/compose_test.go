@@ -0,0 +1,87 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func symbolsOf(input string) []Symbol {
+	symbols := make([]Symbol, 0, len(input))
+	for _, char := range input {
+		symbols = append(symbols, Symbol(string(char)))
+	}
+	return symbols
+}
+
+func runFrom(fa *FiniteAutomaton, state State, input string) State {
+	for _, symbol := range symbolsOf(input) {
+		state = fa.TransitionFunction(state, symbol)
+	}
+	return state
+}
+
+func TestComposeTransitions(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	for _, block := range []string{"", "1", "1101", "0110100"} {
+		apply := fa.ComposeTransitions(symbolsOf(block))
+		for _, state := range fa.States {
+			if got, expected := apply(state), runFrom(fa, state, block); got != expected {
+				t.Errorf("Block '%s' from %s: expected %s, got %s", block, state, expected, got)
+			}
+		}
+	}
+}
+
+func TestComposeTransitions_UndeclaredStates(t *testing.T) {
+	fa := newBoundedModThree()
+
+	apply := fa.ComposeTransitions(symbolsOf("11"))
+	for _, state := range []State{"0", "1", "big"} {
+		if got, expected := apply(state), runFrom(fa, state, "11"); got != expected {
+			t.Errorf("From %s: expected %s, got %s", state, expected, got)
+		}
+	}
+}
+
+func TestComposeRepeated(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	for _, n := range []int{0, 1, 2, 3, 7, 64, 1000} {
+		for _, block := range []string{"1", "10", "110"} {
+			apply, err := fa.ComposeRepeated(symbolsOf(block), n)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			expected, err := fa.ProcessInput(strings.Repeat(block, n))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := apply(fa.InitialState); got != expected {
+				t.Errorf("Block '%s' x%d: expected %s, got %s", block, n, expected, got)
+			}
+		}
+	}
+}
+
+func TestComposeRepeated_UndeclaredStates(t *testing.T) {
+	fa := newBoundedModThree()
+
+	apply, err := fa.ComposeRepeated(symbolsOf("1"), 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := apply("0"); got != "7" {
+		t.Errorf("Expected 7, got %s", got)
+	}
+	if got := apply("1"); got != "big" {
+		t.Errorf("Expected big, got %s", got)
+	}
+}
+
+func TestComposeRepeated_NegativeCount(t *testing.T) {
+	apply, err := newRunnerTestAutomaton().ComposeRepeated(symbolsOf("1"), -1)
+	if err == nil || apply != nil {
+		t.Error("Expected an error for a negative repeat count")
+	}
+}
@@ -0,0 +1,222 @@
+package fsm
+
+import "fmt"
+
+type CounterTest int
+
+const (
+	AnyCount CounterTest = iota
+	IsZero
+	NonZero
+)
+
+// CounterTransition fires on Symbol from From when every register passes its
+// test, then adds Deltas to the registers. Tests and Deltas are indexed by
+// register; missing entries mean AnyCount and 0. A negative delta implies a
+// NonZero test, so registers never go below zero.
+type CounterTransition struct {
+	From   State
+	Symbol Symbol
+	Tests  []CounterTest
+	Deltas []int
+	To     State
+}
+
+type CounterConfiguration struct {
+	State    State
+	Counters []int
+}
+
+// CounterAutomaton is a deterministic automaton with Registers unbounded
+// counters. With one register it recognises languages such as a^n b^n that are
+// beyond finite automata but need no full stack. Input is accepted when the
+// run ends in an accepting state, with every counter at zero if RequireZero is
+// set. A symbol with no enabled transition rejects the input.
+type CounterAutomaton struct {
+	States          []State
+	Alphabet        []Symbol
+	InitialState    State
+	AcceptingStates []State
+	Registers       int
+	RequireZero     bool
+	Transitions     []CounterTransition
+
+	index       map[State]map[Symbol][]int
+	alphabetSet *symbolSet
+}
+
+func NewCounterAutomaton(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+	registers int,
+	transitions []CounterTransition,
+) (*CounterAutomaton, error) {
+	if registers < 1 {
+		return nil, fmt.Errorf("counter automaton needs at least one register, got %d", registers)
+	}
+
+	known := make(map[State]bool, len(states))
+	for _, state := range states {
+		known[state] = true
+	}
+	if !known[initialState] {
+		return nil, fmt.Errorf("initial state '%s' is not declared", initialState)
+	}
+	for _, state := range acceptingStates {
+		if !known[state] {
+			return nil, fmt.Errorf("accepting state '%s' is not declared", state)
+		}
+	}
+
+	ca := &CounterAutomaton{
+		States:          states,
+		Alphabet:        alphabet,
+		InitialState:    initialState,
+		AcceptingStates: acceptingStates,
+		Registers:       registers,
+		Transitions:     transitions,
+		index:           make(map[State]map[Symbol][]int),
+		alphabetSet:     newSymbolSet(alphabet),
+	}
+
+	for i, transition := range transitions {
+		if !known[transition.From] {
+			return nil, fmt.Errorf("transition from undeclared state '%s'", transition.From)
+		}
+		if !known[transition.To] {
+			return nil, fmt.Errorf("transition to undeclared state '%s'", transition.To)
+		}
+		if !ca.isValidSymbol(transition.Symbol) {
+			return nil, fmt.Errorf("transition from '%s' on symbol '%s' not in alphabet", transition.From, transition.Symbol)
+		}
+		if len(transition.Tests) > registers || len(transition.Deltas) > registers {
+			return nil, fmt.Errorf("transition from '%s' on '%s' refers to more than %d registers", transition.From, transition.Symbol, registers)
+		}
+
+		if ca.index[transition.From] == nil {
+			ca.index[transition.From] = make(map[Symbol][]int)
+		}
+		for _, j := range ca.index[transition.From][transition.Symbol] {
+			if ca.overlaps(transitions[j], transition) {
+				return nil, fmt.Errorf("transitions from '%s' on '%s' to '%s' and '%s' can both fire", transition.From, transition.Symbol, transitions[j].To, transition.To)
+			}
+		}
+		ca.index[transition.From][transition.Symbol] = append(ca.index[transition.From][transition.Symbol], i)
+	}
+
+	return ca, nil
+}
+
+func (ca *CounterAutomaton) test(transition CounterTransition, register int) CounterTest {
+	if register < len(transition.Deltas) && transition.Deltas[register] < 0 {
+		return NonZero
+	}
+	if register < len(transition.Tests) {
+		return transition.Tests[register]
+	}
+	return AnyCount
+}
+
+func (ca *CounterAutomaton) overlaps(a, b CounterTransition) bool {
+	for register := 0; register < ca.Registers; register++ {
+		x, y := ca.test(a, register), ca.test(b, register)
+		if x != AnyCount && y != AnyCount && x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func (ca *CounterAutomaton) enabled(transition CounterTransition, counters []int) bool {
+	for register, count := range counters {
+		switch ca.test(transition, register) {
+		case IsZero:
+			if count != 0 {
+				return false
+			}
+		case NonZero:
+			if count == 0 {
+				return false
+			}
+		}
+		if register < len(transition.Deltas) && count+transition.Deltas[register] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ca *CounterAutomaton) InitialConfiguration() CounterConfiguration {
+	return CounterConfiguration{State: ca.InitialState, Counters: make([]int, ca.Registers)}
+}
+
+// Step returns the configuration after symbol, or false if no transition is
+// enabled.
+func (ca *CounterAutomaton) Step(config CounterConfiguration, symbol Symbol) (CounterConfiguration, bool) {
+	for _, i := range ca.index[config.State][symbol] {
+		transition := ca.Transitions[i]
+		if !ca.enabled(transition, config.Counters) {
+			continue
+		}
+
+		counters := make([]int, len(config.Counters))
+		copy(counters, config.Counters)
+		for register, delta := range transition.Deltas {
+			counters[register] += delta
+		}
+		return CounterConfiguration{State: transition.To, Counters: counters}, true
+	}
+	return config, false
+}
+
+// Process runs input from the initial configuration. It returns the last
+// configuration reached and whether the whole input was consumed.
+func (ca *CounterAutomaton) Process(input string) (CounterConfiguration, bool, error) {
+	config := ca.InitialConfiguration()
+	for i, char := range input {
+		symbol := Symbol(string(char))
+		if !ca.isValidSymbol(symbol) {
+			return config, false, fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, ca.Alphabet)
+		}
+
+		next, ok := ca.Step(config, symbol)
+		if !ok {
+			return config, false, nil
+		}
+		config = next
+	}
+	return config, true, nil
+}
+
+func (ca *CounterAutomaton) Accepts(input string) (bool, error) {
+	config, consumed, err := ca.Process(input)
+	if err != nil || !consumed {
+		return false, err
+	}
+	return ca.IsAccepting(config), nil
+}
+
+func (ca *CounterAutomaton) IsAccepting(config CounterConfiguration) bool {
+	accepting := false
+	for _, state := range ca.AcceptingStates {
+		if state == config.State {
+			accepting = true
+			break
+		}
+	}
+	if !accepting || !ca.RequireZero {
+		return accepting
+	}
+	for _, count := range config.Counters {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ca *CounterAutomaton) isValidSymbol(symbol Symbol) bool {
+	return containsSymbol(ca.alphabetSet, ca.Alphabet, symbol)
+}
@@ -0,0 +1,147 @@
+package fsm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newAnBn(t *testing.T) *CounterAutomaton {
+	t.Helper()
+
+	ca, err := NewCounterAutomaton(
+		[]State{"A", "B"},
+		[]Symbol{"a", "b"},
+		"A",
+		[]State{"A", "B"},
+		1,
+		[]CounterTransition{
+			{From: "A", Symbol: "a", Deltas: []int{1}, To: "A"},
+			{From: "A", Symbol: "b", Deltas: []int{-1}, To: "B"},
+			{From: "B", Symbol: "b", Deltas: []int{-1}, To: "B"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ca.RequireZero = true
+	return ca
+}
+
+func TestCounterAutomaton_AnBn(t *testing.T) {
+	ca := newAnBn(t)
+
+	for _, input := range SeedCorpus(ca.Alphabet, 8) {
+		n := strings.Count(input, "a")
+		expected := input == strings.Repeat("a", n)+strings.Repeat("b", n)
+
+		accepted, err := ca.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != expected {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, expected, accepted)
+		}
+	}
+
+	if _, err := ca.Accepts("abc"); err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+}
+
+func TestCounterAutomaton_Process(t *testing.T) {
+	ca := newAnBn(t)
+
+	config, consumed, err := ca.Process("aaab")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := CounterConfiguration{State: "B", Counters: []int{2}}
+	if !consumed || !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected %+v consumed, got %+v (consumed=%v)", expected, config, consumed)
+	}
+
+	config, consumed, _ = ca.Process("abba")
+	expected = CounterConfiguration{State: "B", Counters: []int{0}}
+	if consumed || !reflect.DeepEqual(config, expected) {
+		t.Errorf("Expected to get stuck at %+v, got %+v (consumed=%v)", expected, config, consumed)
+	}
+}
+
+func TestCounterAutomaton_ZeroTest(t *testing.T) {
+	// a^n b^n c^n with two registers: a counts into both, b drains the first,
+	// c drains the second, and c may only start once the first is empty.
+	ca, err := NewCounterAutomaton(
+		[]State{"A", "B", "C"},
+		[]Symbol{"a", "b", "c"},
+		"A",
+		[]State{"A", "C"},
+		2,
+		[]CounterTransition{
+			{From: "A", Symbol: "a", Deltas: []int{1, 1}, To: "A"},
+			{From: "A", Symbol: "b", Deltas: []int{-1}, To: "B"},
+			{From: "B", Symbol: "b", Deltas: []int{-1}, To: "B"},
+			{From: "B", Symbol: "c", Tests: []CounterTest{IsZero}, Deltas: []int{0, -1}, To: "C"},
+			{From: "C", Symbol: "c", Deltas: []int{0, -1}, To: "C"},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ca.RequireZero = true
+
+	for _, input := range SeedCorpus(ca.Alphabet, 7) {
+		n := strings.Count(input, "a")
+		expected := input == strings.Repeat("a", n)+strings.Repeat("b", n)+strings.Repeat("c", n)
+
+		accepted, err := ca.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != expected {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, expected, accepted)
+		}
+	}
+}
+
+func TestNewCounterAutomaton_Errors(t *testing.T) {
+	states := []State{"A", "B"}
+	alphabet := []Symbol{"a"}
+	tests := []struct {
+		description string
+		registers   int
+		initial     State
+		transitions []CounterTransition
+	}{
+		{"no registers", 0, "A", nil},
+		{"unknown initial", 1, "Z", nil},
+		{"unknown source", 1, "A", []CounterTransition{{From: "Z", Symbol: "a", To: "A"}}},
+		{"unknown target", 1, "A", []CounterTransition{{From: "A", Symbol: "a", To: "Z"}}},
+		{"symbol outside alphabet", 1, "A", []CounterTransition{{From: "A", Symbol: "b", To: "A"}}},
+		{"too many registers", 1, "A", []CounterTransition{{From: "A", Symbol: "a", Deltas: []int{1, 1}, To: "A"}}},
+		{"nondeterministic", 1, "A", []CounterTransition{
+			{From: "A", Symbol: "a", Tests: []CounterTest{IsZero}, To: "A"},
+			{From: "A", Symbol: "a", To: "B"},
+		}},
+		{"decrement overlaps non-zero test", 1, "A", []CounterTransition{
+			{From: "A", Symbol: "a", Deltas: []int{-1}, To: "A"},
+			{From: "A", Symbol: "a", Tests: []CounterTest{NonZero}, To: "B"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if _, err := NewCounterAutomaton(states, alphabet, test.initial, nil, test.registers, test.transitions); err == nil {
+				t.Errorf("Expected error for %s, but got none", test.description)
+			}
+		})
+	}
+
+	_, err := NewCounterAutomaton(states, alphabet, "A", nil, 1, []CounterTransition{
+		{From: "A", Symbol: "a", Tests: []CounterTest{IsZero}, Deltas: []int{1}, To: "A"},
+		{From: "A", Symbol: "a", Deltas: []int{-1}, To: "B"},
+	})
+	if err != nil {
+		t.Errorf("Expected zero and decrement transitions to be deterministic, got %v", err)
+	}
+}
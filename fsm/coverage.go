@@ -0,0 +1,106 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type transitionKey struct {
+	state  State
+	symbol Symbol
+}
+
+type TransitionCount struct {
+	Transition
+	Count int
+}
+
+type CoverageReport struct {
+	Total     int
+	Covered   int
+	Counts    []TransitionCount
+	Uncovered []Transition
+}
+
+type Coverage struct {
+	automaton *FiniteAutomaton
+	mu        sync.Mutex
+	counts    map[transitionKey]int
+}
+
+func NewCoverage(automaton *FiniteAutomaton) *Coverage {
+	return &Coverage{
+		automaton: automaton,
+		counts:    make(map[transitionKey]int),
+	}
+}
+
+func WithCoverage(coverage *Coverage) RunnerOption {
+	return func(r *Runner) {
+		r.coverage = coverage
+	}
+}
+
+func (c *Coverage) Record(state State, symbol Symbol) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[transitionKey{state: state, symbol: symbol}]++
+}
+
+func (c *Coverage) Count(state State, symbol Symbol) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[transitionKey{state: state, symbol: symbol}]
+}
+
+func (c *Coverage) ProcessInput(input string) (State, error) {
+	runner := NewRunner(c.automaton, WithCoverage(c))
+	return runner.Feed(input)
+}
+
+func (c *Coverage) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts = make(map[transitionKey]int)
+}
+
+func (c *Coverage) Report() CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var report CoverageReport
+	for _, transition := range c.automaton.Transitions() {
+		count := c.counts[transitionKey{state: transition.From, symbol: transition.Symbol}]
+
+		report.Total++
+		report.Counts = append(report.Counts, TransitionCount{Transition: transition, Count: count})
+		if count > 0 {
+			report.Covered++
+		} else {
+			report.Uncovered = append(report.Uncovered, transition)
+		}
+	}
+
+	return report
+}
+
+func (r CoverageReport) Percent() float64 {
+	if r.Total == 0 {
+		return 100
+	}
+	return float64(r.Covered) * 100 / float64(r.Total)
+}
+
+func (r CoverageReport) Complete() bool {
+	return r.Covered == r.Total
+}
+
+func (r CoverageReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Transition coverage: %d/%d (%.1f%%)\n", r.Covered, r.Total, r.Percent()))
+	for _, count := range r.Counts {
+		sb.WriteString(fmt.Sprintf("  %s --%s--> %s: %d\n", count.From, count.Symbol, count.To, count.Count))
+	}
+	return sb.String()
+}
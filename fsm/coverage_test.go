@@ -0,0 +1,86 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCoverage_Counts(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	coverage := NewCoverage(fa)
+
+	for _, input := range []string{"1101", "0"} {
+		if _, err := coverage.ProcessInput(input); err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+	}
+
+	tests := []struct {
+		state    State
+		symbol   Symbol
+		expected int
+	}{
+		{"S0", "0", 2},
+		{"S0", "1", 2},
+		{"S1", "1", 1},
+		{"S1", "0", 0},
+		{"S2", "0", 0},
+		{"S2", "1", 0},
+	}
+
+	for _, test := range tests {
+		if count := coverage.Count(test.state, test.symbol); count != test.expected {
+			t.Errorf("For %s on %s: expected count %d, got %d", test.state, test.symbol, test.expected, count)
+		}
+	}
+}
+
+func TestCoverage_Report(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	coverage := NewCoverage(fa)
+
+	runner := NewRunner(fa, WithCoverage(coverage))
+	if _, err := runner.Feed("10"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report := coverage.Report()
+	if report.Total != 6 {
+		t.Errorf("Expected 6 transitions in total, got %d", report.Total)
+	}
+	if report.Covered != 2 {
+		t.Errorf("Expected 2 covered transitions, got %d", report.Covered)
+	}
+	if len(report.Uncovered) != 4 {
+		t.Errorf("Expected 4 uncovered transitions, got %d", len(report.Uncovered))
+	}
+	if report.Complete() {
+		t.Error("Expected report to be incomplete")
+	}
+
+	if _, err := coverage.ProcessInput("010110001"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report = coverage.Report()
+	if !report.Complete() {
+		t.Errorf("Expected complete coverage, uncovered: %v", report.Uncovered)
+	}
+	if report.Percent() != 100 {
+		t.Errorf("Expected 100%% coverage, got %.1f", report.Percent())
+	}
+
+	if !strings.Contains(report.String(), "Transition coverage: 6/6") {
+		t.Errorf("Unexpected report:\n%s", report.String())
+	}
+}
+
+func TestCoverage_Reset(t *testing.T) {
+	coverage := NewCoverage(newRunnerTestAutomaton())
+	coverage.ProcessInput("111")
+	coverage.Reset()
+
+	if report := coverage.Report(); report.Covered != 0 {
+		t.Errorf("Expected no covered transitions after reset, got %d", report.Covered)
+	}
+}
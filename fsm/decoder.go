@@ -0,0 +1,158 @@
+package fsm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+type Decoder interface {
+	Decode(data []byte) ([]Symbol, error)
+}
+
+type DecoderFunc func(data []byte) ([]Symbol, error)
+
+func (f DecoderFunc) Decode(data []byte) ([]Symbol, error) {
+	return f(data)
+}
+
+func RuneDecoder() Decoder {
+	return DecoderFunc(func(data []byte) ([]Symbol, error) {
+		symbols := make([]Symbol, 0, utf8.RuneCount(data))
+		for i := 0; i < len(data); {
+			r, size := utf8.DecodeRune(data[i:])
+			if r == utf8.RuneError && size <= 1 {
+				return nil, fmt.Errorf("invalid UTF-8 at byte %d", i)
+			}
+			symbols = append(symbols, Symbol(string(r)))
+			i += size
+		}
+		return symbols, nil
+	})
+}
+
+func ByteDecoder() Decoder {
+	return DecoderFunc(func(data []byte) ([]Symbol, error) {
+		symbols := make([]Symbol, len(data))
+		for i, b := range data {
+			symbols[i] = ByteSymbol(b)
+		}
+		return symbols, nil
+	})
+}
+
+// HexDecoder reads pairs of hex digits and yields one ByteSymbol per decoded
+// byte, so a byte automaton can consume hex dumps as well as raw bytes.
+func HexDecoder() Decoder {
+	return DecoderFunc(func(data []byte) ([]Symbol, error) {
+		if len(data)%2 != 0 {
+			return nil, fmt.Errorf("odd number of hex digits (%d)", len(data))
+		}
+		symbols := make([]Symbol, len(data)/2)
+		for i := 0; i < len(data); i += 2 {
+			high, ok := hexValue(data[i])
+			if !ok {
+				return nil, fmt.Errorf("invalid hex digit %q at byte %d", data[i], i)
+			}
+			low, ok := hexValue(data[i+1])
+			if !ok {
+				return nil, fmt.Errorf("invalid hex digit %q at byte %d", data[i+1], i+1)
+			}
+			symbols[i/2] = ByteSymbol(high<<4 | low)
+		}
+		return symbols, nil
+	})
+}
+
+func TokenDecoder() Decoder {
+	return DecoderFunc(func(data []byte) ([]Symbol, error) {
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("invalid UTF-8 in token stream")
+		}
+		fields := strings.Fields(string(data))
+		symbols := make([]Symbol, len(fields))
+		for i, field := range fields {
+			symbols[i] = Symbol(field)
+		}
+		return symbols, nil
+	})
+}
+
+func hexValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+var decoders = map[string]func() Decoder{
+	"utf8":   RuneDecoder,
+	"bytes":  ByteDecoder,
+	"hex":    HexDecoder,
+	"tokens": TokenDecoder,
+}
+
+func DecoderNames() []string {
+	names := make([]string, 0, len(decoders))
+	for name := range decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func DecoderByName(name string) (Decoder, error) {
+	constructor, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown decoder '%s' (expected one of %s)", name, strings.Join(DecoderNames(), ", "))
+	}
+	return constructor(), nil
+}
+
+func (fa *FiniteAutomaton) decoder() Decoder {
+	if fa.Decoder == nil {
+		return RuneDecoder()
+	}
+	return fa.Decoder
+}
+
+func (fa *FiniteAutomaton) ProcessEncoded(data []byte) (State, error) {
+	symbols, err := fa.decoder().Decode(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode input: %w", err)
+	}
+
+	currentState := fa.InitialState
+	debug := debugEnabled(fa.Logger)
+
+	for i, symbol := range symbols {
+		symbol = fa.normalize(symbol)
+
+		if !fa.isValidSymbol(symbol) {
+			logInvalidSymbol(fa.Logger, currentState, symbol, i)
+			return "", fmt.Errorf("invalid symbol %q at position %d: not in alphabet", symbol, i)
+		}
+
+		next := fa.TransitionFunction(currentState, symbol)
+		if debug {
+			logTransition(fa.Logger, Transition{From: currentState, Symbol: symbol, To: next}, i)
+		}
+		currentState = next
+	}
+
+	return currentState, nil
+}
+
+func (fa *FiniteAutomaton) AcceptsEncoded(data []byte) (bool, error) {
+	finalState, err := fa.ProcessEncoded(data)
+	if err != nil {
+		return false, err
+	}
+	return fa.IsAcceptingState(finalState), nil
+}
@@ -0,0 +1,128 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecoders(t *testing.T) {
+	tests := []struct {
+		decoder  string
+		input    string
+		expected []Symbol
+	}{
+		{"utf8", "aé1", []Symbol{"a", "é", "1"}},
+		{"bytes", "é", []Symbol{ByteSymbol(0xC3), ByteSymbol(0xA9)}},
+		{"hex", "00fFa9", []Symbol{ByteSymbol(0x00), ByteSymbol(0xFF), ByteSymbol(0xA9)}},
+		{"tokens", "  GET\tack\nGET ", []Symbol{"GET", "ack", "GET"}},
+		{"tokens", "", []Symbol{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.decoder+"/"+test.input, func(t *testing.T) {
+			decoder, err := DecoderByName(test.decoder)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			symbols, err := decoder.Decode([]byte(test.input))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(symbols) != len(test.expected) {
+				t.Fatalf("Expected %q, got %q", test.expected, symbols)
+			}
+			for i := range symbols {
+				if symbols[i] != test.expected[i] {
+					t.Errorf("Expected %q, got %q", test.expected, symbols)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestDecoders_Errors(t *testing.T) {
+	tests := []struct {
+		decoder       string
+		input         []byte
+		expectedError string
+	}{
+		{"utf8", []byte{'a', 0xFF}, "invalid UTF-8 at byte 1"},
+		{"hex", []byte("abc"), "odd number of hex digits"},
+		{"hex", []byte("0g"), "invalid hex digit 'g' at byte 1"},
+		{"tokens", []byte{0xFF}, "invalid UTF-8"},
+	}
+
+	for _, test := range tests {
+		decoder, _ := DecoderByName(test.decoder)
+		if _, err := decoder.Decode(test.input); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("%s %q: expected error containing %q, got %v", test.decoder, test.input, test.expectedError, err)
+		}
+	}
+
+	if _, err := DecoderByName("base64"); err == nil || !strings.Contains(err.Error(), "bytes, hex, tokens, utf8") {
+		t.Errorf("Expected unknown decoder error, got %v", err)
+	}
+}
+
+func TestProcessEncoded(t *testing.T) {
+	fa := newNumberScanner(t)
+
+	fa.Decoder = HexDecoder()
+	accepted, err := fa.AcceptsEncoded([]byte("332e3134"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !accepted {
+		t.Error("Expected hex-encoded \"3.14\" to be accepted")
+	}
+	if _, err := fa.ProcessEncoded([]byte("3")); err == nil || !strings.Contains(err.Error(), "failed to decode input") {
+		t.Errorf("Expected decode error, got %v", err)
+	}
+
+	fa.Decoder = ByteDecoder()
+	state, err := fa.ProcessEncoded([]byte("3.1"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "Frac" {
+		t.Errorf("Expected Frac, got %s", state)
+	}
+
+	protocol := NewTableAutomaton(
+		[]State{"Idle", "Busy"},
+		[]Symbol{"GET", "ack"},
+		"Idle",
+		[]State{"Idle"},
+		TransitionTable{
+			"Idle": {"GET": "Busy", "ack": "Idle"},
+			"Busy": {"GET": "Busy", "ack": "Idle"},
+		},
+	)
+	protocol.Decoder = TokenDecoder()
+	if accepted, err := protocol.AcceptsEncoded([]byte("GET ack GET ack")); err != nil || !accepted {
+		t.Errorf("Expected acceptance, got %v, %v", accepted, err)
+	}
+	if _, err := protocol.ProcessEncoded([]byte("GET PUT")); err == nil || !strings.Contains(err.Error(), `invalid symbol "PUT" at position 1`) {
+		t.Errorf("Expected invalid symbol error, got %v", err)
+	}
+
+	modThree := NewTableAutomaton(
+		[]State{"S0", "S1", "S2"},
+		[]Symbol{"0", "1"},
+		"S0",
+		[]State{"S0"},
+		TransitionTable{
+			"S0": {"0": "S0", "1": "S1"},
+			"S1": {"0": "S2", "1": "S0"},
+			"S2": {"0": "S1", "1": "S2"},
+		},
+	)
+	state, err = modThree.ProcessEncoded([]byte("110"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "S0" {
+		t.Errorf("Expected default UTF-8 decoding to reach S0, got %s", state)
+	}
+}
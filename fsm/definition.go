@@ -0,0 +1,226 @@
+package fsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+type TransitionDefinition struct {
+	From    State  `json:"from"`
+	Symbol  Symbol `json:"symbol,omitempty"`
+	Class   string `json:"class,omitempty"`
+	Default bool   `json:"default,omitempty"`
+	To      State  `json:"to"`
+	Guard   string `json:"guard,omitempty"`
+	Output  string `json:"output,omitempty"`
+}
+
+type Definition struct {
+	Version         int                    `json:"version,omitempty"`
+	Name            string                 `json:"name,omitempty"`
+	States          []State                `json:"states"`
+	Alphabet        []Symbol               `json:"alphabet"`
+	InitialState    State                  `json:"initial"`
+	AcceptingStates []State                `json:"accepting"`
+	Transitions     []TransitionDefinition `json:"transitions"`
+}
+
+func DefinitionOf(fa *FiniteAutomaton) *Definition {
+	definition := &Definition{
+		Version:         DefinitionVersion,
+		States:          fa.States,
+		Alphabet:        fa.Alphabet,
+		InitialState:    fa.InitialState,
+		AcceptingStates: fa.AcceptingStates,
+	}
+
+	written := make(map[State]bool, len(fa.States))
+	for _, transition := range fa.Transitions() {
+		fallback, hasDefault := fa.Table.Default(transition.From)
+		if hasDefault && !written[transition.From] {
+			written[transition.From] = true
+			definition.Transitions = append(definition.Transitions, TransitionDefinition{
+				From:    transition.From,
+				Default: true,
+				To:      fallback,
+			})
+		}
+		if hasDefault && transition.To == fallback {
+			continue
+		}
+
+		definition.Transitions = append(definition.Transitions, TransitionDefinition{
+			From:   transition.From,
+			Symbol: transition.Symbol,
+			To:     transition.To,
+		})
+	}
+
+	return definition
+}
+
+func ReadDefinition(r io.Reader) (*Definition, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode automaton definition: %w", err)
+	}
+	data, err := migrateDefinition(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition Definition
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("failed to decode automaton definition: %w", err)
+	}
+	definition.Version = DefinitionVersion
+
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func LoadDefinition(path string) (*Definition, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open automaton definition: %w", err)
+	}
+	defer file.Close()
+
+	definition, err := ReadDefinition(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return definition, nil
+}
+
+func (d *Definition) Write(w io.Writer) error {
+	versioned := *d
+	versioned.Version = DefinitionVersion
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(&versioned)
+}
+
+func (d *Definition) Validate() error {
+	if len(d.States) == 0 {
+		return fmt.Errorf("definition must declare at least one state")
+	}
+
+	states := make(map[State]bool, len(d.States))
+	for _, state := range d.States {
+		if states[state] {
+			return fmt.Errorf("duplicate state '%s'", state)
+		}
+		states[state] = true
+	}
+
+	symbols := make(map[Symbol]bool, len(d.Alphabet))
+	for _, symbol := range d.Alphabet {
+		if symbol == Epsilon {
+			return fmt.Errorf("alphabet must not contain the empty symbol")
+		}
+		if symbols[symbol] {
+			return fmt.Errorf("duplicate symbol '%s'", symbol)
+		}
+		symbols[symbol] = true
+	}
+
+	if !states[d.InitialState] {
+		return fmt.Errorf("initial state '%s' is not declared", d.InitialState)
+	}
+
+	for _, state := range d.AcceptingStates {
+		if !states[state] {
+			return fmt.Errorf("accepting state '%s' is not declared", state)
+		}
+	}
+
+	closed := make(map[transitionKey]bool, len(d.Transitions))
+	for _, transition := range d.Transitions {
+		if !states[transition.From] {
+			return fmt.Errorf("transition from undeclared state '%s'", transition.From)
+		}
+		if !states[transition.To] {
+			return fmt.Errorf("transition to undeclared state '%s'", transition.To)
+		}
+		labels, err := d.Labels(transition)
+		if err != nil {
+			return err
+		}
+
+		for _, symbol := range labels {
+			if symbol != Wildcard && !symbols[symbol] {
+				return fmt.Errorf("transition on symbol '%s' not in alphabet", symbol)
+			}
+
+			key := transitionKey{state: transition.From, symbol: symbol}
+			if closed[key] {
+				if symbol == Wildcard {
+					return fmt.Errorf("duplicate default transition from '%s'", transition.From)
+				}
+				return fmt.Errorf("duplicate transition from '%s' on '%s'", transition.From, symbol)
+			}
+			if transition.Guard == "" {
+				closed[key] = true
+			}
+		}
+
+		if _, err := compileScript(transition); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Definition) Automaton() (*FiniteAutomaton, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	if d.Scripted() {
+		return nil, fmt.Errorf("definition has guards or outputs; run it with Script instead")
+	}
+
+	table := make(TransitionTable)
+	for _, transition := range d.Transitions {
+		labels, _ := d.Labels(transition)
+		table.SetClass(transition.From, labels, transition.To)
+	}
+
+	return NewTableAutomaton(d.States, d.Alphabet, d.InitialState, d.AcceptingStates, table), nil
+}
+
+func (d *Definition) Labels(transition TransitionDefinition) (SymbolSet, error) {
+	kinds := 0
+	for _, set := range []bool{transition.Symbol != "", transition.Class != "", transition.Default} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return nil, fmt.Errorf("transition from '%s' must have exactly one of symbol, class or default", transition.From)
+	}
+
+	switch {
+	case transition.Default:
+		return SymbolSet{Wildcard}, nil
+	case transition.Class != "":
+		class, err := ParseClass(transition.Class, d.Alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("transition from '%s': %w", transition.From, err)
+		}
+		if len(class) == 0 {
+			return nil, fmt.Errorf("transition from '%s': class %s matches no symbol in the alphabet", transition.From, transition.Class)
+		}
+		return class, nil
+	}
+	return SymbolSet{transition.Symbol}, nil
+}
@@ -0,0 +1,183 @@
+package fsm
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const modThreeDefinition = `{
+  "name": "ModThree",
+  "states": ["S0", "S1", "S2"],
+  "alphabet": ["0", "1"],
+  "initial": "S0",
+  "accepting": ["S0"],
+  "transitions": [
+    {"from": "S0", "symbol": "0", "to": "S0"},
+    {"from": "S0", "symbol": "1", "to": "S1"},
+    {"from": "S1", "symbol": "0", "to": "S2"},
+    {"from": "S1", "symbol": "1", "to": "S0"},
+    {"from": "S2", "symbol": "0", "to": "S1"},
+    {"from": "S2", "symbol": "1", "to": "S2"}
+  ]
+}`
+
+func TestReadDefinition(t *testing.T) {
+	definition, err := ReadDefinition(strings.NewReader(modThreeDefinition))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if definition.Name != "ModThree" {
+		t.Errorf("Expected name ModThree, got %s", definition.Name)
+	}
+
+	fa, err := definition.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	equivalent, counterexample, err := Equivalent(fa, newRunnerTestAutomaton())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !equivalent {
+		t.Errorf("Expected loaded automaton to match mod-three, counterexample %v", counterexample)
+	}
+}
+
+func TestDefinitionOf_RoundTrip(t *testing.T) {
+	original := newRunnerTestAutomaton()
+
+	var buf bytes.Buffer
+	if err := DefinitionOf(original).Write(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	definition, err := ReadDefinition(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(definition.Transitions) != 6 {
+		t.Errorf("Expected 6 transitions, got %d", len(definition.Transitions))
+	}
+
+	loaded, err := definition.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.DOT() != original.DOT() {
+		t.Errorf("Round trip changed the automaton:\n%s\nvs\n%s", original.DOT(), loaded.DOT())
+	}
+}
+
+func TestLoadDefinition(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "modthree.json")
+	if err := os.WriteFile(path, []byte(modThreeDefinition), 0o644); err != nil {
+		t.Fatalf("Failed to write definition: %v", err)
+	}
+
+	if _, err := LoadDefinition(path); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if _, err := LoadDefinition(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file, but got none")
+	}
+}
+
+func TestDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		description string
+		definition  string
+	}{
+		{"no states", `{"states": [], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": []}`},
+		{"duplicate state", `{"states": ["S0", "S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": []}`},
+		{"duplicate symbol", `{"states": ["S0"], "alphabet": ["0", "0"], "initial": "S0", "accepting": [], "transitions": []}`},
+		{"empty symbol", `{"states": ["S0"], "alphabet": [""], "initial": "S0", "accepting": [], "transitions": []}`},
+		{"unknown initial", `{"states": ["S0"], "alphabet": ["0"], "initial": "S9", "accepting": [], "transitions": []}`},
+		{"unknown accepting", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": ["S9"], "transitions": []}`},
+		{"unknown target", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "0", "to": "S9"}]}`},
+		{"unknown source", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S9", "symbol": "0", "to": "S0"}]}`},
+		{"unknown symbol", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "1", "to": "S0"}]}`},
+		{"nondeterministic", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "0", "to": "S0"}, {"from": "S0", "symbol": "0", "to": "S0"}]}`},
+		{"duplicate default", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "default": true, "to": "S0"}, {"from": "S0", "default": true, "to": "S0"}]}`},
+		{"default with symbol", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "0", "default": true, "to": "S0"}]}`},
+		{"symbol and class", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "0", "class": "[0]", "to": "S0"}]}`},
+		{"malformed class", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "class": "[0", "to": "S0"}]}`},
+		{"empty class", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "class": "[1-9]", "to": "S0"}]}`},
+		{"class overlaps symbol", `{"states": ["S0"], "alphabet": ["0", "1"], "initial": "S0", "accepting": [], "transitions": [{"from": "S0", "symbol": "1", "to": "S0"}, {"from": "S0", "class": "[0-1]", "to": "S0"}]}`},
+		{"unknown field", `{"states": ["S0"], "alphabet": ["0"], "initial": "S0", "accepting": [], "transitions": [], "extra": 1}`},
+		{"malformed", `{"states": `},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if _, err := ReadDefinition(strings.NewReader(test.definition)); err == nil {
+				t.Errorf("Expected error for %s, but got none", test.description)
+			}
+		})
+	}
+}
+
+func TestDefinition_DefaultTransitions(t *testing.T) {
+	definition, err := ReadDefinition(strings.NewReader(`{
+		"states": ["Start", "Word", "Space"],
+		"alphabet": ["a", "b", "c", " ", "\t"],
+		"initial": "Start",
+		"accepting": ["Word"],
+		"transitions": [
+			{"from": "Start", "default": true, "to": "Word"},
+			{"from": "Start", "symbol": " ", "to": "Space"},
+			{"from": "Word", "default": true, "to": "Word"},
+			{"from": "Word", "class": "[ \\t]", "to": "Space"},
+			{"from": "Space", "default": true, "to": "Word"}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fa, err := definition.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		input         string
+		expectedState State
+	}{
+		{"abc", "Word"},
+		{"ab ", "Space"},
+		{"a\t", "Space"},
+		{"\t", "Word"},
+		{"a c", "Word"},
+	}
+	for _, test := range tests {
+		finalState, err := fa.ProcessInput(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if finalState != test.expectedState {
+			t.Errorf("For input %q: expected final state %s, got %s", test.input, test.expectedState, finalState)
+		}
+	}
+
+	if _, err := fa.ProcessInput("abd"); err == nil {
+		t.Error("Expected defaults to still reject symbols outside the alphabet")
+	}
+
+	roundTrip := DefinitionOf(fa)
+	if len(roundTrip.Transitions) != 6 {
+		t.Errorf("Expected 6 transitions after round trip, got %d", len(roundTrip.Transitions))
+	}
+	restored, err := roundTrip.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if equal, _, err := Equivalent(fa, restored); err != nil || !equal {
+		t.Error("Expected round-tripped automaton to be equivalent")
+	}
+}
@@ -0,0 +1,78 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type highlight struct {
+	state      State
+	transition *Transition
+}
+
+func (h *highlight) isState(state State) bool {
+	return h != nil && h.state == state
+}
+
+func (h *highlight) isEdge(from, to State) bool {
+	return h != nil && h.transition != nil && h.transition.From == from && h.transition.To == to
+}
+
+func (fa *FiniteAutomaton) DOT() string {
+	return fa.dot(nil)
+}
+
+func (fa *FiniteAutomaton) dot(h *highlight) string {
+	var sb strings.Builder
+	sb.WriteString("digraph FiniteAutomaton {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  __start [shape=point];\n")
+
+	for _, state := range fa.States {
+		shape := "circle"
+		if fa.IsAcceptingState(state) {
+			shape = "doublecircle"
+		}
+		if h.isState(state) {
+			sb.WriteString(fmt.Sprintf("  %q [shape=%s, style=filled, fillcolor=lightblue];\n", state, shape))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %q [shape=%s];\n", state, shape))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("  __start -> %q;\n", fa.InitialState))
+
+	for _, edge := range groupEdges(fa.Transitions()) {
+		from, to, label := edge.from, edge.to, strings.Join(edge.labels, ",")
+		if h.isEdge(from, to) {
+			sb.WriteString(fmt.Sprintf("  %q -> %q [label=%q, color=red, penwidth=2];\n", from, to, label))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %q -> %q [label=%q];\n", from, to, label))
+		}
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+type edge struct {
+	from   State
+	to     State
+	labels []string
+}
+
+func groupEdges(transitions []Transition) []edge {
+	var edges []edge
+	index := make(map[[2]State]int)
+	for _, transition := range transitions {
+		key := [2]State{transition.From, transition.To}
+		i, seen := index[key]
+		if !seen {
+			i = len(edges)
+			index[key] = i
+			edges = append(edges, edge{from: transition.From, to: transition.To})
+		}
+		edges[i].labels = append(edges[i].labels, string(transition.Symbol))
+	}
+	return edges
+}
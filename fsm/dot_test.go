@@ -0,0 +1,41 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	dot := newRunnerTestAutomaton().DOT()
+
+	expectedLines := []string{
+		"digraph FiniteAutomaton {",
+		`__start -> "S0";`,
+		`"S0" [shape=doublecircle];`,
+		`"S1" [shape=circle];`,
+		`"S0" -> "S0" [label="0"];`,
+		`"S1" -> "S2" [label="0"];`,
+		`"S2" -> "S2" [label="1"];`,
+	}
+
+	for _, line := range expectedLines {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT output should contain '%s', got:\n%s", line, dot)
+		}
+	}
+}
+
+func TestDOT_MergesParallelEdges(t *testing.T) {
+	fa := NewFiniteAutomaton(
+		[]State{"A"},
+		[]Symbol{"0", "1"},
+		"A",
+		[]State{"A"},
+		func(State, Symbol) State { return "A" },
+	)
+
+	dot := fa.DOT()
+	if !strings.Contains(dot, `"A" -> "A" [label="0,1"];`) {
+		t.Errorf("Expected merged self-loop edge, got:\n%s", dot)
+	}
+}
@@ -0,0 +1,108 @@
+package fsm
+
+import (
+	"fmt"
+)
+
+type statePair struct {
+	a State
+	b State
+}
+
+func Equivalent(a, b *FiniteAutomaton) (bool, []Symbol, error) {
+	if !sameAlphabet(a.Alphabet, b.Alphabet) {
+		return false, nil, fmt.Errorf("cannot compare automata over different alphabets %v and %v", a.Alphabet, b.Alphabet)
+	}
+
+	start := statePair{a: a.InitialState, b: b.InitialState}
+	witness := map[statePair][]Symbol{start: {}}
+	queue := []statePair{start}
+
+	for len(queue) > 0 {
+		pair := queue[0]
+		queue = queue[1:]
+
+		if a.IsAcceptingState(pair.a) != b.IsAcceptingState(pair.b) {
+			return false, witness[pair], nil
+		}
+
+		for _, symbol := range a.Alphabet {
+			next := statePair{
+				a: a.TransitionFunction(pair.a, symbol),
+				b: b.TransitionFunction(pair.b, symbol),
+			}
+			if _, seen := witness[next]; seen {
+				continue
+			}
+			path := make([]Symbol, len(witness[pair]), len(witness[pair])+1)
+			copy(path, witness[pair])
+			witness[next] = append(path, symbol)
+			queue = append(queue, next)
+		}
+	}
+
+	return true, nil, nil
+}
+
+func sameAlphabet(a, b []Symbol) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := make(map[Symbol]bool, len(a))
+	for _, symbol := range a {
+		set[symbol] = true
+	}
+	for _, symbol := range b {
+		if !set[symbol] {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareOnAllStrings runs a and b on every input of length at most maxLen,
+// shortest first, and returns the first one they disagree on. Equivalent
+// never finishes when a function-based automaton generates unboundedly many
+// states; this check is bounded by maxLen instead.
+func CompareOnAllStrings(a, b *FiniteAutomaton, maxLen int) (bool, []Symbol, error) {
+	if !sameAlphabet(a.Alphabet, b.Alphabet) {
+		return false, nil, fmt.Errorf("cannot compare automata over different alphabets %v and %v", a.Alphabet, b.Alphabet)
+	}
+	if maxLen < 0 {
+		return false, nil, fmt.Errorf("maximum length must not be negative, got %d", maxLen)
+	}
+
+	type run struct {
+		pair  statePair
+		input []Symbol
+	}
+	level := []run{{pair: statePair{a: a.InitialState, b: b.InitialState}, input: []Symbol{}}}
+
+	for length := 0; ; length++ {
+		for _, r := range level {
+			if a.IsAcceptingState(r.pair.a) != b.IsAcceptingState(r.pair.b) {
+				return false, r.input, nil
+			}
+		}
+		if length == maxLen {
+			return true, nil, nil
+		}
+
+		next := make([]run, 0, len(level)*len(a.Alphabet))
+		for _, r := range level {
+			for _, symbol := range a.Alphabet {
+				input := make([]Symbol, len(r.input), len(r.input)+1)
+				copy(input, r.input)
+				next = append(next, run{
+					pair: statePair{
+						a: a.TransitionFunction(r.pair.a, symbol),
+						b: b.TransitionFunction(r.pair.b, symbol),
+					},
+					input: append(input, symbol),
+				})
+			}
+		}
+		level = next
+	}
+}
@@ -0,0 +1,147 @@
+package fsm
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestEquivalent(t *testing.T) {
+	a := newRunnerTestAutomaton()
+
+	renamed := NewFiniteAutomaton(
+		[]State{"A", "B", "C"},
+		[]Symbol{"1", "0"},
+		"A",
+		[]State{"A"},
+		func(currentState State, symbol Symbol) State {
+			table := map[State]map[Symbol]State{
+				"A": {"0": "A", "1": "B"},
+				"B": {"0": "C", "1": "A"},
+				"C": {"0": "B", "1": "C"},
+			}
+			return table[currentState][symbol]
+		},
+	)
+
+	equivalent, counterexample, err := Equivalent(a, renamed)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !equivalent {
+		t.Errorf("Expected automata to be equivalent, counterexample: %v", counterexample)
+	}
+}
+
+func TestEquivalent_Counterexample(t *testing.T) {
+	a := newRunnerTestAutomaton()
+
+	b := NewFiniteAutomaton(
+		[]State{"S0", "S1", "S2"},
+		[]Symbol{"0", "1"},
+		"S0",
+		[]State{"S0", "S1"},
+		a.TransitionFunction,
+	)
+
+	equivalent, counterexample, err := Equivalent(a, b)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if equivalent {
+		t.Fatal("Expected automata to differ")
+	}
+
+	input := ""
+	for _, symbol := range counterexample {
+		input += string(symbol)
+	}
+	acceptedA, _ := a.Accepts(input)
+	acceptedB, _ := b.Accepts(input)
+	if acceptedA == acceptedB {
+		t.Errorf("Counterexample '%s' is accepted by both or neither automaton", input)
+	}
+	if input != "1" {
+		t.Errorf("Expected shortest counterexample '1', got '%s'", input)
+	}
+}
+
+func TestEquivalent_DifferentAlphabets(t *testing.T) {
+	a := newRunnerTestAutomaton()
+	b := NewFiniteAutomaton([]State{"S0"}, []Symbol{"a"}, "S0", nil, func(State, Symbol) State { return "S0" })
+
+	if _, _, err := Equivalent(a, b); err == nil {
+		t.Error("Expected error for different alphabets, but got none")
+	}
+}
+
+func newBoundedModThree() *FiniteAutomaton {
+	// Tracks the value exactly up to 7 and gives up beyond that, so it agrees
+	// with the mod-three machine only on short inputs.
+	return NewFiniteAutomaton(
+		nil,
+		[]Symbol{"0", "1"},
+		"0",
+		[]State{"0", "3", "6"},
+		func(currentState State, symbol Symbol) State {
+			value, err := strconv.Atoi(string(currentState))
+			if err != nil {
+				return currentState
+			}
+			value = value*2 + int(symbol[0]-'0')
+			if value > 7 {
+				return "big"
+			}
+			return State(strconv.Itoa(value))
+		},
+	)
+}
+
+func TestCompareOnAllStrings(t *testing.T) {
+	a := newRunnerTestAutomaton()
+	b := newBoundedModThree()
+
+	agree, disagreement, err := CompareOnAllStrings(a, b, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !agree {
+		t.Errorf("Expected agreement up to length 3, got disagreement %v", disagreement)
+	}
+
+	agree, disagreement, err = CompareOnAllStrings(a, b, 6)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if agree {
+		t.Fatal("Expected a disagreement up to length 6")
+	}
+	if !reflect.DeepEqual(disagreement, []Symbol{"1", "0", "0", "1"}) {
+		t.Errorf("Expected first disagreement 1001, got %v", disagreement)
+	}
+}
+
+func TestCompareOnAllStrings_EmptyInput(t *testing.T) {
+	a := newRunnerTestAutomaton()
+	b := NewFiniteAutomaton(a.States, a.Alphabet, "S0", nil, a.TransitionFunction)
+
+	agree, disagreement, err := CompareOnAllStrings(a, b, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if agree || len(disagreement) != 0 || disagreement == nil {
+		t.Errorf("Expected the empty input as disagreement, got %v, %v", agree, disagreement)
+	}
+}
+
+func TestCompareOnAllStrings_Errors(t *testing.T) {
+	a := newRunnerTestAutomaton()
+	b := NewFiniteAutomaton([]State{"S0"}, []Symbol{"a"}, "S0", nil, func(State, Symbol) State { return "S0" })
+
+	if _, _, err := CompareOnAllStrings(a, b, 2); err == nil {
+		t.Error("Expected error for different alphabets, but got none")
+	}
+	if _, _, err := CompareOnAllStrings(a, a, -1); err == nil {
+		t.Error("Expected error for negative length, but got none")
+	}
+}
@@ -0,0 +1,150 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type FailureReason string
+
+const (
+	ReasonAccepted      FailureReason = "accepted"
+	ReasonInvalidSymbol FailureReason = "invalid symbol"
+	ReasonNotAccepting  FailureReason = "ended in non-accepting state"
+)
+
+type Explanation struct {
+	Input                 string
+	Accepted              bool
+	Reason                FailureReason
+	Position              int
+	State                 State
+	Symbol                Symbol
+	ExpectedSymbols       []Symbol
+	LongestAcceptedPrefix string
+	HasAcceptedPrefix     bool
+	Trace                 []Transition
+}
+
+func (fa *FiniteAutomaton) Explain(input string) *Explanation {
+	explanation := &Explanation{Input: input}
+
+	live := fa.liveStates()
+	currentState := fa.InitialState
+	acceptedPrefix := -1
+	if fa.IsAcceptingState(currentState) {
+		acceptedPrefix = 0
+	}
+
+	for i, char := range input {
+		symbol := fa.normalize(Symbol(string(char)))
+
+		if !fa.isValidSymbol(symbol) {
+			explanation.Reason = ReasonInvalidSymbol
+			explanation.Position = i
+			explanation.State = currentState
+			explanation.Symbol = symbol
+			explanation.ExpectedSymbols = fa.expectedSymbols(currentState, live)
+			explanation.setAcceptedPrefix(acceptedPrefix)
+			return explanation
+		}
+
+		next := fa.TransitionFunction(currentState, symbol)
+		explanation.Trace = append(explanation.Trace, Transition{From: currentState, Symbol: symbol, To: next})
+		currentState = next
+
+		if fa.IsAcceptingState(currentState) {
+			acceptedPrefix = i + len(string(char))
+		}
+	}
+
+	explanation.Position = len(input)
+	explanation.State = currentState
+	explanation.setAcceptedPrefix(acceptedPrefix)
+
+	if fa.IsAcceptingState(currentState) {
+		explanation.Accepted = true
+		explanation.Reason = ReasonAccepted
+		return explanation
+	}
+
+	explanation.Reason = ReasonNotAccepting
+	explanation.ExpectedSymbols = fa.expectedSymbols(currentState, live)
+	return explanation
+}
+
+func (e *Explanation) String() string {
+	var sb strings.Builder
+
+	switch e.Reason {
+	case ReasonAccepted:
+		sb.WriteString(fmt.Sprintf("input '%s' accepted in state %s", e.Input, e.State))
+		return sb.String()
+	case ReasonInvalidSymbol:
+		sb.WriteString(fmt.Sprintf("input '%s' rejected: invalid symbol '%s' at position %d in state %s",
+			e.Input, e.Symbol, e.Position, e.State))
+	default:
+		sb.WriteString(fmt.Sprintf("input '%s' rejected: ended in non-accepting state %s", e.Input, e.State))
+	}
+
+	if len(e.ExpectedSymbols) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  expected one of: %v", e.ExpectedSymbols))
+	} else {
+		sb.WriteString("\n  no continuation can lead to acceptance")
+	}
+
+	if e.HasAcceptedPrefix {
+		sb.WriteString(fmt.Sprintf("\n  longest accepted prefix: '%s'", e.LongestAcceptedPrefix))
+	} else {
+		sb.WriteString("\n  no prefix of the input is accepted")
+	}
+
+	return sb.String()
+}
+
+func (fa *FiniteAutomaton) expectedSymbols(state State, live map[State]bool) []Symbol {
+	var expected []Symbol
+	for _, symbol := range fa.Alphabet {
+		if live[fa.TransitionFunction(state, symbol)] {
+			expected = append(expected, symbol)
+		}
+	}
+	return expected
+}
+
+func (fa *FiniteAutomaton) liveStates() map[State]bool {
+	predecessors := make(map[State][]State)
+	for _, transition := range fa.Transitions() {
+		predecessors[transition.To] = append(predecessors[transition.To], transition.From)
+	}
+
+	live := make(map[State]bool)
+	var queue []State
+	for _, state := range fa.AcceptingStates {
+		if !live[state] {
+			live[state] = true
+			queue = append(queue, state)
+		}
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, previous := range predecessors[state] {
+			if !live[previous] {
+				live[previous] = true
+				queue = append(queue, previous)
+			}
+		}
+	}
+
+	return live
+}
+
+func (e *Explanation) setAcceptedPrefix(length int) {
+	if length < 0 {
+		return
+	}
+	e.HasAcceptedPrefix = true
+	e.LongestAcceptedPrefix = e.Input[:length]
+}
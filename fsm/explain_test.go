@@ -0,0 +1,106 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func newEndsWith01DFA() *FiniteAutomaton {
+	table := TransitionTable{}
+	table.Set("A", "0", "B")
+	table.Set("A", "1", "A")
+	table.Set("B", "0", "B")
+	table.Set("B", "1", "C")
+	table.Set("C", "0", "B")
+	table.Set("C", "1", "A")
+	table.Set("Dead", "0", "Dead")
+	table.Set("Dead", "1", "Dead")
+	return NewTableAutomaton([]State{"A", "B", "C", "Dead"}, []Symbol{"0", "1"}, "A", []State{"C"}, table)
+}
+
+func TestExplain_Accepted(t *testing.T) {
+	explanation := newEndsWith01DFA().Explain("1101")
+
+	if !explanation.Accepted || explanation.Reason != ReasonAccepted {
+		t.Errorf("Expected input to be accepted, got %s", explanation)
+	}
+	if explanation.State != "C" {
+		t.Errorf("Expected final state C, got %s", explanation.State)
+	}
+	if len(explanation.Trace) != 4 {
+		t.Errorf("Expected 4 trace entries, got %d", len(explanation.Trace))
+	}
+}
+
+func TestExplain_InvalidSymbol(t *testing.T) {
+	explanation := newEndsWith01DFA().Explain("01102")
+
+	if explanation.Accepted {
+		t.Fatal("Expected input to be rejected")
+	}
+	if explanation.Reason != ReasonInvalidSymbol {
+		t.Errorf("Expected reason %q, got %q", ReasonInvalidSymbol, explanation.Reason)
+	}
+	if explanation.Position != 4 || explanation.Symbol != "2" {
+		t.Errorf("Expected failure at position 4 on '2', got position %d on '%s'", explanation.Position, explanation.Symbol)
+	}
+	if explanation.State != "B" {
+		t.Errorf("Expected state B at failure, got %s", explanation.State)
+	}
+	if !explanation.HasAcceptedPrefix || explanation.LongestAcceptedPrefix != "01" {
+		t.Errorf("Expected longest accepted prefix '01', got '%s'", explanation.LongestAcceptedPrefix)
+	}
+	if !strings.Contains(explanation.String(), "invalid symbol '2' at position 4") {
+		t.Errorf("Unexpected explanation:\n%s", explanation)
+	}
+}
+
+func TestExplain_NotAccepting(t *testing.T) {
+	fa := newEndsWith01DFA()
+	explanation := fa.Explain("110")
+
+	if explanation.Reason != ReasonNotAccepting {
+		t.Errorf("Expected reason %q, got %q", ReasonNotAccepting, explanation.Reason)
+	}
+	if explanation.Position != 3 || explanation.State != "B" {
+		t.Errorf("Expected failure at end in state B, got position %d in %s", explanation.Position, explanation.State)
+	}
+	if explanation.HasAcceptedPrefix {
+		t.Errorf("Expected no accepted prefix, got '%s'", explanation.LongestAcceptedPrefix)
+	}
+
+	expected := []Symbol{"0", "1"}
+	if len(explanation.ExpectedSymbols) != len(expected) {
+		t.Fatalf("Expected symbols %v, got %v", expected, explanation.ExpectedSymbols)
+	}
+	for i, symbol := range expected {
+		if explanation.ExpectedSymbols[i] != symbol {
+			t.Errorf("Expected symbols %v, got %v", expected, explanation.ExpectedSymbols)
+		}
+	}
+}
+
+func TestExplain_ExpectedSymbolsSkipDeadStates(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.Alphabet = append(fa.Alphabet, "x")
+	fa.Table.Set("A", "x", "Dead")
+	fa.Table.Set("B", "x", "Dead")
+	fa.Table.Set("C", "x", "Dead")
+
+	explanation := fa.Explain("1x")
+	if explanation.Reason != ReasonNotAccepting || explanation.State != "Dead" {
+		t.Fatalf("Expected rejection in Dead, got %s", explanation)
+	}
+	if len(explanation.ExpectedSymbols) != 0 {
+		t.Errorf("Expected no useful continuation from Dead, got %v", explanation.ExpectedSymbols)
+	}
+	if !strings.Contains(explanation.String(), "no continuation can lead to acceptance") {
+		t.Errorf("Unexpected explanation:\n%s", explanation)
+	}
+
+	for _, symbol := range fa.Explain("1").ExpectedSymbols {
+		if symbol == "x" {
+			t.Error("Symbol 'x' leads to Dead and should not be expected")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package fsm
+
+import "unicode"
+
+type CompileOption func(*regexParser)
+
+func FoldCase() CompileOption {
+	return func(p *regexParser) {
+		p.fold = foldASCII
+	}
+}
+
+func FoldCaseUnicode() CompileOption {
+	return func(p *regexParser) {
+		p.fold = foldUnicode
+	}
+}
+
+func foldASCII(r rune) []rune {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return []rune{r, r - 'a' + 'A'}
+	case r >= 'A' && r <= 'Z':
+		return []rune{r, r - 'A' + 'a'}
+	}
+	return []rune{r}
+}
+
+func foldUnicode(r rune) []rune {
+	variants := []rune{r}
+	for folded := unicode.SimpleFold(r); folded != r; folded = unicode.SimpleFold(folded) {
+		variants = append(variants, folded)
+	}
+	return variants
+}
+
+func (p *regexParser) variants(r rune) []rune {
+	if p.fold == nil {
+		return []rune{r}
+	}
+	return p.fold(r)
+}
+
+func (p *regexParser) anyVariant(r rune, predicate func(rune) bool) bool {
+	for _, variant := range p.variants(r) {
+		if predicate(variant) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *regexParser) foldedSymbols(char rune) []Symbol {
+	var symbols []Symbol
+	for _, variant := range p.variants(char) {
+		symbol := Symbol(string(variant))
+		for _, s := range p.alphabet {
+			if s == symbol {
+				symbols = append(symbols, symbol)
+				break
+			}
+		}
+	}
+	return symbols
+}
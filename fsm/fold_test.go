@@ -0,0 +1,105 @@
+package fsm
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCompileRegex_FoldCase(t *testing.T) {
+	patterns := []string{"abc", "[a-c]+x", "[^a]b", "Go(lang)?", "\\w+", "[A-Z]{2}"}
+	inputs := []string{"abc", "ABC", "aBc", "bX", "CAX", "Ab", "ab", "bb", "BB", "go", "GOLANG", "gOlAnG", "xy", "x1", "Z"}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			matcher, err := NewMatcher(pattern, ASCIIAlphabet(), FoldCase())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			reference := regexp.MustCompile("^(?i:" + pattern + ")$")
+
+			for _, input := range inputs {
+				matched, err := matcher.Match(input, MatchFull)
+				if err != nil {
+					t.Fatalf("Unexpected error for input '%s': %v", input, err)
+				}
+				if expected := reference.MatchString(input); matched != expected {
+					t.Errorf("Folded '%s' on '%s': expected %v, got %v", pattern, input, expected, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileRegex_FoldCaseUnicode(t *testing.T) {
+	alphabet := []Symbol{"k", "K", "K", "s", "S", "ſ", "é", "É"}
+
+	tests := []struct {
+		pattern  string
+		options  []CompileOption
+		input    string
+		expected bool
+	}{
+		{"k", []CompileOption{FoldCase()}, "K", true},
+		{"k", []CompileOption{FoldCase()}, "K", false},
+		{"k", []CompileOption{FoldCaseUnicode()}, "K", true},
+		{"s+", []CompileOption{FoldCaseUnicode()}, "sSſ", true},
+		{"é", []CompileOption{FoldCase()}, "É", false},
+		{"é", []CompileOption{FoldCaseUnicode()}, "É", true},
+		{"[^k]", []CompileOption{FoldCaseUnicode()}, "K", false},
+		{"k", nil, "K", false},
+	}
+
+	for _, test := range tests {
+		nfa, err := CompileRegex(test.pattern, alphabet, test.options...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		accepted, err := nfa.ToDFA().Accepts(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accepted != test.expected {
+			t.Errorf("Pattern '%s' on '%s': expected %v, got %v", test.pattern, test.input, test.expected, accepted)
+		}
+	}
+}
+
+func TestCompileRegex_FoldCaseMissingVariant(t *testing.T) {
+	alphabet := []Symbol{"A", "b"}
+
+	if _, err := CompileRegex("a", alphabet); err == nil {
+		t.Error("Expected error for a symbol outside the alphabet, but got none")
+	}
+
+	nfa, err := CompileRegex("aB", alphabet, FoldCase())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if accepted, _ := nfa.Accepts("Ab"); !accepted {
+		t.Error("Expected folded literals to use the spelling present in the alphabet")
+	}
+}
+
+func TestCompileGlob_FoldCase(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"*.GO", "main.go", true},
+		{"README*", "readme.md", true},
+		{"[a-c]?", "Bx", true},
+		{"[^a-c]?", "Bx", false},
+		{"*.go", "main.rs", false},
+	}
+
+	for _, test := range tests {
+		dfa, err := CompileGlob(test.pattern, FoldCase())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accepted, _ := dfa.Accepts(test.input); accepted != test.expected {
+			t.Errorf("Glob '%s' on '%s': expected %v, got %v", test.pattern, test.input, test.expected, accepted)
+		}
+	}
+}
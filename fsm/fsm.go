@@ -2,6 +2,7 @@ package fsm
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 )
 
@@ -17,6 +18,13 @@ type FiniteAutomaton struct {
 	InitialState       State
 	AcceptingStates    []State
 	TransitionFunction TransitionFunction
+	Table              TransitionTable
+	Sparse             *SparseTable
+	Logger             *slog.Logger
+	Normalizer         SymbolNormalizer
+	Decoder            Decoder
+
+	symbols *symbolIndex
 }
 
 func NewFiniteAutomaton(
@@ -26,38 +34,57 @@ func NewFiniteAutomaton(
 	acceptingStates []State,
 	transitionFunction TransitionFunction,
 ) *FiniteAutomaton {
-	return &FiniteAutomaton{
+	fa := &FiniteAutomaton{
 		States:             states,
 		Alphabet:           alphabet,
 		InitialState:       initialState,
 		AcceptingStates:    acceptingStates,
 		TransitionFunction: transitionFunction,
 	}
+	fa.Intern()
+	return fa
 }
 
 func (fa *FiniteAutomaton) ProcessInput(input string) (State, error) {
 	currentState := fa.InitialState
+	debug := debugEnabled(fa.Logger)
+	if run, ok := fa.fastPath(debug); ok {
+		if finalState, ok := run.processRunes(input); ok {
+			return finalState, nil
+		}
+	}
 
 	for i, char := range input {
-		symbol := Symbol(string(char))
+		symbol := fa.normalize(runeSymbol(char))
 
 		if !fa.isValidSymbol(symbol) {
+			logInvalidSymbol(fa.Logger, currentState, symbol, i)
 			return "", fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, fa.Alphabet)
 		}
 
-		currentState = fa.TransitionFunction(currentState, symbol)
+		next := fa.TransitionFunction(currentState, symbol)
+		if debug {
+			logTransition(fa.Logger, Transition{From: currentState, Symbol: symbol, To: next}, i)
+		}
+		currentState = next
 	}
 
 	return currentState, nil
 }
 
+func (fa *FiniteAutomaton) Accepts(input string) (bool, error) {
+	finalState, err := fa.ProcessInput(input)
+	if err != nil {
+		return false, err
+	}
+	return fa.IsAcceptingState(finalState), nil
+}
+
 func (fa *FiniteAutomaton) isValidSymbol(symbol Symbol) bool {
-	for _, s := range fa.Alphabet {
-		if s == symbol {
-			return true
-		}
+	if idx := fa.interned(); idx != nil {
+		return idx.symbolID(symbol) >= 0
 	}
-	return false
+	return containsSymbol(nil, fa.Alphabet, symbol)
 }
 
 func (fa *FiniteAutomaton) IsAcceptingState(state State) bool {
@@ -287,3 +287,35 @@ func contains(s, substr string) bool {
 		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
 			contains(s[1:len(s)-1], substr)))
 }
+
+func TestAccepts(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"", true},
+		{"0", true},
+		{"11", true},
+		{"1", false},
+		{"10", false},
+		{"110", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			accepted, err := fa.Accepts(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if accepted != test.expected {
+				t.Errorf("For input '%s': expected accepted=%v, got %v", test.input, test.expected, accepted)
+			}
+		})
+	}
+
+	if _, err := fa.Accepts("012"); err == nil {
+		t.Error("Expected error for invalid input, but got none")
+	}
+}
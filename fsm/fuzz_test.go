@@ -0,0 +1,100 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func TestSeedCorpus(t *testing.T) {
+	corpus := SeedCorpus([]Symbol{"0", "1"}, 3)
+
+	if len(corpus) != 15 {
+		t.Fatalf("Expected 15 seeds, got %d", len(corpus))
+	}
+
+	expectedPrefix := []string{"", "0", "1", "00", "01", "10", "11", "000"}
+	for i, seed := range expectedPrefix {
+		if corpus[i] != seed {
+			t.Errorf("Expected seed '%s' at position %d, got '%s'", seed, i, corpus[i])
+		}
+	}
+}
+
+func FuzzProcessInput(f *testing.F) {
+	fa := newRunnerTestAutomaton()
+	for _, seed := range SeedCorpus(fa.Alphabet, 4) {
+		f.Add(seed)
+	}
+	f.Add("0120")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		finalState, err := fa.ProcessInput(input)
+		if err != nil {
+			return
+		}
+
+		runner := NewRunner(fa)
+		runnerState, err := runner.Feed(input)
+		if err != nil {
+			t.Fatalf("Runner rejected input '%s' accepted by ProcessInput: %v", input, err)
+		}
+		if runnerState != finalState {
+			t.Fatalf("For input '%s': ProcessInput returned %s but Runner reached %s", input, finalState, runnerState)
+		}
+
+		valid := false
+		for _, state := range fa.States {
+			if state == finalState {
+				valid = true
+			}
+		}
+		if !valid {
+			t.Fatalf("For input '%s': final state %s is not a declared state", input, finalState)
+		}
+	})
+}
+
+func fuzzNFA(data []byte) *NFA {
+	states := []State{"A", "B", "C", "D"}
+	symbols := []Symbol{Epsilon, "a", "b"}
+
+	nfa := NewNFA(states, []Symbol{"a", "b"}, "A", nil)
+	if len(data) > 0 {
+		for i, state := range states {
+			if data[0]&(1<<i) != 0 {
+				nfa.AcceptingStates = append(nfa.AcceptingStates, state)
+			}
+		}
+		data = data[1:]
+	}
+
+	for len(data) >= 2 {
+		from := states[int(data[0])%len(states)]
+		symbol := symbols[int(data[0]>>2)%len(symbols)]
+		to := states[int(data[1])%len(states)]
+		nfa.AddTransition(from, symbol, to)
+		data = data[2:]
+	}
+
+	return nfa
+}
+
+func FuzzNFAToDFA(f *testing.F) {
+	f.Add([]byte{0x08, 0x04, 0x01, 0x05, 0x02}, "abab")
+	f.Add([]byte{0x01}, "")
+	f.Add([]byte{0x0f, 0x00, 0x01, 0x01, 0x02, 0x0a, 0x03}, "ba")
+
+	f.Fuzz(func(t *testing.T, data []byte, input string) {
+		nfa := fuzzNFA(data)
+		dfa := nfa.ToDFA()
+
+		expected, nfaErr := nfa.Accepts(input)
+		accepted, dfaErr := dfa.Accepts(input)
+
+		if (nfaErr == nil) != (dfaErr == nil) {
+			t.Fatalf("For input '%s': NFA error %v but DFA error %v", input, nfaErr, dfaErr)
+		}
+		if accepted != expected {
+			t.Fatalf("For input '%s': NFA accepted=%v but DFA accepted=%v", input, expected, accepted)
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package fsm
+
+import "fmt"
+
+// ASCIIByteAlphabet holds one symbol for each 7-bit byte value, 0 to 127.
+func ASCIIByteAlphabet() []Symbol {
+	alphabet := make([]Symbol, 0, 128)
+	for b := 0; b < 128; b++ {
+		alphabet = append(alphabet, Symbol(string(rune(b))))
+	}
+	return alphabet
+}
+
+// CompileGlob compiles pattern to a DFA over ASCIIByteAlphabet plus Wildcard.
+// Any other rune, or any byte above 127 for AcceptsBytes, is read as Wildcard,
+// so `*`, `?` and negated classes match non-ASCII paths.
+func CompileGlob(pattern string, options ...CompileOption) (*FiniteAutomaton, error) {
+	alphabet := append(ASCIIByteAlphabet(), Wildcard)
+	node, err := parseGlob(pattern, alphabet, options...)
+	if err != nil {
+		return nil, err
+	}
+	dfa, _, err := compileNode(node, alphabet).toDFA(dfaStateLimit(options))
+	if err != nil {
+		return nil, err
+	}
+	dfa.Normalizer = OtherSymbols(alphabet)
+	return dfa, nil
+}
+
+func parseGlob(pattern string, alphabet []Symbol, options ...CompileOption) (*regexNode, error) {
+	p := &regexParser{pattern: []rune(pattern), alphabet: alphabet}
+	for _, option := range options {
+		option(p)
+	}
+	notSeparator := func(r rune) bool { return r != '/' }
+
+	concat := &regexNode{kind: regexConcat}
+	for p.pos < len(p.pattern) {
+		char := p.pattern[p.pos]
+		switch char {
+		case '*':
+			p.pos++
+			concat.children = append(concat.children, &regexNode{
+				kind:     regexRepeat,
+				children: []*regexNode{p.set(notSeparator)},
+				min:      0,
+				max:      -1,
+			})
+		case '?':
+			p.pos++
+			concat.children = append(concat.children, p.set(notSeparator))
+		case '[':
+			node, err := p.parseGlobClass()
+			if err != nil {
+				return nil, err
+			}
+			concat.children = append(concat.children, node)
+		case '\\':
+			p.pos++
+			escaped, ok := p.peek()
+			if !ok {
+				return nil, p.globErrorf("trailing backslash")
+			}
+			p.pos++
+			node, err := p.globLiteral(escaped)
+			if err != nil {
+				return nil, err
+			}
+			concat.children = append(concat.children, node)
+		default:
+			p.pos++
+			node, err := p.globLiteral(char)
+			if err != nil {
+				return nil, err
+			}
+			concat.children = append(concat.children, node)
+		}
+	}
+
+	if len(concat.children) == 0 {
+		return &regexNode{kind: regexEmpty}, nil
+	}
+	return concat, nil
+}
+
+func (p *regexParser) globErrorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid glob %q at position %d: %s", string(p.pattern), p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *regexParser) globLiteral(char rune) (*regexNode, error) {
+	if char >= 128 {
+		return nil, p.globErrorf("character %q is not a single byte", char)
+	}
+	return &regexNode{kind: regexSet, symbols: p.foldedSymbols(char)}, nil
+}
+
+func (p *regexParser) parseGlobClass() (*regexNode, error) {
+	p.pos++
+	negated := false
+	if char, ok := p.peek(); ok && char == '^' {
+		negated = true
+		p.pos++
+	}
+
+	var ranges [][2]rune
+	for {
+		char, ok := p.peek()
+		if !ok {
+			return nil, p.globErrorf("missing ']'")
+		}
+		if char == ']' {
+			if len(ranges) == 0 {
+				return nil, p.globErrorf("empty character class")
+			}
+			p.pos++
+			break
+		}
+
+		low, err := p.globClassChar()
+		if err != nil {
+			return nil, err
+		}
+		high := low
+		if next, ok := p.peek(); ok && next == '-' {
+			p.pos++
+			if high, err = p.globClassChar(); err != nil {
+				return nil, err
+			}
+			if high < low {
+				return nil, p.globErrorf("invalid range %c-%c", low, high)
+			}
+		}
+		ranges = append(ranges, [2]rune{low, high})
+	}
+
+	return p.set(func(r rune) bool {
+		for _, bounds := range ranges {
+			if p.anyVariant(r, func(v rune) bool { return v >= bounds[0] && v <= bounds[1] }) {
+				return !negated
+			}
+		}
+		return negated
+	}), nil
+}
+
+func (p *regexParser) globClassChar() (rune, error) {
+	char, ok := p.peek()
+	if !ok || char == ']' || char == '-' {
+		return 0, p.globErrorf("missing character in class")
+	}
+	p.pos++
+
+	if char == '\\' {
+		if char, ok = p.peek(); !ok {
+			return 0, p.globErrorf("trailing backslash")
+		}
+		p.pos++
+	}
+	return char, nil
+}
@@ -0,0 +1,98 @@
+package fsm
+
+import (
+	"path"
+	"testing"
+)
+
+func TestCompileGlob_MatchesPathMatch(t *testing.T) {
+	patterns := []string{
+		"*.go",
+		"cmd/*",
+		"*/*_test.go",
+		"a?c",
+		"[abc]*",
+		"[^a-c]?",
+		"[a-c0-9]x",
+		"file\\*",
+		"\\[x]",
+		"*",
+		"",
+		"a*b*c",
+	}
+	inputs := []string{
+		"", "a", "abc", "a/c", "main.go", "cmd/main.go", "cmd/sub/x.go",
+		"fsm/glob_test.go", "bx", "9x", "dx", "-x", "file*", "fileX", "[x]",
+		"a/b", "axbyc", "ab/c", "d", "/", "café.go", "日本/x.go", "é", "éx",
+		"a日c", "cmd/ü",
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			dfa, err := CompileGlob(pattern)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for _, input := range inputs {
+				expected, err := path.Match(pattern, input)
+				if err != nil {
+					t.Fatalf("Reference error for '%s': %v", pattern, err)
+				}
+
+				accepted, err := dfa.Accepts(input)
+				if err != nil {
+					t.Fatalf("Unexpected error for input '%s': %v", input, err)
+				}
+				if accepted != expected {
+					t.Errorf("Glob '%s' on '%s': expected %v, got %v", pattern, input, expected, accepted)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileGlob_Errors(t *testing.T) {
+	for _, pattern := range []string{"[", "[]", "[a", "[z-a]", "a\\", "[a-]", "é"} {
+		if _, err := CompileGlob(pattern); err == nil {
+			t.Errorf("Expected error for glob '%s', but got none", pattern)
+		}
+	}
+}
+
+func TestCompileGlob_NonASCIIBytes(t *testing.T) {
+	dfa, err := CompileGlob("*.txt")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]bool{"café.txt": true, "日本語.txt": true, "naïve/x.txt": false, "café.tx": false}
+	for input, expected := range tests {
+		accepted, err := dfa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		acceptedBytes, err := dfa.AcceptsBytes([]byte(input))
+		if err != nil {
+			t.Fatalf("Unexpected error for bytes of '%s': %v", input, err)
+		}
+		if accepted != expected || acceptedBytes != expected {
+			t.Errorf("Input '%s': expected %v, got %v (bytes %v)", input, expected, accepted, acceptedBytes)
+		}
+	}
+
+	single, _ := CompileGlob("caf?")
+	if accepted, _ := single.Accepts("café"); !accepted {
+		t.Error("Expected '?' to match one non-ASCII rune")
+	}
+	if accepted, _ := single.AcceptsBytes([]byte("café")); accepted {
+		t.Error("Expected '?' to match one byte of the UTF-8 encoding")
+	}
+}
+
+func TestASCIIByteAlphabet(t *testing.T) {
+	alphabet := ASCIIByteAlphabet()
+	if len(alphabet) != 128 || alphabet[0] != "\x00" || alphabet[127] != "\x7f" {
+		t.Errorf("Unexpected byte alphabet of length %d", len(alphabet))
+	}
+}
@@ -0,0 +1,146 @@
+package fsm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	grailStart = "(START)"
+	grailFinal = "(FINAL)"
+)
+
+func ReadGrail(r io.Reader) (*NFA, error) {
+	nfa := NewNFA(nil, nil, "", nil)
+	seenStates := make(map[State]bool)
+	seenSymbols := make(map[Symbol]bool)
+	addState := func(state State) {
+		if !seenStates[state] {
+			seenStates[state] = true
+			nfa.States = append(nfa.States, state)
+		}
+	}
+
+	var initial []State
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected 3 fields, got %d", line, len(fields))
+		}
+
+		from, label, to := fields[0], fields[1], fields[2]
+		switch {
+		case from == grailStart && label == "|-":
+			addState(State(to))
+			initial = append(initial, State(to))
+		case to == grailFinal && label == "-|":
+			addState(State(from))
+			nfa.AcceptingStates = append(nfa.AcceptingStates, State(from))
+		case from == grailStart || to == grailFinal || label == "|-" || label == "-|":
+			return nil, fmt.Errorf("line %d: malformed start or final marker", line)
+		default:
+			addState(State(from))
+			addState(State(to))
+			symbol := Symbol(label)
+			if !seenSymbols[symbol] {
+				seenSymbols[symbol] = true
+				nfa.Alphabet = append(nfa.Alphabet, symbol)
+			}
+			nfa.AddTransition(State(from), symbol, State(to))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Grail machine: %w", err)
+	}
+
+	switch len(initial) {
+	case 0:
+		return nil, fmt.Errorf("grail machine has no %s line", grailStart)
+	case 1:
+		nfa.InitialState = initial[0]
+	default:
+		nfa.InitialState = "(start)"
+		nfa.States = append([]State{nfa.InitialState}, nfa.States...)
+		for _, state := range initial {
+			nfa.AddTransition(nfa.InitialState, Epsilon, state)
+		}
+	}
+
+	return nfa, nil
+}
+
+func (n *NFA) AsDFA() (*FiniteAutomaton, error) {
+	table := make(TransitionTable)
+	needsDeadState := false
+	for _, state := range n.States {
+		if len(n.Transitions[state][Epsilon]) > 0 {
+			return nil, fmt.Errorf("state '%s' has epsilon transitions", state)
+		}
+		for _, symbol := range n.Alphabet {
+			switch targets := n.Transitions[state][symbol]; len(targets) {
+			case 0:
+				table.Set(state, symbol, DeadState)
+				needsDeadState = true
+			case 1:
+				table.Set(state, symbol, targets[0])
+			default:
+				return nil, fmt.Errorf("state '%s' has %d transitions on '%s'", state, len(targets), symbol)
+			}
+		}
+	}
+
+	states := n.States
+	if needsDeadState {
+		for _, state := range states {
+			if state == DeadState {
+				return nil, fmt.Errorf("state name '%s' is reserved for the added sink state", DeadState)
+			}
+		}
+		for _, symbol := range n.Alphabet {
+			table.Set(DeadState, symbol, DeadState)
+		}
+		states = append(append([]State(nil), states...), DeadState)
+	}
+
+	return NewTableAutomaton(states, n.Alphabet, n.InitialState, n.AcceptingStates, table), nil
+}
+
+func (fa *FiniteAutomaton) WriteGrail(w io.Writer) error {
+	numbers := make(map[State]int, len(fa.States))
+	for i, state := range fa.States {
+		numbers[state] = i
+	}
+	for _, symbol := range fa.Alphabet {
+		if !validGrailSymbol(symbol) {
+			return fmt.Errorf("symbol %q cannot be written in Grail format", symbol)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s |- %d\n", grailStart, numbers[fa.InitialState])
+	for _, transition := range fa.Transitions() {
+		to, ok := numbers[transition.To]
+		if !ok {
+			return fmt.Errorf("transition from '%s' on '%s' leads to undeclared state '%s'", transition.From, transition.Symbol, transition.To)
+		}
+		fmt.Fprintf(bw, "%d %s %d\n", numbers[transition.From], transition.Symbol, to)
+	}
+	for _, state := range fa.States {
+		if fa.IsAcceptingState(state) {
+			fmt.Fprintf(bw, "%d -| %s\n", numbers[state], grailFinal)
+		}
+	}
+	return bw.Flush()
+}
+
+func validGrailSymbol(symbol Symbol) bool {
+	return symbol != "" && !strings.ContainsFunc(string(symbol), func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+	}) && symbol != "|-" && symbol != "-|"
+}
@@ -0,0 +1,112 @@
+package fsm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadGrail(t *testing.T) {
+	nfa, err := ReadGrail(strings.NewReader(`(START) |- 0
+0 a 0
+0 b 1
+1 a 2
+
+2 -| (FINAL)
+`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if nfa.InitialState != "0" || len(nfa.States) != 3 || len(nfa.Alphabet) != 2 {
+		t.Fatalf("Unexpected machine: initial %s, states %v, alphabet %v", nfa.InitialState, nfa.States, nfa.Alphabet)
+	}
+
+	fa, err := nfa.AsDFA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fa.States) != 4 || fa.States[3] != DeadState {
+		t.Errorf("Expected a sink state for missing transitions, got %v", fa.States)
+	}
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"ba", true},
+		{"aaba", true},
+		{"b", false},
+		{"bab", false},
+		{"", false},
+	}
+	for _, test := range tests {
+		if accepted, _ := fa.Accepts(test.input); accepted != test.expected {
+			t.Errorf("For input %q: expected %v, got %v", test.input, test.expected, accepted)
+		}
+	}
+}
+
+func TestReadGrail_Nondeterministic(t *testing.T) {
+	nfa, err := ReadGrail(strings.NewReader("(START) |- 0\n(START) |- 1\n0 a 2\n1 b 2\n0 a 0\n2 -| (FINAL)\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for input, expected := range map[string]bool{"a": true, "b": true, "aa": true, "ab": false} {
+		if accepted, _ := nfa.Accepts(input); accepted != expected {
+			t.Errorf("For input %q: expected %v, got %v", input, expected, accepted)
+		}
+	}
+	if _, err := nfa.AsDFA(); err == nil {
+		t.Error("Expected AsDFA to reject a nondeterministic machine, but got none")
+	}
+}
+
+func TestReadGrail_Errors(t *testing.T) {
+	tests := []struct {
+		description string
+		machine     string
+	}{
+		{"no start", "0 a 1\n1 -| (FINAL)\n"},
+		{"wrong field count", "(START) |- 0\n0 a\n"},
+		{"bad marker", "(START) -| 0\n"},
+	}
+
+	for _, test := range tests {
+		if _, err := ReadGrail(strings.NewReader(test.machine)); err == nil {
+			t.Errorf("Expected error for %s, but got none", test.description)
+		}
+	}
+}
+
+func TestWriteGrail_RoundTrip(t *testing.T) {
+	fa := newDivisibleByThree()
+
+	var buf bytes.Buffer
+	if err := fa.WriteGrail(&buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "(START) |- 0\n0 0 0\n0 1 1\n1 0 2\n1 1 0\n2 0 1\n2 1 2\n0 -| (FINAL)\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	nfa, err := ReadGrail(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restored, err := nfa.AsDFA()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if equal, _, err := Equivalent(fa, restored); err != nil || !equal {
+		t.Error("Expected the Grail round trip to preserve the language")
+	}
+
+	spaced := NewTableAutomaton([]State{"A"}, []Symbol{" "}, "A", nil, TransitionTable{})
+	if err := spaced.WriteGrail(&bytes.Buffer{}); err == nil {
+		t.Error("Expected error for a whitespace symbol, but got none")
+	}
+}
@@ -0,0 +1,106 @@
+package fsm
+
+func (fa *FiniteAutomaton) successors(state State) []State {
+	var result []State
+	seen := make(map[State]bool, len(fa.Alphabet))
+	for _, symbol := range fa.Alphabet {
+		next := fa.TransitionFunction(state, symbol)
+		if !seen[next] {
+			seen[next] = true
+			result = append(result, next)
+		}
+	}
+	return result
+}
+
+func (fa *FiniteAutomaton) SCCs() [][]State {
+	index := 0
+	indices := make(map[State]int, len(fa.States))
+	lowlink := make(map[State]int, len(fa.States))
+	onStack := make(map[State]bool, len(fa.States))
+	var stack []State
+	var components [][]State
+
+	var strongConnect func(state State)
+	strongConnect = func(state State) {
+		indices[state] = index
+		lowlink[state] = index
+		index++
+		stack = append(stack, state)
+		onStack[state] = true
+
+		for _, next := range fa.successors(state) {
+			if _, visited := indices[next]; !visited {
+				strongConnect(next)
+				lowlink[state] = min(lowlink[state], lowlink[next])
+			} else if onStack[next] {
+				lowlink[state] = min(lowlink[state], indices[next])
+			}
+		}
+
+		if lowlink[state] == indices[state] {
+			var component []State
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				component = append(component, top)
+				if top == state {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for _, state := range fa.States {
+		if _, visited := indices[state]; !visited {
+			strongConnect(state)
+		}
+	}
+
+	return components
+}
+
+func (fa *FiniteAutomaton) HasCycle() bool {
+	for _, component := range fa.SCCs() {
+		if len(component) > 1 {
+			return true
+		}
+		for _, next := range fa.successors(component[0]) {
+			if next == component[0] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (fa *FiniteAutomaton) CyclesThrough(state State) [][]State {
+	var cycles [][]State
+	path := []State{state}
+	onPath := map[State]bool{state: true}
+
+	var walk func(current State)
+	walk = func(current State) {
+		for _, next := range fa.successors(current) {
+			if next == state {
+				cycle := make([]State, len(path), len(path)+1)
+				copy(cycle, path)
+				cycles = append(cycles, append(cycle, state))
+				continue
+			}
+			if onPath[next] {
+				continue
+			}
+			onPath[next] = true
+			path = append(path, next)
+			walk(next)
+			path = path[:len(path)-1]
+			onPath[next] = false
+		}
+	}
+	walk(state)
+
+	return cycles
+}
@@ -0,0 +1,85 @@
+package fsm
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func newChainAutomaton() *FiniteAutomaton {
+	table := TransitionTable{}
+	table.Set("A", "x", "B")
+	table.Set("B", "x", "C")
+	table.Set("C", "x", "C")
+	return NewTableAutomaton([]State{"A", "B", "C"}, []Symbol{"x"}, "A", []State{"C"}, table)
+}
+
+func componentKeys(components [][]State) []string {
+	var keys []string
+	for _, component := range components {
+		names := make([]string, len(component))
+		for i, state := range component {
+			names[i] = string(state)
+		}
+		sort.Strings(names)
+		keys = append(keys, strings.Join(names, ","))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestSCCs(t *testing.T) {
+	keys := componentKeys(newRunnerTestAutomaton().SCCs())
+	if len(keys) != 1 || keys[0] != "S0,S1,S2" {
+		t.Errorf("Expected a single component S0,S1,S2, got %v", keys)
+	}
+
+	keys = componentKeys(newChainAutomaton().SCCs())
+	expected := []string{"A", "B", "C"}
+	if strings.Join(keys, "|") != strings.Join(expected, "|") {
+		t.Errorf("Expected components %v, got %v", expected, keys)
+	}
+}
+
+func TestHasCycle(t *testing.T) {
+	if !newRunnerTestAutomaton().HasCycle() {
+		t.Error("Expected mod-three automaton to have a cycle")
+	}
+	if !newChainAutomaton().HasCycle() {
+		t.Error("Expected self-loop on C to count as a cycle")
+	}
+
+	noTransitions := NewFiniteAutomaton([]State{"A", "B"}, nil, "A", nil, func(currentState State, symbol Symbol) State {
+		return currentState
+	})
+	if noTransitions.HasCycle() {
+		t.Error("Expected automaton without transitions to be acyclic")
+	}
+}
+
+func TestCyclesThrough(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	cycles := fa.CyclesThrough("S1")
+	var rendered []string
+	for _, cycle := range cycles {
+		names := make([]string, len(cycle))
+		for i, state := range cycle {
+			names[i] = string(state)
+		}
+		rendered = append(rendered, strings.Join(names, "->"))
+	}
+	sort.Strings(rendered)
+
+	expected := []string{"S1->S0->S1", "S1->S2->S1"}
+	if strings.Join(rendered, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected cycles %v, got %v", expected, rendered)
+	}
+
+	if cycles := newChainAutomaton().CyclesThrough("A"); len(cycles) != 0 {
+		t.Errorf("Expected no cycles through A, got %v", cycles)
+	}
+	if cycles := newChainAutomaton().CyclesThrough("C"); len(cycles) != 1 {
+		t.Errorf("Expected the self-loop on C, got %v", cycles)
+	}
+}
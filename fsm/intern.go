@@ -0,0 +1,372 @@
+package fsm
+
+import (
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// symbolIndex interns an automaton's alphabet, and for table automata its
+// states and transitions, as small integers so the hot loops compare and
+// index ints instead of hashing strings.
+type symbolIndex struct {
+	alphabet []Symbol
+	// latin1 maps single-rune symbols below 256, which covers ASCII and
+	// every ByteSymbol, to their ID plus one; zero means not in the alphabet.
+	latin1  [256]int32
+	symbols map[Symbol]int32
+
+	// table is nil when the automaton has no Table or it is too large to
+	// expand. It is replaced when an edit to the Table is detected.
+	table atomic.Pointer[denseTable]
+}
+
+// denseTable is a TransitionTable with states and symbols interned.
+type denseTable struct {
+	states   []State
+	stateIDs map[State]int32
+	// next[state*len(alphabet)+symbol] is the target state's ID.
+	next []int32
+
+	// key and version identify the Table this was built from; seen is the
+	// version's edit count when next last matched it.
+	key     uintptr
+	version *tableVersion
+	seen    atomic.Uint64
+}
+
+// tableVersion counts the Set calls on one interned TransitionTable. refs is
+// the number of dense tables built from it, guarded by tableVersionsMu.
+type tableVersion struct {
+	edits atomic.Uint64
+	refs  int
+}
+
+// tableVersions maps the address of every interned table's map to its
+// tableVersion. Set only bumps the count of its own table, so editing one
+// table never makes automata built from another recheck theirs.
+var (
+	tableVersions   sync.Map
+	tableVersionsMu sync.Mutex
+)
+
+func tableKey(t TransitionTable) uintptr {
+	return reflect.ValueOf(t).Pointer()
+}
+
+// noteTableEdit records an edit to t if any automaton has interned it.
+func noteTableEdit(t TransitionTable) {
+	if version, ok := tableVersions.Load(tableKey(t)); ok {
+		version.(*tableVersion).edits.Add(1)
+	}
+}
+
+func retainTableVersion(key uintptr) *tableVersion {
+	tableVersionsMu.Lock()
+	defer tableVersionsMu.Unlock()
+	existing, _ := tableVersions.Load(key)
+	version, _ := existing.(*tableVersion)
+	if version == nil {
+		version = &tableVersion{}
+		tableVersions.Store(key, version)
+	}
+	version.refs++
+	return version
+}
+
+// release drops t's reference to its table's version once t is unreachable.
+func (t *denseTable) release() {
+	tableVersionsMu.Lock()
+	defer tableVersionsMu.Unlock()
+	if t.version.refs--; t.version.refs == 0 {
+		tableVersions.CompareAndDelete(t.key, t.version)
+	}
+}
+
+// maxInternedTransitions caps the dense table Intern builds, 4 MiB of int32s.
+// Larger machines keep only the interned alphabet; SparseTable is the
+// compact representation for them.
+const maxInternedTransitions = 1 << 20
+
+// Intern rebuilds the integer index the constructors create for the alphabet
+// and transition table. Reassigning Alphabet and editing Table through Set or
+// SetDefault are detected automatically; Intern is only needed after writing
+// to the Table's maps directly. Automata built as struct literals are not
+// interned and use the string path.
+func (fa *FiniteAutomaton) Intern() {
+	idx := &symbolIndex{
+		alphabet: fa.Alphabet,
+		symbols:  make(map[Symbol]int32, len(fa.Alphabet)),
+	}
+	for i, symbol := range fa.Alphabet {
+		if _, seen := idx.symbols[symbol]; seen {
+			continue
+		}
+		idx.symbols[symbol] = int32(i)
+		if r, ok := singleRune(symbol); ok && r < 256 {
+			idx.latin1[r] = int32(i) + 1
+		}
+	}
+
+	if table := fa.internTable(); table != nil {
+		idx.table.Store(table)
+	}
+	fa.symbols = idx
+}
+
+func (fa *FiniteAutomaton) internTable() *denseTable {
+	if fa.Table == nil || len(fa.States)*len(fa.Alphabet) > maxInternedTransitions {
+		return nil
+	}
+
+	// Register before reading the table, so edits made while it is read
+	// are seen on the next run.
+	key := tableKey(fa.Table)
+	version := retainTableVersion(key)
+	t := &denseTable{stateIDs: make(map[State]int32, len(fa.States)), key: key, version: version}
+	t.seen.Store(version.edits.Load())
+	runtime.SetFinalizer(t, (*denseTable).release)
+
+	for _, state := range fa.States {
+		t.intern(state)
+	}
+	t.intern(fa.InitialState)
+
+	// Undeclared transition targets are interned as they are found, so the
+	// loop also fills in their rows.
+	for s := 0; s < len(t.states); s++ {
+		for _, symbol := range fa.Alphabet {
+			t.next = append(t.next, t.intern(fa.Table.Next(t.states[s], symbol)))
+		}
+	}
+	return t
+}
+
+func (t *denseTable) intern(state State) int32 {
+	if id, ok := t.stateIDs[state]; ok {
+		return id
+	}
+	id := int32(len(t.states))
+	t.states = append(t.states, state)
+	t.stateIDs[state] = id
+	return id
+}
+
+// matches reports whether every interned transition still agrees with the
+// live Table.
+func (t *denseTable) matches(table TransitionTable, alphabet []Symbol) bool {
+	if table == nil {
+		return false
+	}
+	for s, state := range t.states {
+		row := t.next[s*len(alphabet) : (s+1)*len(alphabet)]
+		for a, symbol := range alphabet {
+			if t.states[row[a]] != table.Next(state, symbol) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// currentTable returns the interned table, first rechecking it against
+// fa.Table if that table has been edited or replaced since the last check.
+func (fa *FiniteAutomaton) currentTable(idx *symbolIndex) *denseTable {
+	t := idx.table.Load()
+	if t == nil {
+		return nil
+	}
+	edits := t.version.edits.Load()
+	sameTable := t.key == tableKey(fa.Table)
+	if sameTable && t.seen.Load() == edits {
+		return t
+	}
+	if !sameTable || !t.matches(fa.Table, idx.alphabet) {
+		if t = fa.internTable(); t == nil {
+			return nil
+		}
+		idx.table.Store(t)
+		return t
+	}
+	t.seen.Store(edits)
+	return t
+}
+
+// interned returns the index if it still describes fa.Alphabet.
+func (fa *FiniteAutomaton) interned() *symbolIndex {
+	idx := fa.symbols
+	if idx == nil || !sameSlice(idx.alphabet, fa.Alphabet) {
+		return nil
+	}
+	return idx
+}
+
+func (idx *symbolIndex) runeID(r rune) int32 {
+	if r >= 0 && r < 256 {
+		return idx.latin1[r] - 1
+	}
+	if id, ok := idx.symbols[Symbol(string(r))]; ok {
+		return id
+	}
+	return -1
+}
+
+func (idx *symbolIndex) symbolID(symbol Symbol) int32 {
+	if r, ok := singleRune(symbol); ok {
+		return idx.runeID(r)
+	}
+	if id, ok := idx.symbols[symbol]; ok {
+		return id
+	}
+	return -1
+}
+
+func singleRune(symbol Symbol) (rune, bool) {
+	r, size := utf8.DecodeRuneInString(string(symbol))
+	return r, size > 0 && size == len(symbol) && r != utf8.RuneError
+}
+
+// internedRun steps an automaton on interned IDs, through either the dense
+// table built by Intern or the automaton's SparseTable.
+type internedRun struct {
+	idx     *symbolIndex
+	dense   *denseTable
+	sparse  *SparseTable
+	initial int32
+}
+
+// fastPath reports whether a run can stay on integer IDs: the automaton is a
+// current table or sparse automaton without a normalizer, and nothing needs
+// per-transition logging.
+func (fa *FiniteAutomaton) fastPath(debug bool) (internedRun, bool) {
+	if debug || fa.Normalizer != nil {
+		return internedRun{}, false
+	}
+	idx := fa.interned()
+	if idx == nil {
+		return internedRun{}, false
+	}
+
+	if dense := fa.currentTable(idx); dense != nil {
+		initial, ok := dense.stateIDs[fa.InitialState]
+		return internedRun{idx: idx, dense: dense, initial: initial}, ok
+	}
+	if fa.Sparse != nil && sameSlice(fa.Sparse.alphabet, fa.Alphabet) {
+		initial, ok := fa.Sparse.stateIDs[fa.InitialState]
+		return internedRun{idx: idx, sparse: fa.Sparse, initial: initial}, ok && int(initial) < len(fa.Sparse.defaults)
+	}
+	return internedRun{}, false
+}
+
+func (r internedRun) next(state, symbol int32) int32 {
+	if r.sparse != nil {
+		return r.sparse.step(state, symbol)
+	}
+	return r.dense.next[state*int32(len(r.idx.alphabet))+symbol]
+}
+
+func (r internedRun) stateCount() int {
+	if r.sparse != nil {
+		return len(r.sparse.defaults)
+	}
+	return len(r.dense.states)
+}
+
+func (r internedRun) state(id int32) State {
+	if r.sparse != nil {
+		return r.sparse.states[id]
+	}
+	return r.dense.states[id]
+}
+
+// processRunes runs the interned machine over input. It reports false on the
+// first symbol outside the alphabet so the caller can redo the run on the
+// string path, which produces the error and log record.
+func (r internedRun) processRunes(input string) (State, bool) {
+	state, ok := r.runRunes(r.initial, input)
+	if !ok {
+		return "", false
+	}
+	return r.state(state), true
+}
+
+func (r internedRun) runRunes(state int32, input string) (int32, bool) {
+	for _, char := range input {
+		symbol := r.idx.runeID(char)
+		if symbol < 0 {
+			return 0, false
+		}
+		state = r.next(state, symbol)
+	}
+	return state, true
+}
+
+func (r internedRun) processBytes(data []byte) (State, bool) {
+	state := r.initial
+	for _, b := range data {
+		symbol := r.idx.latin1[b] - 1
+		if symbol < 0 {
+			return "", false
+		}
+		state = r.next(state, symbol)
+	}
+	return r.state(state), true
+}
+
+// symbolSet is the membership-only counterpart of symbolIndex, used by the
+// automaton types that do not need symbol IDs.
+type symbolSet struct {
+	alphabet []Symbol
+	latin1   [256]bool
+	other    map[Symbol]bool
+}
+
+func newSymbolSet(alphabet []Symbol) *symbolSet {
+	set := &symbolSet{alphabet: alphabet, other: make(map[Symbol]bool)}
+	for _, symbol := range alphabet {
+		if r, ok := singleRune(symbol); ok && r < 256 {
+			set.latin1[r] = true
+		} else {
+			set.other[symbol] = true
+		}
+	}
+	return set
+}
+
+// containsSymbol reports whether symbol is in alphabet, using set while it
+// was built from that same slice and a linear scan otherwise.
+func containsSymbol(set *symbolSet, alphabet []Symbol, symbol Symbol) bool {
+	if set != nil && sameSlice(set.alphabet, alphabet) {
+		if r, ok := singleRune(symbol); ok && r < 256 {
+			return set.latin1[r]
+		}
+		return set.other[symbol]
+	}
+	for _, s := range alphabet {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func sameSlice(a, b []Symbol) bool {
+	return len(a) == len(b) && (len(a) == 0 || &a[0] == &b[0])
+}
+
+var asciiSymbols = func() (symbols [utf8.RuneSelf]Symbol) {
+	for r := range symbols {
+		symbols[r] = Symbol(string(rune(r)))
+	}
+	return symbols
+}()
+
+// runeSymbol is Symbol(string(r)) without allocating for ASCII runes.
+func runeSymbol(r rune) Symbol {
+	if r >= 0 && r < utf8.RuneSelf {
+		return asciiSymbols[r]
+	}
+	return Symbol(string(r))
+}
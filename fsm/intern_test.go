@@ -0,0 +1,237 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func uninterned(fa *FiniteAutomaton) *FiniteAutomaton {
+	plain := *fa
+	plain.symbols = nil
+	return &plain
+}
+
+func TestIntern_MatchesStringPath(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("A", "é", "B")
+	table.Set("A", "→", "Undeclared")
+	table.SetDefault("B", "A")
+	fa := NewTableAutomaton([]State{"A", "B"}, []Symbol{"a", "é", "→"}, "A", []State{"B"}, table)
+
+	for _, input := range []string{"", "a", "é", "éa", "é→", "→aé", "aaéaé", "é→→a"} {
+		expected, err := uninterned(fa).ProcessInput(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		state, err := fa.ProcessInput(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state != expected {
+			t.Errorf("Input '%s': expected %s, got %s", input, expected, state)
+		}
+	}
+}
+
+func TestIntern_ProcessBytes(t *testing.T) {
+	fa, err := NewByteAutomaton([]State{"Low", "High"}, "Low", []State{"High"}, []ClassTransition{
+		{From: "Low", Class: Range(0x80, 0xFF), To: "High"},
+		{From: "Low", Default: true, To: "Low"},
+		{From: "High", Default: true, To: "High"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, data := range [][]byte{nil, {0x00, 0x7F}, {0x10, 0xFF, 0x00}} {
+		expected, _ := uninterned(fa).ProcessBytes(data)
+		state, err := fa.ProcessBytes(data)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if state != expected {
+			t.Errorf("Data %v: expected %s, got %s", data, expected, state)
+		}
+	}
+}
+
+func TestIntern_InvalidSymbol(t *testing.T) {
+	fa := newEndsWith01DFA()
+
+	_, expected := uninterned(fa).ProcessInput("01x")
+	_, err := fa.ProcessInput("01x")
+	if err == nil {
+		t.Fatal("Expected error for invalid symbol, but got none")
+	}
+	if err.Error() != expected.Error() {
+		t.Errorf("Expected error %q, got %q", expected, err)
+	}
+}
+
+func TestIntern_IsValidSymbol(t *testing.T) {
+	fa := NewFiniteAutomaton([]State{"S"}, []Symbol{"0", "é", "→", "ab"}, "S", nil, func(State, Symbol) State { return "S" })
+
+	for symbol, valid := range map[Symbol]bool{"0": true, "é": true, "→": true, "ab": true, "a": false, "1": false, "": false, "\xff": false} {
+		if fa.isValidSymbol(symbol) != valid {
+			t.Errorf("Expected isValidSymbol(%q) to be %v", symbol, valid)
+		}
+	}
+}
+
+func TestIntern_AlphabetReassigned(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.Alphabet = append(fa.Alphabet, "x")
+	fa.Table.Set("C", "x", "A")
+
+	state, err := fa.ProcessInput("01x")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "A" {
+		t.Errorf("Expected A, got %s", state)
+	}
+}
+
+func TestIntern_TableEditedInPlace(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.Table.Set("B", "1", "Dead")
+
+	state, err := fa.ProcessInput("01")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "Dead" {
+		t.Errorf("Expected Dead after Set, got %s", state)
+	}
+
+	runner := NewRunner(fa)
+	if state, _ := runner.Feed("01"); state != "Dead" {
+		t.Errorf("Expected the runner to agree with ProcessInput, got %s", state)
+	}
+}
+
+func TestIntern_TableDefaultEditedInPlace(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.Table.SetDefault("A", "Dead")
+	delete(fa.Table["A"], "1")
+
+	if state, _ := fa.ProcessInput("1"); state != "Dead" {
+		t.Errorf("Expected the new default to apply, got %s", state)
+	}
+}
+
+func TestIntern_TableEditRecheckKeepsIndex(t *testing.T) {
+	fa := newEndsWith01DFA()
+	before := fa.symbols.table.Load()
+	edits := before.version.edits.Load()
+	TransitionTable{}.Set("X", "0", "Y")
+	newEndsWith01DFA().Table.Set("B", "1", "Dead")
+
+	if _, err := fa.ProcessInput("01"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fa.symbols.table.Load() != before || before.version.edits.Load() != edits {
+		t.Error("Expected edits to unrelated tables to leave this table's version alone")
+	}
+
+	fa.Table.Set("B", "1", "C")
+	if _, err := fa.ProcessInput("01"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if fa.symbols.table.Load() != before {
+		t.Error("Expected a Set that keeps every transition to keep the interned table")
+	}
+}
+
+func TestIntern_SharedTable(t *testing.T) {
+	first := newEndsWith01DFA()
+	second := NewTableAutomaton(first.States, first.Alphabet, first.InitialState, first.AcceptingStates, first.Table)
+	first.Table.Set("B", "1", "Dead")
+
+	for _, fa := range []*FiniteAutomaton{first, second} {
+		if state, _ := fa.ProcessInput("01"); state != "Dead" {
+			t.Errorf("Expected both automata to see the edit, got %s", state)
+		}
+	}
+}
+
+func TestIntern_TableReassigned(t *testing.T) {
+	fa := newEndsWith01DFA()
+	replacement := TransitionTable{}
+	for from, row := range fa.Table {
+		for symbol, to := range row {
+			replacement.Set(from, symbol, to)
+		}
+	}
+	replacement.Set("B", "1", "Dead")
+	fa.Table = replacement
+	fa.TransitionFunction = replacement.Next
+
+	if state, _ := fa.ProcessInput("01"); state != "Dead" {
+		t.Errorf("Expected the new table to be used, got %s", state)
+	}
+}
+
+func TestIntern_DirectMapWriteNeedsIntern(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.Table["B"]["1"] = "Dead"
+	fa.Intern()
+
+	if state, _ := fa.ProcessInput("01"); state != "Dead" {
+		t.Errorf("Expected Dead after Intern, got %s", state)
+	}
+}
+
+func TestIntern_InitialStateReassigned(t *testing.T) {
+	fa := newEndsWith01DFA()
+	fa.InitialState = "Elsewhere"
+
+	state, err := fa.ProcessInput("0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "Elsewhere" {
+		t.Errorf("Expected the implicit self-loop on Elsewhere, got %s", state)
+	}
+}
+
+func TestContainsSymbol(t *testing.T) {
+	alphabet := []Symbol{"0", "é", "→", "ab"}
+	set := newSymbolSet(alphabet)
+
+	for symbol, expected := range map[Symbol]bool{"0": true, "é": true, "→": true, "ab": true, "1": false, "a": false, "": false} {
+		if got := containsSymbol(set, alphabet, symbol); got != expected {
+			t.Errorf("Expected containsSymbol(%q) to be %v, got %v", symbol, expected, got)
+		}
+		if got := containsSymbol(nil, alphabet, symbol); got != expected {
+			t.Errorf("Expected linear containsSymbol(%q) to be %v, got %v", symbol, expected, got)
+		}
+	}
+
+	grown := append(alphabet[:len(alphabet):len(alphabet)], "1")
+	if !containsSymbol(set, grown, "1") {
+		t.Error("Expected a stale set to fall back to the current alphabet")
+	}
+}
+
+func TestNFA_AlphabetGrownAfterConstruction(t *testing.T) {
+	nfa := NewNFA([]State{"S"}, []Symbol{"a"}, "S", []State{"S"})
+	nfa.Alphabet = append(nfa.Alphabet, "b")
+	nfa.AddTransition("S", "b", "S")
+
+	accepted, err := nfa.Accepts("bb")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !accepted {
+		t.Error("Expected 'bb' to be accepted")
+	}
+}
+
+func BenchmarkIntern_UnrelatedTableEdit(b *testing.B) {
+	fa := newEndsWith01DFA()
+	other := TransitionTable{}
+	for i := 0; i < b.N; i++ {
+		other.Set("X", "0", "Y")
+		fa.ProcessInput("0101010101")
+	}
+}
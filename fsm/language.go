@@ -0,0 +1,125 @@
+package fsm
+
+func (fa *FiniteAutomaton) reachableStates() map[State]bool {
+	reachable := map[State]bool{fa.InitialState: true}
+	queue := []State{fa.InitialState}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, next := range fa.successors(state) {
+			if !reachable[next] {
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return reachable
+}
+
+func (fa *FiniteAutomaton) IsEmptyLanguage() bool {
+	live := fa.liveStates()
+	return !live[fa.InitialState]
+}
+
+func (fa *FiniteAutomaton) IsFiniteLanguage() bool {
+	reachable := fa.reachableStates()
+	live := fa.liveStates()
+
+	useful := func(state State) bool { return reachable[state] && live[state] }
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	color := make(map[State]int)
+
+	var hasCycle func(state State) bool
+	hasCycle = func(state State) bool {
+		color[state] = visiting
+		for _, next := range fa.successors(state) {
+			if !useful(next) {
+				continue
+			}
+			switch color[next] {
+			case visiting:
+				return true
+			case unvisited:
+				if hasCycle(next) {
+					return true
+				}
+			}
+		}
+		color[state] = done
+		return false
+	}
+
+	if !useful(fa.InitialState) {
+		return true
+	}
+	return !hasCycle(fa.InitialState)
+}
+
+func (fa *FiniteAutomaton) ShortestAccepted() (string, bool) {
+	type entry struct {
+		state State
+		input string
+	}
+
+	seen := map[State]bool{fa.InitialState: true}
+	queue := []entry{{state: fa.InitialState}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if fa.IsAcceptingState(current.state) {
+			return current.input, true
+		}
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(current.state, symbol)
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, entry{state: next, input: current.input + string(symbol)})
+			}
+		}
+	}
+	return "", false
+}
+
+func (fa *FiniteAutomaton) Examples(maxLen, limit int) ([]string, []string) {
+	var accepted, rejected []string
+
+	type entry struct {
+		state State
+		input string
+	}
+	level := []entry{{state: fa.InitialState}}
+
+	for length := 0; length <= maxLen && len(level) > 0; length++ {
+		var next []entry
+		for _, current := range level {
+			if fa.IsAcceptingState(current.state) {
+				if len(accepted) < limit {
+					accepted = append(accepted, current.input)
+				}
+			} else if len(rejected) < limit {
+				rejected = append(rejected, current.input)
+			}
+
+			if len(accepted) >= limit && len(rejected) >= limit {
+				return accepted, rejected
+			}
+
+			if length < maxLen {
+				for _, symbol := range fa.Alphabet {
+					next = append(next, entry{
+						state: fa.TransitionFunction(current.state, symbol),
+						input: current.input + string(symbol),
+					})
+				}
+			}
+		}
+		level = next
+	}
+
+	return accepted, rejected
+}
@@ -0,0 +1,69 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsEmptyLanguage(t *testing.T) {
+	if newRunnerTestAutomaton().IsEmptyLanguage() {
+		t.Error("Expected mod-three language to be non-empty")
+	}
+
+	fa := newRunnerTestAutomaton()
+	fa.AcceptingStates = nil
+	if !fa.IsEmptyLanguage() {
+		t.Error("Expected language without accepting states to be empty")
+	}
+}
+
+func TestIsFiniteLanguage(t *testing.T) {
+	if newRunnerTestAutomaton().IsFiniteLanguage() {
+		t.Error("Expected mod-three language to be infinite")
+	}
+
+	exactlyAB, err := CompileRegex("ab|a", []Symbol{"a", "b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !exactlyAB.ToDFA().IsFiniteLanguage() {
+		t.Error("Expected {a, ab} to be finite")
+	}
+
+	empty := newRunnerTestAutomaton()
+	empty.AcceptingStates = nil
+	if !empty.IsFiniteLanguage() {
+		t.Error("Expected the empty language to be finite")
+	}
+}
+
+func TestShortestAccepted(t *testing.T) {
+	fa := newEndsWith01DFA()
+
+	input, ok := fa.ShortestAccepted()
+	if !ok || input != "01" {
+		t.Errorf("Expected shortest accepted '01', got '%s' (%v)", input, ok)
+	}
+
+	fa.AcceptingStates = nil
+	if _, ok := fa.ShortestAccepted(); ok {
+		t.Error("Expected no accepted string")
+	}
+}
+
+func TestExamples(t *testing.T) {
+	fa := newEndsWith01DFA()
+
+	accepted, rejected := fa.Examples(4, 3)
+	if strings.Join(accepted, ",") != "01,001,101" {
+		t.Errorf("Unexpected accepted examples %v", accepted)
+	}
+	if strings.Join(rejected, ",") != ",0,1" {
+		t.Errorf("Unexpected rejected examples %v", rejected)
+	}
+
+	accepted, _ = fa.Examples(1, 5)
+	if len(accepted) != 0 {
+		t.Errorf("Expected no accepted strings up to length 1, got %v", accepted)
+	}
+}
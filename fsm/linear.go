@@ -0,0 +1,151 @@
+package fsm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrRequiresBacktracking = errors.New("construct requires backtracking and cannot run in linear time")
+	// ErrUnsupportedSyntax marks constructs a DFA could express but the
+	// parser does not implement.
+	ErrUnsupportedSyntax = errors.New("unsupported regex syntax")
+	// ErrPatternTooLarge is returned when a pattern exceeds MaxRepeat, the
+	// expanded NFA size or the DFA state limit.
+	ErrPatternTooLarge = errors.New("pattern exceeds the compile limits")
+)
+
+const (
+	// MaxRepeat bounds the counts in {n}, {n,} and {n,m}.
+	MaxRepeat = 1000
+	// DefaultMaxDFAStates bounds the subset construction for matchers and
+	// globs; MaxDFAStates overrides it.
+	DefaultMaxDFAStates = 10000
+	// maxRegexStates bounds the NFA a pattern expands to, which nested
+	// counted repetitions multiply.
+	maxRegexStates = 100000
+)
+
+// MaxDFAStates sets the number of DFA states a Matcher may build before
+// giving up with ErrPatternTooLarge. Subset construction can be exponential
+// in the pattern, as in (a|b)*a(a|b){n}.
+func MaxDFAStates(n int) CompileOption {
+	return func(p *regexParser) {
+		p.maxDFAStates = n
+	}
+}
+
+func dfaStateLimit(options []CompileOption) int {
+	p := &regexParser{maxDFAStates: DefaultMaxDFAStates}
+	for _, option := range options {
+		option(p)
+	}
+	return p.maxDFAStates
+}
+
+func (p *regexParser) backtrackingError(construct string) error {
+	return fmt.Errorf("invalid regex %q at position %d: %s: %w", string(p.pattern), p.pos, construct, ErrRequiresBacktracking)
+}
+
+func (p *regexParser) unsupportedError(construct string) error {
+	return fmt.Errorf("invalid regex %q at position %d: %s: %w", string(p.pattern), p.pos, construct, ErrUnsupportedSyntax)
+}
+
+// parseGroupPrefix consumes the "?..." after an opening parenthesis. It
+// accepts non-capturing groups (?:…), named groups (?P<name>…) and (?<name>…),
+// and the case-insensitive flag as (?i), (?-i) or (?i:…). It reports whether
+// the group was a bare flag setting, which applies to the rest of the
+// enclosing group and has no body.
+func (p *regexParser) parseGroupPrefix() (bool, error) {
+	p.pos++
+	rest := string(p.pattern[p.pos:])
+	for _, lookaround := range []string{"=", "!", "<=", "<!"} {
+		if strings.HasPrefix(rest, lookaround) {
+			return false, p.backtrackingError("lookaround '(?" + lookaround + "'")
+		}
+	}
+	if strings.HasPrefix(rest, "P<") || strings.HasPrefix(rest, "<") {
+		end := strings.IndexRune(rest, '>')
+		if end < 0 {
+			return false, p.errorf("unterminated group name")
+		}
+		p.pos += len([]rune(rest[:end+1]))
+		return false, nil
+	}
+
+	negated := false
+	for {
+		char, ok := p.peek()
+		if !ok {
+			return false, p.errorf("missing ')'")
+		}
+		switch {
+		case char == '-' && !negated:
+			negated = true
+		case char == 'i' && negated:
+			p.fold = nil
+		case char == 'i':
+			if p.fold == nil {
+				p.fold = foldUnicode
+			}
+		case char == ':':
+			p.pos++
+			return false, nil
+		case char == ')':
+			p.pos++
+			return true, nil
+		default:
+			return false, p.unsupportedError(fmt.Sprintf("inline flag '%c'", char))
+		}
+		p.pos++
+	}
+}
+
+func (p *regexParser) checkEscape(escaped rune) error {
+	switch {
+	case escaped >= '1' && escaped <= '9':
+		return p.backtrackingError(fmt.Sprintf("backreference '\\%c'", escaped))
+	case escaped == 'k':
+		return p.backtrackingError("named backreference '\\k'")
+	case escaped == 'b' || escaped == 'B':
+		return p.unsupportedError(fmt.Sprintf("word boundary '\\%c'", escaped))
+	}
+	return nil
+}
+
+// regexStates returns the number of NFA states node compiles to, stopping
+// once it passes limit.
+func regexStates(node *regexNode, limit int) int {
+	sum := func(nodes []*regexNode) int {
+		total := 0
+		for _, child := range nodes {
+			if total += regexStates(child, limit); total > limit {
+				break
+			}
+		}
+		return total
+	}
+	times := func(count, size int) int {
+		if count > 0 && size > limit/count {
+			return limit + 1
+		}
+		return count * size
+	}
+
+	switch node.kind {
+	case regexConcat:
+		return sum(node.children)
+	case regexAlternate:
+		return 2 + sum(node.children)
+	case regexRepeat:
+		child := regexStates(node.children[0], limit)
+		copies := node.min + 1
+		if node.max > node.min {
+			copies = node.max
+		}
+		return 2 + times(copies, child)
+	default:
+		return 2
+	}
+}
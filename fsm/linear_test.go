@@ -0,0 +1,142 @@
+package fsm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompileRegex_RejectsBacktracking(t *testing.T) {
+	patterns := []string{
+		"(a)\\1",
+		"(a)b\\9",
+		"(?=a)a",
+		"(?!a)b",
+		"(?<=a)b",
+		"(?<!a)b",
+		"\\k<name>",
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			_, err := CompileRegex(pattern, ASCIIAlphabet())
+			if !errors.Is(err, ErrRequiresBacktracking) {
+				t.Errorf("Expected ErrRequiresBacktracking, got %v", err)
+			}
+
+			if _, err := NewMatcher(pattern, ASCIIAlphabet()); !errors.Is(err, ErrRequiresBacktracking) {
+				t.Errorf("Expected NewMatcher to reject the pattern, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompileRegex_UnsupportedSyntax(t *testing.T) {
+	for _, pattern := range []string{"\\ba\\b", "a\\B", "(?s).", "(?m:a)"} {
+		t.Run(pattern, func(t *testing.T) {
+			_, err := CompileRegex(pattern, ASCIIAlphabet())
+			if !errors.Is(err, ErrUnsupportedSyntax) || errors.Is(err, ErrRequiresBacktracking) {
+				t.Errorf("Expected ErrUnsupportedSyntax, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompileRegex_Groups(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		accepted []string
+		rejected []string
+	}{
+		{"(?:ab)+", []string{"ab", "abab"}, []string{"a", "aba"}},
+		{"(?P<year>\\d\\d)-(?<month>\\d)", []string{"24-1"}, []string{"24-"}},
+		{"(?i)go", []string{"go", "GO", "gO"}, []string{"g"}},
+		{"a(?i:b)c", []string{"abc", "aBc"}, []string{"aBC", "Abc"}},
+		{"(a(?i)b)c", []string{"abc", "aBc"}, []string{"aBC"}},
+		{"(?i)a(?-i)b", []string{"Ab"}, []string{"AB"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			matcher, err := NewMatcher(test.pattern, ASCIIAlphabet())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, input := range test.accepted {
+				if ok, _ := matcher.Match(input, MatchFull); !ok {
+					t.Errorf("Expected %q to match", input)
+				}
+			}
+			for _, input := range test.rejected {
+				if ok, _ := matcher.Match(input, MatchFull); ok {
+					t.Errorf("Expected %q not to match", input)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileRegex_Limits(t *testing.T) {
+	for _, pattern := range []string{"a{1001}", "a{2,5000}", "(a{1000}){1000}"} {
+		if _, err := NewMatcher(pattern, ASCIIAlphabet()); !errors.Is(err, ErrPatternTooLarge) {
+			t.Errorf("Expected ErrPatternTooLarge for %s, got %v", pattern, err)
+		}
+	}
+	if _, err := NewMatcher("a{1000}", ASCIIAlphabet()); err != nil {
+		t.Errorf("Unexpected error at the repetition limit: %v", err)
+	}
+
+	matcher, err := NewMatcher("(a|b)*a(a|b){12}", []Symbol{"a", "b"}, MaxDFAStates(1000))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := matcher.Match("ab", MatchFull); !errors.Is(err, ErrPatternTooLarge) {
+		t.Errorf("Expected the DFA state limit to stop the subset construction, got %v", err)
+	}
+	if _, err := CompileGlob("*a??????????????", MaxDFAStates(1000)); !errors.Is(err, ErrPatternTooLarge) {
+		t.Errorf("Expected the DFA state limit to apply to globs, got %v", err)
+	}
+}
+
+func TestCompileRegex_AllowsLinearEscapes(t *testing.T) {
+	for _, pattern := range []string{"\\d+", "[\\1]", "\\(a\\)", "a\\?", "\\\\"} {
+		if _, err := CompileRegex(pattern, ASCIIAlphabet()); err != nil {
+			t.Errorf("Unexpected error for '%s': %v", pattern, err)
+		}
+	}
+}
+
+func TestMatcher_PathologicalPatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+	}{
+		{"(a*)*b", strings.Repeat("a", 100000)},
+		{"(a|aa)+c", strings.Repeat("a", 100000)},
+		{"(x+x+)+y", strings.Repeat("x", 100000)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			matcher, err := NewMatcher(test.pattern, ASCIIAlphabet())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			dfa, err := matcher.DFA(MatchFull)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			runner := NewRunner(dfa, WithHistory())
+			if _, err := runner.Feed(test.input); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if steps := len(runner.History()); steps != len(test.input) {
+				t.Errorf("Expected exactly %d transitions, got %d", len(test.input), steps)
+			}
+			if runner.IsAccepting() {
+				t.Error("Expected the input to be rejected")
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package fsm
+
+import (
+	"context"
+	"log/slog"
+)
+
+func debugEnabled(logger *slog.Logger) bool {
+	return logger != nil && logger.Enabled(context.Background(), slog.LevelDebug)
+}
+
+func logTransition(logger *slog.Logger, transition Transition, position int) {
+	attrs := []slog.Attr{
+		slog.String("from", string(transition.From)),
+		slog.String("symbol", string(transition.Symbol)),
+		slog.String("to", string(transition.To)),
+	}
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "transition", withPosition(attrs, position)...)
+}
+
+func logInvalidSymbol(logger *slog.Logger, state State, symbol Symbol, position int) {
+	if logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("state", string(state)),
+		slog.String("symbol", string(symbol)),
+	}
+	logger.LogAttrs(context.Background(), slog.LevelWarn, "invalid symbol", withPosition(attrs, position)...)
+}
+
+func withPosition(attrs []slog.Attr, position int) []slog.Attr {
+	if position < 0 {
+		return attrs
+	}
+	return append(attrs, slog.Int("position", position))
+}
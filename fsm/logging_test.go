@@ -0,0 +1,90 @@
+package fsm
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	})
+	return slog.New(handler), &buf
+}
+
+func TestProcessInput_Logging(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	logger, buf := newTestLogger(slog.LevelDebug)
+	fa.Logger = logger
+
+	if _, err := fa.ProcessInput("10"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"level=DEBUG msg=transition from=S0 symbol=1 to=S1 position=0",
+		"level=DEBUG msg=transition from=S1 symbol=0 to=S2 position=1",
+	}
+	for _, line := range expected {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Log should contain %q:\n%s", line, buf.String())
+		}
+	}
+
+	buf.Reset()
+	fa.ProcessInput("1x")
+	if !strings.Contains(buf.String(), "level=WARN msg=\"invalid symbol\" state=S1 symbol=x position=1") {
+		t.Errorf("Expected invalid symbol warning, got:\n%s", buf.String())
+	}
+}
+
+func TestProcessInput_LoggingLevel(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	logger, buf := newTestLogger(slog.LevelInfo)
+	fa.Logger = logger
+
+	fa.ProcessInput("1101")
+	if buf.Len() != 0 {
+		t.Errorf("Transitions should not be logged above debug level, got:\n%s", buf.String())
+	}
+
+	fa.Logger = nil
+	if _, err := fa.ProcessInput("2"); err == nil {
+		t.Error("Expected error for invalid input, but got none")
+	}
+}
+
+func TestRunner_Logging(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	logger, buf := newTestLogger(slog.LevelDebug)
+
+	runner := NewRunner(fa, WithLogger(logger))
+	runner.Feed("1")
+	runner.Step("2")
+
+	expected := []string{
+		"level=DEBUG msg=transition from=S0 symbol=1 to=S1",
+		"level=WARN msg=\"invalid symbol\" state=S1 symbol=2",
+	}
+	for _, line := range expected {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Log should contain %q:\n%s", line, buf.String())
+		}
+	}
+
+	inherited, inheritedBuf := newTestLogger(slog.LevelDebug)
+	fa.Logger = inherited
+	NewRunner(fa).Step("0")
+	if !strings.Contains(inheritedBuf.String(), "msg=transition") {
+		t.Error("Runner should inherit the automaton's logger")
+	}
+}
@@ -0,0 +1,75 @@
+package fsm
+
+import "sync"
+
+type MatchMode int
+
+const (
+	MatchFull MatchMode = iota
+	MatchSubstring
+)
+
+func (m MatchMode) String() string {
+	if m == MatchSubstring {
+		return "substring"
+	}
+	return "full"
+}
+
+type Matcher struct {
+	mu        sync.Mutex
+	node      *regexNode
+	alphabet  []Symbol
+	maxStates int
+	dfas      map[MatchMode]*FiniteAutomaton
+}
+
+func NewMatcher(pattern string, alphabet []Symbol, options ...CompileOption) (*Matcher, error) {
+	node, err := parseRegex(pattern, alphabet, options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{node: node, alphabet: alphabet, maxStates: dfaStateLimit(options), dfas: make(map[MatchMode]*FiniteAutomaton)}, nil
+}
+
+// DFA builds the automaton for mode on first use. It fails with
+// ErrPatternTooLarge if the DFA would exceed the MaxDFAStates limit. If the
+// alphabet contains Wildcard, symbols outside it are read as Wildcard instead
+// of being rejected.
+func (m *Matcher) DFA(mode MatchMode) (*FiniteAutomaton, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if dfa, ok := m.dfas[mode]; ok {
+		return dfa, nil
+	}
+
+	node := m.node
+	if mode == MatchSubstring {
+		anything := &regexNode{
+			kind:     regexRepeat,
+			children: []*regexNode{{kind: regexSet, symbols: m.alphabet}},
+			min:      0,
+			max:      -1,
+		}
+		node = &regexNode{kind: regexConcat, children: []*regexNode{anything, node, anything}}
+	}
+
+	dfa, _, err := compileNode(node, m.alphabet).toDFA(m.maxStates)
+	if err != nil {
+		return nil, err
+	}
+	if containsSymbol(nil, m.alphabet, Wildcard) {
+		dfa.Normalizer = OtherSymbols(m.alphabet)
+	}
+	m.dfas[mode] = dfa
+	return dfa, nil
+}
+
+func (m *Matcher) Match(input string, mode MatchMode) (bool, error) {
+	dfa, err := m.DFA(mode)
+	if err != nil {
+		return false, err
+	}
+	return dfa.Accepts(input)
+}
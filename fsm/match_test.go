@@ -0,0 +1,94 @@
+package fsm
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestMatcher_MatchesStandardLibrary(t *testing.T) {
+	alphabet := []Symbol{"a", "b", "c", "0", "1"}
+	patterns := []string{"ab", "a|bc", "a*", "(ab)+", "[01]{2}", "c?0", "()"}
+
+	inputs := SeedCorpus(alphabet, 4)
+	inputs = append(inputs, "cabc", "1ab1", "bbbc0")
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			matcher, err := NewMatcher(pattern, alphabet)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			references := map[MatchMode]*regexp.Regexp{
+				MatchFull:      regexp.MustCompile("^(?:" + pattern + ")$"),
+				MatchSubstring: regexp.MustCompile(pattern),
+			}
+			for mode, reference := range references {
+				for _, input := range inputs {
+					matched, err := matcher.Match(input, mode)
+					if err != nil {
+						t.Fatalf("Unexpected error for input '%s': %v", input, err)
+					}
+					if expected := reference.MatchString(input); matched != expected {
+						t.Errorf("%s match of '%s' on input '%s': expected %v, got %v", mode, pattern, input, expected, matched)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestMatcher_WildcardAlphabet(t *testing.T) {
+	matcher, err := NewMatcher("a.c|x[^y]", []Symbol{"a", "c", "x", "y", Wildcard})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := map[string]bool{"aéc": true, "a日c": true, "xé": true, "xy": false, "é": false, "日ac": false}
+	for input, expected := range tests {
+		matched, err := matcher.Match(input, MatchFull)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if matched != expected {
+			t.Errorf("Input '%s': expected %v, got %v", input, expected, matched)
+		}
+	}
+
+	if matched, _ := matcher.Match("日xé日", MatchSubstring); !matched {
+		t.Error("Expected a substring match between non-alphabet runes")
+	}
+}
+
+func TestMatcher_CachesAutomata(t *testing.T) {
+	matcher, err := NewMatcher("ab", []Symbol{"a", "b"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	full, _ := matcher.DFA(MatchFull)
+	again, _ := matcher.DFA(MatchFull)
+	if full != again {
+		t.Error("Expected the full-match DFA to be built once")
+	}
+	if substring, _ := matcher.DFA(MatchSubstring); substring == full {
+		t.Error("Expected separate automata per mode")
+	}
+}
+
+func TestMatcher_Errors(t *testing.T) {
+	if _, err := NewMatcher("(a", []Symbol{"a"}); err == nil {
+		t.Error("Expected error for an invalid pattern, but got none")
+	}
+
+	matcher, _ := NewMatcher("a", []Symbol{"a"})
+	if _, err := matcher.Match("ab", MatchSubstring); err == nil {
+		t.Error("Expected error for a symbol outside the alphabet, but got none")
+	}
+}
+
+func TestMatchMode_String(t *testing.T) {
+	if MatchFull.String() != "full" || MatchSubstring.String() != "substring" {
+		t.Errorf("Unexpected mode names: %s, %s", MatchFull, MatchSubstring)
+	}
+}
@@ -0,0 +1,51 @@
+package fsm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+const DefinitionVersion = 1
+
+type definitionMigration func(document map[string]json.RawMessage) error
+
+var definitionMigrations = map[int]definitionMigration{}
+
+func migrateDefinition(data []byte) ([]byte, error) {
+	return upgradeDefinition(data, DefinitionVersion, definitionMigrations)
+}
+
+func upgradeDefinition(data []byte, target int, migrations map[int]definitionMigration) ([]byte, error) {
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode automaton definition: %w", err)
+	}
+
+	version := 1
+	if raw, ok := document["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid definition version %s: expected an integer", raw)
+		}
+	}
+	switch {
+	case version < 1:
+		return nil, fmt.Errorf("invalid definition version %d: versions start at 1", version)
+	case version > target:
+		return nil, fmt.Errorf("definition version %d is newer than the supported version %d; upgrade to load it", version, target)
+	case version == target:
+		return data, nil
+	}
+
+	for from := version; from < target; from++ {
+		migrate, ok := migrations[from]
+		if !ok {
+			return nil, fmt.Errorf("no migration from definition version %d to %d", from, from+1)
+		}
+		if err := migrate(document); err != nil {
+			return nil, fmt.Errorf("migrating definition from version %d to %d: %w", from, from+1, err)
+		}
+	}
+	document["version"] = json.RawMessage(strconv.Itoa(target))
+	return json.Marshal(document)
+}
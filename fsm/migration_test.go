@@ -0,0 +1,101 @@
+package fsm
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadDefinition_Versions(t *testing.T) {
+	unversioned := modThreeDefinition
+	versioned := strings.Replace(modThreeDefinition, "{", `{"version": 1,`, 1)
+
+	tests := []struct {
+		name          string
+		definition    string
+		expectedError string
+	}{
+		{"unversioned", unversioned, ""},
+		{"current", versioned, ""},
+		{"future", strings.Replace(modThreeDefinition, "{", `{"version": 2,`, 1), "newer than the supported version 1"},
+		{"zero", strings.Replace(modThreeDefinition, "{", `{"version": 0,`, 1), "versions start at 1"},
+		{"not a number", strings.Replace(modThreeDefinition, "{", `{"version": "1",`, 1), "expected an integer"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			definition, err := ReadDefinition(strings.NewReader(test.definition))
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Fatalf("Expected error containing %q, got %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if definition.Version != DefinitionVersion {
+				t.Errorf("Expected version %d, got %d", DefinitionVersion, definition.Version)
+			}
+		})
+	}
+}
+
+func TestDefinition_WriteVersion(t *testing.T) {
+	var sb strings.Builder
+	if err := (&Definition{States: []State{"A"}, InitialState: "A"}).Write(&sb); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(sb.String(), `"version": 1`) {
+		t.Errorf("Expected written definition to carry the current version, got %s", sb.String())
+	}
+}
+
+func TestUpgradeDefinition(t *testing.T) {
+	renameInitial := func(document map[string]json.RawMessage) error {
+		document["start"] = document["initial"]
+		delete(document, "initial")
+		return nil
+	}
+	addNote := func(document map[string]json.RawMessage) error {
+		if _, ok := document["start"]; !ok {
+			return errors.New("expected version 2 document")
+		}
+		document["note"] = json.RawMessage(`"migrated"`)
+		return nil
+	}
+	migrations := map[int]definitionMigration{1: renameInitial, 2: addNote}
+
+	tests := []struct {
+		name          string
+		document      string
+		migrations    map[int]definitionMigration
+		expected      string
+		expectedError string
+	}{
+		{"unversioned through all", `{"initial":"A"}`, migrations, `{"note":"migrated","start":"A","version":3}`, ""},
+		{"from middle", `{"version":2,"start":"B"}`, migrations, `{"note":"migrated","start":"B","version":3}`, ""},
+		{"already current", `{"version":3,"start":"C"}`, migrations, `{"version":3,"start":"C"}`, ""},
+		{"missing step", `{"initial":"A"}`, map[int]definitionMigration{1: renameInitial}, "", "no migration from definition version 2 to 3"},
+		{"failing step", `{"version":2}`, migrations, "", "migrating definition from version 2 to 3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			upgraded, err := upgradeDefinition([]byte(test.document), 3, test.migrations)
+			if test.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), test.expectedError) {
+					t.Fatalf("Expected error containing %q, got %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if string(upgraded) != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, upgraded)
+			}
+		})
+	}
+}
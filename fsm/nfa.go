@@ -0,0 +1,172 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+)
+
+const Epsilon Symbol = ""
+
+type NFA struct {
+	States          []State
+	Alphabet        []Symbol
+	InitialState    State
+	AcceptingStates []State
+	Transitions     map[State]map[Symbol][]State
+
+	alphabetSet *symbolSet
+}
+
+func NewNFA(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+) *NFA {
+	return &NFA{
+		States:          states,
+		Alphabet:        alphabet,
+		InitialState:    initialState,
+		AcceptingStates: acceptingStates,
+		Transitions:     make(map[State]map[Symbol][]State),
+		alphabetSet:     newSymbolSet(alphabet),
+	}
+}
+
+func (n *NFA) AddTransition(from State, symbol Symbol, to State) {
+	if n.Transitions[from] == nil {
+		n.Transitions[from] = make(map[Symbol][]State)
+	}
+	for _, existing := range n.Transitions[from][symbol] {
+		if existing == to {
+			return
+		}
+	}
+	n.Transitions[from][symbol] = append(n.Transitions[from][symbol], to)
+}
+
+func (n *NFA) Accepts(input string) (bool, error) {
+	current := n.EpsilonClosure([]State{n.InitialState})
+
+	for i, char := range input {
+		symbol := Symbol(string(char))
+		if !n.isValidSymbol(symbol) {
+			return false, fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, n.Alphabet)
+		}
+		current = n.EpsilonClosure(n.Move(current, symbol))
+	}
+
+	return n.ContainsAccepting(current), nil
+}
+
+func (n *NFA) ToDFA() *FiniteAutomaton {
+	dfa, _ := n.ToDFAWithSubsets()
+	return dfa
+}
+
+func (n *NFA) ToDFAWithSubsets() (*FiniteAutomaton, map[State][]State) {
+	dfa, sets, _ := n.toDFA(0)
+	return dfa, sets
+}
+
+// toDFA is the subset construction. It fails with ErrPatternTooLarge once it
+// would build more than maxStates states; zero means no limit.
+func (n *NFA) toDFA(maxStates int) (*FiniteAutomaton, map[State][]State, error) {
+	start := n.EpsilonClosure([]State{n.InitialState})
+	startName := n.setName(start)
+
+	sets := map[State][]State{startName: start}
+	states := []State{startName}
+	var accepting []State
+	table := make(TransitionTable)
+
+	for i := 0; i < len(states); i++ {
+		name := states[i]
+		set := sets[name]
+		if n.ContainsAccepting(set) {
+			accepting = append(accepting, name)
+		}
+
+		for _, symbol := range n.Alphabet {
+			next := n.EpsilonClosure(n.Move(set, symbol))
+			nextName := n.setName(next)
+			if _, seen := sets[nextName]; !seen {
+				if maxStates > 0 && len(states) == maxStates {
+					return nil, nil, fmt.Errorf("subset construction exceeds %d DFA states: %w", maxStates, ErrPatternTooLarge)
+				}
+				sets[nextName] = next
+				states = append(states, nextName)
+			}
+			table.Set(name, symbol, nextName)
+		}
+	}
+
+	return NewTableAutomaton(states, n.Alphabet, startName, accepting, table), sets, nil
+}
+
+func (n *NFA) EpsilonClosure(states []State) []State {
+	seen := make(map[State]bool, len(states))
+	stack := make([]State, 0, len(states))
+	for _, state := range states {
+		if !seen[state] {
+			seen[state] = true
+			stack = append(stack, state)
+		}
+	}
+
+	for len(stack) > 0 {
+		state := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, next := range n.Transitions[state][Epsilon] {
+			if !seen[next] {
+				seen[next] = true
+				stack = append(stack, next)
+			}
+		}
+	}
+
+	return n.ordered(seen)
+}
+
+func (n *NFA) Move(states []State, symbol Symbol) []State {
+	seen := make(map[State]bool)
+	for _, state := range states {
+		for _, next := range n.Transitions[state][symbol] {
+			seen[next] = true
+		}
+	}
+	return n.ordered(seen)
+}
+
+func (n *NFA) ordered(set map[State]bool) []State {
+	result := make([]State, 0, len(set))
+	for _, state := range n.States {
+		if set[state] {
+			result = append(result, state)
+		}
+	}
+	return result
+}
+
+func (n *NFA) setName(states []State) State {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = string(state)
+	}
+	return State("{" + strings.Join(names, ",") + "}")
+}
+
+func (n *NFA) ContainsAccepting(states []State) bool {
+	for _, state := range states {
+		for _, accepting := range n.AcceptingStates {
+			if state == accepting {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (n *NFA) isValidSymbol(symbol Symbol) bool {
+	return containsSymbol(n.alphabetSet, n.Alphabet, symbol)
+}
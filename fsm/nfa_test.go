@@ -0,0 +1,144 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func newEndsWith01NFA() *NFA {
+	nfa := NewNFA([]State{"A", "B", "C"}, []Symbol{"0", "1"}, "A", []State{"C"})
+	nfa.AddTransition("A", "0", "A")
+	nfa.AddTransition("A", "1", "A")
+	nfa.AddTransition("A", "0", "B")
+	nfa.AddTransition("B", "1", "C")
+	return nfa
+}
+
+func TestNFA_Accepts(t *testing.T) {
+	nfa := newEndsWith01NFA()
+
+	for _, input := range SeedCorpus(nfa.Alphabet, 6) {
+		accepted, err := nfa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		expected := strings.HasSuffix(input, "01")
+		if accepted != expected {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, expected, accepted)
+		}
+	}
+
+	if _, err := nfa.Accepts("012"); err == nil {
+		t.Error("Expected error for invalid input, but got none")
+	}
+}
+
+func TestNFA_EpsilonTransitions(t *testing.T) {
+	nfa := NewNFA([]State{"Start", "Zeros", "Ones"}, []Symbol{"0", "1"}, "Start", []State{"Zeros", "Ones"})
+	nfa.AddTransition("Start", Epsilon, "Zeros")
+	nfa.AddTransition("Start", Epsilon, "Ones")
+	nfa.AddTransition("Zeros", "0", "Zeros")
+	nfa.AddTransition("Ones", "1", "Ones")
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"", true},
+		{"000", true},
+		{"11", true},
+		{"01", false},
+		{"10", false},
+	}
+
+	for _, test := range tests {
+		accepted, err := nfa.Accepts(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accepted != test.expected {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", test.input, test.expected, accepted)
+		}
+	}
+}
+
+func TestNFA_ToDFA(t *testing.T) {
+	nfa := newEndsWith01NFA()
+	dfa := nfa.ToDFA()
+
+	if dfa.InitialState != "{A}" {
+		t.Errorf("Expected initial state {A}, got %s", dfa.InitialState)
+	}
+	if len(dfa.States) != 3 {
+		t.Errorf("Expected 3 reachable subset states, got %d: %v", len(dfa.States), dfa.States)
+	}
+
+	for _, input := range SeedCorpus(nfa.Alphabet, 8) {
+		expected, _ := nfa.Accepts(input)
+		accepted, err := dfa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != expected {
+			t.Errorf("For input '%s': NFA accepted=%v but DFA accepted=%v", input, expected, accepted)
+		}
+	}
+}
+
+func TestNFA_AddTransitionDeduplicates(t *testing.T) {
+	nfa := NewNFA([]State{"A", "B"}, []Symbol{"0"}, "A", nil)
+	nfa.AddTransition("A", "0", "B")
+	nfa.AddTransition("A", "0", "B")
+
+	if len(nfa.Transitions["A"]["0"]) != 1 {
+		t.Errorf("Expected 1 transition, got %d", len(nfa.Transitions["A"]["0"]))
+	}
+}
+
+func TestNFA_Primitives(t *testing.T) {
+	nfa := NewNFA([]State{"q0", "q1", "q2", "q3"}, []Symbol{"a", "b"}, "q0", []State{"q3"})
+	nfa.AddTransition("q0", Epsilon, "q1")
+	nfa.AddTransition("q1", Epsilon, "q2")
+	nfa.AddTransition("q1", "a", "q3")
+	nfa.AddTransition("q2", "a", "q2")
+	nfa.AddTransition("q2", "b", "q3")
+	nfa.AddTransition("q3", Epsilon, "q0")
+
+	tests := []struct {
+		description string
+		got         []State
+		expected    []State
+	}{
+		{"closure of q0", nfa.EpsilonClosure([]State{"q0"}), []State{"q0", "q1", "q2"}},
+		{"closure of q3", nfa.EpsilonClosure([]State{"q3"}), []State{"q0", "q1", "q2", "q3"}},
+		{"closure of nothing", nfa.EpsilonClosure(nil), []State{}},
+		{"move on a", nfa.Move([]State{"q0", "q1", "q2"}, "a"), []State{"q2", "q3"}},
+		{"move on b", nfa.Move([]State{"q1"}, "b"), []State{}},
+	}
+
+	for _, test := range tests {
+		if strings.Join(statesToStrings(test.got), ",") != strings.Join(statesToStrings(test.expected), ",") {
+			t.Errorf("%s: expected %v, got %v", test.description, test.expected, test.got)
+		}
+	}
+
+	for _, input := range SeedCorpus(nfa.Alphabet, 5) {
+		current := nfa.EpsilonClosure([]State{nfa.InitialState})
+		for _, char := range input {
+			current = nfa.EpsilonClosure(nfa.Move(current, Symbol(string(char))))
+		}
+
+		expected, _ := nfa.Accepts(input)
+		if nfa.ContainsAccepting(current) != expected {
+			t.Errorf("For input '%s': primitives disagree with Accepts", input)
+		}
+	}
+}
+
+func statesToStrings(states []State) []string {
+	result := make([]string, len(states))
+	for i, state := range states {
+		result[i] = string(state)
+	}
+	return result
+}
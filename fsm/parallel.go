@@ -0,0 +1,147 @@
+package fsm
+
+import (
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// minParallelChunk is the smallest chunk ProcessParallel hands to a worker.
+const minParallelChunk = 1 << 12
+
+// ProcessParallel returns the same final state as ProcessInput. It splits
+// input into one chunk per worker and computes, in parallel, the function
+// from start state to end state of every chunk but the first. The first chunk
+// is run from the initial state, and the other chunks' functions are then
+// applied in order. A chunk costs one pass per state, so this pays off when
+// workers outnumber states, or when runs converge quickly. workers < 1 means
+// GOMAXPROCS.
+func (fa *FiniteAutomaton) ProcessParallel(input string, workers int) (State, error) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n := len(input) / minParallelChunk; n < workers {
+		workers = n
+	}
+	debug := debugEnabled(fa.Logger)
+	if workers < 2 || debug {
+		return fa.ProcessInput(input)
+	}
+
+	chunks := splitInput(input, workers)
+	var state State
+	var ok bool
+	if run, interned := fa.fastPath(debug); interned {
+		state, ok = run.processParallel(chunks)
+	} else {
+		state, ok = fa.processParallel(chunks)
+	}
+	if !ok {
+		return fa.ProcessInput(input)
+	}
+	return state, nil
+}
+
+func splitInput(input string, n int) []string {
+	chunks := make([]string, 0, n)
+	start := 0
+	for k := 1; k <= n; k++ {
+		end := len(input) * k / n
+		for end < len(input) && !utf8.RuneStart(input[end]) {
+			end++
+		}
+		if end > start {
+			chunks = append(chunks, input[start:end])
+			start = end
+		}
+	}
+	return chunks
+}
+
+func (r internedRun) processParallel(chunks []string) (State, bool) {
+	maps := make([][]int32, len(chunks))
+	var wg sync.WaitGroup
+	for k := 1; k < len(chunks); k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			maps[k] = r.chunkMap(chunks[k])
+		}(k)
+	}
+
+	state, ok := r.runRunes(r.initial, chunks[0])
+	wg.Wait()
+	if !ok {
+		return "", false
+	}
+	for _, m := range maps[1:] {
+		if m == nil {
+			return "", false
+		}
+		state = m[state]
+	}
+	return r.state(state), true
+}
+
+// chunkMap returns the end state of chunk from every interned state, or nil
+// if chunk contains a symbol outside the alphabet.
+func (r internedRun) chunkMap(chunk string) []int32 {
+	states := make([]int32, r.stateCount())
+	for i := range states {
+		states[i] = int32(i)
+	}
+	for _, char := range chunk {
+		symbol := r.idx.runeID(char)
+		if symbol < 0 {
+			return nil
+		}
+		for i, state := range states {
+			states[i] = r.next(state, symbol)
+		}
+	}
+	return states
+}
+
+func (fa *FiniteAutomaton) processParallel(chunks []string) (State, bool) {
+	effects := make([]*transformation, len(chunks))
+	var wg sync.WaitGroup
+	for k := 1; k < len(chunks); k++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			if symbols, ok := fa.chunkSymbols(chunks[k]); ok {
+				effect := fa.transformationOf(symbols)
+				effects[k] = &effect
+			}
+		}(k)
+	}
+
+	symbols, ok := fa.chunkSymbols(chunks[0])
+	wg.Wait()
+	if !ok {
+		return "", false
+	}
+	state := fa.InitialState
+	for _, symbol := range symbols {
+		state = fa.TransitionFunction(state, symbol)
+	}
+	for _, effect := range effects[1:] {
+		if effect == nil {
+			return "", false
+		}
+		state = effect.apply(state)
+	}
+	return state, true
+}
+
+func (fa *FiniteAutomaton) chunkSymbols(chunk string) ([]Symbol, bool) {
+	symbols := make([]Symbol, 0, len(chunk))
+	for _, char := range chunk {
+		symbol := fa.normalize(Symbol(string(char)))
+		if !fa.isValidSymbol(symbol) {
+			return nil, false
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, true
+}
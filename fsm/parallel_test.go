@@ -0,0 +1,109 @@
+package fsm
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestProcessParallel_MatchesProcessInput(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	table := newModTableAutomaton(7, []Symbol{"0", "1"})
+	sparse, err := table.ToSparse()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	closure := newRunnerTestAutomaton()
+	normalized := newEndsWith01DFA()
+	normalized.Normalizer = Aliases(map[Symbol]Symbol{"o": "0", "i": "1"})
+
+	automata := map[string]*FiniteAutomaton{
+		"table":      table,
+		"sparse":     sparse,
+		"closure":    closure,
+		"normalized": normalized,
+	}
+	for name, fa := range automata {
+		t.Run(name, func(t *testing.T) {
+			for _, workers := range []int{0, 1, 2, 3, 8} {
+				for _, length := range []int{0, minParallelChunk - 1, 5*minParallelChunk + 17} {
+					input := randomInput(r, []Symbol{"0", "1"}, length)
+					expected, err := fa.ProcessInput(input)
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					state, err := fa.ProcessParallel(input, workers)
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					if state != expected {
+						t.Errorf("%d workers, length %d: expected %s, got %s", workers, length, expected, state)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestProcessParallel_UndeclaredStates(t *testing.T) {
+	fa := newBoundedModThree()
+	input := strings.Repeat("0", 3*minParallelChunk) + "11"
+
+	state, err := fa.ProcessParallel(input, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != "3" {
+		t.Errorf("Expected 3, got %s", state)
+	}
+}
+
+func TestProcessParallel_InvalidSymbol(t *testing.T) {
+	fa := newModTableAutomaton(3, []Symbol{"0", "1"})
+
+	for _, position := range []int{10, 2*minParallelChunk + 5} {
+		input := []byte(strings.Repeat("01", 2*minParallelChunk))
+		input[position] = 'x'
+
+		_, expected := fa.ProcessInput(string(input))
+		_, err := fa.ProcessParallel(string(input), 4)
+		if err == nil {
+			t.Fatalf("Expected error for invalid symbol at %d, but got none", position)
+		}
+		if err.Error() != expected.Error() {
+			t.Errorf("Expected error %q, got %q", expected, err)
+		}
+	}
+}
+
+func TestSplitInput(t *testing.T) {
+	input := strings.Repeat("aé→", 5)
+	chunks := splitInput(input, 4)
+
+	if strings.Join(chunks, "") != input {
+		t.Errorf("Expected chunks to cover the input, got %q", chunks)
+	}
+	for _, chunk := range chunks {
+		if !strings.HasPrefix(chunk, "a") && !strings.HasPrefix(chunk, "é") && !strings.HasPrefix(chunk, "→") {
+			t.Errorf("Chunk %q does not start on a rune boundary", chunk)
+		}
+	}
+}
+
+func BenchmarkProcessParallel(b *testing.B) {
+	fa := newModTableAutomaton(7, []Symbol{"0", "1"})
+	input := randomInput(rand.New(rand.NewSource(1)), fa.Alphabet, 1<<20)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			fa.ProcessInput(input)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			fa.ProcessParallel(input, 0)
+		}
+	})
+}
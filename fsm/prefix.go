@@ -0,0 +1,31 @@
+package fsm
+
+func (fa *FiniteAutomaton) LongestAcceptedPrefix(input string) (int, State) {
+	return fa.longestAcceptedPrefix(input, fa.liveStates())
+}
+
+func (fa *FiniteAutomaton) longestAcceptedPrefix(input string, live map[State]bool) (int, State) {
+	currentState := fa.InitialState
+	length, acceptedState := -1, State("")
+	if fa.IsAcceptingState(currentState) {
+		length, acceptedState = 0, currentState
+	}
+
+	for i, char := range input {
+		if !live[currentState] {
+			break
+		}
+
+		symbol := fa.normalize(Symbol(string(char)))
+		if !fa.isValidSymbol(symbol) {
+			break
+		}
+
+		currentState = fa.TransitionFunction(currentState, symbol)
+		if fa.IsAcceptingState(currentState) {
+			length, acceptedState = i+len(string(char)), currentState
+		}
+	}
+
+	return length, acceptedState
+}
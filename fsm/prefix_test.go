@@ -0,0 +1,67 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func newDigitsDFA() *FiniteAutomaton {
+	table := TransitionTable{}
+	for _, digit := range []Symbol{"0", "1", "2"} {
+		table.Set("Start", digit, "Int")
+		table.Set("Int", digit, "Int")
+		table.Set("Dot", digit, "Frac")
+		table.Set("Frac", digit, "Frac")
+		table.Set("Dead", digit, "Dead")
+	}
+	table.Set("Start", ".", "Dead")
+	table.Set("Int", ".", "Dot")
+	table.Set("Dot", ".", "Dead")
+	table.Set("Frac", ".", "Dead")
+	table.Set("Dead", ".", "Dead")
+
+	return NewTableAutomaton(
+		[]State{"Start", "Int", "Dot", "Frac", "Dead"},
+		[]Symbol{"0", "1", "2", "."},
+		"Start",
+		[]State{"Int", "Frac"},
+		table,
+	)
+}
+
+func TestLongestAcceptedPrefix(t *testing.T) {
+	fa := newDigitsDFA()
+
+	tests := []struct {
+		input          string
+		expectedLength int
+		expectedState  State
+	}{
+		{"12", 2, "Int"},
+		{"12.", 2, "Int"},
+		{"12.01+3", 5, "Frac"},
+		{"1.2.3", 3, "Frac"},
+		{"21 rest", 2, "Int"},
+		{".12", -1, ""},
+		{"", -1, ""},
+		{"x", -1, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			length, state := fa.LongestAcceptedPrefix(test.input)
+			if length != test.expectedLength || state != test.expectedState {
+				t.Errorf("For input '%s': expected (%d, %s), got (%d, %s)",
+					test.input, test.expectedLength, test.expectedState, length, state)
+			}
+		})
+	}
+}
+
+func TestLongestAcceptedPrefix_EmptyAccepted(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	length, state := fa.LongestAcceptedPrefix("1x")
+	if length != 0 || state != "S0" {
+		t.Errorf("Expected the empty prefix to be accepted in S0, got (%d, %s)", length, state)
+	}
+}
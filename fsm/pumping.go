@@ -0,0 +1,49 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type PumpingWitness struct {
+	X     string
+	Y     string
+	Z     string
+	State State
+}
+
+func (w PumpingWitness) Pump(i int) string {
+	return w.X + strings.Repeat(w.Y, i) + w.Z
+}
+
+func (fa *FiniteAutomaton) PumpingDecomposition(input string) (PumpingWitness, error) {
+	symbols := []rune(input)
+	if len(symbols) < len(fa.States) {
+		return PumpingWitness{}, fmt.Errorf("input has %d symbols; the pumping lemma needs at least %d (the state count)", len(symbols), len(fa.States))
+	}
+
+	accepted, err := fa.Accepts(input)
+	if err != nil {
+		return PumpingWitness{}, err
+	}
+	if !accepted {
+		return PumpingWitness{}, fmt.Errorf("input %q is not accepted", input)
+	}
+
+	firstVisit := map[State]int{fa.InitialState: 0}
+	state := fa.InitialState
+	for i, char := range symbols {
+		state = fa.TransitionFunction(state, fa.normalize(Symbol(string(char))))
+		if start, seen := firstVisit[state]; seen {
+			return PumpingWitness{
+				X:     string(symbols[:start]),
+				Y:     string(symbols[start : i+1]),
+				Z:     string(symbols[i+1:]),
+				State: state,
+			}, nil
+		}
+		firstVisit[state] = i + 1
+	}
+
+	return PumpingWitness{}, fmt.Errorf("no state repeats while reading %q; the transition function leaves the declared states", input)
+}
@@ -0,0 +1,61 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func newDivisibleByThree() *FiniteAutomaton {
+	table := TransitionTable{}
+	for r := 0; r < 3; r++ {
+		for digit, symbol := range []Symbol{"0", "1"} {
+			table.Set(State(rune('0'+r)), symbol, State(rune('0'+(2*r+digit)%3)))
+		}
+	}
+	return NewTableAutomaton([]State{"0", "1", "2"}, []Symbol{"0", "1"}, "0", []State{"0"}, table)
+}
+
+func TestFiniteAutomaton_PumpingDecomposition(t *testing.T) {
+	fa := newDivisibleByThree()
+
+	tests := []struct {
+		input         string
+		expectedX     string
+		expectedY     string
+		expectedZ     string
+		expectedState State
+	}{
+		{"110", "", "11", "0", "0"},
+		{"1001", "1", "00", "1", "1"},
+		{"000", "", "0", "00", "0"},
+	}
+
+	for _, test := range tests {
+		witness, err := fa.PumpingDecomposition(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", test.input, err)
+		}
+		if witness.X != test.expectedX || witness.Y != test.expectedY || witness.Z != test.expectedZ || witness.State != test.expectedState {
+			t.Errorf("For input %s: expected x=%q y=%q z=%q at %s, got %+v",
+				test.input, test.expectedX, test.expectedY, test.expectedZ, test.expectedState, witness)
+		}
+		if len(witness.X)+len(witness.Y) > len(fa.States) || witness.Y == "" {
+			t.Errorf("For input %s: decomposition %+v violates |xy| <= p or |y| >= 1", test.input, witness)
+		}
+
+		for i := 0; i <= 3; i++ {
+			if accepted, _ := fa.Accepts(witness.Pump(i)); !accepted {
+				t.Errorf("For input %s: expected pumped string %q to be accepted", test.input, witness.Pump(i))
+			}
+		}
+	}
+}
+
+func TestFiniteAutomaton_PumpingDecompositionErrors(t *testing.T) {
+	fa := newDivisibleByThree()
+
+	for _, input := range []string{"11", "111", "10a"} {
+		if _, err := fa.PumpingDecomposition(input); err == nil {
+			t.Errorf("Expected error for %q, but got none", input)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package fsm
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const DeadState State = "Dead"
+
+func Random(numStates int, alphabet []Symbol, density float64, seed int64) *FiniteAutomaton {
+	r := rand.New(rand.NewSource(seed))
+	states := randomStates(numStates)
+	accepting := randomAccepting(r, states)
+
+	table := make(TransitionTable)
+	for _, slot := range spanningSlots(r, states, alphabet) {
+		table.Set(slot.state, slot.symbol, slot.to)
+	}
+
+	needsDeadState := false
+	for _, state := range states {
+		for _, symbol := range alphabet {
+			if _, ok := table[state][symbol]; ok {
+				continue
+			}
+			if r.Float64() < density {
+				table.Set(state, symbol, states[r.Intn(len(states))])
+			} else {
+				table.Set(state, symbol, DeadState)
+				needsDeadState = true
+			}
+		}
+	}
+
+	if needsDeadState {
+		for _, symbol := range alphabet {
+			table.Set(DeadState, symbol, DeadState)
+		}
+		states = append(states, DeadState)
+	}
+
+	return NewTableAutomaton(states, alphabet, states[0], accepting, table)
+}
+
+func RandomNFA(numStates int, alphabet []Symbol, density float64, seed int64) *NFA {
+	r := rand.New(rand.NewSource(seed))
+	states := randomStates(numStates)
+
+	nfa := NewNFA(states, alphabet, states[0], randomAccepting(r, states))
+	for _, slot := range spanningSlots(r, states, alphabet) {
+		nfa.AddTransition(slot.state, slot.symbol, slot.to)
+	}
+
+	for _, from := range states {
+		for _, symbol := range append([]Symbol{Epsilon}, alphabet...) {
+			for _, to := range states {
+				if r.Float64() < density {
+					nfa.AddTransition(from, symbol, to)
+				}
+			}
+		}
+	}
+
+	return nfa
+}
+
+type slot struct {
+	state  State
+	symbol Symbol
+	to     State
+}
+
+// spanningSlots picks, for every state after the first, an unused
+// (state, symbol) slot on an earlier state so all states are reachable.
+func spanningSlots(r *rand.Rand, states []State, alphabet []Symbol) []slot {
+	var slots, free []slot
+
+	for i := 1; i < len(states); i++ {
+		for _, symbol := range alphabet {
+			free = append(free, slot{state: states[i-1], symbol: symbol})
+		}
+		if len(free) == 0 {
+			break
+		}
+		pick := r.Intn(len(free))
+		chosen := free[pick]
+		chosen.to = states[i]
+		slots = append(slots, chosen)
+		free = append(free[:pick], free[pick+1:]...)
+	}
+
+	return slots
+}
+func randomStates(numStates int) []State {
+	if numStates < 1 {
+		numStates = 1
+	}
+
+	states := make([]State, numStates)
+	for i := range states {
+		states[i] = State(fmt.Sprintf("Q%d", i))
+	}
+	return states
+}
+
+func randomAccepting(r *rand.Rand, states []State) []State {
+	var accepting []State
+	for _, state := range states {
+		if r.Intn(2) == 0 {
+			accepting = append(accepting, state)
+		}
+	}
+	if len(accepting) == 0 {
+		accepting = append(accepting, states[r.Intn(len(states))])
+	}
+	return accepting
+}
@@ -0,0 +1,107 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func reachableStates(fa *FiniteAutomaton) map[State]bool {
+	seen := map[State]bool{fa.InitialState: true}
+	queue := []State{fa.InitialState}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+func TestRandom_Deterministic(t *testing.T) {
+	alphabet := []Symbol{"a", "b", "c"}
+	a := Random(8, alphabet, 0.7, 42)
+	b := Random(8, alphabet, 0.7, 42)
+
+	if a.DOT() != b.DOT() {
+		t.Error("Expected the same seed to produce the same automaton")
+	}
+}
+
+func TestRandom_Valid(t *testing.T) {
+	alphabet := []Symbol{"0", "1"}
+
+	for seed := int64(0); seed < 50; seed++ {
+		fa := Random(10, alphabet, 0.5, seed)
+
+		declared := make(map[State]bool)
+		for _, state := range fa.States {
+			declared[state] = true
+		}
+
+		for _, state := range fa.States {
+			for _, symbol := range alphabet {
+				if next := fa.TransitionFunction(state, symbol); !declared[next] {
+					t.Fatalf("Seed %d: transition %s --%s--> %s leaves the declared states", seed, state, symbol, next)
+				}
+			}
+		}
+
+		reachable := reachableStates(fa)
+		for i := 0; i < 10; i++ {
+			if !reachable[fa.States[i]] {
+				t.Fatalf("Seed %d: state %s is unreachable", seed, fa.States[i])
+			}
+		}
+
+		if len(fa.AcceptingStates) == 0 {
+			t.Fatalf("Seed %d: expected at least one accepting state", seed)
+		}
+	}
+}
+
+func TestRandom_Density(t *testing.T) {
+	alphabet := []Symbol{"0", "1"}
+
+	dense := Random(6, alphabet, 1, 3)
+	for _, state := range dense.States {
+		if state == DeadState {
+			t.Error("Expected no dead state at density 1")
+		}
+	}
+
+	sparse := Random(6, alphabet, 0, 3)
+	if sparse.States[len(sparse.States)-1] != DeadState {
+		t.Errorf("Expected a dead state at density 0, got states %v", sparse.States)
+	}
+	if sparse.IsAcceptingState(DeadState) {
+		t.Error("Dead state should not be accepting")
+	}
+}
+
+func TestRandomNFA_ToDFA(t *testing.T) {
+	alphabet := []Symbol{"a", "b"}
+
+	for seed := int64(0); seed < 20; seed++ {
+		nfa := RandomNFA(5, alphabet, 0.2, seed)
+		dfa := nfa.ToDFA()
+
+		for _, input := range SeedCorpus(alphabet, 6) {
+			expected, _ := nfa.Accepts(input)
+			accepted, _ := dfa.Accepts(input)
+			if accepted != expected {
+				t.Fatalf("Seed %d, input '%s': NFA accepted=%v but DFA accepted=%v", seed, input, expected, accepted)
+			}
+		}
+	}
+}
+
+func TestRandom_SingleState(t *testing.T) {
+	fa := Random(0, []Symbol{"x"}, 1, 1)
+	if len(fa.States) != 1 {
+		t.Errorf("Expected 1 state, got %d", len(fa.States))
+	}
+}
@@ -0,0 +1,48 @@
+package fsm
+
+func (fa *FiniteAutomaton) Reachable(from, to State) (bool, []Transition) {
+	return fa.ReachableAvoiding(from, to)
+}
+
+func (fa *FiniteAutomaton) ReachableAvoiding(from, to State, avoid ...State) (bool, []Transition) {
+	avoided := make(map[State]bool, len(avoid))
+	for _, state := range avoid {
+		avoided[state] = true
+	}
+
+	if from == to {
+		return true, []Transition{}
+	}
+	if avoided[from] {
+		return false, nil
+	}
+
+	parent := make(map[State]Transition)
+	seen := map[State]bool{from: true}
+	queue := []State{from}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			if seen[next] || (avoided[next] && next != to) {
+				continue
+			}
+			seen[next] = true
+			parent[next] = Transition{From: state, Symbol: symbol, To: next}
+
+			if next == to {
+				var path []Transition
+				for current := to; current != from; current = parent[current].From {
+					path = append([]Transition{parent[current]}, path...)
+				}
+				return true, path
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return false, nil
+}
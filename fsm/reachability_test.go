@@ -0,0 +1,76 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func newSessionAutomaton() *FiniteAutomaton {
+	table := TransitionTable{}
+	table.Set("Anonymous", "l", "Authenticated")
+	table.Set("Authenticated", "o", "LoggedOut")
+	table.Set("Authenticated", "e", "Error")
+	table.Set("Authenticated", "r", "Authenticated")
+	table.Set("LoggedOut", "e", "Error")
+	table.Set("LoggedOut", "l", "Authenticated")
+
+	return NewTableAutomaton(
+		[]State{"Anonymous", "Authenticated", "LoggedOut", "Error"},
+		[]Symbol{"l", "o", "e", "r"},
+		"Anonymous",
+		[]State{"LoggedOut"},
+		table,
+	)
+}
+
+func TestReachable(t *testing.T) {
+	fa := newSessionAutomaton()
+
+	reachable, path := fa.Reachable("Anonymous", "Error")
+	if !reachable {
+		t.Fatal("Expected Error to be reachable from Anonymous")
+	}
+	if len(path) != 2 || path[0].Symbol != "l" || path[1].Symbol != "e" {
+		t.Errorf("Expected shortest witness l,e, got %v", path)
+	}
+
+	state := State("Anonymous")
+	for _, transition := range path {
+		if transition.From != state {
+			t.Fatalf("Witness path is not connected at %v", transition)
+		}
+		state = fa.TransitionFunction(state, transition.Symbol)
+	}
+	if state != "Error" {
+		t.Errorf("Expected witness to end in Error, got %s", state)
+	}
+
+	if reachable, path := fa.Reachable("Error", "Anonymous"); reachable || path != nil {
+		t.Errorf("Expected Anonymous to be unreachable from Error, got path %v", path)
+	}
+
+	if reachable, path := fa.Reachable("LoggedOut", "LoggedOut"); !reachable || len(path) != 0 {
+		t.Errorf("Expected a state to reach itself with an empty path, got %v, %v", reachable, path)
+	}
+}
+
+func TestReachableAvoiding(t *testing.T) {
+	fa := newSessionAutomaton()
+
+	reachable, path := fa.ReachableAvoiding("Authenticated", "Error", "LoggedOut")
+	if !reachable {
+		t.Fatal("Expected Error to be reachable directly from Authenticated")
+	}
+	for _, transition := range path {
+		if transition.To == "LoggedOut" {
+			t.Errorf("Witness should avoid LoggedOut, got %v", path)
+		}
+	}
+
+	fa.Table.Set("Authenticated", "e", "Authenticated")
+	if reachable, _ := fa.ReachableAvoiding("Authenticated", "Error", "LoggedOut"); reachable {
+		t.Error("Expected Error to be unreachable without passing LoggedOut")
+	}
+	if reachable, _ := fa.Reachable("Authenticated", "Error"); !reachable {
+		t.Error("Expected Error to still be reachable via LoggedOut")
+	}
+}
@@ -0,0 +1,462 @@
+package fsm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type regexKind int
+
+const (
+	regexSet regexKind = iota
+	regexEmpty
+	regexConcat
+	regexAlternate
+	regexRepeat
+)
+
+type regexNode struct {
+	kind     regexKind
+	symbols  []Symbol
+	children []*regexNode
+	min      int
+	max      int
+}
+
+type regexParser struct {
+	pattern      []rune
+	pos          int
+	alphabet     []Symbol
+	fold         func(rune) []rune
+	maxDFAStates int
+}
+
+func ASCIIAlphabet() []Symbol {
+	alphabet := []Symbol{"\t", "\n", "\r"}
+	for r := rune(' '); r <= '~'; r++ {
+		alphabet = append(alphabet, Symbol(string(r)))
+	}
+	return alphabet
+}
+
+func CompileRegex(pattern string, alphabet []Symbol, options ...CompileOption) (*NFA, error) {
+	node, err := parseRegex(pattern, alphabet, options...)
+	if err != nil {
+		return nil, err
+	}
+	return compileNode(node, alphabet), nil
+}
+
+func compileNode(node *regexNode, alphabet []Symbol) *NFA {
+	builder := &nfaBuilder{nfa: NewNFA(nil, alphabet, "", nil)}
+	start, accept := builder.build(node)
+	builder.nfa.InitialState = start
+	builder.nfa.AcceptingStates = []State{accept}
+	return builder.nfa
+}
+
+func parseRegex(pattern string, alphabet []Symbol, options ...CompileOption) (*regexNode, error) {
+	p := &regexParser{pattern: []rune(pattern), alphabet: alphabet}
+	for _, option := range options {
+		option(p)
+	}
+
+	node, err := p.parseAlternate()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.pattern) {
+		return nil, p.errorf("unexpected '%c'", p.pattern[p.pos])
+	}
+	if regexStates(node, maxRegexStates) > maxRegexStates {
+		return nil, fmt.Errorf("invalid regex %q: expands to more than %d NFA states: %w", pattern, maxRegexStates, ErrPatternTooLarge)
+	}
+	return node, nil
+}
+
+func (p *regexParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid regex %q at position %d: %s", string(p.pattern), p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *regexParser) peek() (rune, bool) {
+	if p.pos >= len(p.pattern) {
+		return 0, false
+	}
+	return p.pattern[p.pos], true
+}
+
+func (p *regexParser) parseAlternate() (*regexNode, error) {
+	first, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+
+	alternatives := []*regexNode{first}
+	for {
+		char, ok := p.peek()
+		if !ok || char != '|' {
+			break
+		}
+		p.pos++
+		next, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		alternatives = append(alternatives, next)
+	}
+
+	if len(alternatives) == 1 {
+		return first, nil
+	}
+	return &regexNode{kind: regexAlternate, children: alternatives}, nil
+}
+
+func (p *regexParser) parseConcat() (*regexNode, error) {
+	var parts []*regexNode
+	for {
+		char, ok := p.peek()
+		if !ok || char == '|' || char == ')' {
+			break
+		}
+		part, err := p.parseRepeat()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+
+	switch len(parts) {
+	case 0:
+		return &regexNode{kind: regexEmpty}, nil
+	case 1:
+		return parts[0], nil
+	default:
+		return &regexNode{kind: regexConcat, children: parts}, nil
+	}
+}
+
+func (p *regexParser) parseRepeat() (*regexNode, error) {
+	node, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		char, ok := p.peek()
+		if !ok {
+			return node, nil
+		}
+
+		switch char {
+		case '*':
+			p.pos++
+			node = &regexNode{kind: regexRepeat, children: []*regexNode{node}, min: 0, max: -1}
+		case '+':
+			p.pos++
+			node = &regexNode{kind: regexRepeat, children: []*regexNode{node}, min: 1, max: -1}
+		case '?':
+			p.pos++
+			node = &regexNode{kind: regexRepeat, children: []*regexNode{node}, min: 0, max: 1}
+		case '{':
+			min, max, err := p.parseBounds()
+			if err != nil {
+				return nil, err
+			}
+			node = &regexNode{kind: regexRepeat, children: []*regexNode{node}, min: min, max: max}
+		default:
+			return node, nil
+		}
+	}
+}
+
+func (p *regexParser) parseBounds() (int, int, error) {
+	end := p.pos
+	for end < len(p.pattern) && p.pattern[end] != '}' {
+		end++
+	}
+	if end == len(p.pattern) {
+		return 0, 0, p.errorf("unterminated repetition")
+	}
+
+	body := string(p.pattern[p.pos+1 : end])
+	lower, upper, hasComma := strings.Cut(body, ",")
+
+	min, err := strconv.Atoi(lower)
+	if err != nil || min < 0 {
+		return 0, 0, p.errorf("invalid repetition {%s}", body)
+	}
+
+	max := min
+	if hasComma {
+		if upper == "" {
+			max = -1
+		} else if max, err = strconv.Atoi(upper); err != nil || max < min {
+			return 0, 0, p.errorf("invalid repetition {%s}", body)
+		}
+	}
+	if min > MaxRepeat || max > MaxRepeat {
+		return 0, 0, fmt.Errorf("invalid regex %q at position %d: repetition {%s} exceeds %d: %w", string(p.pattern), p.pos, body, MaxRepeat, ErrPatternTooLarge)
+	}
+
+	p.pos = end + 1
+	return min, max, nil
+}
+
+func (p *regexParser) parseAtom() (*regexNode, error) {
+	char := p.pattern[p.pos]
+
+	switch char {
+	case '(':
+		// Flags set inside a group end with it.
+		fold := p.fold
+		p.pos++
+		if next, ok := p.peek(); ok && next == '?' {
+			bare, err := p.parseGroupPrefix()
+			if err != nil {
+				return nil, err
+			}
+			if bare {
+				return &regexNode{kind: regexEmpty}, nil
+			}
+		}
+		node, err := p.parseAlternate()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.peek(); !ok || closing != ')' {
+			return nil, p.errorf("missing ')'")
+		}
+		p.pos++
+		p.fold = fold
+		return node, nil
+	case '[':
+		return p.parseClass()
+	case '.':
+		p.pos++
+		return p.set(func(rune) bool { return true }), nil
+	case '\\':
+		p.pos++
+		escaped, ok := p.peek()
+		if !ok {
+			return nil, p.errorf("trailing backslash")
+		}
+		if err := p.checkEscape(escaped); err != nil {
+			return nil, err
+		}
+		p.pos++
+		if class := escapeClass(escaped); class != nil {
+			return p.set(class), nil
+		}
+		return p.literal(escapeLiteral(escaped))
+	case '*', '+', '?', '{':
+		return nil, p.errorf("missing operand for '%c'", char)
+	case ')':
+		return nil, p.errorf("unbalanced ')'")
+	default:
+		p.pos++
+		return p.literal(char)
+	}
+}
+
+func (p *regexParser) parseClass() (*regexNode, error) {
+	p.pos++
+	negated := false
+	if char, ok := p.peek(); ok && char == '^' {
+		negated = true
+		p.pos++
+	}
+
+	var predicates []func(rune) bool
+	first := true
+	for {
+		char, ok := p.peek()
+		if !ok {
+			return nil, p.errorf("missing ']'")
+		}
+		if char == ']' && !first {
+			p.pos++
+			break
+		}
+		first = false
+		p.pos++
+
+		if char == '\\' {
+			escaped, ok := p.peek()
+			if !ok {
+				return nil, p.errorf("trailing backslash")
+			}
+			p.pos++
+			if class := escapeClass(escaped); class != nil {
+				predicates = append(predicates, class)
+				continue
+			}
+			char = escapeLiteral(escaped)
+		}
+
+		low, high := char, char
+		if p.pos+1 < len(p.pattern) && p.pattern[p.pos] == '-' && p.pattern[p.pos+1] != ']' {
+			high = p.pattern[p.pos+1]
+			p.pos += 2
+			if high == '\\' && p.pos < len(p.pattern) {
+				high = escapeLiteral(p.pattern[p.pos])
+				p.pos++
+			}
+			if high < low {
+				return nil, p.errorf("invalid range %c-%c", low, high)
+			}
+		}
+		predicates = append(predicates, func(r rune) bool { return r >= low && r <= high })
+	}
+
+	return p.set(func(r rune) bool {
+		for _, predicate := range predicates {
+			if p.anyVariant(r, predicate) {
+				return !negated
+			}
+		}
+		return negated
+	}), nil
+}
+
+func (p *regexParser) literal(char rune) (*regexNode, error) {
+	if symbols := p.foldedSymbols(char); len(symbols) > 0 {
+		return &regexNode{kind: regexSet, symbols: symbols}, nil
+	}
+	return nil, p.errorf("symbol %q is not in the alphabet", char)
+}
+
+// set collects the alphabet symbols that satisfy predicate. A Wildcard in the
+// alphabet stands for every rune outside it and is tested as U+FFFD, so `.` and
+// negated classes cover it while literals never do.
+func (p *regexParser) set(predicate func(rune) bool) *regexNode {
+	var symbols []Symbol
+	for _, symbol := range p.alphabet {
+		if symbol == Wildcard {
+			if predicate(unicode.ReplacementChar) {
+				symbols = append(symbols, symbol)
+			}
+			continue
+		}
+		runes := []rune(string(symbol))
+		if len(runes) == 1 && predicate(runes[0]) {
+			symbols = append(symbols, symbol)
+		}
+	}
+	return &regexNode{kind: regexSet, symbols: symbols}
+}
+
+func escapeClass(char rune) func(rune) bool {
+	isWord := func(r rune) bool {
+		return r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))
+	}
+	isDigit := func(r rune) bool { return r >= '0' && r <= '9' }
+	isSpace := func(r rune) bool { return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v' }
+
+	switch char {
+	case 'd':
+		return isDigit
+	case 'D':
+		return func(r rune) bool { return !isDigit(r) }
+	case 'w':
+		return isWord
+	case 'W':
+		return func(r rune) bool { return !isWord(r) }
+	case 's':
+		return isSpace
+	case 'S':
+		return func(r rune) bool { return !isSpace(r) }
+	}
+	return nil
+}
+
+func escapeLiteral(char rune) rune {
+	switch char {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	case 'f':
+		return '\f'
+	case 'v':
+		return '\v'
+	}
+	return char
+}
+
+type nfaBuilder struct {
+	nfa *NFA
+}
+
+func (b *nfaBuilder) newState() State {
+	state := State(fmt.Sprintf("r%d", len(b.nfa.States)))
+	b.nfa.States = append(b.nfa.States, state)
+	return state
+}
+
+func (b *nfaBuilder) build(node *regexNode) (State, State) {
+	switch node.kind {
+	case regexSet:
+		start, accept := b.newState(), b.newState()
+		for _, symbol := range node.symbols {
+			b.nfa.AddTransition(start, symbol, accept)
+		}
+		return start, accept
+	case regexConcat:
+		start, accept := b.build(node.children[0])
+		for _, child := range node.children[1:] {
+			childStart, childAccept := b.build(child)
+			b.nfa.AddTransition(accept, Epsilon, childStart)
+			accept = childAccept
+		}
+		return start, accept
+	case regexAlternate:
+		start, accept := b.newState(), b.newState()
+		for _, child := range node.children {
+			childStart, childAccept := b.build(child)
+			b.nfa.AddTransition(start, Epsilon, childStart)
+			b.nfa.AddTransition(childAccept, Epsilon, accept)
+		}
+		return start, accept
+	case regexRepeat:
+		return b.buildRepeat(node.children[0], node.min, node.max)
+	default:
+		start, accept := b.newState(), b.newState()
+		b.nfa.AddTransition(start, Epsilon, accept)
+		return start, accept
+	}
+}
+
+func (b *nfaBuilder) buildRepeat(child *regexNode, min, max int) (State, State) {
+	start := b.newState()
+	accept := start
+
+	for i := 0; i < min; i++ {
+		childStart, childAccept := b.build(child)
+		b.nfa.AddTransition(accept, Epsilon, childStart)
+		accept = childAccept
+	}
+
+	if max < 0 {
+		loopStart, loopAccept := b.build(child)
+		end := b.newState()
+		b.nfa.AddTransition(accept, Epsilon, loopStart)
+		b.nfa.AddTransition(accept, Epsilon, end)
+		b.nfa.AddTransition(loopAccept, Epsilon, loopStart)
+		b.nfa.AddTransition(loopAccept, Epsilon, end)
+		return start, end
+	}
+
+	end := b.newState()
+	for i := min; i < max; i++ {
+		b.nfa.AddTransition(accept, Epsilon, end)
+		childStart, childAccept := b.build(child)
+		b.nfa.AddTransition(accept, Epsilon, childStart)
+		accept = childAccept
+	}
+	b.nfa.AddTransition(accept, Epsilon, end)
+	return start, end
+}
@@ -0,0 +1,89 @@
+package fsm
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestCompileRegex_MatchesStandardLibrary(t *testing.T) {
+	alphabet := []Symbol{"a", "b", "c", "0", "1", "-", "."}
+
+	patterns := []string{
+		"abc",
+		"a|b",
+		"a*",
+		"(ab)+",
+		"a?b",
+		"[a-c]+",
+		"[^a]*",
+		"a{2}",
+		"a{1,3}",
+		"(a|bc){2,}",
+		".*01.*",
+		"\\d+(\\.\\d*)?",
+		"-?[01]+",
+		"a||b",
+		"()",
+		"[a\\-]+",
+	}
+
+	inputs := SeedCorpus(alphabet, 4)
+	inputs = append(inputs, "0.10", "-0101", "aaaa", "bcbca", "ababab")
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			nfa, err := CompileRegex(pattern, alphabet)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			dfa := nfa.ToDFA()
+			reference := regexp.MustCompile("^(?:" + pattern + ")$")
+
+			for _, input := range inputs {
+				expected := reference.MatchString(input)
+
+				accepted, err := nfa.Accepts(input)
+				if err != nil {
+					t.Fatalf("Unexpected error for input '%s': %v", input, err)
+				}
+				if accepted != expected {
+					t.Errorf("NFA for '%s' on input '%s': expected %v, got %v", pattern, input, expected, accepted)
+				}
+
+				if accepted, _ := dfa.Accepts(input); accepted != expected {
+					t.Errorf("DFA for '%s' on input '%s': expected %v, got %v", pattern, input, expected, accepted)
+				}
+			}
+		})
+	}
+}
+
+func TestCompileRegex_Errors(t *testing.T) {
+	alphabet := []Symbol{"a", "b"}
+
+	invalid := []string{"(a", "a)", "*a", "[ab", "a{2", "a{3,1}", "z", "a\\", "[b-a]"}
+	for _, pattern := range invalid {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := CompileRegex(pattern, alphabet); err == nil {
+				t.Errorf("Expected error for pattern '%s', but got none", pattern)
+			}
+		})
+	}
+}
+
+func TestASCIIAlphabet(t *testing.T) {
+	alphabet := ASCIIAlphabet()
+	if len(alphabet) != 98 {
+		t.Errorf("Expected 98 symbols, got %d", len(alphabet))
+	}
+
+	nfa, err := CompileRegex("[A-Za-z_]\\w*", alphabet)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for input, expected := range map[string]bool{"foo_1": true, "_": true, "1abc": false, "a b": false} {
+		if accepted, _ := nfa.Accepts(input); accepted != expected {
+			t.Errorf("For input '%s': expected %v, got %v", input, expected, accepted)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package fsm
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+type Transition struct {
+	From   State  `json:"from"`
+	Symbol Symbol `json:"symbol"`
+	To     State  `json:"to"`
+}
+
+type Snapshot struct {
+	State   State        `json:"state"`
+	History []Transition `json:"history,omitempty"`
+}
+
+type RunnerOption func(*Runner)
+
+func WithHistory() RunnerOption {
+	return func(r *Runner) {
+		r.recordHistory = true
+	}
+}
+
+// WithHistoryBuffer enables history mode and records into buf, so a caller
+// that reuses runners or buffers avoids growing a new slice per run.
+func WithHistoryBuffer(buf []Transition) RunnerOption {
+	return func(r *Runner) {
+		r.recordHistory = true
+		r.history = buf[:0]
+	}
+}
+
+// WithHistoryLimit enables history mode but keeps only the last n
+// transitions, so a long-lived runner can still roll back without its
+// history growing with every step.
+func WithHistoryLimit(n int) RunnerOption {
+	return func(r *Runner) {
+		r.recordHistory = true
+		r.historyLimit = n
+	}
+}
+
+func WithLogger(logger *slog.Logger) RunnerOption {
+	return func(r *Runner) {
+		r.logger = logger
+	}
+}
+
+type Runner struct {
+	automaton     *FiniteAutomaton
+	currentState  State
+	recordHistory bool
+	historyLimit  int
+	history       []Transition
+	coverage      *Coverage
+	logger        *slog.Logger
+	byteAlphabet  *bool
+}
+
+func NewRunner(automaton *FiniteAutomaton, options ...RunnerOption) *Runner {
+	r := &Runner{
+		automaton:    automaton,
+		currentState: automaton.InitialState,
+		logger:       automaton.Logger,
+	}
+
+	for _, option := range options {
+		option(r)
+	}
+
+	return r
+}
+
+func (r *Runner) Step(symbol Symbol) (State, error) {
+	symbol = r.automaton.normalize(symbol)
+	if !r.automaton.isValidSymbol(symbol) {
+		logInvalidSymbol(r.logger, r.currentState, symbol, -1)
+		return r.currentState, fmt.Errorf("invalid symbol '%s': not in alphabet %v", symbol, r.automaton.Alphabet)
+	}
+
+	r.advance(symbol, r.automaton.TransitionFunction(r.currentState, symbol))
+	return r.currentState, nil
+}
+
+func (r *Runner) advance(symbol Symbol, to State) {
+	from := r.currentState
+	r.currentState = to
+
+	if debugEnabled(r.logger) {
+		logTransition(r.logger, Transition{From: from, Symbol: symbol, To: to}, -1)
+	}
+
+	if r.coverage != nil {
+		r.coverage.Record(from, symbol)
+	}
+
+	if r.recordHistory {
+		if r.historyLimit > 0 && len(r.history) >= r.historyLimit {
+			r.history = r.history[len(r.history)-r.historyLimit+1:]
+		}
+		r.history = append(r.history, Transition{From: from, Symbol: symbol, To: to})
+	}
+}
+
+func (r *Runner) Feed(input string) (State, error) {
+	for i, char := range input {
+		symbol := runeSymbol(char)
+		if _, err := r.Step(symbol); err != nil {
+			return r.currentState, fmt.Errorf("at position %d: %w", i, err)
+		}
+	}
+
+	return r.currentState, nil
+}
+
+func (r *Runner) CurrentState() State {
+	return r.currentState
+}
+
+func (r *Runner) IsAccepting() bool {
+	return r.automaton.IsAcceptingState(r.currentState)
+}
+
+func (r *Runner) Reset() {
+	r.currentState = r.automaton.InitialState
+	r.history = r.history[:0]
+}
+
+func (r *Runner) History() []Transition {
+	history := make([]Transition, len(r.history))
+	copy(history, r.history)
+	return history
+}
+
+// LastTransition returns the most recent recorded transition without
+// copying the history.
+func (r *Runner) LastTransition() (Transition, bool) {
+	if len(r.history) == 0 {
+		return Transition{}, false
+	}
+	return r.history[len(r.history)-1], true
+}
+
+// HistoryInto appends the recorded transitions to dst[:0] and returns it,
+// for callers that want a copy without allocating one per call.
+func (r *Runner) HistoryInto(dst []Transition) []Transition {
+	return append(dst[:0], r.history...)
+}
+
+func (r *Runner) Rollback(n int) error {
+	if !r.recordHistory {
+		return fmt.Errorf("rollback requires history mode: create the runner with WithHistory()")
+	}
+
+	if n < 0 || n > len(r.history) {
+		return fmt.Errorf("cannot roll back %d symbols: only %d recorded", n, len(r.history))
+	}
+
+	if n == 0 {
+		return nil
+	}
+
+	// Rewind to the recorded source state rather than replaying from the
+	// initial state: after Restore or a history limit, the history need not
+	// start there.
+	r.currentState = r.history[len(r.history)-n].From
+	r.history = r.history[:len(r.history)-n]
+
+	return nil
+}
+
+func (r *Runner) Snapshot() Snapshot {
+	return Snapshot{State: r.currentState, History: r.History()}
+}
+
+func (r *Runner) Restore(snapshot Snapshot) error {
+	known := false
+	for _, state := range r.automaton.States {
+		if state == snapshot.State {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return fmt.Errorf("cannot restore snapshot: unknown state %s", snapshot.State)
+	}
+
+	if r.recordHistory {
+		if err := r.checkHistory(snapshot); err != nil {
+			return fmt.Errorf("cannot restore snapshot: %w", err)
+		}
+	}
+
+	r.currentState = snapshot.State
+	r.history = nil
+	if r.recordHistory {
+		history := snapshot.History
+		if r.historyLimit > 0 && len(history) > r.historyLimit {
+			history = history[len(history)-r.historyLimit:]
+		}
+		r.history = append([]Transition(nil), history...)
+	}
+	return nil
+}
+
+// checkHistory verifies that a snapshot's history is a run of the automaton
+// ending in its state: each transition is one the automaton takes, and each
+// starts where the previous one ended. The run must begin at the initial
+// state unless the runner has a history limit, since a limited history may
+// have dropped its first steps.
+func (r *Runner) checkHistory(snapshot Snapshot) error {
+	history := snapshot.History
+	if len(history) == 0 {
+		return nil
+	}
+	if r.historyLimit == 0 && history[0].From != r.automaton.InitialState {
+		return fmt.Errorf("history starts in %s, not the initial state %s", history[0].From, r.automaton.InitialState)
+	}
+
+	for i, transition := range history {
+		if i > 0 && transition.From != history[i-1].To {
+			return fmt.Errorf("history step %d starts in %s, but step %d ended in %s", i+1, transition.From, i, history[i-1].To)
+		}
+		if !r.automaton.isValidSymbol(transition.Symbol) {
+			return fmt.Errorf("history step %d: symbol '%s' not in alphabet %v", i+1, transition.Symbol, r.automaton.Alphabet)
+		}
+		if next := r.automaton.TransitionFunction(transition.From, transition.Symbol); next != transition.To {
+			return fmt.Errorf("history step %d: %s on '%s' leads to %s, not %s", i+1, transition.From, transition.Symbol, next, transition.To)
+		}
+	}
+
+	if last := history[len(history)-1]; last.To != snapshot.State {
+		return fmt.Errorf("history ends in %s, but the snapshot state is %s", last.To, snapshot.State)
+	}
+	return nil
+}
+
+func (r *Runner) Fork() *Runner {
+	fork := *r
+	fork.history = make([]Transition, len(r.history))
+	copy(fork.history, r.history)
+	return &fork
+}
@@ -0,0 +1,304 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func newRunnerTestAutomaton() *FiniteAutomaton {
+	states := []State{"S0", "S1", "S2"}
+	alphabet := []Symbol{"0", "1"}
+	acceptingStates := []State{"S0"}
+
+	transitionFunction := func(currentState State, symbol Symbol) State {
+		table := map[State]map[Symbol]State{
+			"S0": {"0": "S0", "1": "S1"},
+			"S1": {"0": "S2", "1": "S0"},
+			"S2": {"0": "S1", "1": "S2"},
+		}
+		return table[currentState][symbol]
+	}
+
+	return NewFiniteAutomaton(states, alphabet, "S0", acceptingStates, transitionFunction)
+}
+
+func TestRunner_Feed(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	runner := NewRunner(fa)
+
+	state, err := runner.Feed("1101")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedState, _ := fa.ProcessInput("1101")
+	if state != expectedState {
+		t.Errorf("Expected state %s, got %s", expectedState, state)
+	}
+
+	if runner.CurrentState() != expectedState {
+		t.Errorf("Expected current state %s, got %s", expectedState, runner.CurrentState())
+	}
+}
+
+func TestRunner_StepInvalidSymbol(t *testing.T) {
+	runner := NewRunner(newRunnerTestAutomaton())
+
+	if _, err := runner.Feed("1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	state, err := runner.Step("2")
+	if err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+
+	if state != "S1" {
+		t.Errorf("Expected state to stay S1 after invalid symbol, got %s", state)
+	}
+}
+
+func TestRunner_Rollback(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	tests := []struct {
+		input    string
+		rollback int
+	}{
+		{"1101", 0},
+		{"1101", 1},
+		{"1101", 2},
+		{"1101", 4},
+		{"111000", 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			runner := NewRunner(fa, WithHistory())
+			if _, err := runner.Feed(test.input); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if err := runner.Rollback(test.rollback); err != nil {
+				t.Fatalf("Unexpected rollback error: %v", err)
+			}
+
+			prefix := test.input[:len(test.input)-test.rollback]
+			expectedState, _ := fa.ProcessInput(prefix)
+			if runner.CurrentState() != expectedState {
+				t.Errorf("After rolling back %d symbols: expected state %s, got %s",
+					test.rollback, expectedState, runner.CurrentState())
+			}
+
+			if len(runner.History()) != len(prefix) {
+				t.Errorf("Expected %d history entries, got %d", len(prefix), len(runner.History()))
+			}
+		})
+	}
+}
+
+func TestRunner_RollbackThenContinue(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	runner := NewRunner(fa, WithHistory())
+
+	if _, err := runner.Feed("111"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := runner.Rollback(2); err != nil {
+		t.Fatalf("Unexpected rollback error: %v", err)
+	}
+	if _, err := runner.Feed("00"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedState, _ := fa.ProcessInput("100")
+	if runner.CurrentState() != expectedState {
+		t.Errorf("Expected state %s, got %s", expectedState, runner.CurrentState())
+	}
+}
+
+func TestRunner_RollbackErrors(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	withoutHistory := NewRunner(fa)
+	withoutHistory.Feed("11")
+	if err := withoutHistory.Rollback(1); err == nil {
+		t.Error("Expected error when rolling back without history mode, but got none")
+	}
+
+	withHistory := NewRunner(fa, WithHistory())
+	withHistory.Feed("11")
+	if err := withHistory.Rollback(3); err == nil {
+		t.Error("Expected error when rolling back past the start, but got none")
+	}
+	if err := withHistory.Rollback(-1); err == nil {
+		t.Error("Expected error for negative rollback, but got none")
+	}
+}
+
+func TestRunner_Fork(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	live := NewRunner(fa, WithHistory())
+
+	if _, err := live.Feed("11"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fork := live.Fork()
+	if fork.CurrentState() != live.CurrentState() {
+		t.Errorf("Expected fork to start at %s, got %s", live.CurrentState(), fork.CurrentState())
+	}
+
+	if _, err := fork.Feed("10"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if live.CurrentState() != "S0" {
+		t.Errorf("Expected live runner to stay at S0, got %s", live.CurrentState())
+	}
+	if len(live.History()) != 2 {
+		t.Errorf("Expected live runner history to stay at 2 entries, got %d", len(live.History()))
+	}
+
+	expectedState, _ := fa.ProcessInput("1110")
+	if fork.CurrentState() != expectedState {
+		t.Errorf("Expected fork state %s, got %s", expectedState, fork.CurrentState())
+	}
+
+	if err := fork.Rollback(4); err != nil {
+		t.Fatalf("Unexpected rollback error: %v", err)
+	}
+	if live.CurrentState() != "S0" || len(live.History()) != 2 {
+		t.Error("Rolling back the fork should not affect the live runner")
+	}
+}
+
+func TestRunner_SnapshotRestore(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+	runner := NewRunner(fa, WithHistory())
+	runner.Feed("110")
+
+	snapshot := runner.Snapshot()
+	if snapshot.State != "S0" || len(snapshot.History) != 3 {
+		t.Fatalf("Unexpected snapshot: %+v", snapshot)
+	}
+
+	restored := NewRunner(fa, WithHistory())
+	if err := restored.Restore(snapshot); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	restored.Feed("1")
+	if restored.CurrentState() != "S1" || len(restored.History()) != 4 {
+		t.Errorf("Expected restored runner to continue from S0, got %s with %d transitions", restored.CurrentState(), len(restored.History()))
+	}
+	if len(runner.History()) != 3 {
+		t.Error("Expected the original runner to be unaffected")
+	}
+
+	if err := restored.Rollback(4); err != nil || restored.CurrentState() != "S0" {
+		t.Errorf("Expected rollback through restored history, got %s (err %v)", restored.CurrentState(), err)
+	}
+
+	plain := NewRunner(fa)
+	if err := plain.Restore(snapshot); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(plain.History()) != 0 {
+		t.Error("Expected history to be dropped without WithHistory")
+	}
+
+	if err := plain.Restore(Snapshot{State: "S9"}); err == nil {
+		t.Error("Expected error restoring an unknown state, but got none")
+	}
+}
+
+func TestRunner_RestoreChecksHistory(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+	}{
+		{"not from the initial state", Snapshot{State: "S2", History: []Transition{{From: "S1", Symbol: "0", To: "S2"}}}},
+		{"broken chain", Snapshot{State: "S2", History: []Transition{{From: "S0", Symbol: "1", To: "S1"}, {From: "S2", Symbol: "1", To: "S2"}}}},
+		{"wrong target", Snapshot{State: "S2", History: []Transition{{From: "S0", Symbol: "1", To: "S2"}}}},
+		{"unknown symbol", Snapshot{State: "S0", History: []Transition{{From: "S0", Symbol: "x", To: "S0"}}}},
+		{"ends elsewhere", Snapshot{State: "S2", History: []Transition{{From: "S0", Symbol: "1", To: "S1"}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			runner := NewRunner(fa, WithHistory())
+			if err := runner.Restore(test.snapshot); err == nil {
+				t.Fatal("Expected an error, but got none")
+			}
+			if runner.CurrentState() != "S0" || len(runner.History()) != 0 {
+				t.Errorf("Expected a failed restore to leave the runner untouched, got %s", runner.CurrentState())
+			}
+		})
+	}
+
+	limited := NewRunner(fa, WithHistoryLimit(4))
+	if err := limited.Restore(tests[0].snapshot); err != nil {
+		t.Errorf("Expected a limited runner to accept a history suffix, got %v", err)
+	}
+}
+
+func TestRunner_HistoryLimit(t *testing.T) {
+	runner := NewRunner(newRunnerTestAutomaton(), WithHistoryLimit(2))
+	if _, err := runner.Feed("1101"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	history := runner.History()
+	if len(history) != 2 || history[0].Symbol != "0" || history[1].Symbol != "1" {
+		t.Fatalf("Expected the last two transitions, got %v", history)
+	}
+	if last, ok := runner.LastTransition(); !ok || last != history[1] {
+		t.Errorf("Expected last transition %v, got %v", history[1], last)
+	}
+	if err := runner.Rollback(3); err == nil {
+		t.Error("Expected an error rolling back past the limit")
+	}
+}
+
+func TestRunner_HistoryCopiesSurviveReset(t *testing.T) {
+	runner := NewRunner(newRunnerTestAutomaton(), WithHistoryBuffer(make([]Transition, 0, 16)))
+	if _, err := runner.Feed("1101"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	history := runner.History()
+	snapshot := runner.Snapshot()
+	want := append([]Transition(nil), history...)
+
+	// Reset keeps the buffer, so these steps overwrite its first entries.
+	runner.Reset()
+	if _, err := runner.Feed("0000"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := range want {
+		if history[i] != want[i] || snapshot.History[i] != want[i] {
+			t.Fatalf("Expected earlier copies to keep %v, got %v and %v", want, history, snapshot.History)
+		}
+	}
+}
+
+func TestRunner_RollbackAfterRestore(t *testing.T) {
+	source := NewRunner(newRunnerTestAutomaton(), WithHistoryLimit(2))
+	if _, err := source.Feed("1011"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored := NewRunner(newRunnerTestAutomaton(), WithHistoryLimit(2))
+	if err := restored.Restore(source.Snapshot()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 1011 visits S1, S2, S2, S2; the kept history starts in S2, not S0.
+	if err := restored.Rollback(1); err != nil || restored.CurrentState() != "S2" {
+		t.Errorf("Expected S2 after rolling back one step, got %s (%v)", restored.CurrentState(), err)
+	}
+	if err := restored.Rollback(1); err != nil || restored.CurrentState() != "S2" {
+		t.Errorf("Expected S2 after rolling back two steps, got %s (%v)", restored.CurrentState(), err)
+	}
+}
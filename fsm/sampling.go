@@ -0,0 +1,93 @@
+package fsm
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strings"
+)
+
+type Sampler struct {
+	automaton *FiniteAutomaton
+	length    int
+	counts    []map[State]*big.Int
+}
+
+func (fa *FiniteAutomaton) Sampler(length int) *Sampler {
+	s := &Sampler{automaton: fa, length: length}
+	if length < 0 {
+		return s
+	}
+
+	s.counts = make([]map[State]*big.Int, length+1)
+	s.counts[0] = make(map[State]*big.Int, len(fa.States))
+	for _, state := range fa.States {
+		s.counts[0][state] = big.NewInt(0)
+		if fa.IsAcceptingState(state) {
+			s.counts[0][state].SetInt64(1)
+		}
+	}
+
+	for k := 1; k <= length; k++ {
+		s.counts[k] = make(map[State]*big.Int, len(fa.States))
+		for _, state := range fa.States {
+			total := new(big.Int)
+			for _, symbol := range fa.Alphabet {
+				total.Add(total, s.count(k-1, fa.TransitionFunction(state, symbol)))
+			}
+			s.counts[k][state] = total
+		}
+	}
+
+	return s
+}
+
+func (s *Sampler) count(remaining int, state State) *big.Int {
+	if count, ok := s.counts[remaining][state]; ok {
+		return count
+	}
+	return new(big.Int)
+}
+
+func (s *Sampler) Count() *big.Int {
+	if s.length < 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(s.count(s.length, s.automaton.InitialState))
+}
+
+func (s *Sampler) Sample(r *rand.Rand) (string, error) {
+	total := s.Count()
+	if total.Sign() == 0 {
+		return "", fmt.Errorf("no accepted strings of length %d", s.length)
+	}
+	if r == nil {
+		r = rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	k := new(big.Int).Rand(r, total)
+	state := s.automaton.InitialState
+	var sb strings.Builder
+	for remaining := s.length; remaining > 0; remaining-- {
+		for _, symbol := range s.automaton.Alphabet {
+			next := s.automaton.TransitionFunction(state, symbol)
+			count := s.count(remaining-1, next)
+			if k.Cmp(count) < 0 {
+				sb.WriteString(string(symbol))
+				state = next
+				break
+			}
+			k.Sub(k, count)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+func (fa *FiniteAutomaton) CountAccepted(length int) *big.Int {
+	return fa.Sampler(length).Count()
+}
+
+func (fa *FiniteAutomaton) SampleUniform(length int, r *rand.Rand) (string, error) {
+	return fa.Sampler(length).Sample(r)
+}
@@ -0,0 +1,74 @@
+package fsm
+
+import (
+	"math/big"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestFiniteAutomaton_CountAccepted(t *testing.T) {
+	fa := newDivisibleByThree()
+
+	for length := 0; length <= 10; length++ {
+		expected := 0
+		for value := 0; value < 1<<length; value++ {
+			if value%3 == 0 {
+				expected++
+			}
+		}
+		if length == 0 {
+			expected = 1
+		}
+
+		if count := fa.CountAccepted(length); count.Cmp(big.NewInt(int64(expected))) != 0 {
+			t.Errorf("For length %d: expected %d accepted strings, got %s", length, expected, count)
+		}
+	}
+
+	if count := fa.CountAccepted(-1); count.Sign() != 0 {
+		t.Errorf("Expected 0 for a negative length, got %s", count)
+	}
+
+	huge := fa.CountAccepted(200)
+	if huge.BitLen() < 190 {
+		t.Errorf("Expected a count beyond int64 for length 200, got %s", huge)
+	}
+}
+
+func TestFiniteAutomaton_SampleUniform(t *testing.T) {
+	fa := newDivisibleByThree()
+	r := rand.New(rand.NewSource(1))
+
+	sampler := fa.Sampler(4)
+	counts := make(map[string]int)
+	const draws = 6000
+	for i := 0; i < draws; i++ {
+		sample, err := sampler.Sample(r)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		value, _ := strconv.ParseInt(sample, 2, 64)
+		if len(sample) != 4 || value%3 != 0 {
+			t.Fatalf("Sample %q is not an accepted string of length 4", sample)
+		}
+		counts[sample]++
+	}
+
+	if len(counts) != 6 {
+		t.Fatalf("Expected all 6 accepted strings to be sampled, got %v", counts)
+	}
+	for sample, count := range counts {
+		if count < draws/6*8/10 || count > draws/6*12/10 {
+			t.Errorf("Sample %q drawn %d times, expected about %d", sample, count, draws/6)
+		}
+	}
+
+	empty := NewTableAutomaton([]State{"A"}, []Symbol{"x"}, "A", nil, TransitionTable{"A": {"x": "A"}})
+	if _, err := empty.SampleUniform(3, r); err == nil {
+		t.Error("Expected error when no strings are accepted, but got none")
+	}
+	if sample, err := fa.SampleUniform(0, nil); err != nil || sample != "" {
+		t.Errorf("Expected the empty string for length 0, got %q (%v)", sample, err)
+	}
+}
@@ -0,0 +1,190 @@
+package fsm
+
+import (
+	"fmt"
+	"sort"
+
+	"fsm-modulo-three/expr"
+)
+
+var scriptVariables = []string{"symbol", "state", "position", "input"}
+
+type ScriptResult struct {
+	State    State        `json:"state"`
+	Accepted bool         `json:"accepted"`
+	Outputs  []any        `json:"outputs,omitempty"`
+	Path     []Transition `json:"path"`
+}
+
+type scriptRule struct {
+	to     State
+	guard  *expr.Program
+	output *expr.Program
+}
+
+type compiledScript struct {
+	guard  *expr.Program
+	output *expr.Program
+}
+
+type ScriptedMachine struct {
+	definition *Definition
+	accepting  map[State]bool
+	alphabet   map[Symbol]bool
+	rules      map[State]map[Symbol][]scriptRule
+	env        expr.Env
+}
+
+func (d *Definition) Scripted() bool {
+	for _, transition := range d.Transitions {
+		if transition.Guard != "" || transition.Output != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func compileScript(transition TransitionDefinition) (compiledScript, error) {
+	var compiled compiledScript
+	var err error
+	if transition.Guard != "" {
+		if compiled.guard, err = expr.Compile(transition.Guard); err != nil {
+			return compiled, fmt.Errorf("transition from '%s' to '%s': guard %q: %w", transition.From, transition.To, transition.Guard, err)
+		}
+	}
+	if transition.Output != "" {
+		if compiled.output, err = expr.Compile(transition.Output); err != nil {
+			return compiled, fmt.Errorf("transition from '%s' to '%s': output %q: %w", transition.From, transition.To, transition.Output, err)
+		}
+	}
+	return compiled, nil
+}
+
+func (d *Definition) Script(env expr.Env) (*ScriptedMachine, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(env)+len(scriptVariables))
+	for _, name := range scriptVariables {
+		if _, ok := env[name]; ok {
+			return nil, fmt.Errorf("environment must not define reserved name '%s'", name)
+		}
+		known[name] = true
+	}
+	base := make(expr.Env, len(env))
+	for name, value := range env {
+		known[name] = true
+		base[name] = value
+	}
+
+	m := &ScriptedMachine{
+		definition: d,
+		accepting:  make(map[State]bool, len(d.AcceptingStates)),
+		alphabet:   make(map[Symbol]bool, len(d.Alphabet)),
+		rules:      make(map[State]map[Symbol][]scriptRule),
+		env:        base,
+	}
+	for _, state := range d.AcceptingStates {
+		m.accepting[state] = true
+	}
+	for _, symbol := range d.Alphabet {
+		m.alphabet[symbol] = true
+	}
+
+	for _, transition := range d.Transitions {
+		compiled, _ := compileScript(transition)
+		for _, program := range []*expr.Program{compiled.guard, compiled.output} {
+			if program == nil {
+				continue
+			}
+			for _, name := range program.Identifiers() {
+				if !known[name] {
+					return nil, fmt.Errorf("transition from '%s' to '%s': %q uses unknown name '%s'", transition.From, transition.To, program, name)
+				}
+			}
+		}
+
+		labels, _ := d.Labels(transition)
+		if m.rules[transition.From] == nil {
+			m.rules[transition.From] = make(map[Symbol][]scriptRule)
+		}
+		for _, symbol := range labels {
+			m.rules[transition.From][symbol] = append(m.rules[transition.From][symbol], scriptRule{
+				to:     transition.To,
+				guard:  compiled.guard,
+				output: compiled.output,
+			})
+		}
+	}
+
+	return m, nil
+}
+
+func (m *ScriptedMachine) Env() []string {
+	names := make([]string, 0, len(m.env))
+	for name := range m.env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *ScriptedMachine) Run(input string) (*ScriptResult, error) {
+	env := make(expr.Env, len(m.env)+len(scriptVariables))
+	for name, value := range m.env {
+		env[name] = value
+	}
+	env["input"] = input
+
+	result := &ScriptResult{State: m.definition.InitialState, Path: []Transition{}}
+	position := 0
+	for _, char := range input {
+		symbol := Symbol(string(char))
+		if !m.alphabet[symbol] {
+			return result, fmt.Errorf("at position %d: invalid symbol '%s': not in alphabet %v", position, symbol, m.definition.Alphabet)
+		}
+		env["symbol"] = string(symbol)
+		env["state"] = string(result.State)
+		env["position"] = position
+
+		rule, err := m.choose(result.State, symbol, env)
+		if err != nil {
+			return result, fmt.Errorf("at position %d: %w", position, err)
+		}
+		if rule != nil {
+			if rule.output != nil {
+				output, err := rule.output.Eval(env)
+				if err != nil {
+					return result, fmt.Errorf("at position %d: output %q: %w", position, rule.output, err)
+				}
+				result.Outputs = append(result.Outputs, output)
+			}
+			result.Path = append(result.Path, Transition{From: result.State, Symbol: symbol, To: rule.to})
+			result.State = rule.to
+		}
+		position++
+	}
+
+	result.Accepted = m.accepting[result.State]
+	return result, nil
+}
+
+func (m *ScriptedMachine) choose(state State, symbol Symbol, env expr.Env) (*scriptRule, error) {
+	for _, candidates := range [][]scriptRule{m.rules[state][symbol], m.rules[state][Wildcard]} {
+		for i := range candidates {
+			rule := &candidates[i]
+			if rule.guard == nil {
+				return rule, nil
+			}
+			ok, err := rule.guard.EvalBool(env)
+			if err != nil {
+				return nil, fmt.Errorf("guard %q: %w", rule.guard, err)
+			}
+			if ok {
+				return rule, nil
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,99 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/expr"
+)
+
+const scriptedDefinition = `{
+	"states": ["S0", "S1", "S2"],
+	"alphabet": ["0", "1"],
+	"initial": "S0",
+	"accepting": ["S0"],
+	"transitions": [
+		{"from": "S0", "symbol": "0", "to": "S0"},
+		{"from": "S0", "symbol": "1", "to": "S1", "guard": "position < limit", "output": "state + '>' + symbol"},
+		{"from": "S0", "symbol": "1", "to": "S2"},
+		{"from": "S1", "symbol": "0", "to": "S2"},
+		{"from": "S1", "symbol": "1", "to": "S0", "output": "position"},
+		{"from": "S2", "symbol": "0", "to": "S1"},
+		{"from": "S2", "symbol": "1", "to": "S2"}
+	]
+}`
+
+func TestScript_Run(t *testing.T) {
+	definition, err := ReadDefinition(strings.NewReader(scriptedDefinition))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !definition.Scripted() {
+		t.Fatal("Expected definition to be scripted")
+	}
+	machine, err := definition.Script(expr.Env{"limit": 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := machine.Run("11")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "S0" || !result.Accepted {
+		t.Errorf("Expected accepted run ending in S0, got %+v", result)
+	}
+	if len(result.Outputs) != 2 || result.Outputs[0] != "S0>1" || result.Outputs[1] != int64(1) {
+		t.Errorf("Expected outputs [S0>1 1], got %v", result.Outputs)
+	}
+
+	result, err = machine.Run("0011")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "S2" || result.Accepted {
+		t.Errorf("Expected guard to fail past the limit and end in S2, got %+v", result)
+	}
+	if len(result.Outputs) != 0 {
+		t.Errorf("Expected no outputs, got %v", result.Outputs)
+	}
+
+	if _, err := machine.Run("12"); err == nil || !strings.Contains(err.Error(), "invalid symbol '2'") {
+		t.Errorf("Expected invalid symbol error, got %v", err)
+	}
+}
+
+func TestScript_Errors(t *testing.T) {
+	definition, err := ReadDefinition(strings.NewReader(scriptedDefinition))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := definition.Automaton(); err == nil || !strings.Contains(err.Error(), "run it with Script") {
+		t.Errorf("Expected Automaton to refuse scripted definition, got %v", err)
+	}
+	if _, err := definition.Script(nil); err == nil || !strings.Contains(err.Error(), "unknown name 'limit'") {
+		t.Errorf("Expected unknown name error, got %v", err)
+	}
+	if _, err := definition.Script(expr.Env{"limit": 1, "state": "S0"}); err == nil || !strings.Contains(err.Error(), "reserved name 'state'") {
+		t.Errorf("Expected reserved name error, got %v", err)
+	}
+
+	machine, err := definition.Script(expr.Env{"limit": "two"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := machine.Run("1"); err == nil || !strings.Contains(err.Error(), "at position 0: guard") {
+		t.Errorf("Expected guard evaluation error, got %v", err)
+	}
+
+	invalid := strings.Replace(scriptedDefinition, `"guard": "position < limit"`, `"guard": "position <"`, 1)
+	if _, err := ReadDefinition(strings.NewReader(invalid)); err == nil || !strings.Contains(err.Error(), "guard") {
+		t.Errorf("Expected guard compile error, got %v", err)
+	}
+
+	unguarded := strings.Replace(scriptedDefinition, `"guard": "position < limit", `, "", 1)
+	if _, err := ReadDefinition(strings.NewReader(unguarded)); err == nil || !strings.Contains(err.Error(), "duplicate transition from 'S0' on '1'") {
+		t.Errorf("Expected duplicate transition error, got %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+package fsm
+
+func SeedCorpus(alphabet []Symbol, maxLen int) []string {
+	corpus := []string{""}
+	previous := []string{""}
+
+	for length := 1; length <= maxLen; length++ {
+		var current []string
+		for _, prefix := range previous {
+			for _, symbol := range alphabet {
+				current = append(current, prefix+string(symbol))
+			}
+		}
+		corpus = append(corpus, current...)
+		previous = current
+	}
+
+	return corpus
+}
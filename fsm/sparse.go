@@ -0,0 +1,204 @@
+package fsm
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SparseTable is a read-only transition table in compressed sparse row form.
+// Each row keeps a default target, the most common one in the row, and only
+// the transitions that differ from it. A DFA with tens of thousands of states
+// that mostly lead to a dead state then costs a few int32s per state instead
+// of a map per state.
+type SparseTable struct {
+	alphabet  []Symbol
+	symbolIDs map[Symbol]int32
+	states    []State
+	stateIDs  map[State]int32
+
+	defaults []int32
+	// The exceptions of row s are columns[rows[s]:rows[s+1]], sorted by
+	// symbol ID, with their targets at the same positions in targets.
+	rows    []int32
+	columns []int32
+	targets []int32
+}
+
+func newSparseTable(alphabet []Symbol) *SparseTable {
+	t := &SparseTable{
+		alphabet:  alphabet,
+		symbolIDs: make(map[Symbol]int32, len(alphabet)),
+		stateIDs:  make(map[State]int32),
+		rows:      []int32{0},
+	}
+	for i, symbol := range alphabet {
+		if _, seen := t.symbolIDs[symbol]; !seen {
+			t.symbolIDs[symbol] = int32(i)
+		}
+	}
+	return t
+}
+
+// NewSparseTable compresses table over the given states and alphabet.
+// Missing entries and Wildcard defaults are resolved as TransitionTable.Next
+// resolves them. Undeclared target states get rows of their own.
+func NewSparseTable(states []State, alphabet []Symbol, table TransitionTable) *SparseTable {
+	t := newSparseTable(alphabet)
+	for _, state := range states {
+		t.intern(state)
+	}
+
+	row := make([]int32, len(alphabet))
+	for s := 0; s < len(t.states); s++ {
+		for i, symbol := range alphabet {
+			row[i] = t.intern(table.Next(t.states[s], symbol))
+		}
+		t.appendRow(row)
+	}
+	return t
+}
+
+func (t *SparseTable) intern(state State) int32 {
+	if id, ok := t.stateIDs[state]; ok {
+		return id
+	}
+	id := int32(len(t.states))
+	t.states = append(t.states, state)
+	t.stateIDs[state] = id
+	return id
+}
+
+// appendRow stores the next row, given as one target per alphabet symbol.
+func (t *SparseTable) appendRow(row []int32) {
+	counts := make(map[int32]int)
+	var common int32 = -1
+	for _, target := range row {
+		counts[target]++
+		if common < 0 || counts[target] > counts[common] {
+			common = target
+		}
+	}
+
+	t.defaults = append(t.defaults, common)
+	for symbol, target := range row {
+		if target != common {
+			t.columns = append(t.columns, int32(symbol))
+			t.targets = append(t.targets, target)
+		}
+	}
+	t.rows = append(t.rows, int32(len(t.columns)))
+}
+
+func (t *SparseTable) Lookup(currentState State, symbol Symbol) (State, bool) {
+	state, ok := t.stateIDs[currentState]
+	if !ok || int(state) >= len(t.defaults) {
+		return "", false
+	}
+	column, ok := t.symbolIDs[symbol]
+	if !ok {
+		return "", false
+	}
+	return t.states[t.step(state, column)], true
+}
+
+func (t *SparseTable) Next(currentState State, symbol Symbol) State {
+	if next, ok := t.Lookup(currentState, symbol); ok {
+		return next
+	}
+	return currentState
+}
+
+func (t *SparseTable) step(state, column int32) int32 {
+	lo, hi := t.rows[state], t.rows[state+1]
+	i := lo + int32(sort.Search(int(hi-lo), func(i int) bool { return t.columns[lo+int32(i)] >= column }))
+	if i < hi && t.columns[i] == column {
+		return t.targets[i]
+	}
+	return t.defaults[state]
+}
+
+// States returns the states with rows, declared ones first.
+func (t *SparseTable) States() []State {
+	return t.states
+}
+
+// Stored returns the number of transitions kept as row exceptions, out of
+// len(States()) * len(alphabet).
+func (t *SparseTable) Stored() int {
+	return len(t.columns)
+}
+
+func NewSparseAutomaton(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+	table *SparseTable,
+) *FiniteAutomaton {
+	fa := NewFiniteAutomaton(states, alphabet, initialState, acceptingStates, table.Next)
+	fa.Sparse = table
+	return fa
+}
+
+// ToSparse converts any automaton to one backed by a SparseTable. Like
+// ToTable, it fails if a transition leads to an undeclared state.
+func (fa *FiniteAutomaton) ToSparse() (*FiniteAutomaton, error) {
+	declared := make(map[State]bool, len(fa.States))
+	for _, state := range fa.States {
+		declared[state] = true
+	}
+
+	t := newSparseTable(fa.Alphabet)
+	for _, state := range fa.States {
+		t.intern(state)
+	}
+	row := make([]int32, len(fa.Alphabet))
+	for _, state := range fa.States {
+		for i, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			if !declared[next] {
+				return nil, fmt.Errorf("transition from '%s' on '%s' leads to undeclared state '%s'", state, symbol, next)
+			}
+			row[i] = t.stateIDs[next]
+		}
+		t.appendRow(row)
+	}
+
+	converted := NewSparseAutomaton(fa.States, fa.Alphabet, fa.InitialState, fa.AcceptingStates, t)
+	converted.Logger = fa.Logger
+	converted.Normalizer = fa.Normalizer
+	converted.Decoder = fa.Decoder
+	return converted, nil
+}
+
+// ToSparseDFA runs the subset construction straight into a SparseTable, so
+// no per-state maps are built and each subset is dropped once its row is
+// written.
+func (n *NFA) ToSparseDFA() *FiniteAutomaton {
+	start := n.EpsilonClosure([]State{n.InitialState})
+	t := newSparseTable(n.Alphabet)
+	pending := [][]State{start}
+	t.intern(n.setName(start))
+
+	var accepting []State
+	row := make([]int32, len(n.Alphabet))
+	for s := 0; s < len(t.states); s++ {
+		set := pending[s]
+		pending[s] = nil
+		if n.ContainsAccepting(set) {
+			accepting = append(accepting, t.states[s])
+		}
+
+		for i, symbol := range n.Alphabet {
+			next := n.EpsilonClosure(n.Move(set, symbol))
+			name := n.setName(next)
+			if _, seen := t.stateIDs[name]; !seen {
+				pending = append(pending, next)
+			}
+			row[i] = t.intern(name)
+		}
+		t.appendRow(row)
+	}
+
+	return NewSparseAutomaton(t.states, n.Alphabet, t.states[0], accepting, t)
+}
@@ -0,0 +1,214 @@
+package fsm
+
+import (
+	"math/rand"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func dictionaryNFA(t testing.TB, words int) *NFA {
+	r := rand.New(rand.NewSource(1))
+	alternatives := make([]string, words)
+	for i := range alternatives {
+		word := make([]byte, 5+r.Intn(4))
+		for j := range word {
+			word[j] = byte('a' + r.Intn(26))
+		}
+		alternatives[i] = string(word)
+	}
+
+	nfa, err := CompileRegex(strings.Join(alternatives, "|"), ASCIIAlphabet())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return nfa
+}
+
+func TestNewSparseTable(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("A", "0", "B")
+	table.Set("A", "1", "A")
+	table.Set("A", "2", "A")
+	table.Set("B", "1", "Undeclared")
+	table.SetDefault("C", "A")
+	alphabet := []Symbol{"0", "1", "2"}
+
+	sparse := NewSparseTable([]State{"A", "B", "C"}, alphabet, table)
+	for _, state := range []State{"A", "B", "C", "Undeclared"} {
+		for _, symbol := range alphabet {
+			if got, expected := sparse.Next(state, symbol), table.Next(state, symbol); got != expected {
+				t.Errorf("Next(%s, %s): expected %s, got %s", state, symbol, expected, got)
+			}
+		}
+	}
+
+	if len(sparse.States()) != 4 {
+		t.Errorf("Expected the undeclared target to get a row, got states %v", sparse.States())
+	}
+	// A and B each differ from their most common target once; C and
+	// Undeclared are uniform.
+	if sparse.Stored() != 2 {
+		t.Errorf("Expected 2 stored transitions, got %d", sparse.Stored())
+	}
+}
+
+func TestSparseTable_Lookup(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("A", "0", "B")
+	sparse := NewSparseTable([]State{"A", "B"}, []Symbol{"0", "1"}, table)
+
+	if next, ok := sparse.Lookup("A", "0"); !ok || next != "B" {
+		t.Errorf("Expected A --0--> B, got %s, %v", next, ok)
+	}
+	if _, ok := sparse.Lookup("Z", "0"); ok {
+		t.Error("Expected no transition from an unknown state")
+	}
+	if _, ok := sparse.Lookup("A", "x"); ok {
+		t.Error("Expected no transition on an unknown symbol")
+	}
+	if next := sparse.Next("Z", "0"); next != "Z" {
+		t.Errorf("Expected Next to stay in an unknown state, got %s", next)
+	}
+}
+
+func TestToSparse(t *testing.T) {
+	fa := newRunnerTestAutomaton()
+
+	sparse, err := fa.ToSparse()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if sparse.Sparse == nil {
+		t.Fatal("Expected the converted automaton to carry its SparseTable")
+	}
+	equivalent, counterexample, err := Equivalent(fa, sparse)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !equivalent {
+		t.Errorf("Expected equivalent automata, counterexample: %v", counterexample)
+	}
+
+	broken := NewFiniteAutomaton(fa.States, fa.Alphabet, "S0", nil, func(State, Symbol) State { return "Nowhere" })
+	if _, err := broken.ToSparse(); err == nil {
+		t.Error("Expected error for undeclared target state, but got none")
+	}
+}
+
+func TestToSparseDFA(t *testing.T) {
+	nfa := dictionaryNFA(t, 50)
+
+	dense := nfa.ToDFA()
+	sparse := nfa.ToSparseDFA()
+	if len(sparse.States) != len(dense.States) {
+		t.Errorf("Expected %d states, got %d", len(dense.States), len(sparse.States))
+	}
+	equivalent, counterexample, err := Equivalent(dense, sparse)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !equivalent {
+		t.Errorf("Expected equivalent automata, counterexample: %v", counterexample)
+	}
+
+	for _, input := range []string{"", "a", "zzzzzz", "hello world", strings.Repeat("ab", 10)} {
+		expected, _ := dense.Accepts(input)
+		accepted, err := sparse.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if accepted != expected {
+			t.Errorf("Input '%s': expected %v, got %v", input, expected, accepted)
+		}
+	}
+	if _, err := sparse.Accepts("é"); err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+
+	// Every state of a dictionary trie has at most one live successor per
+	// letter and sends everything else to the dead state.
+	full := len(sparse.States) * len(sparse.Alphabet)
+	if sparse.Sparse.Stored() > 2*len(sparse.States) {
+		t.Errorf("Expected a compressed table, stored %d of %d transitions", sparse.Sparse.Stored(), full)
+	}
+}
+
+func TestIntern_SkipsDenseTableForHugeMachines(t *testing.T) {
+	states := make([]State, maxInternedTransitions/256+1)
+	table := TransitionTable{}
+	for i := range states {
+		states[i] = State("S" + strconv.Itoa(i))
+	}
+	for i, state := range states {
+		table.SetDefault(state, states[(i+1)%len(states)])
+	}
+	fa := NewTableAutomaton(states, ExtendedByteAlphabet(), states[0], nil, table)
+
+	if fa.symbols.table.Load() != nil {
+		t.Error("Expected no dense table above maxInternedTransitions")
+	}
+	state, err := fa.ProcessBytes([]byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state != states[3] {
+		t.Errorf("Expected %s, got %s", states[3], state)
+	}
+}
+
+func retainedBytes(build func() *FiniteAutomaton) uint64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	fa := build()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+	runtime.KeepAlive(fa)
+	if after.HeapAlloc < before.HeapAlloc {
+		return 0
+	}
+	return after.HeapAlloc - before.HeapAlloc
+}
+
+func BenchmarkSubsetConstruction(b *testing.B) {
+	nfa := dictionaryNFA(b, 100)
+	for _, strategy := range []struct {
+		name  string
+		build func() *FiniteAutomaton
+	}{
+		{"table", nfa.ToDFA},
+		{"sparse", nfa.ToSparseDFA},
+	} {
+		b.Run(strategy.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var fa *FiniteAutomaton
+			for i := 0; i < b.N; i++ {
+				fa = strategy.build()
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(len(fa.States)), "states")
+			b.ReportMetric(float64(retainedBytes(strategy.build))/float64(len(fa.States)), "B/state")
+		})
+	}
+}
+
+func BenchmarkSparseAccepts(b *testing.B) {
+	nfa := dictionaryNFA(b, 100)
+	input := strings.Repeat("a", 8)
+	for _, strategy := range []struct {
+		name string
+		fa   *FiniteAutomaton
+	}{
+		{"table", nfa.ToDFA()},
+		{"sparse", nfa.ToSparseDFA()},
+	} {
+		b.Run(strategy.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				strategy.fa.Accepts(input)
+			}
+		})
+	}
+}
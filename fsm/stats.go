@@ -0,0 +1,68 @@
+package fsm
+
+type Stats struct {
+	States          int     `json:"states"`
+	AcceptingStates int     `json:"accepting_states"`
+	AlphabetSize    int     `json:"alphabet_size"`
+	Transitions     int     `json:"transitions"`
+	Edges           int     `json:"edges"`
+	Density         float64 `json:"density"`
+	Reachable       int     `json:"reachable_states"`
+	Depth           int     `json:"depth"`
+	Diameter        int     `json:"diameter"`
+}
+
+func (fa *FiniteAutomaton) Stats() Stats {
+	stats := Stats{
+		States:          len(fa.States),
+		AcceptingStates: len(fa.AcceptingStates),
+		AlphabetSize:    len(fa.Alphabet),
+	}
+
+	transitions := fa.Transitions()
+	stats.Transitions = len(transitions)
+
+	successors := make(map[State][]State, len(fa.States))
+	edges := make(map[[2]State]bool)
+	for _, transition := range transitions {
+		key := [2]State{transition.From, transition.To}
+		if !edges[key] {
+			edges[key] = true
+			successors[transition.From] = append(successors[transition.From], transition.To)
+		}
+	}
+	stats.Edges = len(edges)
+	if stats.States > 0 {
+		stats.Density = float64(stats.Edges) / float64(stats.States*stats.States)
+	}
+
+	fromInitial := distances(fa.InitialState, successors)
+	stats.Reachable = len(fromInitial)
+	for _, distance := range fromInitial {
+		stats.Depth = max(stats.Depth, distance)
+	}
+
+	for _, state := range fa.States {
+		for _, distance := range distances(state, successors) {
+			stats.Diameter = max(stats.Diameter, distance)
+		}
+	}
+
+	return stats
+}
+
+func distances(from State, successors map[State][]State) map[State]int {
+	distance := map[State]int{from: 0}
+	queue := []State{from}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, next := range successors[state] {
+			if _, seen := distance[next]; !seen {
+				distance[next] = distance[state] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return distance
+}
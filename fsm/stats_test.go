@@ -0,0 +1,49 @@
+package fsm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFiniteAutomaton_Stats(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("A", "0", "B")
+	table.Set("A", "1", "A")
+	table.Set("B", "0", "C")
+	table.Set("B", "1", "A")
+	table.Set("C", "0", "C")
+	table.Set("C", "1", "C")
+	table.Set("D", "0", "A")
+	table.Set("D", "1", "A")
+	fa := NewTableAutomaton([]State{"A", "B", "C", "D"}, []Symbol{"0", "1"}, "A", []State{"C"}, table)
+
+	stats := fa.Stats()
+	expected := Stats{
+		States:          4,
+		AcceptingStates: 1,
+		AlphabetSize:    2,
+		Transitions:     8,
+		Edges:           6,
+		Density:         6.0 / 16,
+		Reachable:       3,
+		Depth:           2,
+		Diameter:        3,
+	}
+
+	if math.Abs(stats.Density-expected.Density) > 1e-9 {
+		t.Errorf("Expected density %.4f, got %.4f", expected.Density, stats.Density)
+	}
+	stats.Density = expected.Density
+	if stats != expected {
+		t.Errorf("Expected %+v, got %+v", expected, stats)
+	}
+}
+
+func TestFiniteAutomaton_StatsEmpty(t *testing.T) {
+	fa := NewTableAutomaton([]State{"Only"}, nil, "Only", nil, TransitionTable{})
+
+	stats := fa.Stats()
+	if stats.States != 1 || stats.Transitions != 0 || stats.Diameter != 0 || stats.Reachable != 1 {
+		t.Errorf("Unexpected stats for a single-state machine: %+v", stats)
+	}
+}
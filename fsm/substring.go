@@ -0,0 +1,55 @@
+package fsm
+
+import (
+	"fmt"
+)
+
+func SubstringAutomaton(pattern string, alphabet []Symbol) (*FiniteAutomaton, error) {
+	var symbols []Symbol
+	for _, char := range pattern {
+		symbols = append(symbols, Symbol(string(char)))
+	}
+
+	for i, symbol := range symbols {
+		valid := false
+		for _, s := range alphabet {
+			if s == symbol {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("pattern symbol '%s' at position %d is not in alphabet %v", symbol, i, alphabet)
+		}
+	}
+
+	states := make([]State, len(symbols)+1)
+	index := make(map[State]int, len(states))
+	for i := range states {
+		states[i] = State(fmt.Sprintf("P%d", i))
+		index[states[i]] = i
+	}
+
+	table := make(TransitionTable)
+	fallback := 0
+	for i := 0; i <= len(symbols); i++ {
+		for _, symbol := range alphabet {
+			switch {
+			case i == len(symbols):
+				table.Set(states[i], symbol, states[i])
+			case symbol == symbols[i]:
+				table.Set(states[i], symbol, states[i+1])
+			case i == 0:
+				table.Set(states[i], symbol, states[0])
+			default:
+				table.Set(states[i], symbol, table[states[fallback]][symbol])
+			}
+		}
+
+		if i > 0 && i < len(symbols) {
+			fallback = index[table[states[fallback]][symbols[i]]]
+		}
+	}
+
+	return NewTableAutomaton(states, alphabet, states[0], []State{states[len(symbols)]}, table), nil
+}
@@ -0,0 +1,39 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubstringAutomaton(t *testing.T) {
+	alphabet := []Symbol{"a", "b", "c"}
+
+	for _, pattern := range []string{"", "a", "ab", "aab", "abab", "abcab", "aaa"} {
+		t.Run(pattern, func(t *testing.T) {
+			fa, err := SubstringAutomaton(pattern, alphabet)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(fa.States) != len(pattern)+1 {
+				t.Errorf("Expected %d states, got %d", len(pattern)+1, len(fa.States))
+			}
+
+			for _, input := range SeedCorpus(alphabet, 6) {
+				accepted, err := fa.Accepts(input)
+				if err != nil {
+					t.Fatalf("Unexpected error for input '%s': %v", input, err)
+				}
+				if expected := strings.Contains(input, pattern); accepted != expected {
+					t.Errorf("For input '%s': expected accepted=%v, got %v", input, expected, accepted)
+				}
+			}
+		})
+	}
+}
+
+func TestSubstringAutomaton_InvalidPattern(t *testing.T) {
+	if _, err := SubstringAutomaton("abx", []Symbol{"a", "b"}); err == nil {
+		t.Error("Expected error for pattern outside the alphabet, but got none")
+	}
+}
@@ -0,0 +1,158 @@
+package fsm
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+const (
+	svgRadius      = 24.0
+	svgLayerGap    = 160.0
+	svgRowGap      = 100.0
+	svgMargin      = 70.0
+	svgCurveOffset = 30.0
+)
+
+type svgPoint struct {
+	x, y float64
+}
+
+func (fa *FiniteAutomaton) SVG() string {
+	return fa.svg(nil)
+}
+
+func (fa *FiniteAutomaton) svg(h *highlight) string {
+	positions, width, height := fa.layeredLayout()
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="14">`+"\n",
+		width, height, width, height))
+	sb.WriteString(`<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M 0 0 L 10 5 L 0 10 z"/></marker></defs>` + "\n")
+
+	start := positions[fa.InitialState]
+	sb.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black" marker-end="url(#arrow)"/>`+"\n",
+		start.x-svgRadius-40, start.y, start.x-svgRadius, start.y))
+
+	for _, edge := range groupEdges(fa.Transitions()) {
+		from, to := edge.from, edge.to
+		if _, declared := positions[to]; !declared {
+			continue
+		}
+
+		label := html.EscapeString(strings.Join(edge.labels, ","))
+		stroke := "black"
+		if h.isEdge(from, to) {
+			stroke = "red"
+		}
+		if from == to {
+			writeSelfLoop(&sb, positions[from], label, stroke)
+		} else {
+			writeEdge(&sb, positions[from], positions[to], label, stroke)
+		}
+	}
+
+	for _, state := range fa.States {
+		p := positions[state]
+		fill := "white"
+		if h.isState(state) {
+			fill = "#a6d8ff"
+		}
+		sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="%.1f" fill="%s" stroke="black"/>`+"\n", p.x, p.y, svgRadius, fill))
+		if fa.IsAcceptingState(state) {
+			sb.WriteString(fmt.Sprintf(`<circle cx="%.1f" cy="%.1f" r="%.1f" fill="none" stroke="black"/>`+"\n", p.x, p.y, svgRadius-4))
+		}
+		sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central">%s</text>`+"\n",
+			p.x, p.y, html.EscapeString(string(state))))
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func (fa *FiniteAutomaton) layeredLayout() (map[State]svgPoint, float64, float64) {
+	depth := map[State]int{fa.InitialState: 0}
+	queue := []State{fa.InitialState}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, next := range fa.successors(state) {
+			if _, seen := depth[next]; !seen {
+				depth[next] = depth[state] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	maxDepth := 0
+	for _, d := range depth {
+		maxDepth = max(maxDepth, d)
+	}
+
+	var layers [][]State
+	for _, state := range fa.States {
+		d, reachable := depth[state]
+		if !reachable {
+			d = maxDepth + 1
+		}
+		for len(layers) <= d {
+			layers = append(layers, nil)
+		}
+		layers[d] = append(layers[d], state)
+	}
+
+	positions := make(map[State]svgPoint, len(fa.States))
+	rows := 0
+	for column, layer := range layers {
+		rows = max(rows, len(layer))
+		for row, state := range layer {
+			positions[state] = svgPoint{
+				x: svgMargin + float64(column)*svgLayerGap,
+				y: svgMargin + float64(row)*svgRowGap,
+			}
+		}
+	}
+
+	width := 2*svgMargin + float64(max(len(layers)-1, 0))*svgLayerGap
+	height := 2*svgMargin + float64(max(rows-1, 0))*svgRowGap
+	return positions, width, height
+}
+
+func writeSelfLoop(sb *strings.Builder, p svgPoint, label, stroke string) {
+	top := p.y - svgRadius
+	sb.WriteString(fmt.Sprintf(`<path d="M %.1f %.1f C %.1f %.1f %.1f %.1f %.1f %.1f" fill="none" stroke="%s" marker-end="url(#arrow)"/>`+"\n",
+		p.x-10, top+2, p.x-35, top-50, p.x+35, top-50, p.x+10, top+2, stroke))
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", p.x, top-42, label))
+}
+
+func writeEdge(sb *strings.Builder, from, to svgPoint, label, stroke string) {
+	dx, dy := to.x-from.x, to.y-from.y
+	length := math.Hypot(dx, dy)
+	ux, uy := dx/length, dy/length
+
+	control := svgPoint{
+		x: (from.x+to.x)/2 + uy*svgCurveOffset,
+		y: (from.y+to.y)/2 - ux*svgCurveOffset,
+	}
+
+	startDir := normalize(control.x-from.x, control.y-from.y)
+	endDir := normalize(to.x-control.x, to.y-control.y)
+	start := svgPoint{x: from.x + startDir.x*svgRadius, y: from.y + startDir.y*svgRadius}
+	end := svgPoint{x: to.x - endDir.x*svgRadius, y: to.y - endDir.y*svgRadius}
+
+	sb.WriteString(fmt.Sprintf(`<path d="M %.1f %.1f Q %.1f %.1f %.1f %.1f" fill="none" stroke="%s" marker-end="url(#arrow)"/>`+"\n",
+		start.x, start.y, control.x, control.y, end.x, end.y, stroke))
+
+	labelX := 0.25*start.x + 0.5*control.x + 0.25*end.x
+	labelY := 0.25*start.y + 0.5*control.y + 0.25*end.y - 6
+	sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", labelX, labelY, label))
+}
+
+func normalize(x, y float64) svgPoint {
+	length := math.Hypot(x, y)
+	if length == 0 {
+		return svgPoint{x: 1}
+	}
+	return svgPoint{x: x / length, y: y / length}
+}
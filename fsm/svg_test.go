@@ -0,0 +1,74 @@
+package fsm
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSVG_WellFormed(t *testing.T) {
+	svg := newRunnerTestAutomaton().SVG()
+
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well-formed XML: %v\n%s", err, svg)
+		}
+	}
+}
+
+func TestSVG_Content(t *testing.T) {
+	svg := newRunnerTestAutomaton().SVG()
+
+	if count := strings.Count(svg, ">S0</text>") + strings.Count(svg, ">S1</text>") + strings.Count(svg, ">S2</text>"); count != 3 {
+		t.Errorf("Expected one label per state, got %d", count)
+	}
+
+	if strings.Count(svg, "<circle") != 4 {
+		t.Errorf("Expected 3 state circles plus 1 accepting ring, got %d", strings.Count(svg, "<circle"))
+	}
+
+	if strings.Count(svg, `marker-end="url(#arrow)"`) != 7 {
+		t.Errorf("Expected 6 transitions plus the start arrow, got %d", strings.Count(svg, `marker-end="url(#arrow)"`))
+	}
+}
+
+func TestSVG_EscapesNames(t *testing.T) {
+	fa := NewFiniteAutomaton(
+		[]State{"<A&B>"},
+		[]Symbol{"<"},
+		"<A&B>",
+		nil,
+		func(currentState State, symbol Symbol) State { return currentState },
+	)
+
+	svg := fa.SVG()
+	if strings.Contains(svg, "<A&B>") {
+		t.Error("SVG must escape state names")
+	}
+	if !strings.Contains(svg, "&lt;A&amp;B&gt;") {
+		t.Errorf("Expected escaped state name in SVG:\n%s", svg)
+	}
+}
+
+func TestSVG_UnreachableStatesArePlaced(t *testing.T) {
+	fa := newChainAutomaton()
+	fa.States = append(fa.States, "Island")
+
+	positions, width, height := fa.layeredLayout()
+	if len(positions) != 4 {
+		t.Fatalf("Expected 4 positioned states, got %d", len(positions))
+	}
+	if positions["Island"].x <= positions["C"].x {
+		t.Errorf("Expected unreachable state after the last layer, got %v", positions["Island"])
+	}
+	if width <= 0 || height <= 0 {
+		t.Errorf("Expected positive dimensions, got %.0fx%.0f", width, height)
+	}
+}
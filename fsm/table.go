@@ -0,0 +1,90 @@
+package fsm
+
+import "fmt"
+
+const Wildcard Symbol = "\x00*"
+
+type TransitionTable map[State]map[Symbol]State
+
+func NewTableAutomaton(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+	table TransitionTable,
+) *FiniteAutomaton {
+	fa := NewFiniteAutomaton(states, alphabet, initialState, acceptingStates, table.Next)
+	fa.Table = table
+	fa.Intern()
+	return fa
+}
+
+func (t TransitionTable) Set(from State, symbol Symbol, to State) {
+	if t[from] == nil {
+		t[from] = make(map[Symbol]State)
+	}
+	t[from][symbol] = to
+	noteTableEdit(t)
+}
+
+func (t TransitionTable) SetDefault(from State, to State) {
+	t.Set(from, Wildcard, to)
+}
+
+func (t TransitionTable) Default(state State) (State, bool) {
+	next, ok := t[state][Wildcard]
+	return next, ok
+}
+
+func (t TransitionTable) Lookup(currentState State, symbol Symbol) (State, bool) {
+	if next, ok := t[currentState][symbol]; ok {
+		return next, true
+	}
+	return t.Default(currentState)
+}
+
+func (t TransitionTable) Next(currentState State, symbol Symbol) State {
+	if next, ok := t.Lookup(currentState, symbol); ok {
+		return next
+	}
+	return currentState
+}
+
+func (fa *FiniteAutomaton) Transitions() []Transition {
+	next := fa.TransitionFunction
+	if fa.Table != nil {
+		next = fa.Table.Next
+	}
+
+	transitions := make([]Transition, 0, len(fa.States)*len(fa.Alphabet))
+	for _, state := range fa.States {
+		for _, symbol := range fa.Alphabet {
+			transitions = append(transitions, Transition{From: state, Symbol: symbol, To: next(state, symbol)})
+		}
+	}
+	return transitions
+}
+
+func (fa *FiniteAutomaton) ToTable() (*FiniteAutomaton, error) {
+	declared := make(map[State]bool, len(fa.States))
+	for _, state := range fa.States {
+		declared[state] = true
+	}
+
+	table := make(TransitionTable, len(fa.States))
+	for _, state := range fa.States {
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			if !declared[next] {
+				return nil, fmt.Errorf("transition from '%s' on '%s' leads to undeclared state '%s'", state, symbol, next)
+			}
+			table.Set(state, symbol, next)
+		}
+	}
+
+	converted := NewTableAutomaton(fa.States, fa.Alphabet, fa.InitialState, fa.AcceptingStates, table)
+	converted.Logger = fa.Logger
+	converted.Normalizer = fa.Normalizer
+	converted.Decoder = fa.Decoder
+	return converted, nil
+}
@@ -0,0 +1,130 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func TestNewTableAutomaton(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("S0", "0", "S0")
+	table.Set("S0", "1", "S1")
+	table.Set("S1", "0", "S1")
+	table.Set("S1", "1", "S0")
+
+	fa := NewTableAutomaton([]State{"S0", "S1"}, []Symbol{"0", "1"}, "S0", []State{"S0"}, table)
+
+	if fa.Table == nil {
+		t.Fatal("Expected table-based automaton to keep its table")
+	}
+
+	tests := []struct {
+		input         string
+		expectedState State
+	}{
+		{"", "S0"},
+		{"1", "S1"},
+		{"101", "S0"},
+		{"1000", "S1"},
+	}
+
+	for _, test := range tests {
+		finalState, err := fa.ProcessInput(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if finalState != test.expectedState {
+			t.Errorf("For input '%s': expected final state %s, got %s", test.input, test.expectedState, finalState)
+		}
+	}
+}
+
+func TestTransitionTable_MissingEntryStays(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("S0", "1", "S1")
+
+	if next := table.Next("S0", "0"); next != "S0" {
+		t.Errorf("Expected missing entry to stay in S0, got %s", next)
+	}
+}
+
+func TestTransitionTable_Default(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("S0", "a", "S1")
+	table.SetDefault("S0", "S2")
+
+	tests := []struct {
+		symbol        Symbol
+		expectedState State
+	}{
+		{"a", "S1"},
+		{"b", "S2"},
+		{"*", "S2"},
+	}
+	for _, test := range tests {
+		if next := table.Next("S0", test.symbol); next != test.expectedState {
+			t.Errorf("For symbol %q: expected %s, got %s", test.symbol, test.expectedState, next)
+		}
+	}
+
+	if _, ok := table.Lookup("S1", "a"); ok {
+		t.Error("Expected no entry for S1 without a default")
+	}
+	if next, ok := table.Default("S0"); !ok || next != "S2" {
+		t.Errorf("Expected default S2, got %s (%v)", next, ok)
+	}
+}
+
+func TestFiniteAutomaton_Transitions(t *testing.T) {
+	table := TransitionTable{}
+	table.Set("S0", "0", "S1")
+	table.SetDefault("S1", "S0")
+	tableBased := NewTableAutomaton([]State{"S0", "S1"}, []Symbol{"0", "1"}, "S0", nil, table)
+	functionBased := NewFiniteAutomaton(tableBased.States, tableBased.Alphabet, "S0", nil, table.Next)
+
+	expected := []Transition{
+		{From: "S0", Symbol: "0", To: "S1"},
+		{From: "S0", Symbol: "1", To: "S0"},
+		{From: "S1", Symbol: "0", To: "S0"},
+		{From: "S1", Symbol: "1", To: "S0"},
+	}
+
+	for name, fa := range map[string]*FiniteAutomaton{"table": tableBased, "function": functionBased} {
+		transitions := fa.Transitions()
+		if len(transitions) != len(expected) {
+			t.Fatalf("%s: expected %d transitions, got %d", name, len(expected), len(transitions))
+		}
+		for i, transition := range transitions {
+			if transition != expected[i] {
+				t.Errorf("%s: expected transition %d to be %v, got %v", name, i, expected[i], transition)
+			}
+		}
+	}
+}
+
+func TestFiniteAutomaton_ToTable(t *testing.T) {
+	parity := NewFiniteAutomaton([]State{"Even", "Odd"}, []Symbol{"0", "1"}, "Even", []State{"Even"}, func(state State, symbol Symbol) State {
+		if symbol == "0" {
+			return state
+		}
+		if state == "Even" {
+			return "Odd"
+		}
+		return "Even"
+	})
+
+	table, err := parity.ToTable()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if table.Table == nil || table.Table["Odd"]["1"] != "Even" {
+		t.Fatalf("Expected an explicit table, got %v", table.Table)
+	}
+	if equal, _, err := Equivalent(parity, table); err != nil || !equal {
+		t.Error("Expected the converted automaton to be equivalent")
+	}
+
+	leaky := NewFiniteAutomaton([]State{"A"}, []Symbol{"x"}, "A", nil, func(State, Symbol) State { return "B" })
+	if _, err := leaky.ToTable(); err == nil {
+		t.Error("Expected error for undeclared target state, but got none")
+	}
+}
@@ -0,0 +1,341 @@
+package fsm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func (fa *FiniteAutomaton) ToRegex() (string, error) {
+	for _, symbol := range fa.Alphabet {
+		if len([]rune(string(symbol))) != 1 {
+			return "", fmt.Errorf("symbol %q cannot be written in a regular expression: symbols must be single characters", symbol)
+		}
+	}
+
+	live := fa.liveStates()
+	var states []State
+	index := make(map[State]int)
+	queue := []State{fa.InitialState}
+	seen := map[State]bool{fa.InitialState: true}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		if live[state] {
+			index[state] = len(states)
+			states = append(states, state)
+		}
+		for _, symbol := range fa.Alphabet {
+			if next := fa.TransitionFunction(state, symbol); !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	if !live[fa.InitialState] {
+		if len(fa.Alphabet) == 0 {
+			return "", fmt.Errorf("the empty language cannot be written over an empty alphabet")
+		}
+		return "[^" + classBody(fa.Alphabet) + "]", nil
+	}
+
+	start, final := len(states), len(states)+1
+	edges := make(map[[2]int]*regexNode)
+	addEdge := func(from, to int, node *regexNode) {
+		edges[[2]int{from, to}] = unionRegex(edges[[2]int{from, to}], node)
+	}
+
+	addEdge(start, index[fa.InitialState], &regexNode{kind: regexEmpty})
+	for _, state := range states {
+		for _, symbol := range fa.Alphabet {
+			if to, ok := index[fa.TransitionFunction(state, symbol)]; ok {
+				addEdge(index[state], to, &regexNode{kind: regexSet, symbols: []Symbol{symbol}})
+			}
+		}
+		if fa.IsAcceptingState(state) {
+			addEdge(index[state], final, &regexNode{kind: regexEmpty})
+		}
+	}
+
+	remaining := make(map[int]bool, len(states))
+	for i := range states {
+		remaining[i] = true
+	}
+
+	for len(remaining) > 0 {
+		k := cheapestToEliminate(remaining, edges, len(states))
+		delete(remaining, k)
+
+		loop := starRegex(edges[[2]int{k, k}])
+		delete(edges, [2]int{k, k})
+
+		var incoming, outgoing []int
+		for key := range edges {
+			if key[1] == k {
+				incoming = append(incoming, key[0])
+			}
+			if key[0] == k {
+				outgoing = append(outgoing, key[1])
+			}
+		}
+		sort.Ints(incoming)
+		sort.Ints(outgoing)
+
+		for _, from := range incoming {
+			for _, to := range outgoing {
+				addEdge(from, to, concatRegex(edges[[2]int{from, k}], loop, edges[[2]int{k, to}]))
+			}
+		}
+		for _, from := range incoming {
+			delete(edges, [2]int{from, k})
+		}
+		for _, to := range outgoing {
+			delete(edges, [2]int{k, to})
+		}
+	}
+
+	return regexString(edges[[2]int{start, final}]), nil
+}
+
+func cheapestToEliminate(remaining map[int]bool, edges map[[2]int]*regexNode, count int) int {
+	best, bestCost := -1, 0
+	for k := 0; k < count; k++ {
+		if !remaining[k] {
+			continue
+		}
+		in, out := 0, 0
+		for key := range edges {
+			if key[1] == k && key[0] != k {
+				in++
+			}
+			if key[0] == k && key[1] != k {
+				out++
+			}
+		}
+		if best < 0 || in*out < bestCost {
+			best, bestCost = k, in*out
+		}
+	}
+	return best
+}
+
+func unionRegex(a, b *regexNode) *regexNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.kind == regexSet && b.kind == regexSet {
+		return &regexNode{kind: regexSet, symbols: mergeSymbols(a.symbols, b.symbols)}
+	}
+
+	var alternatives []*regexNode
+	hasEmpty := false
+	for _, node := range []*regexNode{a, b} {
+		for _, alternative := range alternativesOf(node) {
+			if alternative.kind == regexEmpty {
+				hasEmpty = true
+				continue
+			}
+			alternatives = appendAlternative(alternatives, alternative)
+		}
+	}
+
+	var result *regexNode
+	switch len(alternatives) {
+	case 0:
+		return &regexNode{kind: regexEmpty}
+	case 1:
+		result = alternatives[0]
+	default:
+		result = &regexNode{kind: regexAlternate, children: alternatives}
+	}
+	if hasEmpty && result.kind == regexRepeat && result.min == 1 && result.max < 0 {
+		return &regexNode{kind: regexRepeat, children: result.children, min: 0, max: -1}
+	}
+	if hasEmpty && !(result.kind == regexRepeat && result.min == 0) {
+		result = &regexNode{kind: regexRepeat, children: []*regexNode{result}, min: 0, max: 1}
+	}
+	return result
+}
+
+func alternativesOf(node *regexNode) []*regexNode {
+	if node.kind == regexAlternate {
+		return node.children
+	}
+	if node.kind == regexRepeat && node.min == 0 && node.max == 1 {
+		return append([]*regexNode{{kind: regexEmpty}}, alternativesOf(node.children[0])...)
+	}
+	return []*regexNode{node}
+}
+
+func appendAlternative(alternatives []*regexNode, node *regexNode) []*regexNode {
+	if node.kind == regexSet {
+		for i, existing := range alternatives {
+			if existing.kind == regexSet {
+				alternatives[i] = &regexNode{kind: regexSet, symbols: mergeSymbols(existing.symbols, node.symbols)}
+				return alternatives
+			}
+		}
+	}
+	for _, existing := range alternatives {
+		if regexString(existing) == regexString(node) {
+			return alternatives
+		}
+	}
+	return append(alternatives, node)
+}
+
+func mergeSymbols(a, b []Symbol) []Symbol {
+	merged := append([]Symbol(nil), a...)
+	for _, symbol := range b {
+		if !SymbolSet(merged).Contains(symbol) {
+			merged = append(merged, symbol)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+	return merged
+}
+
+func concatRegex(parts ...*regexNode) *regexNode {
+	var children []*regexNode
+	for _, part := range parts {
+		if part == nil {
+			return nil
+		}
+		switch part.kind {
+		case regexEmpty:
+			continue
+		case regexConcat:
+			children = append(children, part.children...)
+		default:
+			children = append(children, part)
+		}
+	}
+
+	var folded []*regexNode
+	for _, child := range children {
+		if last := len(folded) - 1; last >= 0 && child.kind == regexRepeat && child.min == 0 && child.max < 0 &&
+			regexString(folded[last]) == regexString(child.children[0]) {
+			folded[last] = &regexNode{kind: regexRepeat, children: child.children, min: 1, max: -1}
+			continue
+		}
+		folded = append(folded, child)
+	}
+
+	switch len(folded) {
+	case 0:
+		return &regexNode{kind: regexEmpty}
+	case 1:
+		return folded[0]
+	}
+	return &regexNode{kind: regexConcat, children: folded}
+}
+
+func starRegex(node *regexNode) *regexNode {
+	if node == nil || node.kind == regexEmpty {
+		return &regexNode{kind: regexEmpty}
+	}
+	if node.kind == regexRepeat && node.max < 0 && node.min <= 1 {
+		return &regexNode{kind: regexRepeat, children: node.children, min: 0, max: -1}
+	}
+	if node.kind == regexRepeat && node.min == 0 && node.max == 1 {
+		node = node.children[0]
+	}
+	return &regexNode{kind: regexRepeat, children: []*regexNode{node}, min: 0, max: -1}
+}
+
+func regexString(node *regexNode) string {
+	switch node.kind {
+	case regexEmpty:
+		return "()"
+	case regexSet:
+		if len(node.symbols) == 1 {
+			return escapeRegexSymbol(node.symbols[0])
+		}
+		return "[" + classBody(node.symbols) + "]"
+	case regexConcat:
+		var sb strings.Builder
+		for _, child := range node.children {
+			if child.kind == regexAlternate {
+				sb.WriteString("(" + regexString(child) + ")")
+			} else {
+				sb.WriteString(regexString(child))
+			}
+		}
+		return sb.String()
+	case regexAlternate:
+		parts := make([]string, len(node.children))
+		for i, child := range node.children {
+			parts[i] = regexString(child)
+		}
+		return strings.Join(parts, "|")
+	}
+
+	child := regexString(node.children[0])
+	if kind := node.children[0].kind; kind == regexConcat || kind == regexAlternate || kind == regexRepeat {
+		child = "(" + child + ")"
+	}
+	switch {
+	case node.min == 0 && node.max < 0:
+		return child + "*"
+	case node.min == 1 && node.max < 0:
+		return child + "+"
+	case node.min == 0 && node.max == 1:
+		return child + "?"
+	}
+	return fmt.Sprintf("%s{%d,%d}", child, node.min, node.max)
+}
+
+func escapeRegexSymbol(symbol Symbol) string {
+	switch symbol {
+	case "\n":
+		return `\n`
+	case "\t":
+		return `\t`
+	case "\r":
+		return `\r`
+	case "\f":
+		return `\f`
+	case "\v":
+		return `\v`
+	}
+	if strings.ContainsAny(string(symbol), `\.[]()*+?{}|`) {
+		return `\` + string(symbol)
+	}
+	return string(symbol)
+}
+
+func classBody(symbols []Symbol) string {
+	runes := make([]rune, 0, len(symbols))
+	for _, symbol := range symbols {
+		runes = append(runes, []rune(string(symbol))[0])
+	}
+	sort.Slice(runes, func(i, j int) bool { return runes[i] < runes[j] })
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 {
+			j++
+		}
+		sb.WriteString(escapeClassRune(runes[i]))
+		if j-i >= 2 {
+			sb.WriteString("-" + escapeClassRune(runes[j]))
+		} else if j > i {
+			sb.WriteString(escapeClassRune(runes[j]))
+		}
+		i = j + 1
+	}
+	return sb.String()
+}
+
+func escapeClassRune(r rune) string {
+	switch r {
+	case ']', '\\', '^', '-':
+		return `\` + string(r)
+	}
+	return escapeRegexSymbol(Symbol(string(r)))
+}
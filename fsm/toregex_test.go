@@ -0,0 +1,80 @@
+package fsm
+
+import (
+	"testing"
+)
+
+func assertRegexRoundTrip(t *testing.T, fa *FiniteAutomaton) string {
+	t.Helper()
+
+	pattern, err := fa.ToRegex()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	nfa, err := CompileRegex(pattern, fa.Alphabet)
+	if err != nil {
+		t.Fatalf("Generated pattern %q does not compile: %v", pattern, err)
+	}
+	if equal, witness, err := Equivalent(fa, nfa.ToDFA()); err != nil || !equal {
+		t.Fatalf("Pattern %q is not equivalent to the automaton (witness %v, err %v)", pattern, witness, err)
+	}
+	return pattern
+}
+
+func TestFiniteAutomaton_ToRegex(t *testing.T) {
+	tests := []struct {
+		description string
+		pattern     string
+		alphabet    []Symbol
+		expected    string
+	}{
+		{"single symbol", "a", []Symbol{"a", "b"}, "a"},
+		{"star", "a*", []Symbol{"a", "b"}, "a*"},
+		{"plus", "ab+", []Symbol{"a", "b"}, "ab+"},
+		{"class", "[abc]", []Symbol{"a", "b", "c"}, "[a-c]"},
+		{"empty string", "", []Symbol{"a"}, "()"},
+		{"metacharacters", `\(\.`, []Symbol{"(", ".", "x"}, `\(\.`},
+		{"empty language", "[^ab]", []Symbol{"a", "b"}, "[^ab]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			nfa, err := CompileRegex(test.pattern, test.alphabet)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if pattern := assertRegexRoundTrip(t, nfa.ToDFA()); pattern != test.expected {
+				t.Errorf("Expected %q, got %q", test.expected, pattern)
+			}
+		})
+	}
+}
+
+func TestFiniteAutomaton_ToRegexRoundTrip(t *testing.T) {
+	assertRegexRoundTrip(t, newDivisibleByThree())
+	assertRegexRoundTrip(t, newEndsWith01NFA().ToDFA())
+
+	for seed := int64(0); seed < 25; seed++ {
+		assertRegexRoundTrip(t, Random(5, []Symbol{"a", "b", "|", "\n"}, 0.7, seed))
+	}
+
+	for _, pattern := range []string{"(ab|ba)*c?", "a{2,3}b*", `[\t ]+x|y`, "((a|b)(a|b))*"} {
+		nfa, err := CompileRegex(pattern, ASCIIAlphabet())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		assertRegexRoundTrip(t, nfa.ToDFA())
+	}
+}
+
+func TestFiniteAutomaton_ToRegexErrors(t *testing.T) {
+	multi := NewTableAutomaton([]State{"A"}, []Symbol{"ab"}, "A", []State{"A"}, TransitionTable{})
+	if _, err := multi.ToRegex(); err == nil {
+		t.Error("Expected error for multi-character symbols, but got none")
+	}
+
+	empty := NewTableAutomaton([]State{"A"}, nil, "A", nil, TransitionTable{})
+	if _, err := empty.ToRegex(); err == nil {
+		t.Error("Expected error for the empty language over an empty alphabet, but got none")
+	}
+}
@@ -0,0 +1,66 @@
+package fsm
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RunResult receives the outcome of RunInto. Trace keeps its capacity from
+// run to run, so a reused RunResult, or one taken from the pool with
+// NewRunResult, records traces without allocating once it is warm.
+type RunResult struct {
+	State    State
+	Accepted bool
+	Trace    []Transition
+}
+
+// maxPooledTrace bounds the buffers Release keeps, so one huge input does
+// not pin its trace in the pool.
+const maxPooledTrace = 1 << 16
+
+var runResultPool = sync.Pool{
+	New: func() any { return new(RunResult) },
+}
+
+// NewRunResult returns an empty RunResult from a shared pool. Call Release
+// when the trace is no longer needed.
+func NewRunResult() *RunResult {
+	return runResultPool.Get().(*RunResult)
+}
+
+func (r *RunResult) Release() {
+	if cap(r.Trace) > maxPooledTrace {
+		return
+	}
+	r.State, r.Accepted, r.Trace = "", false, r.Trace[:0]
+	runResultPool.Put(r)
+}
+
+// RunInto is ProcessInput with a trace. It overwrites result, recording
+// every transition into result.Trace[:0]. On an invalid symbol, State is
+// empty and Trace holds the transitions taken before it.
+func (fa *FiniteAutomaton) RunInto(input string, result *RunResult) error {
+	result.State, result.Accepted, result.Trace = "", false, result.Trace[:0]
+	currentState := fa.InitialState
+	debug := debugEnabled(fa.Logger)
+
+	for i, char := range input {
+		symbol := fa.normalize(runeSymbol(char))
+
+		if !fa.isValidSymbol(symbol) {
+			logInvalidSymbol(fa.Logger, currentState, symbol, i)
+			return fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, fa.Alphabet)
+		}
+
+		transition := Transition{From: currentState, Symbol: symbol, To: fa.TransitionFunction(currentState, symbol)}
+		if debug {
+			logTransition(fa.Logger, transition, i)
+		}
+		result.Trace = append(result.Trace, transition)
+		currentState = transition.To
+	}
+
+	result.State = currentState
+	result.Accepted = fa.IsAcceptingState(currentState)
+	return nil
+}
@@ -0,0 +1,99 @@
+package fsm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRunInto(t *testing.T) {
+	fa := newEndsWith01DFA()
+	var result RunResult
+
+	if err := fa.RunInto("101", &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []Transition{
+		{From: "A", Symbol: "1", To: "A"},
+		{From: "A", Symbol: "0", To: "B"},
+		{From: "B", Symbol: "1", To: "C"},
+	}
+	if result.State != "C" || !result.Accepted || !reflect.DeepEqual(result.Trace, expected) {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	if err := fa.RunInto("0", &result); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.State != "B" || result.Accepted || len(result.Trace) != 1 {
+		t.Errorf("Expected the second run to overwrite the first, got %+v", result)
+	}
+}
+
+func TestRunInto_InvalidSymbol(t *testing.T) {
+	fa := newEndsWith01DFA()
+	result := NewRunResult()
+	defer result.Release()
+
+	err := fa.RunInto("01x", result)
+	_, expected := fa.ProcessInput("01x")
+	if err == nil || err.Error() != expected.Error() {
+		t.Fatalf("Expected error %q, got %v", expected, err)
+	}
+	if result.State != "" || result.Accepted || len(result.Trace) != 2 {
+		t.Errorf("Expected the partial trace and no state, got %+v", result)
+	}
+}
+
+func TestRunInto_ReusesTrace(t *testing.T) {
+	fa := newEndsWith01DFA()
+	result := &RunResult{Trace: make([]Transition, 0, 64)}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		fa.RunInto("0101010101", result)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations with a warm trace, got %.1f", allocs)
+	}
+}
+
+func TestRunResult_Release(t *testing.T) {
+	result := NewRunResult()
+	result.State = "A"
+	result.Accepted = true
+	result.Trace = append(result.Trace, Transition{From: "A", Symbol: "0", To: "B"})
+	result.Release()
+
+	again := NewRunResult()
+	if again.State != "" || again.Accepted || len(again.Trace) != 0 {
+		t.Errorf("Expected a cleared result from the pool, got %+v", again)
+	}
+	again.Release()
+}
+
+func TestRunner_WithHistoryBuffer(t *testing.T) {
+	fa := newEndsWith01DFA()
+	buf := make([]Transition, 0, 8)
+	runner := NewRunner(fa, WithHistoryBuffer(buf))
+
+	runner.Feed("110")
+	if err := runner.Rollback(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	history := runner.HistoryInto(nil)
+	if len(history) != 2 || history[1] != (Transition{From: "A", Symbol: "1", To: "A"}) {
+		t.Errorf("Unexpected history: %v", history)
+	}
+	if &buf[:1][0] != &runner.history[0] {
+		t.Error("Expected the runner to record into the provided buffer")
+	}
+
+	runner.Reset()
+	allocs := testing.AllocsPerRun(100, func() {
+		runner.Reset()
+		runner.Feed("1101")
+		history = runner.HistoryInto(history)
+	})
+	if allocs != 0 {
+		t.Errorf("Expected no allocations when reusing the history buffer, got %.1f", allocs)
+	}
+}
@@ -0,0 +1,203 @@
+package fsm
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Direction int
+
+const (
+	Left  Direction = -1
+	Right Direction = 1
+)
+
+const (
+	LeftEndMarker  Symbol = "⊢"
+	RightEndMarker Symbol = "⊣"
+)
+
+type TwoWayMove struct {
+	To        State
+	Direction Direction
+}
+
+// TwoWayDFA is a deterministic automaton whose head moves left or right over
+// ⊢ input ⊣, starting on ⊢ in InitialState. It accepts by moving right off ⊣
+// in an accepting state. Moving left off ⊢, reaching a missing transition or
+// looping forever rejects.
+type TwoWayDFA struct {
+	States          []State
+	Alphabet        []Symbol
+	InitialState    State
+	AcceptingStates []State
+	Transitions     map[State]map[Symbol]TwoWayMove
+
+	alphabetSet *symbolSet
+}
+
+func NewTwoWayDFA(
+	states []State,
+	alphabet []Symbol,
+	initialState State,
+	acceptingStates []State,
+) *TwoWayDFA {
+	return &TwoWayDFA{
+		States:          states,
+		Alphabet:        alphabet,
+		InitialState:    initialState,
+		AcceptingStates: acceptingStates,
+		Transitions:     make(map[State]map[Symbol]TwoWayMove),
+		alphabetSet:     newSymbolSet(alphabet),
+	}
+}
+
+func (m *TwoWayDFA) AddTransition(from State, symbol Symbol, to State, direction Direction) {
+	if m.Transitions[from] == nil {
+		m.Transitions[from] = make(map[Symbol]TwoWayMove)
+	}
+	m.Transitions[from][symbol] = TwoWayMove{To: to, Direction: direction}
+}
+
+func (m *TwoWayDFA) Accepts(input string) (bool, error) {
+	tape := []Symbol{LeftEndMarker}
+	for i, char := range input {
+		symbol := Symbol(string(char))
+		if !m.isValidSymbol(symbol) {
+			return false, fmt.Errorf("invalid symbol '%s' at position %d: not in alphabet %v", symbol, i, m.Alphabet)
+		}
+		tape = append(tape, symbol)
+	}
+	tape = append(tape, RightEndMarker)
+
+	type configuration struct {
+		state    State
+		position int
+	}
+	seen := make(map[configuration]bool)
+	state, position := m.InitialState, 0
+	for position >= 0 && position < len(tape) {
+		config := configuration{state, position}
+		if seen[config] {
+			return false, nil
+		}
+		seen[config] = true
+
+		move, ok := m.Transitions[state][tape[position]]
+		if !ok {
+			return false, nil
+		}
+		state, position = move.To, position+int(move.Direction)
+	}
+
+	return position == len(tape) && m.isAccepting(state), nil
+}
+
+// ToDFA converts the machine to an equivalent one-way DFA with Shepherdson's
+// construction. After reading ⊢u, the DFA state records where the head first
+// leaves ⊢u to the right when started from the beginning, and for every q,
+// where it leaves when re-entering ⊢u from the right in state q. States are
+// named after that table, in the order start, then m.States, with ⊥ when the
+// head never leaves to the right.
+func (m *TwoWayDFA) ToDFA() *FiniteAutomaton {
+	start := m.entryTable()
+	exits := func(table []State, symbol Symbol) []State {
+		next := make([]State, len(table))
+		next[0] = m.crossCell(table, symbol, table[0])
+		for i, state := range m.States {
+			next[i+1] = m.crossCell(table, symbol, state)
+		}
+		return next
+	}
+
+	names := map[State][]State{}
+	states := []State{m.tableName(start)}
+	names[states[0]] = start
+	var accepting []State
+	transitions := make(TransitionTable)
+
+	for i := 0; i < len(states); i++ {
+		name := states[i]
+		table := names[name]
+		if final := m.crossCell(table, RightEndMarker, table[0]); final != "" && m.isAccepting(final) {
+			accepting = append(accepting, name)
+		}
+
+		for _, symbol := range m.Alphabet {
+			next := exits(table, symbol)
+			nextName := m.tableName(next)
+			if _, seen := names[nextName]; !seen {
+				names[nextName] = next
+				states = append(states, nextName)
+			}
+			transitions.Set(name, symbol, nextName)
+		}
+	}
+
+	return NewTableAutomaton(states, m.Alphabet, states[0], accepting, transitions)
+}
+
+// entryTable is the table for ⊢ alone: the ⊢ cell is entered from the left
+// only at the start, in InitialState.
+func (m *TwoWayDFA) entryTable() []State {
+	none := make([]State, len(m.States)+1)
+	table := make([]State, len(m.States)+1)
+	table[0] = m.crossCell(none, LeftEndMarker, m.InitialState)
+	for i, state := range m.States {
+		table[i+1] = m.crossCell(none, LeftEndMarker, state)
+	}
+	return table
+}
+
+// crossCell runs the head on a cell holding symbol, entered in state, with
+// the prefix to its left summarised by table. It returns the state in which
+// the head leaves the cell to the right, or "" if it never does.
+func (m *TwoWayDFA) crossCell(table []State, symbol Symbol, state State) State {
+	seen := make(map[State]bool)
+	for state != "" && !seen[state] {
+		seen[state] = true
+
+		move, ok := m.Transitions[state][symbol]
+		if !ok {
+			return ""
+		}
+		if move.Direction == Right {
+			return move.To
+		}
+		state = m.reenter(table, move.To)
+	}
+	return ""
+}
+
+func (m *TwoWayDFA) reenter(table []State, state State) State {
+	for i, s := range m.States {
+		if s == state {
+			return table[i+1]
+		}
+	}
+	return ""
+}
+
+func (m *TwoWayDFA) tableName(table []State) State {
+	names := make([]string, len(table))
+	for i, state := range table {
+		names[i] = string(state)
+		if state == "" {
+			names[i] = "⊥"
+		}
+	}
+	return State("(" + strings.Join(names, ",") + ")")
+}
+
+func (m *TwoWayDFA) isAccepting(state State) bool {
+	for _, accepting := range m.AcceptingStates {
+		if state == accepting {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TwoWayDFA) isValidSymbol(symbol Symbol) bool {
+	return containsSymbol(m.alphabetSet, m.Alphabet, symbol)
+}
@@ -0,0 +1,93 @@
+package fsm
+
+import (
+	"strings"
+	"testing"
+)
+
+// newThirdFromEnd scans to ⊣, walks back three cells and checks for a 1.
+func newThirdFromEnd() *TwoWayDFA {
+	m := NewTwoWayDFA(
+		[]State{"Scan", "Back1", "Back2", "Back3", "Accept"},
+		[]Symbol{"0", "1"},
+		"Scan",
+		[]State{"Accept"},
+	)
+	m.AddTransition("Scan", LeftEndMarker, "Scan", Right)
+	m.AddTransition("Scan", "0", "Scan", Right)
+	m.AddTransition("Scan", "1", "Scan", Right)
+	m.AddTransition("Scan", RightEndMarker, "Back1", Left)
+	m.AddTransition("Back1", "0", "Back2", Left)
+	m.AddTransition("Back1", "1", "Back2", Left)
+	m.AddTransition("Back2", "0", "Back3", Left)
+	m.AddTransition("Back2", "1", "Back3", Left)
+	m.AddTransition("Back3", "1", "Accept", Right)
+	m.AddTransition("Accept", "0", "Accept", Right)
+	m.AddTransition("Accept", "1", "Accept", Right)
+	m.AddTransition("Accept", RightEndMarker, "Accept", Right)
+	return m
+}
+
+func thirdFromEndIsOne(input string) bool {
+	return len(input) >= 3 && input[len(input)-3] == '1'
+}
+
+func TestTwoWayDFA_Accepts(t *testing.T) {
+	m := newThirdFromEnd()
+
+	for _, input := range SeedCorpus(m.Alphabet, 8) {
+		accepted, err := m.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != thirdFromEndIsOne(input) {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, thirdFromEndIsOne(input), accepted)
+		}
+	}
+
+	if _, err := m.Accepts("102"); err == nil {
+		t.Error("Expected error for invalid symbol, but got none")
+	}
+}
+
+func TestTwoWayDFA_ToDFA(t *testing.T) {
+	dfa := newThirdFromEnd().ToDFA()
+
+	for _, input := range SeedCorpus(dfa.Alphabet, 8) {
+		accepted, err := dfa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != thirdFromEndIsOne(input) {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, thirdFromEndIsOne(input), accepted)
+		}
+	}
+
+	if !strings.HasPrefix(string(dfa.InitialState), "(Scan,") {
+		t.Errorf("Expected initial state to leave ⊢ in Scan, got %s", dfa.InitialState)
+	}
+}
+
+func TestTwoWayDFA_LoopsReject(t *testing.T) {
+	// Bounces between ⊢ and a leading 0 forever; a leading 1 is accepted.
+	m := NewTwoWayDFA([]State{"Go", "Bounce", "Done"}, []Symbol{"0", "1"}, "Go", []State{"Done"})
+	m.AddTransition("Go", LeftEndMarker, "Bounce", Right)
+	m.AddTransition("Bounce", "0", "Go", Left)
+	m.AddTransition("Bounce", "1", "Done", Right)
+	for _, symbol := range []Symbol{"0", "1", RightEndMarker} {
+		m.AddTransition("Done", symbol, "Done", Right)
+	}
+
+	dfa := m.ToDFA()
+	for _, input := range SeedCorpus(m.Alphabet, 5) {
+		expected := strings.HasPrefix(input, "1")
+		accepted, err := m.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		converted, _ := dfa.Accepts(input)
+		if accepted != expected || converted != expected {
+			t.Errorf("For input '%s': expected accepted=%v, got %v (2DFA) and %v (DFA)", input, expected, accepted, converted)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package fsmdoc
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+const (
+	exampleMaxLen = 6
+	exampleLimit  = 5
+)
+
+type Description struct {
+	Name             string
+	States           []fsm.State
+	Alphabet         []fsm.Symbol
+	InitialState     fsm.State
+	AcceptingStates  []fsm.State
+	Table            [][]fsm.State
+	DOT              string
+	SVG              template.HTML
+	Language         []string
+	AcceptedExamples []string
+	RejectedExamples []string
+}
+
+func Describe(name string, fa *fsm.FiniteAutomaton) *Description {
+	d := &Description{
+		Name:            name,
+		States:          fa.States,
+		Alphabet:        fa.Alphabet,
+		InitialState:    fa.InitialState,
+		AcceptingStates: fa.AcceptingStates,
+		DOT:             fa.DOT(),
+		SVG:             template.HTML(fa.SVG()),
+	}
+
+	for _, state := range fa.States {
+		row := make([]fsm.State, len(fa.Alphabet))
+		for i, symbol := range fa.Alphabet {
+			row[i] = fa.TransitionFunction(state, symbol)
+		}
+		d.Table = append(d.Table, row)
+	}
+
+	d.Language = describeLanguage(fa)
+	d.AcceptedExamples, d.RejectedExamples = fa.Examples(exampleMaxLen, exampleLimit)
+	return d
+}
+
+func describeLanguage(fa *fsm.FiniteAutomaton) []string {
+	if fa.IsEmptyLanguage() {
+		return []string{"The language is empty: no input is accepted."}
+	}
+
+	var facts []string
+	if fa.IsAcceptingState(fa.InitialState) {
+		facts = append(facts, "The empty input is accepted.")
+	} else {
+		facts = append(facts, "The empty input is rejected.")
+	}
+
+	if fa.IsFiniteLanguage() {
+		facts = append(facts, "The language is finite.")
+	} else {
+		facts = append(facts, "The language is infinite.")
+	}
+
+	if shortest, ok := fa.ShortestAccepted(); ok && shortest != "" {
+		facts = append(facts, fmt.Sprintf("The shortest accepted input is `%s`.", shortest))
+	}
+
+	return facts
+}
+
+func (d *Description) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", d.Name))
+	sb.WriteString(fmt.Sprintf("- **States:** %s\n", joinStates(d.States)))
+	sb.WriteString(fmt.Sprintf("- **Alphabet:** %s\n", joinSymbols(d.Alphabet)))
+	sb.WriteString(fmt.Sprintf("- **Initial state:** %s\n", d.InitialState))
+	sb.WriteString(fmt.Sprintf("- **Accepting states:** %s\n\n", joinStates(d.AcceptingStates)))
+
+	sb.WriteString("## Transition Table\n\n")
+	sb.WriteString("| State |")
+	for _, symbol := range d.Alphabet {
+		sb.WriteString(fmt.Sprintf(" `%s` |", symbol))
+	}
+	sb.WriteString("\n|---|")
+	for range d.Alphabet {
+		sb.WriteString("---|")
+	}
+	sb.WriteString("\n")
+	for i, state := range d.States {
+		sb.WriteString(fmt.Sprintf("| %s%s |", state, d.marker(state)))
+		for _, next := range d.Table[i] {
+			sb.WriteString(fmt.Sprintf(" %s |", next))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n→ initial state, * accepting state\n\n")
+
+	sb.WriteString("## Accepted Language\n\n")
+	for _, fact := range d.Language {
+		sb.WriteString(fmt.Sprintf("- %s\n", fact))
+	}
+
+	sb.WriteString("\n## Examples\n\n")
+	sb.WriteString(fmt.Sprintf("- **Accepted:** %s\n", quoteExamples(d.AcceptedExamples)))
+	sb.WriteString(fmt.Sprintf("- **Rejected:** %s\n", quoteExamples(d.RejectedExamples)))
+
+	sb.WriteString("\n## Diagram\n\n```dot\n")
+	sb.WriteString(d.DOT)
+	sb.WriteString("```\n")
+
+	return sb.String()
+}
+
+var htmlTemplate = template.Must(template.New("fsmdoc").Funcs(template.FuncMap{
+	"marker": func(d *Description, state fsm.State) string { return d.marker(state) },
+	"quote":  quoteExamples,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 0.3em 0.8em; text-align: center; }
+pre { background: #f4f4f4; padding: 1em; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+<ul>
+<li><strong>States:</strong> {{range $i, $s := .States}}{{if $i}}, {{end}}{{$s}}{{end}}</li>
+<li><strong>Alphabet:</strong> {{range $i, $s := .Alphabet}}{{if $i}}, {{end}}<code>{{$s}}</code>{{end}}</li>
+<li><strong>Initial state:</strong> {{.InitialState}}</li>
+<li><strong>Accepting states:</strong> {{range $i, $s := .AcceptingStates}}{{if $i}}, {{end}}{{$s}}{{end}}</li>
+</ul>
+<h2>Transition Table</h2>
+<table>
+<tr><th>State</th>{{range .Alphabet}}<th><code>{{.}}</code></th>{{end}}</tr>
+{{- $d := .}}
+{{- range $i, $state := .States}}
+<tr><td>{{$state}}{{marker $d $state}}</td>{{range index $d.Table $i}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+<p>→ initial state, * accepting state</p>
+<h2>Accepted Language</h2>
+<ul>
+{{- range .Language}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+<h2>Examples</h2>
+<ul>
+<li><strong>Accepted:</strong> {{quote .AcceptedExamples}}</li>
+<li><strong>Rejected:</strong> {{quote .RejectedExamples}}</li>
+</ul>
+<h2>Diagram</h2>
+{{.SVG}}
+<details>
+<summary>DOT source</summary>
+<pre>{{.DOT}}</pre>
+</details>
+</body>
+</html>
+`))
+
+func (d *Description) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func (d *Description) marker(state fsm.State) string {
+	marker := ""
+	if state == d.InitialState {
+		marker += " →"
+	}
+	for _, accepting := range d.AcceptingStates {
+		if accepting == state {
+			marker += " *"
+			break
+		}
+	}
+	return marker
+}
+
+func joinStates(states []fsm.State) string {
+	names := make([]string, len(states))
+	for i, state := range states {
+		names[i] = string(state)
+	}
+	return strings.Join(names, ", ")
+}
+
+func joinSymbols(symbols []fsm.Symbol) string {
+	names := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		names[i] = fmt.Sprintf("`%s`", symbol)
+	}
+	return strings.Join(names, ", ")
+}
+
+func quoteExamples(examples []string) string {
+	if len(examples) == 0 {
+		return fmt.Sprintf("none up to length %d", exampleMaxLen)
+	}
+
+	quoted := make([]string, len(examples))
+	for i, example := range examples {
+		if example == "" {
+			quoted[i] = "ε"
+		} else {
+			quoted[i] = example
+		}
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,102 @@
+package fsmdoc
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func newDivisibleByThree() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("S0", "0", "S0")
+	table.Set("S0", "1", "S1")
+	table.Set("S1", "0", "S2")
+	table.Set("S1", "1", "S0")
+	table.Set("S2", "0", "S1")
+	table.Set("S2", "1", "S2")
+
+	return fsm.NewTableAutomaton([]fsm.State{"S0", "S1", "S2"}, []fsm.Symbol{"0", "1"}, "S0", []fsm.State{"S0"}, table)
+}
+
+func TestDescribe(t *testing.T) {
+	d := Describe("Divisible by three", newDivisibleByThree())
+
+	if len(d.Table) != 3 || d.Table[1][0] != "S2" {
+		t.Errorf("Unexpected transition table %v", d.Table)
+	}
+	if strings.Join(d.AcceptedExamples, ",") != ",0,00,11,000" {
+		t.Errorf("Unexpected accepted examples %v", d.AcceptedExamples)
+	}
+	if strings.Join(d.RejectedExamples, ",") != "1,01,10,001,010" {
+		t.Errorf("Unexpected rejected examples %v", d.RejectedExamples)
+	}
+
+	language := strings.Join(d.Language, " ")
+	for _, fact := range []string{"empty input is accepted", "infinite"} {
+		if !strings.Contains(language, fact) {
+			t.Errorf("Language description should mention %q, got %v", fact, d.Language)
+		}
+	}
+}
+
+func TestDescribe_EmptyLanguage(t *testing.T) {
+	fa := newDivisibleByThree()
+	fa.AcceptingStates = nil
+
+	d := Describe("Nothing", fa)
+	if len(d.Language) != 1 || !strings.Contains(d.Language[0], "empty") {
+		t.Errorf("Expected empty language description, got %v", d.Language)
+	}
+	if !strings.Contains(d.Markdown(), "**Accepted:** none up to length 6") {
+		t.Errorf("Expected no accepted examples in report:\n%s", d.Markdown())
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	report := Describe("ModThree", newDivisibleByThree()).Markdown()
+
+	expected := []string{
+		"# ModThree",
+		"| State | `0` | `1` |",
+		"| S0 → * | S0 | S1 |",
+		"| S2 | S1 | S2 |",
+		"## Accepted Language",
+		"- **Accepted:** ε, 0, 00, 11, 000",
+		"```dot\ndigraph FiniteAutomaton {",
+	}
+	for _, line := range expected {
+		if !strings.Contains(report, line) {
+			t.Errorf("Markdown report should contain %q:\n%s", line, report)
+		}
+	}
+}
+
+func TestHTML(t *testing.T) {
+	fa := newDivisibleByThree()
+	fa.States = append(fa.States, "<script>")
+
+	report, err := Describe("ModThree & friends", fa).HTML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"<title>ModThree &amp; friends</title>",
+		"<tr><td>S0 → *</td><td>S0</td><td>S1</td></tr>",
+		"&lt;script&gt;",
+		"<h2>Diagram</h2>",
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+	}
+	for _, fragment := range expected {
+		if !strings.Contains(report, fragment) {
+			t.Errorf("HTML report should contain %q:\n%s", fragment, report)
+		}
+	}
+	if !strings.Contains(report, ">&lt;script&gt;</text>") {
+		t.Error("Embedded SVG must escape state names")
+	}
+	if strings.Contains(report, "<script>") {
+		t.Error("HTML report must escape state names")
+	}
+}
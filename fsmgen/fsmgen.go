@@ -0,0 +1,177 @@
+package fsmgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Options struct {
+	Package string
+	Name    string
+}
+
+type caseData struct {
+	Symbol string
+	To     string
+}
+
+type stateData struct {
+	State   string
+	Cases   []caseData
+	Default string
+}
+
+type templateData struct {
+	Package   string
+	Name      string
+	Initial   string
+	Accepting []string
+	Alphabet  []string
+	States    []stateData
+	Defaults  bool
+}
+
+var sourceTemplate = template.Must(template.New("fsmgen").Parse(`// Code generated by fsmgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import "fmt"
+
+type {{.Name}}State string
+
+const {{.Name}}InitialState {{.Name}}State = {{.Initial}}
+
+func {{.Name}}Transition(state {{.Name}}State, symbol string) ({{.Name}}State, bool) {
+	switch state {
+{{- range .States}}
+	case {{.State}}:
+		switch symbol {
+{{- range .Cases}}
+		case {{.Symbol}}:
+			return {{.To}}, true
+{{- end}}
+{{- if .Default}}
+		default:
+			if {{$.Name}}InAlphabet(symbol) {
+				return {{.Default}}, true
+			}
+{{- end}}
+		}
+{{- end}}
+	}
+	return state, false
+}
+
+{{if .Defaults -}}
+func {{.Name}}InAlphabet(symbol string) bool {
+	switch symbol {
+	case {{range $i, $symbol := .Alphabet}}{{if $i}}, {{end}}{{$symbol}}{{end}}:
+		return true
+	}
+	return false
+}
+
+{{end -}}
+func {{.Name}}IsAccepting(state {{.Name}}State) bool {
+{{- if .Accepting}}
+	switch state {
+	case {{range $i, $state := .Accepting}}{{if $i}}, {{end}}{{$state}}{{end}}:
+		return true
+	}
+{{- end}}
+	return false
+}
+
+func {{.Name}}Run(input string) ({{.Name}}State, error) {
+	state := {{.Name}}InitialState
+	for i, char := range input {
+		next, ok := {{.Name}}Transition(state, string(char))
+		if !ok {
+			return state, fmt.Errorf("invalid symbol '%c' at position %d in state %s", char, i, state)
+		}
+		state = next
+	}
+	return state, nil
+}
+`))
+
+func Generate(definition *fsm.Definition, options Options) ([]byte, error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+
+	name := options.Name
+	if name == "" {
+		name = definition.Name
+	}
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return nil, fmt.Errorf("name %q must be an exported Go identifier", name)
+	}
+	if !token.IsIdentifier(options.Package) {
+		return nil, fmt.Errorf("package %q must be a Go identifier", options.Package)
+	}
+
+	data := templateData{
+		Package: options.Package,
+		Name:    name,
+		Initial: strconv.Quote(string(definition.InitialState)),
+	}
+
+	for _, state := range definition.AcceptingStates {
+		data.Accepting = append(data.Accepting, strconv.Quote(string(state)))
+	}
+
+	cases := make(map[fsm.State][]caseData)
+	defaults := make(map[fsm.State]string)
+	for _, transition := range definition.Transitions {
+		if transition.Default {
+			defaults[transition.From] = strconv.Quote(string(transition.To))
+			data.Defaults = true
+			continue
+		}
+
+		labels, err := definition.Labels(transition)
+		if err != nil {
+			return nil, err
+		}
+		quoted := make([]string, len(labels))
+		for i, symbol := range labels {
+			quoted[i] = strconv.Quote(string(symbol))
+		}
+		cases[transition.From] = append(cases[transition.From], caseData{
+			Symbol: strings.Join(quoted, ", "),
+			To:     strconv.Quote(string(transition.To)),
+		})
+	}
+	for _, state := range definition.States {
+		if len(cases[state]) == 0 && defaults[state] == "" {
+			continue
+		}
+		data.States = append(data.States, stateData{
+			State:   strconv.Quote(string(state)),
+			Cases:   cases[state],
+			Default: defaults[state],
+		})
+	}
+	for _, symbol := range definition.Alphabet {
+		data.Alphabet = append(data.Alphabet, strconv.Quote(string(symbol)))
+	}
+
+	var buf bytes.Buffer
+	if err := sourceTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render source: %w", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated source: %w", err)
+	}
+	return source, nil
+}
@@ -0,0 +1,105 @@
+package fsmgen
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestGenerate_Golden(t *testing.T) {
+	definition, err := fsm.LoadDefinition("testdata/modthree.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	source, err := Generate(definition, Options{Package: "gen"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	golden, err := os.ReadFile("testdata/modthree.go.golden")
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if string(source) != string(golden) {
+		t.Errorf("Generated source does not match golden file:\n%s", source)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", source, 0); err != nil {
+		t.Errorf("Generated source does not parse: %v", err)
+	}
+}
+
+func TestGenerate_NameOverrideAndQuoting(t *testing.T) {
+	definition := &fsm.Definition{
+		Name:         "ignored",
+		States:       []fsm.State{`say "hi"`, "Done"},
+		Alphabet:     []fsm.Symbol{`\`, "x"},
+		InitialState: `say "hi"`,
+		Transitions: []fsm.TransitionDefinition{
+			{From: `say "hi"`, Symbol: `\`, To: "Done"},
+		},
+	}
+
+	source, err := Generate(definition, Options{Package: "quoted", Name: "Greeter"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"type GreeterState string", `case "say \"hi\"":`, `case "\\":`, "return false"} {
+		if !strings.Contains(string(source), expected) {
+			t.Errorf("Generated source should contain %q:\n%s", expected, source)
+		}
+	}
+}
+
+func TestGenerate_DefaultTransitions(t *testing.T) {
+	definition := &fsm.Definition{
+		Name:         "Vowels",
+		States:       []fsm.State{"Other", "Vowel"},
+		Alphabet:     []fsm.Symbol{"a", "e", "x", "y"},
+		InitialState: "Other",
+		Transitions: []fsm.TransitionDefinition{
+			{From: "Other", Default: true, To: "Other"},
+			{From: "Other", Class: "[ae]", To: "Vowel"},
+			{From: "Vowel", Default: true, To: "Other"},
+		},
+	}
+
+	source, err := Generate(definition, Options{Package: "vowels"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"func VowelsInAlphabet(symbol string) bool", `case "a", "e", "x", "y":`, `case "a", "e":`, "default:\n\t\t\tif VowelsInAlphabet(symbol) {"} {
+		if !strings.Contains(string(source), expected) {
+			t.Errorf("Generated source should contain %q:\n%s", expected, source)
+		}
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "gen.go", source, 0); err != nil {
+		t.Errorf("Generated source does not parse: %v", err)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	definition, err := fsm.LoadDefinition("testdata/modthree.json")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := Generate(definition, Options{Package: "gen", Name: "lowercase"}); err == nil {
+		t.Error("Expected error for unexported name, but got none")
+	}
+	if _, err := Generate(definition, Options{Package: "not a package", Name: "ModThree"}); err == nil {
+		t.Error("Expected error for invalid package name, but got none")
+	}
+
+	definition.InitialState = "missing"
+	if _, err := Generate(definition, Options{Package: "gen"}); err == nil {
+		t.Error("Expected error for invalid definition, but got none")
+	}
+}
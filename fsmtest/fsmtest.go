@@ -0,0 +1,74 @@
+package fsmtest
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func AssertAccepts(t testing.TB, fa *fsm.FiniteAutomaton, inputs ...string) {
+	t.Helper()
+
+	for _, input := range inputs {
+		accepted, err := fa.Accepts(input)
+		if err != nil {
+			t.Errorf("Expected input '%s' to be accepted, but got error: %v", input, err)
+			continue
+		}
+		if !accepted {
+			t.Errorf("Expected input '%s' to be accepted, but it was rejected", input)
+		}
+	}
+}
+
+func AssertRejects(t testing.TB, fa *fsm.FiniteAutomaton, inputs ...string) {
+	t.Helper()
+
+	for _, input := range inputs {
+		accepted, err := fa.Accepts(input)
+		if err == nil && accepted {
+			t.Errorf("Expected input '%s' to be rejected, but it was accepted", input)
+		}
+	}
+}
+
+func AssertFinalState(t testing.TB, fa *fsm.FiniteAutomaton, input string, expected fsm.State) {
+	t.Helper()
+
+	finalState, err := fa.ProcessInput(input)
+	if err != nil {
+		t.Errorf("Unexpected error for input '%s': %v", input, err)
+		return
+	}
+	if finalState != expected {
+		t.Errorf("For input '%s': expected final state %s, got %s", input, expected, finalState)
+	}
+}
+
+func AssertEquivalent(t testing.TB, a, b *fsm.FiniteAutomaton) {
+	t.Helper()
+
+	equivalent, counterexample, err := fsm.Equivalent(a, b)
+	if err != nil {
+		t.Errorf("Cannot compare automata: %v", err)
+		return
+	}
+	if !equivalent {
+		t.Errorf("Expected automata to be equivalent, but they disagree on input '%s'", joinSymbols(counterexample))
+	}
+}
+
+func AssertGoldenDOT(t testing.TB, fa *fsm.FiniteAutomaton, path string) {
+	t.Helper()
+
+	AssertGoldenText(t, fa.DOT(), path)
+}
+
+func joinSymbols(symbols []fsm.Symbol) string {
+	var sb strings.Builder
+	for _, symbol := range symbols {
+		sb.WriteString(string(symbol))
+	}
+	return sb.String()
+}
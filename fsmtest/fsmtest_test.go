@@ -0,0 +1,108 @@
+package fsmtest
+
+import (
+	"fmt"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func newEvenOnes() *fsm.FiniteAutomaton {
+	return fsm.NewFiniteAutomaton(
+		[]fsm.State{"Even", "Odd"},
+		[]fsm.Symbol{"0", "1"},
+		"Even",
+		[]fsm.State{"Even"},
+		func(currentState fsm.State, symbol fsm.Symbol) fsm.State {
+			if symbol == "0" {
+				return currentState
+			}
+			if currentState == "Even" {
+				return "Odd"
+			}
+			return "Even"
+		},
+	)
+}
+
+func TestAssertAccepts(t *testing.T) {
+	fa := newEvenOnes()
+
+	AssertAccepts(t, fa, "", "0", "11", "1010")
+
+	r := &recorder{TB: t}
+	AssertAccepts(r, fa, "1", "11", "a")
+	if len(r.failures) != 2 {
+		t.Errorf("Expected 2 failures, got %d: %v", len(r.failures), r.failures)
+	}
+}
+
+func TestAssertRejects(t *testing.T) {
+	fa := newEvenOnes()
+
+	AssertRejects(t, fa, "1", "010", "2")
+
+	r := &recorder{TB: t}
+	AssertRejects(r, fa, "11", "1")
+	if len(r.failures) != 1 {
+		t.Errorf("Expected 1 failure, got %d: %v", len(r.failures), r.failures)
+	}
+}
+
+func TestAssertFinalState(t *testing.T) {
+	fa := newEvenOnes()
+
+	AssertFinalState(t, fa, "111", "Odd")
+
+	r := &recorder{TB: t}
+	AssertFinalState(r, fa, "111", "Even")
+	AssertFinalState(r, fa, "x", "Even")
+	if len(r.failures) != 2 {
+		t.Errorf("Expected 2 failures, got %d: %v", len(r.failures), r.failures)
+	}
+}
+
+func TestAssertEquivalent(t *testing.T) {
+	a := newEvenOnes()
+	b := newEvenOnes()
+
+	AssertEquivalent(t, a, b)
+
+	b.AcceptingStates = []fsm.State{"Odd"}
+	r := &recorder{TB: t}
+	AssertEquivalent(r, a, b)
+	if len(r.failures) != 1 {
+		t.Fatalf("Expected 1 failure, got %d: %v", len(r.failures), r.failures)
+	}
+}
+
+func TestAssertGoldenDOT(t *testing.T) {
+	compareOnly(t)
+	fa := newEvenOnes()
+
+	AssertGoldenDOT(t, fa, "testdata/even_ones.dot")
+
+	r := &recorder{TB: t}
+	AssertGoldenDOT(r, fa, "testdata/missing.dot")
+	if len(r.failures) != 1 {
+		t.Errorf("Expected 1 failure for missing golden file, got %d", len(r.failures))
+	}
+
+	fa.AcceptingStates = []fsm.State{"Odd"}
+	r = &recorder{TB: t}
+	AssertGoldenDOT(r, fa, "testdata/even_ones.dot")
+	if len(r.failures) != 1 {
+		t.Errorf("Expected 1 failure for mismatched DOT output, got %d", len(r.failures))
+	}
+}
@@ -0,0 +1,104 @@
+package fsmtest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"fsm-modulo-three/fsm"
+)
+
+// UpdateEnv turns on golden file updates for a run: FSMTEST_UPDATE=1 go test ./...
+const UpdateEnv = "FSMTEST_UPDATE"
+
+// Update rewrites golden files instead of comparing against them. Setting
+// UpdateEnv, or a boolean -update flag that the test binary defines itself,
+// does the same. fsmtest registers no flags, so importing it never clashes
+// with a package's own -update.
+var Update bool
+
+func updating() bool {
+	if Update {
+		return true
+	}
+	if enabled, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && enabled {
+		return true
+	}
+	if f := flag.Lookup("update"); f != nil {
+		enabled, err := strconv.ParseBool(f.Value.String())
+		return err == nil && enabled
+	}
+	return false
+}
+
+// Snapshot is a textual dump of fa for golden files: the transition table of
+// its canonical form followed by the canonical DOT diagram. Renaming states
+// does not change it, so only structural changes show up in diffs.
+func Snapshot(fa *fsm.FiniteAutomaton) string {
+	canonical := fa.Canonicalize()
+
+	var sb strings.Builder
+	sb.WriteString("# table\n")
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "state")
+	for _, symbol := range canonical.Alphabet {
+		fmt.Fprintf(tw, "\t%s", symbol)
+	}
+	fmt.Fprintln(tw)
+	for _, state := range canonical.States {
+		name := string(state)
+		if state == canonical.InitialState {
+			name = "-> " + name
+		}
+		if canonical.IsAcceptingState(state) {
+			name += " *"
+		}
+		fmt.Fprint(tw, name)
+		for _, symbol := range canonical.Alphabet {
+			fmt.Fprintf(tw, "\t%s", canonical.TransitionFunction(state, symbol))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+
+	sb.WriteString("\n# dot\n")
+	sb.WriteString(canonical.DOT())
+	return sb.String()
+}
+
+// AssertGolden compares Snapshot(fa) with the golden file at path, or
+// rewrites the file when updating is on (see Update).
+func AssertGolden(t testing.TB, fa *fsm.FiniteAutomaton, path string) {
+	t.Helper()
+
+	AssertGoldenText(t, Snapshot(fa), path)
+}
+
+func AssertGoldenText(t testing.TB, actual, path string) {
+	t.Helper()
+
+	if updating() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Errorf("Failed to create directory for golden file %s: %v", path, err)
+			return
+		}
+		if err := os.WriteFile(path, []byte(actual), 0o644); err != nil {
+			t.Errorf("Failed to update golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("Failed to read golden file %s (rerun with FSMTEST_UPDATE=1 to create it): %v", path, err)
+		return
+	}
+	if actual != string(golden) {
+		t.Errorf("Output does not match golden file %s (rerun with FSMTEST_UPDATE=1 to accept)\n--- expected ---\n%s--- actual ---\n%s", path, golden, actual)
+	}
+}
@@ -0,0 +1,111 @@
+package fsmtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestSnapshot(t *testing.T) {
+	snapshot := Snapshot(newEvenOnes())
+
+	expected := "# table\n" +
+		"state    0   1\n" +
+		"-> Q0 *  Q0  Q1\n" +
+		"Q1       Q1  Q0\n"
+	if !strings.HasPrefix(snapshot, expected) {
+		t.Errorf("Expected snapshot to start with\n%sgot\n%s", expected, snapshot)
+	}
+	if !strings.Contains(snapshot, "\n# dot\ndigraph FiniteAutomaton {\n") {
+		t.Errorf("Expected snapshot to contain the DOT diagram, got\n%s", snapshot)
+	}
+
+	renamed := fsm.TransitionTable{}
+	renamed.Set("A", "0", "A")
+	renamed.Set("A", "1", "B")
+	renamed.Set("B", "0", "B")
+	renamed.Set("B", "1", "A")
+	fa := fsm.NewTableAutomaton([]fsm.State{"B", "A"}, []fsm.Symbol{"0", "1"}, "A", []fsm.State{"A"}, renamed)
+	if Snapshot(fa) != snapshot {
+		t.Error("Expected renaming and reordering states not to change the snapshot")
+	}
+}
+
+// update is this package's own -update flag. Defining it would panic if
+// importing fsmtest registered one too.
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// compareOnly keeps an update run from rewriting golden files in tests that
+// also check mismatches against them.
+func compareOnly(t *testing.T) {
+	t.Setenv(UpdateEnv, "")
+	saved, savedFlag := Update, *update
+	Update, *update = false, false
+	t.Cleanup(func() { Update, *update = saved, savedFlag })
+}
+
+func TestAssertGolden(t *testing.T) {
+	compareOnly(t)
+	fa := newEvenOnes()
+
+	AssertGolden(t, fa, "testdata/even_ones.golden")
+
+	fa.AcceptingStates = []fsm.State{"Odd"}
+	r := &recorder{TB: t}
+	AssertGolden(r, fa, "testdata/even_ones.golden")
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], UpdateEnv) {
+		t.Errorf("Expected 1 failure mentioning %s, got %v", UpdateEnv, r.failures)
+	}
+}
+
+func TestAssertGolden_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "machine.golden")
+
+	compareOnly(t)
+	Update = true
+
+	r := &recorder{TB: t}
+	AssertGolden(r, newEvenOnes(), path)
+	if len(r.failures) != 0 {
+		t.Fatalf("Unexpected failures: %v", r.failures)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected golden file to be written: %v", err)
+	}
+	if string(written) != Snapshot(newEvenOnes()) {
+		t.Errorf("Expected the snapshot to be written, got\n%s", written)
+	}
+}
+
+func TestUpdating(t *testing.T) {
+	compareOnly(t)
+	if updating() {
+		t.Fatal("Expected updating to be off by default")
+	}
+
+	t.Setenv(UpdateEnv, "1")
+	if !updating() {
+		t.Errorf("Expected %s=1 to turn updating on", UpdateEnv)
+	}
+	t.Setenv(UpdateEnv, "")
+
+	*update = true
+	if !updating() {
+		t.Error("Expected the test binary's own -update flag to turn updating on")
+	}
+}
+
+func TestAssertGolden_SubsetConstruction(t *testing.T) {
+	nfa, err := fsm.CompileRegex("(0|1)*1(0|1)", []fsm.Symbol{"0", "1"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	AssertGolden(t, nfa.ToDFA(), "testdata/second_last_one.golden")
+}
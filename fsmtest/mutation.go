@@ -0,0 +1,168 @@
+package fsmtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Mutant struct {
+	Description string
+	Automaton   *fsm.FiniteAutomaton
+}
+
+// Mutants returns every single-edit variant of fa: each transition redirected
+// to each other state, and each state's accepting flag toggled.
+func Mutants(fa *fsm.FiniteAutomaton) []Mutant {
+	table := tableOf(fa)
+	mutant := func(description string, accepting []fsm.State, mutated fsm.TransitionTable) Mutant {
+		automaton := fsm.NewTableAutomaton(fa.States, fa.Alphabet, fa.InitialState, accepting, mutated)
+		automaton.Normalizer = fa.Normalizer
+		return Mutant{Description: description, Automaton: automaton}
+	}
+
+	var mutants []Mutant
+	for _, state := range fa.States {
+		for _, symbol := range fa.Alphabet {
+			for _, target := range fa.States {
+				if target == table[state][symbol] {
+					continue
+				}
+				mutated := make(fsm.TransitionTable, len(table))
+				for from, row := range table {
+					for s, to := range row {
+						mutated.Set(from, s, to)
+					}
+				}
+				mutated.Set(state, symbol, target)
+				description := fmt.Sprintf("redirect %s --%s--> %s to %s", state, symbol, table[state][symbol], target)
+				mutants = append(mutants, mutant(description, fa.AcceptingStates, mutated))
+			}
+		}
+	}
+
+	for _, toggled := range fa.States {
+		var accepting []fsm.State
+		for _, state := range fa.States {
+			if fa.IsAcceptingState(state) != (state == toggled) {
+				accepting = append(accepting, state)
+			}
+		}
+		description := fmt.Sprintf("make %s accepting", toggled)
+		if fa.IsAcceptingState(toggled) {
+			description = fmt.Sprintf("make %s rejecting", toggled)
+		}
+		mutants = append(mutants, mutant(description, accepting, table))
+	}
+
+	return mutants
+}
+
+// MutationReport counts how many mutants a suite killed. Mutants that accept
+// the same language as the original cannot be killed by any suite and are
+// counted separately.
+type MutationReport struct {
+	Total      int
+	Killed     int
+	Equivalent int
+	Survivors  []Mutant
+}
+
+// Score is the fraction of killable mutants that were killed.
+func (r MutationReport) Score() float64 {
+	killable := r.Total - r.Equivalent
+	if killable == 0 {
+		return 1
+	}
+	return float64(r.Killed) / float64(killable)
+}
+
+func (r MutationReport) String() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Mutation score: %d/%d killed (%.1f%%), %d equivalent\n", r.Killed, r.Total-r.Equivalent, 100*r.Score(), r.Equivalent))
+	for _, survivor := range r.Survivors {
+		sb.WriteString(fmt.Sprintf("  survived: %s\n", survivor.Description))
+	}
+	return sb.String()
+}
+
+// Mutate runs suite against every mutant of fa. A mutant is killed when the
+// suite reports a failure on it through the testing.TB it is given; failures
+// are recorded, not reported to t.
+func Mutate(t testing.TB, fa *fsm.FiniteAutomaton, suite func(t testing.TB, fa *fsm.FiniteAutomaton)) MutationReport {
+	t.Helper()
+
+	return mutate(fa, func(mutant *fsm.FiniteAutomaton) bool {
+		recorder := &mutantT{TB: t}
+		recorder.run(func() { suite(recorder, mutant) })
+		return recorder.failed
+	})
+}
+
+// MutateExamples uses example inputs as the suite: a mutant is killed when it
+// rejects one of accepted, accepts one of rejected, or errors on either.
+func MutateExamples(fa *fsm.FiniteAutomaton, accepted, rejected []string) MutationReport {
+	return mutate(fa, func(mutant *fsm.FiniteAutomaton) bool {
+		for _, input := range accepted {
+			if ok, err := mutant.Accepts(input); err != nil || !ok {
+				return true
+			}
+		}
+		for _, input := range rejected {
+			if ok, err := mutant.Accepts(input); err != nil || ok {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func mutate(fa *fsm.FiniteAutomaton, kills func(*fsm.FiniteAutomaton) bool) MutationReport {
+	var report MutationReport
+	for _, mutant := range Mutants(fa) {
+		report.Total++
+		if equivalent, _, err := fsm.Equivalent(fa, mutant.Automaton); err == nil && equivalent {
+			report.Equivalent++
+			continue
+		}
+		if kills(mutant.Automaton) {
+			report.Killed++
+		} else {
+			report.Survivors = append(report.Survivors, mutant)
+		}
+	}
+	return report
+}
+
+// mutantT records failures of a suite run against a mutant. FailNow stops the
+// suite with a panic that run recovers.
+type mutantT struct {
+	testing.TB
+	failed bool
+}
+
+type stopMutant struct{}
+
+func (m *mutantT) run(suite func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(stopMutant); !ok {
+				panic(r)
+			}
+		}
+	}()
+	suite()
+}
+
+func (m *mutantT) Helper()                                   {}
+func (m *mutantT) Log(args ...interface{})                   {}
+func (m *mutantT) Logf(format string, args ...interface{})   {}
+func (m *mutantT) Fail()                                     { m.failed = true }
+func (m *mutantT) Failed() bool                              { return m.failed }
+func (m *mutantT) Error(args ...interface{})                 { m.Fail() }
+func (m *mutantT) Errorf(format string, args ...interface{}) { m.Fail() }
+func (m *mutantT) FailNow()                                  { m.Fail(); panic(stopMutant{}) }
+func (m *mutantT) Fatal(args ...interface{})                 { m.FailNow() }
+func (m *mutantT) Fatalf(format string, args ...interface{}) { m.FailNow() }
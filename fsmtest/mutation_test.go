@@ -0,0 +1,78 @@
+package fsmtest
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestMutants(t *testing.T) {
+	mutants := Mutants(newEvenOnes())
+
+	// 2 states x 2 symbols x 1 other target, plus 2 accepting toggles.
+	if len(mutants) != 6 {
+		t.Fatalf("Expected 6 mutants, got %d", len(mutants))
+	}
+	if mutants[0].Description != "redirect Even --0--> Even to Odd" {
+		t.Errorf("Unexpected first mutant %q", mutants[0].Description)
+	}
+	if last := mutants[5]; last.Description != "make Odd accepting" || !last.Automaton.IsAcceptingState("Odd") {
+		t.Errorf("Unexpected last mutant %q", last.Description)
+	}
+}
+
+func TestMutateExamples(t *testing.T) {
+	fa := newEvenOnes()
+
+	weak := MutateExamples(fa, []string{""}, []string{"1"})
+	if weak.Killed == weak.Total || len(weak.Survivors) == 0 {
+		t.Errorf("Expected a weak example set to leave survivors, got %+v", weak)
+	}
+
+	strong := MutateExamples(fa, []string{"", "0", "11", "1010"}, []string{"1", "01", "10", "0111"})
+	if strong.Score() != 1 || len(strong.Survivors) != 0 {
+		t.Errorf("Expected every mutant killed, got:\n%s", strong)
+	}
+}
+
+func TestMutate_Suite(t *testing.T) {
+	fa := newEvenOnes()
+
+	report := Mutate(t, fa, func(t testing.TB, fa *fsm.FiniteAutomaton) {
+		AssertAccepts(t, fa, "11")
+		accepted, err := fa.Accepts("0")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !accepted {
+			t.Fatalf("Expected '0' to be accepted")
+		}
+		AssertRejects(t, fa, "1")
+	})
+
+	if report.Total != 6 || report.Killed+report.Equivalent+len(report.Survivors) != report.Total {
+		t.Errorf("Inconsistent report %+v", report)
+	}
+	if report.Killed == 0 {
+		t.Error("Expected the suite to kill some mutants")
+	}
+	if !strings.Contains(report.String(), "Mutation score:") {
+		t.Errorf("Unexpected report %q", report.String())
+	}
+}
+
+func TestMutate_EquivalentMutants(t *testing.T) {
+	table := fsm.TransitionTable{}
+	table.Set("A", "0", "A")
+	table.Set("Unused", "0", "A")
+	fa := fsm.NewTableAutomaton([]fsm.State{"A", "Unused"}, []fsm.Symbol{"0"}, "A", []fsm.State{"A"}, table)
+
+	report := MutateExamples(fa, []string{"", "0", "00"}, nil)
+	if report.Equivalent != 2 {
+		t.Errorf("Expected the 2 mutants touching the unreachable state to be equivalent, got %+v", report)
+	}
+	if report.Score() != 1 {
+		t.Errorf("Expected all killable mutants killed, got %s", report)
+	}
+}
@@ -0,0 +1,175 @@
+package fsmtest
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Generator interface {
+	Value(r *rand.Rand) reflect.Value
+}
+
+type StringGenerator struct {
+	Alphabet []fsm.Symbol
+	MinLen   int
+	MaxLen   int
+}
+
+func (g StringGenerator) Generate(r *rand.Rand) string {
+	length := g.MinLen
+	if g.MaxLen > g.MinLen {
+		length += r.Intn(g.MaxLen - g.MinLen + 1)
+	}
+
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		sb.WriteString(string(g.Alphabet[r.Intn(len(g.Alphabet))]))
+	}
+	return sb.String()
+}
+
+func (g StringGenerator) Value(r *rand.Rand) reflect.Value {
+	return reflect.ValueOf(g.Generate(r))
+}
+
+type AutomatonGenerator struct {
+	Alphabet  []fsm.Symbol
+	MinStates int
+	MaxStates int
+}
+
+func (g AutomatonGenerator) Generate(r *rand.Rand) *fsm.FiniteAutomaton {
+	count := g.MinStates
+	if count < 1 {
+		count = 1
+	}
+	if g.MaxStates > count {
+		count += r.Intn(g.MaxStates - count + 1)
+	}
+
+	return fsm.Random(count, g.Alphabet, 1, r.Int63())
+}
+
+func (g AutomatonGenerator) Value(r *rand.Rand) reflect.Value {
+	return reflect.ValueOf(g.Generate(r))
+}
+
+func Values(generators ...Generator) func([]reflect.Value, *rand.Rand) {
+	return func(values []reflect.Value, r *rand.Rand) {
+		for i, generator := range generators {
+			values[i] = generator.Value(r)
+		}
+	}
+}
+
+type BinaryString string
+
+func (BinaryString) Generate(r *rand.Rand, size int) reflect.Value {
+	generator := StringGenerator{Alphabet: []fsm.Symbol{"0", "1"}, MinLen: 1, MaxLen: size}
+	return reflect.ValueOf(BinaryString(generator.Generate(r)))
+}
+
+func ShrinkString(input string) []string {
+	if input == "" {
+		return nil
+	}
+
+	var candidates []string
+	if len(input) > 1 {
+		half := len(input) / 2
+		candidates = append(candidates, input[:half], input[half:])
+	}
+	for i := 0; i < len(input); i++ {
+		candidates = append(candidates, input[:i]+input[i+1:])
+	}
+	return candidates
+}
+
+func ShrinkAutomaton(fa *fsm.FiniteAutomaton) []*fsm.FiniteAutomaton {
+	table := tableOf(fa)
+
+	var candidates []*fsm.FiniteAutomaton
+	for _, removed := range fa.States {
+		if removed == fa.InitialState {
+			continue
+		}
+
+		var states, accepting []fsm.State
+		shrunk := make(fsm.TransitionTable)
+		for _, state := range fa.States {
+			if state == removed {
+				continue
+			}
+			states = append(states, state)
+			if fa.IsAcceptingState(state) {
+				accepting = append(accepting, state)
+			}
+			for _, symbol := range fa.Alphabet {
+				next := table[state][symbol]
+				if next == removed {
+					next = fa.InitialState
+				}
+				shrunk.Set(state, symbol, next)
+			}
+		}
+		candidates = append(candidates, fsm.NewTableAutomaton(states, fa.Alphabet, fa.InitialState, accepting, shrunk))
+	}
+
+	for _, dropped := range fa.AcceptingStates {
+		var accepting []fsm.State
+		for _, state := range fa.AcceptingStates {
+			if state != dropped {
+				accepting = append(accepting, state)
+			}
+		}
+		candidates = append(candidates, fsm.NewTableAutomaton(fa.States, fa.Alphabet, fa.InitialState, accepting, table))
+	}
+
+	return candidates
+}
+
+func MinimizeFailingString(input string, property func(string) bool) string {
+	for {
+		shrunk := false
+		for _, candidate := range ShrinkString(input) {
+			if !property(candidate) {
+				input = candidate
+				shrunk = true
+				break
+			}
+		}
+		if !shrunk {
+			return input
+		}
+	}
+}
+
+func CheckStrings(t testing.TB, generator StringGenerator, iterations int, seed int64, property func(string) bool) {
+	t.Helper()
+
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < iterations; i++ {
+		input := generator.Generate(r)
+		if property(input) {
+			continue
+		}
+
+		minimal := MinimizeFailingString(input, property)
+		t.Errorf("Property failed after %d iterations (seed %d) on input '%s', shrunk to '%s'", i+1, seed, input, minimal)
+		return
+	}
+}
+
+func tableOf(fa *fsm.FiniteAutomaton) fsm.TransitionTable {
+	table := make(fsm.TransitionTable, len(fa.States))
+	for _, state := range fa.States {
+		for _, symbol := range fa.Alphabet {
+			table.Set(state, symbol, fa.TransitionFunction(state, symbol))
+		}
+	}
+	return table
+}
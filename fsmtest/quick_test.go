@@ -0,0 +1,121 @@
+package fsmtest
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestStringGenerator(t *testing.T) {
+	generator := StringGenerator{Alphabet: []fsm.Symbol{"a", "b"}, MinLen: 2, MaxLen: 5}
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		input := generator.Generate(r)
+		if len(input) < 2 || len(input) > 5 {
+			t.Fatalf("Generated string '%s' has length outside [2, 5]", input)
+		}
+		if strings.Trim(input, "ab") != "" {
+			t.Fatalf("Generated string '%s' contains symbols outside the alphabet", input)
+		}
+	}
+}
+
+func TestAutomatonGenerator_WithQuick(t *testing.T) {
+	alphabet := []fsm.Symbol{"0", "1"}
+	automata := AutomatonGenerator{Alphabet: alphabet, MinStates: 1, MaxStates: 6}
+	inputs := StringGenerator{Alphabet: alphabet, MaxLen: 20}
+
+	property := func(fa *fsm.FiniteAutomaton, input string) bool {
+		finalState, err := fa.ProcessInput(input)
+		if err != nil {
+			return false
+		}
+		for _, state := range fa.States {
+			if state == finalState {
+				return true
+			}
+		}
+		return false
+	}
+
+	config := &quick.Config{
+		MaxCount: 200,
+		Rand:     rand.New(rand.NewSource(7)),
+		Values:   Values(automata, inputs),
+	}
+	if err := quick.Check(property, config); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBinaryString_WithQuick(t *testing.T) {
+	property := func(input BinaryString) bool {
+		return len(input) > 0 && strings.Trim(string(input), "01") == ""
+	}
+
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestShrinkString(t *testing.T) {
+	if candidates := ShrinkString(""); len(candidates) != 0 {
+		t.Errorf("Expected no candidates for empty string, got %v", candidates)
+	}
+
+	candidates := ShrinkString("abc")
+	for _, candidate := range candidates {
+		if len(candidate) >= 3 {
+			t.Errorf("Candidate '%s' is not smaller than the input", candidate)
+		}
+	}
+}
+
+func TestMinimizeFailingString(t *testing.T) {
+	property := func(input string) bool {
+		return !strings.Contains(input, "11")
+	}
+
+	minimal := MinimizeFailingString("0100110100", property)
+	if minimal != "11" {
+		t.Errorf("Expected minimal failing input '11', got '%s'", minimal)
+	}
+}
+
+func TestCheckStrings(t *testing.T) {
+	generator := StringGenerator{Alphabet: []fsm.Symbol{"0", "1"}, MaxLen: 30}
+
+	CheckStrings(t, generator, 100, 1, func(input string) bool {
+		return len(input) <= 30
+	})
+
+	r := &recorder{TB: t}
+	CheckStrings(r, generator, 100, 1, func(input string) bool {
+		return !strings.Contains(input, "111")
+	})
+	if len(r.failures) != 1 || !strings.Contains(r.failures[0], "shrunk to '111'") {
+		t.Errorf("Expected one failure shrunk to '111', got %v", r.failures)
+	}
+}
+
+func TestShrinkAutomaton(t *testing.T) {
+	fa := newEvenOnes()
+
+	candidates := ShrinkAutomaton(fa)
+	if len(candidates) != 2 {
+		t.Fatalf("Expected 2 candidates, got %d", len(candidates))
+	}
+
+	for _, candidate := range candidates {
+		if len(candidate.States)+len(candidate.AcceptingStates) >= len(fa.States)+len(fa.AcceptingStates) {
+			t.Errorf("Candidate is not smaller than the original: %v", candidate)
+		}
+		if _, err := candidate.ProcessInput("0110"); err != nil {
+			t.Errorf("Candidate failed to process input: %v", err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package lexer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Rule struct {
+	Name    string
+	Pattern string
+	Skip    bool
+}
+
+type Token struct {
+	Type   string
+	Value  string
+	Offset int
+	Line   int
+	Column int
+}
+
+type Lexer struct {
+	rules      []Rule
+	automaton  *fsm.FiniteAutomaton
+	acceptRule map[fsm.State]int
+	dead       map[fsm.State]bool
+}
+
+func New(rules []Rule) (*Lexer, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("lexer requires at least one rule")
+	}
+
+	alphabet := fsm.ASCIIAlphabet()
+	combined := fsm.NewNFA([]fsm.State{"start"}, alphabet, "start", nil)
+	ruleOf := make(map[fsm.State]int, len(rules))
+
+	for i, rule := range rules {
+		nfa, err := fsm.CompileRegex(rule.Pattern, alphabet)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+
+		rename := func(state fsm.State) fsm.State {
+			return fsm.State(fmt.Sprintf("%d:%s", i, state))
+		}
+
+		for _, state := range nfa.States {
+			combined.States = append(combined.States, rename(state))
+			for symbol, targets := range nfa.Transitions[state] {
+				for _, target := range targets {
+					combined.AddTransition(rename(state), symbol, rename(target))
+				}
+			}
+		}
+		combined.AddTransition("start", fsm.Epsilon, rename(nfa.InitialState))
+
+		for _, accepting := range nfa.AcceptingStates {
+			combined.AcceptingStates = append(combined.AcceptingStates, rename(accepting))
+			ruleOf[rename(accepting)] = i
+		}
+	}
+
+	automaton, subsets := combined.ToDFAWithSubsets()
+
+	l := &Lexer{
+		rules:      rules,
+		automaton:  automaton,
+		acceptRule: make(map[fsm.State]int),
+		dead:       make(map[fsm.State]bool),
+	}
+
+	for state, subset := range subsets {
+		if len(subset) == 0 {
+			l.dead[state] = true
+			continue
+		}
+
+		best := -1
+		for _, nfaState := range subset {
+			if rule, ok := ruleOf[nfaState]; ok && (best < 0 || rule < best) {
+				best = rule
+			}
+		}
+		if best >= 0 {
+			l.acceptRule[state] = best
+		}
+	}
+
+	return l, nil
+}
+
+func (l *Lexer) Automaton() *fsm.FiniteAutomaton {
+	return l.automaton
+}
+
+func (l *Lexer) Tokenize(r io.Reader) *Scanner {
+	return &Scanner{
+		lexer:  l,
+		reader: bufio.NewReader(r),
+		line:   1,
+		column: 1,
+	}
+}
+
+func (l *Lexer) TokenizeString(input string) ([]Token, error) {
+	scanner := l.Tokenize(strings.NewReader(input))
+
+	var tokens []Token
+	for {
+		token, err := scanner.Next()
+		if errors.Is(err, io.EOF) {
+			return tokens, nil
+		}
+		if err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, token)
+	}
+}
+
+type Scanner struct {
+	lexer   *Lexer
+	reader  *bufio.Reader
+	pending []rune
+	eof     bool
+	offset  int
+	line    int
+	column  int
+}
+
+func (s *Scanner) Next() (Token, error) {
+	for {
+		token, skip, err := s.scan()
+		if err != nil {
+			return Token{}, err
+		}
+		if !skip {
+			return token, nil
+		}
+	}
+}
+
+func (s *Scanner) scan() (Token, bool, error) {
+	automaton := s.lexer.automaton
+	state := automaton.InitialState
+	accepted, rule := 0, -1
+
+	for i := 0; ; i++ {
+		char, ok, err := s.runeAt(i)
+		if err != nil {
+			return Token{}, false, err
+		}
+		if !ok {
+			break
+		}
+
+		next, ok := automaton.Table[state][fsm.Symbol(string(char))]
+		if !ok || s.lexer.dead[next] {
+			break
+		}
+		state = next
+
+		if matched, ok := s.lexer.acceptRule[state]; ok {
+			accepted, rule = i+1, matched
+		}
+	}
+
+	if rule < 0 || accepted == 0 {
+		if len(s.pending) == 0 && s.eof {
+			return Token{}, false, io.EOF
+		}
+		return Token{}, false, fmt.Errorf("unexpected character %q at line %d, column %d", s.pending[0], s.line, s.column)
+	}
+
+	value := string(s.pending[:accepted])
+	token := Token{
+		Type:   s.lexer.rules[rule].Name,
+		Value:  value,
+		Offset: s.offset,
+		Line:   s.line,
+		Column: s.column,
+	}
+
+	s.advance(accepted)
+	return token, s.lexer.rules[rule].Skip, nil
+}
+
+func (s *Scanner) runeAt(i int) (rune, bool, error) {
+	for len(s.pending) <= i {
+		if s.eof {
+			return 0, false, nil
+		}
+		char, _, err := s.reader.ReadRune()
+		if errors.Is(err, io.EOF) {
+			s.eof = true
+			continue
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		s.pending = append(s.pending, char)
+	}
+	return s.pending[i], true, nil
+}
+
+func (s *Scanner) advance(n int) {
+	for _, char := range s.pending[:n] {
+		s.offset += len(string(char))
+		if char == '\n' {
+			s.line++
+			s.column = 1
+		} else {
+			s.column++
+		}
+	}
+	s.pending = s.pending[n:]
+}
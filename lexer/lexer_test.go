@@ -0,0 +1,124 @@
+package lexer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newCalculatorLexer(t *testing.T) *Lexer {
+	t.Helper()
+
+	l, err := New([]Rule{
+		{Name: "IF", Pattern: "if"},
+		{Name: "IDENT", Pattern: "[A-Za-z_]\\w*"},
+		{Name: "NUMBER", Pattern: "\\d+(\\.\\d+)?"},
+		{Name: "OP", Pattern: "==|=|\\+|-|\\*|/"},
+		{Name: "LPAREN", Pattern: "\\("},
+		{Name: "RPAREN", Pattern: "\\)"},
+		{Name: "WS", Pattern: "\\s+", Skip: true},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return l
+}
+
+func TestLexer_TokenizeString(t *testing.T) {
+	l := newCalculatorLexer(t)
+
+	tokens, err := l.TokenizeString("if iffy == 3.14 * (x1 + 2)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		tokenType string
+		value     string
+	}{
+		{"IF", "if"},
+		{"IDENT", "iffy"},
+		{"OP", "=="},
+		{"NUMBER", "3.14"},
+		{"OP", "*"},
+		{"LPAREN", "("},
+		{"IDENT", "x1"},
+		{"OP", "+"},
+		{"NUMBER", "2"},
+		{"RPAREN", ")"},
+	}
+
+	if len(tokens) != len(expected) {
+		t.Fatalf("Expected %d tokens, got %d: %v", len(expected), len(tokens), tokens)
+	}
+	for i, token := range tokens {
+		if token.Type != expected[i].tokenType || token.Value != expected[i].value {
+			t.Errorf("Token %d: expected %s(%s), got %s(%s)", i, expected[i].tokenType, expected[i].value, token.Type, token.Value)
+		}
+	}
+}
+
+func TestLexer_MaximalMunchBacktracksToLastAccept(t *testing.T) {
+	l := newCalculatorLexer(t)
+
+	tokens, err := l.TokenizeString("12.x")
+	if err == nil {
+		t.Fatalf("Expected error after '12' on '.', got tokens %v", tokens)
+	}
+	if len(tokens) != 1 || tokens[0].Value != "12" {
+		t.Errorf("Expected NUMBER(12) before the error, got %v", tokens)
+	}
+}
+
+func TestLexer_Positions(t *testing.T) {
+	l := newCalculatorLexer(t)
+	scanner := l.Tokenize(strings.NewReader("a\n  bb = 1"))
+
+	expected := []Token{
+		{Type: "IDENT", Value: "a", Offset: 0, Line: 1, Column: 1},
+		{Type: "IDENT", Value: "bb", Offset: 4, Line: 2, Column: 3},
+		{Type: "OP", Value: "=", Offset: 7, Line: 2, Column: 6},
+		{Type: "NUMBER", Value: "1", Offset: 9, Line: 2, Column: 8},
+	}
+
+	for _, want := range expected {
+		token, err := scanner.Next()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if token != want {
+			t.Errorf("Expected %+v, got %+v", want, token)
+		}
+	}
+
+	if _, err := scanner.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestLexer_UnexpectedCharacter(t *testing.T) {
+	l := newCalculatorLexer(t)
+
+	for _, input := range []string{"x $ y", "x é"} {
+		_, err := l.TokenizeString(input)
+		if err == nil {
+			t.Errorf("Expected error for input '%s', but got none", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "line 1, column 3") {
+			t.Errorf("Expected error position in message, got: %v", err)
+		}
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	if _, err := New(nil); err == nil {
+		t.Error("Expected error for empty rule list, but got none")
+	}
+
+	_, err := New([]Rule{{Name: "BROKEN", Pattern: "(a"}})
+	if err == nil || !strings.Contains(err.Error(), "rule BROKEN") {
+		t.Errorf("Expected error naming the broken rule, got %v", err)
+	}
+}
@@ -0,0 +1,291 @@
+package modelcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Counterexample struct {
+	Prefix []fsm.Transition
+	Loop   []fsm.Transition
+}
+
+type Result struct {
+	Property       string
+	Holds          bool
+	Counterexample *Counterexample
+}
+
+type Property interface {
+	Check(fa *fsm.FiniteAutomaton) Result
+	String() string
+}
+
+type Never struct {
+	Target fsm.State
+	From   fsm.State
+	Inputs *fsm.FiniteAutomaton
+}
+
+type Invariant struct {
+	Name      string
+	Predicate func(fsm.State) bool
+}
+
+type AlwaysEventually struct {
+	Targets []fsm.State
+}
+
+type AlwaysEventuallyLeaves struct {
+	State fsm.State
+}
+
+func Check(fa *fsm.FiniteAutomaton, properties ...Property) []Result {
+	results := make([]Result, len(properties))
+	for i, property := range properties {
+		results[i] = property.Check(fa)
+	}
+	return results
+}
+
+func (p Never) String() string {
+	from := "the initial state"
+	if p.From != "" {
+		from = string(p.From)
+	}
+	if p.Inputs != nil {
+		return fmt.Sprintf("never reach %s from %s on restricted inputs", p.Target, from)
+	}
+	return fmt.Sprintf("never reach %s from %s", p.Target, from)
+}
+
+func (p Never) Check(fa *fsm.FiniteAutomaton) Result {
+	from := p.From
+	if from == "" {
+		from = fa.InitialState
+	}
+
+	if p.Inputs == nil {
+		path, found := shortestPath(fa, from, func(state fsm.State) bool { return state == p.Target })
+		return result(p, found, &Counterexample{Prefix: path})
+	}
+
+	path, found := restrictedPath(fa, from, p.Target, p.Inputs)
+	return result(p, found, &Counterexample{Prefix: path})
+}
+
+func (p Invariant) String() string {
+	return fmt.Sprintf("always %s", p.Name)
+}
+
+func (p Invariant) Check(fa *fsm.FiniteAutomaton) Result {
+	path, found := shortestPath(fa, fa.InitialState, func(state fsm.State) bool { return !p.Predicate(state) })
+	return result(p, found, &Counterexample{Prefix: path})
+}
+
+func (p AlwaysEventually) String() string {
+	return fmt.Sprintf("always eventually reach one of %v", p.Targets)
+}
+
+func (p AlwaysEventually) Check(fa *fsm.FiniteAutomaton) Result {
+	targets := make(map[fsm.State]bool, len(p.Targets))
+	for _, target := range p.Targets {
+		targets[target] = true
+	}
+
+	prefix, loop, found := lassoWithin(fa, func(state fsm.State) bool { return !targets[state] })
+	return result(p, found, &Counterexample{Prefix: prefix, Loop: loop})
+}
+
+func (p AlwaysEventuallyLeaves) String() string {
+	return fmt.Sprintf("%s is always eventually left", p.State)
+}
+
+func (p AlwaysEventuallyLeaves) Check(fa *fsm.FiniteAutomaton) Result {
+	prefix, loop, found := lassoWithin(fa, func(state fsm.State) bool { return state == p.State })
+	return result(p, found, &Counterexample{Prefix: prefix, Loop: loop})
+}
+
+func (c *Counterexample) Input() string {
+	var sb strings.Builder
+	for _, transition := range c.Prefix {
+		sb.WriteString(string(transition.Symbol))
+	}
+	if len(c.Loop) > 0 {
+		sb.WriteString("(")
+		for _, transition := range c.Loop {
+			sb.WriteString(string(transition.Symbol))
+		}
+		sb.WriteString(")^ω")
+	}
+	return sb.String()
+}
+
+func (c *Counterexample) String() string {
+	var sb strings.Builder
+	writePath(&sb, c.Prefix)
+	if len(c.Loop) > 0 {
+		if len(c.Prefix) > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString("(")
+		writePath(&sb, c.Loop)
+		sb.WriteString(")^ω")
+	}
+	return sb.String()
+}
+
+func (r Result) String() string {
+	if r.Holds {
+		return fmt.Sprintf("PASS %s", r.Property)
+	}
+	return fmt.Sprintf("FAIL %s: counterexample %s", r.Property, r.Counterexample)
+}
+
+func result(property Property, violated bool, counterexample *Counterexample) Result {
+	if !violated {
+		return Result{Property: property.String(), Holds: true}
+	}
+	return Result{Property: property.String(), Holds: false, Counterexample: counterexample}
+}
+
+func writePath(sb *strings.Builder, path []fsm.Transition) {
+	for i, transition := range path {
+		if i == 0 {
+			sb.WriteString(string(transition.From))
+		}
+		sb.WriteString(fmt.Sprintf(" --%s--> %s", transition.Symbol, transition.To))
+	}
+}
+
+func shortestPath(fa *fsm.FiniteAutomaton, from fsm.State, goal func(fsm.State) bool) ([]fsm.Transition, bool) {
+	return pathWithin(fa, from, goal, func(fsm.State) bool { return true })
+}
+
+func pathWithin(fa *fsm.FiniteAutomaton, from fsm.State, goal func(fsm.State) bool, allowed func(fsm.State) bool) ([]fsm.Transition, bool) {
+	if goal(from) {
+		return nil, true
+	}
+
+	parent := map[fsm.State]fsm.Transition{}
+	seen := map[fsm.State]bool{from: true}
+	queue := []fsm.State{from}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(state, symbol)
+			if seen[next] || !allowed(next) {
+				continue
+			}
+			seen[next] = true
+			parent[next] = fsm.Transition{From: state, Symbol: symbol, To: next}
+
+			if goal(next) {
+				return unwind(parent, from, next), true
+			}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, false
+}
+
+func unwind(parent map[fsm.State]fsm.Transition, from, to fsm.State) []fsm.Transition {
+	var path []fsm.Transition
+	for state := to; state != from; {
+		transition := parent[state]
+		path = append([]fsm.Transition{transition}, path...)
+		state = transition.From
+	}
+	return path
+}
+
+// lassoWithin finds a reachable cycle whose states all satisfy allowed,
+// returning the path to the cycle and the cycle itself.
+func lassoWithin(fa *fsm.FiniteAutomaton, allowed func(fsm.State) bool) ([]fsm.Transition, []fsm.Transition, bool) {
+	reachable := map[fsm.State]bool{fa.InitialState: true}
+	order := []fsm.State{fa.InitialState}
+	for i := 0; i < len(order); i++ {
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(order[i], symbol)
+			if !reachable[next] {
+				reachable[next] = true
+				order = append(order, next)
+			}
+		}
+	}
+
+	for _, start := range order {
+		if !allowed(start) {
+			continue
+		}
+
+		for _, symbol := range fa.Alphabet {
+			next := fa.TransitionFunction(start, symbol)
+			if !allowed(next) {
+				continue
+			}
+
+			first := fsm.Transition{From: start, Symbol: symbol, To: next}
+			back, found := pathWithin(fa, next, func(state fsm.State) bool { return state == start }, allowed)
+			if !found {
+				continue
+			}
+
+			prefix, _ := shortestPath(fa, fa.InitialState, func(state fsm.State) bool { return state == start })
+			return prefix, append([]fsm.Transition{first}, back...), true
+		}
+	}
+
+	return nil, nil, false
+}
+
+type productState struct {
+	state   fsm.State
+	input   fsm.State
+	reached bool
+}
+
+func restrictedPath(fa *fsm.FiniteAutomaton, from, target fsm.State, inputs *fsm.FiniteAutomaton) ([]fsm.Transition, bool) {
+	start := productState{state: from, input: inputs.InitialState, reached: from == target}
+	parent := map[productState]productState{}
+	via := map[productState]fsm.Symbol{}
+	seen := map[productState]bool{start: true}
+	queue := []productState{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if current.reached && inputs.IsAcceptingState(current.input) {
+			var path []fsm.Transition
+			for node := current; node != start; node = parent[node] {
+				previous := parent[node]
+				path = append([]fsm.Transition{{From: previous.state, Symbol: via[node], To: node.state}}, path...)
+			}
+			return path, true
+		}
+
+		for _, symbol := range fa.Alphabet {
+			next := productState{
+				state: fa.TransitionFunction(current.state, symbol),
+				input: inputs.TransitionFunction(current.input, symbol),
+			}
+			next.reached = current.reached || next.state == target
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			parent[next] = current
+			via[next] = symbol
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,183 @@
+package modelcheck
+
+import (
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func newProtocol() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("Idle", "c", "Connected")
+	table.Set("Idle", "x", "Idle")
+	table.Set("Idle", "d", "Idle")
+	table.Set("Connected", "x", "Busy")
+	table.Set("Connected", "c", "Connected")
+	table.Set("Connected", "d", "Idle")
+	table.Set("Busy", "x", "Busy")
+	table.Set("Busy", "d", "Idle")
+	table.Set("Busy", "c", "Error")
+	table.Set("Error", "c", "Error")
+	table.Set("Error", "x", "Error")
+	table.Set("Error", "d", "Error")
+
+	return fsm.NewTableAutomaton(
+		[]fsm.State{"Idle", "Connected", "Busy", "Error"},
+		[]fsm.Symbol{"c", "x", "d"},
+		"Idle",
+		[]fsm.State{"Idle"},
+		table,
+	)
+}
+
+func newModThree() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("S0", "0", "S0")
+	table.Set("S0", "1", "S1")
+	table.Set("S1", "0", "S2")
+	table.Set("S1", "1", "S0")
+	table.Set("S2", "0", "S1")
+	table.Set("S2", "1", "S2")
+
+	return fsm.NewTableAutomaton([]fsm.State{"S0", "S1", "S2"}, []fsm.Symbol{"0", "1"}, "S0", []fsm.State{"S0"}, table)
+}
+
+func replay(t *testing.T, fa *fsm.FiniteAutomaton, from fsm.State, path []fsm.Transition) fsm.State {
+	t.Helper()
+
+	state := from
+	for _, transition := range path {
+		if transition.From != state {
+			t.Fatalf("Counterexample is not a connected path: expected to leave %s, got %s", state, transition.From)
+		}
+		state = fa.TransitionFunction(state, transition.Symbol)
+		if state != transition.To {
+			t.Fatalf("Counterexample transition %v does not match the automaton (reached %s)", transition, state)
+		}
+	}
+	return state
+}
+
+func TestNever(t *testing.T) {
+	fa := newProtocol()
+
+	result := Never{Target: "Error"}.Check(fa)
+	if result.Holds {
+		t.Fatal("Expected Error to be reachable")
+	}
+	if end := replay(t, fa, fa.InitialState, result.Counterexample.Prefix); end != "Error" {
+		t.Errorf("Expected counterexample to end in Error, got %s", end)
+	}
+	if input := result.Counterexample.Input(); input != "cxc" {
+		t.Errorf("Expected shortest counterexample 'cxc', got '%s'", input)
+	}
+
+	if result := (Never{Target: "Missing"}).Check(fa); !result.Holds {
+		t.Errorf("Expected unreachable state to pass, got %s", result)
+	}
+}
+
+func TestNever_FromStateAndRestrictedInputs(t *testing.T) {
+	fa := newProtocol()
+
+	noDoubleConnect := fsm.NewTableAutomaton(
+		[]fsm.State{"Any"},
+		[]fsm.Symbol{"c", "x", "d"},
+		"Any",
+		[]fsm.State{"Any"},
+		fsm.TransitionTable{"Any": {"x": "Any", "d": "Any", "c": "Never"}},
+	)
+	noDoubleConnect.States = append(noDoubleConnect.States, "Never")
+
+	result := Never{Target: "Error", From: "Connected", Inputs: noDoubleConnect}.Check(fa)
+	if !result.Holds {
+		t.Errorf("Expected Error to be unreachable without 'c' inputs, got %s", result)
+	}
+
+	result = Never{Target: "Error", From: "Busy"}.Check(fa)
+	if result.Holds {
+		t.Fatal("Expected Error to be reachable from Busy")
+	}
+	replay(t, fa, "Busy", result.Counterexample.Prefix)
+}
+
+func TestInvariant(t *testing.T) {
+	fa := newModThree()
+
+	result := Invariant{Name: "state is S0 or S1", Predicate: func(state fsm.State) bool { return state != "S2" }}.Check(fa)
+	if result.Holds {
+		t.Fatal("Expected invariant to be violated")
+	}
+	if input := result.Counterexample.Input(); input != "10" {
+		t.Errorf("Expected counterexample '10', got '%s'", input)
+	}
+
+	result = Invariant{Name: "state is declared", Predicate: func(state fsm.State) bool { return state != "" }}.Check(fa)
+	if !result.Holds {
+		t.Errorf("Expected invariant to hold, got %s", result)
+	}
+}
+
+func TestAlwaysEventuallyLeaves(t *testing.T) {
+	fa := newModThree()
+
+	result := AlwaysEventuallyLeaves{State: "S2"}.Check(fa)
+	if result.Holds {
+		t.Fatal("Expected S2 to have a self-loop violating the property")
+	}
+
+	end := replay(t, fa, fa.InitialState, result.Counterexample.Prefix)
+	if end != "S2" {
+		t.Errorf("Expected prefix to end in S2, got %s", end)
+	}
+	if loopEnd := replay(t, fa, end, result.Counterexample.Loop); loopEnd != end {
+		t.Errorf("Expected loop to return to %s, got %s", end, loopEnd)
+	}
+	if !strings.Contains(result.Counterexample.String(), "(S2 --1--> S2)^ω") {
+		t.Errorf("Unexpected counterexample rendering: %s", result.Counterexample)
+	}
+
+	if result := (AlwaysEventuallyLeaves{State: "Connected"}).Check(newProtocol()); result.Holds {
+		t.Error("Expected Connected to be stuck on repeated 'c'")
+	}
+}
+
+func TestAlwaysEventually(t *testing.T) {
+	fa := newProtocol()
+
+	result := AlwaysEventually{Targets: []fsm.State{"Idle"}}.Check(fa)
+	if result.Holds {
+		t.Fatal("Expected a cycle avoiding Idle")
+	}
+	for _, transition := range result.Counterexample.Loop {
+		if transition.To == "Idle" {
+			t.Errorf("Loop should avoid Idle, got %s", result.Counterexample)
+		}
+	}
+
+	result = AlwaysEventually{Targets: []fsm.State{"S0", "S1", "S2"}}.Check(newModThree())
+	if !result.Holds {
+		t.Errorf("Expected property to hold, got %s", result)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	results := Check(newModThree(),
+		Never{Target: "S2"},
+		AlwaysEventuallyLeaves{State: "S1"},
+	)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Holds {
+		t.Error("Expected S2 to be reachable")
+	}
+	if !results[1].Holds {
+		t.Errorf("Expected S1 to always be left, got %s", results[1])
+	}
+	if !strings.HasPrefix(results[1].String(), "PASS") {
+		t.Errorf("Unexpected result rendering: %s", results[1])
+	}
+}
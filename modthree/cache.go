@@ -0,0 +1,96 @@
+package modthree
+
+import (
+	"container/list"
+	"sync"
+)
+
+type CacheStats struct {
+	Hits     int
+	Misses   int
+	Size     int
+	Capacity int
+}
+
+type resultCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	hits     int
+	misses   int
+}
+
+type cacheEntry struct {
+	input  string
+	result ModThreeResult
+}
+
+func WithCache(capacity int) Option {
+	return func(m *ModThreeFSM) {
+		if capacity > 0 {
+			m.cache = newResultCache(capacity)
+		}
+	}
+}
+
+func newResultCache(capacity int) *resultCache {
+	return &resultCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *resultCache) get(input string) (*ModThreeResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[input]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+
+	c.hits++
+	c.order.MoveToFront(element)
+	result := element.Value.(*cacheEntry).result
+	return &result, true
+}
+
+func (c *resultCache) add(input string, result *ModThreeResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[input]; ok {
+		element.Value.(*cacheEntry).result = *result
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[input] = c.order.PushFront(&cacheEntry{input: input, result: *result})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).input)
+	}
+}
+
+func (c *resultCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:     c.hits,
+		Misses:   c.misses,
+		Size:     c.order.Len(),
+		Capacity: c.capacity,
+	}
+}
+
+func (m *ModThreeFSM) CacheStats() CacheStats {
+	if m.cache == nil {
+		return CacheStats{}
+	}
+	return m.cache.stats()
+}
@@ -0,0 +1,90 @@
+package modthree
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestModThree_Cache(t *testing.T) {
+	fsm := NewModThreeFSM(WithCache(2))
+
+	inputs := []string{"1101", "1110", "1101", "1111", "1110", "1101"}
+	for _, input := range inputs {
+		if _, err := fsm.ModThree(input); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	expected := CacheStats{Hits: 1, Misses: 5, Size: 2, Capacity: 2}
+	if stats := fsm.CacheStats(); stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestModThree_CacheReturnsCopies(t *testing.T) {
+	fsm := NewModThreeFSM(WithCache(4))
+
+	first, _ := fsm.ModThree("1101")
+	first.Remainder = 99
+
+	second, err := fsm.ModThree("1101")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second.Remainder != 1 {
+		t.Errorf("Expected cached remainder 1, got %d", second.Remainder)
+	}
+}
+
+func TestModThree_CacheSkipsErrors(t *testing.T) {
+	fsm := NewModThreeFSM(WithCache(4), IgnoreWhitespace())
+
+	for i := 0; i < 2; i++ {
+		if _, err := fsm.ModThree("12"); err == nil {
+			t.Error("Expected error for invalid input, but got none")
+		}
+	}
+	if _, err := fsm.ModThree("1 1"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := fsm.ModThree("11"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := CacheStats{Hits: 1, Misses: 3, Size: 1, Capacity: 4}
+	if stats := fsm.CacheStats(); stats != expected {
+		t.Errorf("Expected stats %+v, got %+v", expected, stats)
+	}
+}
+
+func TestModThree_CacheDisabled(t *testing.T) {
+	for _, fsm := range []*ModThreeFSM{NewModThreeFSM(), NewModThreeFSM(WithCache(0))} {
+		fsm.ModThree("1101")
+		if stats := fsm.CacheStats(); stats != (CacheStats{}) {
+			t.Errorf("Expected empty stats without a cache, got %+v", stats)
+		}
+	}
+}
+
+func TestModThree_CacheConcurrent(t *testing.T) {
+	fsm := NewModThreeFSM(WithCache(8))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, input := range SeedInputs() {
+				if _, err := fsm.ModThree(input); err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	stats := fsm.CacheStats()
+	if stats.Hits+stats.Misses != 8*len(SeedInputs()) {
+		t.Errorf("Expected %d lookups, got %+v", 8*len(SeedInputs()), stats)
+	}
+}
@@ -0,0 +1,41 @@
+package modthree
+
+import (
+	"math/big"
+	"testing"
+)
+
+func FuzzModThree(f *testing.F) {
+	for _, seed := range SeedInputs() {
+		f.Add(seed)
+	}
+	f.Add("")
+	f.Add("10a1")
+
+	modThreeFSM := NewModThreeFSM()
+
+	f.Fuzz(func(t *testing.T, input string) {
+		if modThreeFSM.validateInput(input) != nil {
+			if _, err := modThreeFSM.ModThree(input); err == nil {
+				t.Fatalf("Expected error for invalid input '%s'", input)
+			}
+			return
+		}
+
+		finalState, err := modThreeFSM.GetAutomaton().ProcessInput(input)
+		if err != nil {
+			t.Fatalf("Automaton rejected valid input '%s': %v", input, err)
+		}
+
+		value, _ := new(big.Int).SetString(input, 2)
+		expected := int(new(big.Int).Mod(value, big.NewInt(3)).Int64())
+		if remainder := modThreeFSM.stateToRemainder(finalState); remainder != expected {
+			t.Fatalf("For input '%s': FSM remainder %d, arithmetic remainder %d", input, remainder, expected)
+		}
+
+		result, err := modThreeFSM.ModThree(input)
+		if err == nil && result.Remainder != expected {
+			t.Fatalf("For input '%s': ModThree remainder %d, arithmetic remainder %d", input, result.Remainder, expected)
+		}
+	})
+}
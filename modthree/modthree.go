@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"fsm-modulo-three/fsm"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 type ModThreeResult struct {
@@ -15,10 +17,42 @@ type ModThreeResult struct {
 }
 
 type ModThreeFSM struct {
-	automaton *fsm.FiniteAutomaton
+	automaton         *fsm.FiniteAutomaton
+	ignoreWhitespace  bool
+	ignoreUnderscores bool
+	allowPrefix       bool
+	lsbFirst          bool
+	remainders        map[fsm.State]int
+	cache             *resultCache
 }
 
-func NewModThreeFSM() *ModThreeFSM {
+type Option func(*ModThreeFSM)
+
+func IgnoreWhitespace() Option {
+	return func(m *ModThreeFSM) {
+		m.ignoreWhitespace = true
+	}
+}
+
+func IgnoreUnderscores() Option {
+	return func(m *ModThreeFSM) {
+		m.ignoreUnderscores = true
+	}
+}
+
+func AllowBinaryPrefix() Option {
+	return func(m *ModThreeFSM) {
+		m.allowPrefix = true
+	}
+}
+
+func LSBFirst() Option {
+	return func(m *ModThreeFSM) {
+		m.lsbFirst = true
+	}
+}
+
+func NewModThreeFSM(options ...Option) *ModThreeFSM {
 	states := []fsm.State{"S0", "S1", "S2"}
 
 	alphabet := []fsm.Symbol{"0", "1"}
@@ -62,12 +96,70 @@ func NewModThreeFSM() *ModThreeFSM {
 		transitionFunction,
 	)
 
-	return &ModThreeFSM{
+	m := &ModThreeFSM{
 		automaton: automaton,
 	}
+	for _, option := range options {
+		option(m)
+	}
+	if m.lsbFirst {
+		m.automaton, m.remainders = lsbFirstAutomaton()
+	}
+	return m
+}
+
+func lsbFirstAutomaton() (*fsm.FiniteAutomaton, map[fsm.State]int) {
+	name := func(remainder, weight int) fsm.State {
+		return fsm.State(fmt.Sprintf("S%d_%d", remainder, weight))
+	}
+
+	var states []fsm.State
+	remainders := make(map[fsm.State]int)
+	table := fsm.TransitionTable{}
+	for _, weight := range []int{1, 2} {
+		for remainder := 0; remainder < 3; remainder++ {
+			state := name(remainder, weight)
+			states = append(states, state)
+			remainders[state] = remainder
+			for digit, symbol := range []fsm.Symbol{"0", "1"} {
+				table.Set(state, symbol, name((remainder+digit*weight)%3, weight*2%3))
+			}
+		}
+	}
+
+	return fsm.NewTableAutomaton(states, []fsm.Symbol{"0", "1"}, name(0, 1), states, table), remainders
+}
+
+func (m *ModThreeFSM) Normalize(input string) string {
+	if m.ignoreWhitespace {
+		input = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, input)
+	}
+	if m.allowPrefix {
+		if rest, ok := strings.CutPrefix(input, "0b"); ok {
+			input = rest
+		} else if rest, ok := strings.CutPrefix(input, "0B"); ok {
+			input = rest
+		}
+	}
+	if m.ignoreUnderscores {
+		input = strings.ReplaceAll(input, "_", "")
+	}
+	return input
 }
 
 func (m *ModThreeFSM) ModThree(input string) (*ModThreeResult, error) {
+	input = m.Normalize(input)
+	if m.cache != nil {
+		if result, ok := m.cache.get(input); ok {
+			return result, nil
+		}
+	}
+
 	if err := m.validateInput(input); err != nil {
 		return nil, err
 	}
@@ -79,7 +171,12 @@ func (m *ModThreeFSM) ModThree(input string) (*ModThreeResult, error) {
 
 	remainder := m.stateToRemainder(finalState)
 
-	binaryValue, err := strconv.ParseInt(input, 2, 64)
+	digits := input
+	if m.lsbFirst {
+		digits = reverse(input)
+	}
+
+	binaryValue, err := strconv.ParseInt(digits, 2, 64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse binary string: %w", err)
 	}
@@ -89,13 +186,17 @@ func (m *ModThreeFSM) ModThree(input string) (*ModThreeResult, error) {
 		return nil, fmt.Errorf("FSM result mismatch: got %d, expected %d", remainder, expectedRemainder)
 	}
 
-	return &ModThreeResult{
+	result := &ModThreeResult{
 		Input:        input,
 		FinalState:   finalState,
 		Remainder:    remainder,
 		BinaryValue:  int(binaryValue),
 		DecimalValue: int(binaryValue),
-	}, nil
+	}
+	if m.cache != nil {
+		m.cache.add(input, result)
+	}
+	return result, nil
 }
 
 func (m *ModThreeFSM) validateInput(input string) error {
@@ -113,6 +214,13 @@ func (m *ModThreeFSM) validateInput(input string) error {
 }
 
 func (m *ModThreeFSM) stateToRemainder(state fsm.State) int {
+	if m.remainders != nil {
+		if remainder, ok := m.remainders[state]; ok {
+			return remainder
+		}
+		return -1
+	}
+
 	switch state {
 	case "S0":
 		return 0
@@ -125,6 +233,24 @@ func (m *ModThreeFSM) stateToRemainder(state fsm.State) int {
 	}
 }
 
+func reverse(input string) string {
+	bytes := []byte(input)
+	for i, j := 0, len(bytes)-1; i < j; i, j = i+1, j-1 {
+		bytes[i], bytes[j] = bytes[j], bytes[i]
+	}
+	return string(bytes)
+}
+
+func SeedInputs() []string {
+	seeds := []string{"1101", "1110", "1111", "110", "1010"}
+	for _, seed := range fsm.SeedCorpus([]fsm.Symbol{"0", "1"}, 6) {
+		if seed != "" {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds
+}
+
 func (m *ModThreeFSM) GetAutomaton() *fsm.FiniteAutomaton {
 	return m.automaton
 }
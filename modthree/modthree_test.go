@@ -2,8 +2,11 @@ package modthree
 
 import (
 	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmtest"
+	"math/big"
 	"strconv"
 	"testing"
+	"testing/quick"
 )
 
 func TestNewModThreeFSM(t *testing.T) {
@@ -154,6 +157,50 @@ func TestModThree_ComprehensiveTest(t *testing.T) {
 	}
 }
 
+func TestModThree_AutomatonFinalStates(t *testing.T) {
+	automaton := NewModThreeFSM().GetAutomaton()
+
+	fsmtest.AssertFinalState(t, automaton, "1101", "S1")
+	fsmtest.AssertFinalState(t, automaton, "1110", "S2")
+	fsmtest.AssertFinalState(t, automaton, "1111", "S0")
+	fsmtest.AssertAccepts(t, automaton, "0", "1", "10", "110")
+	fsmtest.AssertRejects(t, automaton, "2", "01a")
+	fsmtest.AssertGoldenDOT(t, automaton, "testdata/modthree.dot")
+}
+
+func TestModThree_ToTable(t *testing.T) {
+	automaton := NewModThreeFSM().GetAutomaton()
+	table, err := automaton.ToTable()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if table.Table["S2"]["0"] != "S1" {
+		t.Errorf("Expected S2 --0--> S1 in the table, got %s", table.Table["S2"]["0"])
+	}
+	fsmtest.AssertEquivalent(t, automaton, table)
+}
+
+func TestModThree_MatchesArithmeticProperty(t *testing.T) {
+	automaton := NewModThreeFSM().GetAutomaton()
+	modThreeFSM := NewModThreeFSM()
+
+	property := func(input fsmtest.BinaryString) bool {
+		finalState, err := automaton.ProcessInput(string(input))
+		if err != nil {
+			return false
+		}
+
+		value, _ := new(big.Int).SetString(string(input), 2)
+		expected := new(big.Int).Mod(value, big.NewInt(3)).Int64()
+		return modThreeFSM.stateToRemainder(finalState) == int(expected)
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestModThree_EdgeCases(t *testing.T) {
 	fsm := NewModThreeFSM()
 
@@ -317,3 +364,106 @@ func contains(s, substr string) bool {
 		(s[:len(substr)] == substr || s[len(s)-len(substr):] == substr ||
 			contains(s[1:len(s)-1], substr)))
 }
+
+func TestModThree_Normalization(t *testing.T) {
+	tests := []struct {
+		name              string
+		options           []Option
+		input             string
+		expectedInput     string
+		expectedRemainder int
+	}{
+		{"whitespace", []Option{IgnoreWhitespace()}, " 1101\t\n", "1101", 1},
+		{"inner whitespace", []Option{IgnoreWhitespace()}, "1010 1100", "10101100", 1},
+		{"underscores", []Option{IgnoreUnderscores()}, "1010_1100", "10101100", 1},
+		{"prefix", []Option{AllowBinaryPrefix()}, "0b1110", "1110", 2},
+		{"upper prefix", []Option{AllowBinaryPrefix()}, "0B1111", "1111", 0},
+		{"all", []Option{IgnoreWhitespace(), IgnoreUnderscores(), AllowBinaryPrefix()}, " 0b_1010_1100 ", "10101100", 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := NewModThreeFSM(test.options...).ModThree(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Input != test.expectedInput {
+				t.Errorf("Expected normalized input %q, got %q", test.expectedInput, result.Input)
+			}
+			if result.Remainder != test.expectedRemainder {
+				t.Errorf("Expected remainder %d, got %d", test.expectedRemainder, result.Remainder)
+			}
+		})
+	}
+}
+
+func TestModThree_NormalizationDisabled(t *testing.T) {
+	tests := []struct {
+		options []Option
+		input   string
+	}{
+		{nil, " 1101"},
+		{nil, "1010_1100"},
+		{nil, "0b1110"},
+		{[]Option{IgnoreUnderscores()}, "0b1110"},
+		{[]Option{AllowBinaryPrefix()}, "1 1"},
+		{[]Option{AllowBinaryPrefix()}, "0b"},
+		{[]Option{IgnoreWhitespace(), IgnoreUnderscores()}, " _ "},
+	}
+
+	for _, test := range tests {
+		if _, err := NewModThreeFSM(test.options...).ModThree(test.input); err == nil {
+			t.Errorf("Expected error for input %q, but got none", test.input)
+		}
+	}
+}
+
+func TestModThree_LSBFirst(t *testing.T) {
+	lsb := NewModThreeFSM(LSBFirst())
+	msb := NewModThreeFSM()
+
+	if states := len(lsb.GetAutomaton().GetStates()); states != 6 {
+		t.Errorf("Expected 6 states, got %d", states)
+	}
+
+	for _, input := range SeedInputs() {
+		expected, err := msb.ModThree(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		result, err := lsb.ModThree(reverse(input))
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", reverse(input), err)
+		}
+		if result.Remainder != expected.Remainder || result.DecimalValue != expected.DecimalValue {
+			t.Errorf("For LSB-first input %s: expected %d (decimal %d), got %d (decimal %d)",
+				reverse(input), expected.Remainder, expected.DecimalValue, result.Remainder, result.DecimalValue)
+		}
+	}
+}
+
+func TestModThree_LSBFirstExamples(t *testing.T) {
+	fsm := NewModThreeFSM(LSBFirst(), AllowBinaryPrefix())
+
+	tests := []struct {
+		input             string
+		expectedRemainder int
+		expectedDecimal   int
+	}{
+		{"1011", 1, 13},
+		{"0111", 2, 14},
+		{"0b011", 0, 6},
+	}
+
+	for _, test := range tests {
+		result, err := fsm.ModThree(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Remainder != test.expectedRemainder || result.DecimalValue != test.expectedDecimal {
+			t.Errorf("For input %s: expected %d (decimal %d), got %d (decimal %d)",
+				test.input, test.expectedRemainder, test.expectedDecimal, result.Remainder, result.DecimalValue)
+		}
+	}
+}
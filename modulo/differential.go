@@ -0,0 +1,160 @@
+package modulo
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// DifferentialConfig selects the machines and inputs Differential compares.
+// Lengths whose whole input space has at most ExhaustiveLimit strings are
+// enumerated; longer lengths are covered by Samples random strings each.
+type DifferentialConfig struct {
+	Moduli          []int
+	Bases           []int
+	MinLength       int
+	MaxLength       int
+	ExhaustiveLimit int
+	Samples         int
+	Seed            int64
+	Options         []Option
+}
+
+type Mismatch struct {
+	Modulus  int
+	Base     int
+	Input    string
+	Got      int
+	Expected int
+}
+
+type DifferentialReport struct {
+	Machines   int
+	Exhaustive int
+	Sampled    int
+	Mismatches []Mismatch
+}
+
+// Differential runs every machine in config against math/big on the
+// configured inputs. Errors are configuration errors; disagreements are
+// collected in the report.
+func Differential(config DifferentialConfig) (*DifferentialReport, error) {
+	if config.MinLength < 1 || config.MaxLength < config.MinLength {
+		return nil, fmt.Errorf("invalid length range %d..%d", config.MinLength, config.MaxLength)
+	}
+
+	r := rand.New(rand.NewSource(config.Seed))
+	report := &DifferentialReport{}
+	for _, modulus := range config.Moduli {
+		for _, base := range config.Bases {
+			m, err := NewModFSM(modulus, base, config.Options...)
+			if err != nil {
+				return nil, err
+			}
+			if m.encoding != nil {
+				return nil, fmt.Errorf("differential testing does not support %s input", m.encoding.name)
+			}
+			report.Machines++
+
+			for length := config.MinLength; length <= config.MaxLength; length++ {
+				if spaceAtMost(base, length, config.ExhaustiveLimit) {
+					err = enumerate(base, length, func(input string) error {
+						report.Exhaustive++
+						return report.check(m, input)
+					})
+				} else {
+					for i := 0; i < config.Samples && err == nil; i++ {
+						report.Sampled++
+						err = report.check(m, randomDigits(r, base, length))
+					}
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return report, nil
+}
+
+func (report *DifferentialReport) check(m *ModFSM, input string) error {
+	_, got, err := m.fsmRemainder(input)
+	if err != nil {
+		return err
+	}
+	expected, err := m.arithmeticRemainder(input)
+	if err != nil {
+		return err
+	}
+	if got != expected {
+		report.Mismatches = append(report.Mismatches, Mismatch{
+			Modulus:  m.modulus,
+			Base:     m.base,
+			Input:    input,
+			Got:      got,
+			Expected: expected,
+		})
+	}
+	return nil
+}
+
+func (report *DifferentialReport) Checked() int {
+	return report.Exhaustive + report.Sampled
+}
+
+func (report *DifferentialReport) OK() bool {
+	return len(report.Mismatches) == 0
+}
+
+func (report *DifferentialReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d machines, %d inputs (%d exhaustive, %d sampled), %d mismatches\n",
+		report.Machines, report.Checked(), report.Exhaustive, report.Sampled, len(report.Mismatches))
+	for _, mismatch := range report.Mismatches {
+		fmt.Fprintf(&b, "mismatch: mod %d base %d input '%s': got %d, expected %d\n",
+			mismatch.Modulus, mismatch.Base, mismatch.Input, mismatch.Got, mismatch.Expected)
+	}
+	return b.String()
+}
+
+func spaceAtMost(base, length, limit int) bool {
+	size := 1
+	for i := 0; i < length; i++ {
+		size *= base
+		if size > limit {
+			return false
+		}
+	}
+	return true
+}
+
+func enumerate(base, length int, visit func(string) error) error {
+	digits := make([]int, length)
+	input := make([]byte, length)
+	for {
+		for i, digit := range digits {
+			input[i] = strconv.FormatInt(int64(digit), base)[0]
+		}
+		if err := visit(string(input)); err != nil {
+			return err
+		}
+
+		i := length - 1
+		for ; i >= 0 && digits[i] == base-1; i-- {
+			digits[i] = 0
+		}
+		if i < 0 {
+			return nil
+		}
+		digits[i]++
+	}
+}
+
+func randomDigits(r *rand.Rand, base, length int) string {
+	input := make([]byte, length)
+	for i := range input {
+		input[i] = strconv.FormatInt(int64(r.Intn(base)), base)[0]
+	}
+	return string(input)
+}
@@ -0,0 +1,105 @@
+package modulo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDifferential(t *testing.T) {
+	report, err := Differential(DifferentialConfig{
+		Moduli:          []int{1, 3, 7},
+		Bases:           []int{2, 10},
+		MinLength:       1,
+		MaxLength:       4,
+		ExhaustiveLimit: 100,
+		Samples:         20,
+		Seed:            1,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !report.OK() {
+		t.Fatalf("Unexpected mismatches:\n%s", report)
+	}
+	if report.Machines != 6 {
+		t.Errorf("Expected 6 machines, got %d", report.Machines)
+	}
+	// Base 2 enumerates 2+4+8+16 inputs; base 10 enumerates 10+100 and samples the rest.
+	if report.Exhaustive != 3*(30+110) {
+		t.Errorf("Expected %d exhaustive inputs, got %d", 3*(30+110), report.Exhaustive)
+	}
+	if report.Sampled != 3*2*20 {
+		t.Errorf("Expected %d sampled inputs, got %d", 3*2*20, report.Sampled)
+	}
+}
+
+func TestDifferential_Options(t *testing.T) {
+	for name, option := range map[string]Option{
+		"lsb_first":       LSBFirst(),
+		"twos_complement": TwosComplement(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			report, err := Differential(DifferentialConfig{
+				Moduli:          []int{2, 3, 5, 6},
+				Bases:           []int{2},
+				MinLength:       1,
+				MaxLength:       40,
+				ExhaustiveLimit: 1 << 8,
+				Samples:         10,
+				Options:         []Option{option},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !report.OK() {
+				t.Errorf("Unexpected mismatches:\n%s", report)
+			}
+		})
+	}
+}
+
+func TestDifferentialReport_Mismatch(t *testing.T) {
+	m, err := NewModFSM(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m.automaton.Table.Set("S1", "1", "S1")
+	m.automaton.Intern()
+
+	report := &DifferentialReport{Machines: 1}
+	for _, input := range []string{"0", "1", "11"} {
+		report.Exhaustive++
+		if err := report.check(m, input); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if report.OK() {
+		t.Fatal("Expected a mismatch for the corrupted machine")
+	}
+	expected := Mismatch{Modulus: 3, Base: 2, Input: "11", Got: 1, Expected: 0}
+	if len(report.Mismatches) != 1 || report.Mismatches[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, report.Mismatches)
+	}
+	if !strings.Contains(report.String(), "mismatch: mod 3 base 2 input '11': got 1, expected 0") {
+		t.Errorf("Unexpected report:\n%s", report)
+	}
+}
+
+func TestDifferential_InvalidConfig(t *testing.T) {
+	tests := map[string]DifferentialConfig{
+		"empty_range":   {Moduli: []int{3}, Bases: []int{2}, MinLength: 3, MaxLength: 2},
+		"zero_length":   {Moduli: []int{3}, Bases: []int{2}, MinLength: 0, MaxLength: 2},
+		"bad_base":      {Moduli: []int{3}, Bases: []int{1}, MinLength: 1, MaxLength: 2},
+		"bad_modulus":   {Moduli: []int{0}, Bases: []int{2}, MinLength: 1, MaxLength: 2},
+		"encoded_input": {Moduli: []int{3}, Bases: []int{2}, MinLength: 1, MaxLength: 2, Options: []Option{HexInput()}},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Differential(config); err == nil {
+				t.Errorf("Expected error for %s, but got none", name)
+			}
+		})
+	}
+}
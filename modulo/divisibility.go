@@ -0,0 +1,45 @@
+package modulo
+
+import "fsm-modulo-three/fsm"
+
+func DivisibleBy(input string, n int) (bool, error) {
+	m, err := NewModFSM(n, 2)
+	if err != nil {
+		return false, err
+	}
+	return m.Divisible(input)
+}
+
+func (m *ModFSM) Divisible(input string) (bool, error) {
+	if m.signed {
+		result, err := m.Mod(input)
+		if err != nil {
+			return false, err
+		}
+		return result.Remainder == 0, nil
+	}
+
+	if m.encoding != nil {
+		if _, err := m.decode(input); err != nil {
+			return false, err
+		}
+		return m.DivisibilityAutomaton().AcceptsEncoded([]byte(input))
+	}
+	if err := m.validateInput(input); err != nil {
+		return false, err
+	}
+	return m.DivisibilityAutomaton().Accepts(input)
+}
+
+func (m *ModFSM) DivisibilityAutomaton() *fsm.FiniteAutomaton {
+	var accepting []fsm.State
+	for _, state := range m.automaton.States {
+		if m.remainder[state] == 0 {
+			accepting = append(accepting, state)
+		}
+	}
+
+	divisibility := *m.automaton
+	divisibility.AcceptingStates = accepting
+	return &divisibility
+}
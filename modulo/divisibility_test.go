@@ -0,0 +1,110 @@
+package modulo
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestDivisibleBy(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected bool
+	}{
+		{"0", 3, true},
+		{"110", 3, true},
+		{"111", 3, false},
+		{"1010", 5, true},
+		{"1011", 5, false},
+		{"10000000", 8, true},
+		{"1111011", 1, true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s_by_%d", test.input, test.n), func(t *testing.T) {
+			divisible, err := DivisibleBy(test.input, test.n)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if divisible != test.expected {
+				t.Errorf("Expected %v, got %v", test.expected, divisible)
+			}
+		})
+	}
+}
+
+func TestDivisibleBy_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		n     int
+	}{
+		{"", 3},
+		{"102", 3},
+		{"101", 0},
+	}
+
+	for _, test := range tests {
+		if _, err := DivisibleBy(test.input, test.n); err == nil {
+			t.Errorf("Expected error for %q by %d, but got none", test.input, test.n)
+		}
+	}
+}
+
+func TestDivisibilityAutomaton(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+	}{
+		{"msb", nil},
+		{"lsb", []Option{LSBFirst()}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, _ := NewModFSM(6, 10, test.options...)
+			automaton := m.DivisibilityAutomaton()
+
+			if len(m.GetAutomaton().AcceptingStates) != len(m.GetAutomaton().States) {
+				t.Error("Expected the remainder automaton to keep all states accepting")
+			}
+
+			for value := 0; value < 200; value++ {
+				input := strconv.Itoa(value)
+				if m.LSBFirst() {
+					input = reverse(input)
+				}
+				accepted, err := automaton.Accepts(input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if accepted != (value%6 == 0) {
+					t.Errorf("For %d: expected accepted=%v, got %v", value, value%6 == 0, accepted)
+				}
+			}
+		})
+	}
+}
+
+func TestDivisible_TwosComplement(t *testing.T) {
+	m, _ := NewModFSM(3, 2, TwosComplement())
+
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1101", true},
+		{"1111", false},
+		{"0011", true},
+	}
+
+	for _, test := range tests {
+		divisible, err := m.Divisible(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if divisible != test.expected {
+			t.Errorf("For %s: expected %v, got %v", test.input, test.expected, divisible)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package modulo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"fsm-modulo-three/fsm"
+)
+
+type encoding struct {
+	name   string
+	decode func(string) ([]byte, error)
+}
+
+// HexInput reads each input as a hex string holding a big-endian integer.
+// The machine must use base 2, stepping once per bit, or base 256, stepping
+// once per byte.
+func HexInput() Option {
+	return func(m *ModFSM) {
+		m.encoding = &encoding{name: "hex", decode: hex.DecodeString}
+	}
+}
+
+func Base64Input() Option {
+	return func(m *ModFSM) {
+		m.encoding = &encoding{name: "base64", decode: base64.StdEncoding.DecodeString}
+	}
+}
+
+func newEncodedModFSM(m *ModFSM) (*ModFSM, error) {
+	if m.base != 2 && m.base != 256 {
+		return nil, fmt.Errorf("%s input requires base 2 or 256, got %d", m.encoding.name, m.base)
+	}
+	if m.signed || m.lsbFirst {
+		return nil, fmt.Errorf("%s input is not supported with two's complement or LSB-first processing", m.encoding.name)
+	}
+
+	alphabet := []fsm.Symbol{"0", "1"}
+	if m.base == 256 {
+		alphabet = fsm.ExtendedByteAlphabet()
+	}
+
+	states, table, remainder := msbFirstTable(m.modulus, m.base, alphabet)
+	m.remainder = remainder
+	m.automaton = fsm.NewTableAutomaton(states, alphabet, states[0], states, table)
+	m.automaton.Decoder = fsm.DecoderFunc(func(data []byte) ([]fsm.Symbol, error) {
+		decoded, err := m.decode(string(data))
+		if err != nil {
+			return nil, err
+		}
+		if m.base == 256 {
+			return fsm.ByteDecoder().Decode(decoded)
+		}
+		symbols := make([]fsm.Symbol, 0, 8*len(decoded))
+		for _, b := range decoded {
+			for bit := 7; bit >= 0; bit-- {
+				symbols = append(symbols, alphabet[b>>bit&1])
+			}
+		}
+		return symbols, nil
+	})
+
+	return m, nil
+}
+
+func (m *ModFSM) decode(input string) ([]byte, error) {
+	if input == "" {
+		return nil, fmt.Errorf("input string cannot be empty")
+	}
+	data, err := m.encoding.decode(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %w", m.encoding.name, err)
+	}
+	return data, nil
+}
+
+func (m *ModFSM) modEncoded(input string) (*ModResult, error) {
+	data, err := m.decode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	finalState, err := m.automaton.ProcessEncoded([]byte(input))
+	if err != nil {
+		return nil, fmt.Errorf("FSM processing error: %w", err)
+	}
+	remainder := m.stateToRemainder(finalState)
+
+	if m.verify {
+		value := new(big.Int).SetBytes(data)
+		expectedRemainder := int(new(big.Int).Mod(value, big.NewInt(int64(m.modulus))).Int64())
+		if remainder != expectedRemainder {
+			return nil, fmt.Errorf("FSM result mismatch: got %d, expected %d", remainder, expectedRemainder)
+		}
+	}
+
+	return &ModResult{
+		Input:      input,
+		FinalState: finalState,
+		Remainder:  remainder,
+	}, nil
+}
+
+func (m *ModFSM) Encoding() string {
+	if m.encoding == nil {
+		return ""
+	}
+	return m.encoding.name
+}
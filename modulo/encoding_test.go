@@ -0,0 +1,112 @@
+package modulo
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestMod_EncodedInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  Option
+		encode  func([]byte) string
+		modulus int
+		base    int
+	}{
+		{"hex bits", HexInput(), hex.EncodeToString, 3, 2},
+		{"hex bytes", HexInput(), hex.EncodeToString, 7, 256},
+		{"base64 bits", Base64Input(), base64.StdEncoding.EncodeToString, 5, 2},
+		{"base64 bytes", Base64Input(), base64.StdEncoding.EncodeToString, 255, 256},
+	}
+
+	payloads := [][]byte{{0x00}, {0x0D}, {0xFF, 0x01}, []byte("checksum payload"), {0x00, 0x00, 0x2A}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, err := NewModFSM(test.modulus, test.base, test.option)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if m.Encoding() == "" {
+				t.Error("Expected an encoding to be reported")
+			}
+
+			for _, payload := range payloads {
+				input := test.encode(payload)
+				result, err := m.Mod(input)
+				if err != nil {
+					t.Fatalf("Unexpected error for %q: %v", input, err)
+				}
+				expected := int(new(big.Int).Mod(new(big.Int).SetBytes(payload), big.NewInt(int64(test.modulus))).Int64())
+				if result.Remainder != expected {
+					t.Errorf("For %q: expected remainder %d, got %d", input, expected, result.Remainder)
+				}
+				if result.Input != input {
+					t.Errorf("Expected input %q to be echoed, got %q", input, result.Input)
+				}
+
+				divisible, err := m.Divisible(input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if divisible != (expected == 0) {
+					t.Errorf("For %q: expected divisible %v, got %v", input, expected == 0, divisible)
+				}
+			}
+		})
+	}
+}
+
+func TestMod_EncodedInputErrors(t *testing.T) {
+	hexFSM, err := NewModFSM(3, 2, HexInput())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	base64FSM, err := NewModFSM(3, 256, Base64Input())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		m             *ModFSM
+		input         string
+		expectedError string
+	}{
+		{hexFSM, "", "cannot be empty"},
+		{hexFSM, "abc", "invalid hex input"},
+		{hexFSM, "zz", "invalid hex input"},
+		{base64FSM, "AQ", "invalid base64 input"},
+		{base64FSM, "!!!!", "invalid base64 input"},
+	}
+
+	for _, test := range tests {
+		if _, err := test.m.Mod(test.input); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("For %q: expected error containing %q, got %v", test.input, test.expectedError, err)
+		}
+		if _, err := test.m.Divisible(test.input); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("For %q: expected divisibility error containing %q, got %v", test.input, test.expectedError, err)
+		}
+	}
+}
+
+func TestNewModFSM_EncodedInputParameters(t *testing.T) {
+	tests := []struct {
+		base          int
+		options       []Option
+		expectedError string
+	}{
+		{10, []Option{HexInput()}, "requires base 2 or 256"},
+		{256, nil, "base must be between 2 and 36"},
+		{2, []Option{Base64Input(), LSBFirst()}, "not supported"},
+		{2, []Option{HexInput(), TwosComplement()}, "not supported"},
+	}
+
+	for _, test := range tests {
+		if _, err := NewModFSM(3, test.base, test.options...); err == nil || !strings.Contains(err.Error(), test.expectedError) {
+			t.Errorf("Base %d: expected error containing %q, got %v", test.base, test.expectedError, err)
+		}
+	}
+}
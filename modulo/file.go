@@ -0,0 +1,107 @@
+package modulo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type ProcessStats struct {
+	Lines   int
+	Invalid int
+}
+
+type lineJob struct {
+	input  string
+	result chan lineResult
+}
+
+type lineResult struct {
+	text    string
+	invalid bool
+}
+
+func (m *ModFSM) ProcessFile(path string, workers int, w io.Writer) (ProcessStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ProcessStats{}, err
+	}
+	defer file.Close()
+
+	return m.ProcessReader(file, workers, w)
+}
+
+func (m *ModFSM) ProcessReader(r io.Reader, workers int, w io.Writer) (ProcessStats, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan *lineJob, workers)
+	ordered := make(chan *lineJob, 4*workers)
+	done := make(chan struct{})
+	defer close(done)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range jobs {
+				job.result <- m.formatLine(job.input)
+			}
+		}()
+	}
+
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			input := strings.TrimSpace(scanner.Text())
+			if input == "" {
+				continue
+			}
+
+			job := &lineJob{input: input, result: make(chan lineResult, 1)}
+			select {
+			case ordered <- job:
+			case <-done:
+				readErr <- nil
+				return
+			}
+			jobs <- job
+		}
+		readErr <- scanner.Err()
+	}()
+
+	var stats ProcessStats
+	out := bufio.NewWriter(w)
+	for job := range ordered {
+		result := <-job.result
+		stats.Lines++
+		if result.invalid {
+			stats.Invalid++
+		}
+		if _, err := out.WriteString(result.text); err != nil {
+			return stats, fmt.Errorf("writing output: %w", err)
+		}
+	}
+
+	if err := <-readErr; err != nil {
+		return stats, fmt.Errorf("reading input: %w", err)
+	}
+	if err := out.Flush(); err != nil {
+		return stats, fmt.Errorf("writing output: %w", err)
+	}
+	return stats, nil
+}
+
+func (m *ModFSM) formatLine(input string) lineResult {
+	result, err := m.Mod(input)
+	if err != nil {
+		return lineResult{text: fmt.Sprintf("%s\terror\t%v\n", input, err), invalid: true}
+	}
+	return lineResult{text: fmt.Sprintf("%s\t%d\t%s\n", input, result.Remainder, result.FinalState)}
+}
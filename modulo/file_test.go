@@ -0,0 +1,87 @@
+package modulo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inputs.txt")
+	if err := os.WriteFile(path, []byte("1101\n\n  1110 \n102\n1111\n"), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	m, _ := NewModFSM(3, 2)
+	for _, workers := range []int{0, 1, 4} {
+		t.Run(fmt.Sprintf("workers_%d", workers), func(t *testing.T) {
+			var out bytes.Buffer
+			stats, err := m.ProcessFile(path, workers, &out)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expected := "1101\t1\tS1\n" +
+				"1110\t2\tS2\n" +
+				"102\terror\tinvalid character '2' at position 2: not a base-2 digit\n" +
+				"1111\t0\tS0\n"
+			if out.String() != expected {
+				t.Errorf("Expected output:\n%s\ngot:\n%s", expected, out.String())
+			}
+			if stats != (ProcessStats{Lines: 4, Invalid: 1}) {
+				t.Errorf("Expected 4 lines with 1 invalid, got %+v", stats)
+			}
+		})
+	}
+}
+
+func TestProcessReader_PreservesOrder(t *testing.T) {
+	var input strings.Builder
+	var expected strings.Builder
+	for value := 1; value <= 5000; value++ {
+		binary := fmt.Sprintf("%b", value)
+		fmt.Fprintln(&input, binary)
+		fmt.Fprintf(&expected, "%s\t%d\tS%d\n", binary, value%7, value%7)
+	}
+
+	m, _ := NewModFSM(7, 2)
+	var out bytes.Buffer
+	stats, err := m.ProcessReader(strings.NewReader(input.String()), 8, &out)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Lines != 5000 || stats.Invalid != 0 {
+		t.Errorf("Expected 5000 valid lines, got %+v", stats)
+	}
+	if out.String() != expected.String() {
+		t.Error("Expected results in input order")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestProcessReader_Errors(t *testing.T) {
+	m, _ := NewModFSM(3, 2)
+
+	if _, err := m.ProcessFile(filepath.Join(t.TempDir(), "missing.txt"), 2, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for a missing file, but got none")
+	}
+
+	input := strings.Repeat("1101\n", 100000)
+	if _, err := m.ProcessReader(strings.NewReader(input), 4, failingWriter{}); err == nil {
+		t.Error("Expected error from a failing writer, but got none")
+	}
+
+	long := strings.Repeat("1", 2*1024*1024)
+	if _, err := m.ProcessReader(strings.NewReader(long), 2, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for an oversized line, but got none")
+	}
+}
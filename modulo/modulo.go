@@ -0,0 +1,315 @@
+package modulo
+
+import (
+	"context"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// chunkThreshold is the input length from which base-2 machines process
+// eight digits per lookup; shorter inputs do not repay building the tables.
+const chunkThreshold = 64
+
+type ModResult struct {
+	Input      string
+	FinalState fsm.State
+	Remainder  int
+}
+
+type ModFSM struct {
+	modulus   int
+	base      int
+	automaton *fsm.FiniteAutomaton
+	remainder map[fsm.State]int
+	signed    bool
+	lsbFirst  bool
+	verify    bool
+	encoding  *encoding
+
+	chunkOnce sync.Once
+	chunker   *fsm.BinaryChunker
+}
+
+type Option func(*ModFSM)
+
+func TwosComplement() Option {
+	return func(m *ModFSM) {
+		m.signed = true
+	}
+}
+
+func LSBFirst() Option {
+	return func(m *ModFSM) {
+		m.lsbFirst = true
+	}
+}
+
+// CrossCheck makes Mod recompute every remainder with math/big and fail on a
+// mismatch. It is meant for testing: it costs far more than the FSM itself.
+func CrossCheck() Option {
+	return func(m *ModFSM) {
+		m.verify = true
+	}
+}
+
+func NewModFSM(modulus, base int, options ...Option) (*ModFSM, error) {
+	if modulus < 1 {
+		return nil, fmt.Errorf("modulus must be positive, got %d", modulus)
+	}
+
+	m := &ModFSM{
+		modulus: modulus,
+		base:    base,
+	}
+	for _, option := range options {
+		option(m)
+	}
+
+	if m.encoding != nil {
+		return newEncodedModFSM(m)
+	}
+	if base < 2 || base > 36 {
+		return nil, fmt.Errorf("base must be between 2 and 36, got %d", base)
+	}
+
+	if m.signed && base != 2 {
+		return nil, fmt.Errorf("two's complement requires base 2, got %d", base)
+	}
+	if m.signed && m.lsbFirst {
+		return nil, fmt.Errorf("two's complement is not supported with LSB-first processing")
+	}
+
+	alphabet := make([]fsm.Symbol, base)
+	for digit := range alphabet {
+		alphabet[digit] = fsm.Symbol(strconv.FormatInt(int64(digit), base))
+	}
+
+	var states []fsm.State
+	var table fsm.TransitionTable
+	if m.lsbFirst {
+		states, table, m.remainder = lsbFirstTable(modulus, base, alphabet)
+	} else {
+		states, table, m.remainder = msbFirstTable(modulus, base, alphabet)
+	}
+	m.automaton = fsm.NewTableAutomaton(states, alphabet, states[0], states, table)
+
+	return m, nil
+}
+
+func msbFirstTable(modulus, base int, alphabet []fsm.Symbol) ([]fsm.State, fsm.TransitionTable, map[fsm.State]int) {
+	states := make([]fsm.State, modulus)
+	remainder := make(map[fsm.State]int, modulus)
+	for r := range states {
+		states[r] = stateFor(r)
+		remainder[states[r]] = r
+	}
+
+	table := fsm.TransitionTable{}
+	for r, state := range states {
+		for digit, symbol := range alphabet {
+			table.Set(state, symbol, states[(r*base+digit)%modulus])
+		}
+	}
+
+	return states, table, remainder
+}
+
+type weightedRemainder struct {
+	remainder int
+	weight    int
+}
+
+func lsbFirstTable(modulus, base int, alphabet []fsm.Symbol) ([]fsm.State, fsm.TransitionTable, map[fsm.State]int) {
+	name := func(p weightedRemainder) fsm.State {
+		return fsm.State(fmt.Sprintf("S%d_%d", p.remainder, p.weight))
+	}
+
+	initial := weightedRemainder{remainder: 0, weight: 1 % modulus}
+	seen := map[weightedRemainder]bool{initial: true}
+	queue := []weightedRemainder{initial}
+
+	var states []fsm.State
+	remainder := make(map[fsm.State]int)
+	table := fsm.TransitionTable{}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		states = append(states, name(current))
+		remainder[name(current)] = current.remainder
+
+		for digit, symbol := range alphabet {
+			next := weightedRemainder{
+				remainder: (current.remainder + digit*current.weight) % modulus,
+				weight:    current.weight * base % modulus,
+			}
+			table.Set(name(current), symbol, name(next))
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return states, table, remainder
+}
+
+func stateFor(remainder int) fsm.State {
+	return fsm.State(fmt.Sprintf("S%d", remainder))
+}
+
+func (m *ModFSM) Mod(input string) (*ModResult, error) {
+	if m.encoding != nil {
+		return m.modEncoded(input)
+	}
+	if err := m.validateInput(input); err != nil {
+		return nil, err
+	}
+
+	finalState, remainder, err := m.fsmRemainder(input)
+	if err != nil {
+		return nil, err
+	}
+	if m.verify {
+		expectedRemainder, err := m.arithmeticRemainder(input)
+		if err != nil {
+			return nil, err
+		}
+		if remainder != expectedRemainder {
+			return nil, fmt.Errorf("FSM result mismatch: got %d, expected %d", remainder, expectedRemainder)
+		}
+	}
+
+	return &ModResult{
+		Input:      input,
+		FinalState: finalState,
+		Remainder:  remainder,
+	}, nil
+}
+
+func (m *ModFSM) fsmRemainder(input string) (fsm.State, int, error) {
+	finalState, err := m.process(input)
+	if err != nil {
+		return "", 0, fmt.Errorf("FSM processing error: %w", err)
+	}
+
+	remainder := m.stateToRemainder(finalState)
+	if m.signed && input[0] == '1' {
+		remainder, err = m.signedRemainder(remainder, len(input))
+		if err != nil {
+			return "", 0, err
+		}
+		finalState = stateFor(remainder)
+	}
+	return finalState, remainder, nil
+}
+
+func (m *ModFSM) arithmeticRemainder(input string) (int, error) {
+	digits := input
+	if m.lsbFirst {
+		digits = reverse(input)
+	}
+
+	value, ok := new(big.Int).SetString(digits, m.base)
+	if !ok {
+		return 0, fmt.Errorf("failed to parse base-%d string", m.base)
+	}
+	if m.signed && input[0] == '1' {
+		value.Sub(value, new(big.Int).Lsh(big.NewInt(1), uint(len(input))))
+	}
+	return int(new(big.Int).Mod(value, big.NewInt(int64(m.modulus))).Int64()), nil
+}
+
+func (m *ModFSM) process(input string) (fsm.State, error) {
+	// Chunks skip the per-symbol transitions, so debug logging falls back to
+	// ProcessInput, the same way the automaton disables its own fast path.
+	logger := m.automaton.Logger
+	debug := logger != nil && logger.Enabled(context.Background(), slog.LevelDebug)
+	if m.base == 2 && len(input) >= chunkThreshold && !debug {
+		m.chunkOnce.Do(func() {
+			m.chunker, _ = fsm.NewBinaryChunker(m.automaton)
+		})
+		if m.chunker != nil {
+			return m.chunker.Process(input)
+		}
+	}
+	return m.automaton.ProcessInput(input)
+}
+
+func (m *ModFSM) signedRemainder(unsigned, width int) (int, error) {
+	weight, err := m.process("1" + strings.Repeat("0", width))
+	if err != nil {
+		return 0, fmt.Errorf("FSM processing error: %w", err)
+	}
+	return (unsigned - m.stateToRemainder(weight) + m.modulus) % m.modulus, nil
+}
+
+func (m *ModFSM) validateInput(input string) error {
+	if input == "" {
+		return fmt.Errorf("input string cannot be empty")
+	}
+
+	for i := 0; i < len(input); i++ {
+		if digit := digitValue(input[i]); digit < 0 || digit >= m.base {
+			char, _ := utf8.DecodeRuneInString(input[i:])
+			return fmt.Errorf("invalid character '%c' at position %d: not a base-%d digit", char, i, m.base)
+		}
+	}
+
+	return nil
+}
+
+// digitValue returns the value of a lowercase base-36 digit, or -1.
+func digitValue(c byte) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	}
+	return -1
+}
+
+func (m *ModFSM) stateToRemainder(state fsm.State) int {
+	if remainder, ok := m.remainder[state]; ok {
+		return remainder
+	}
+	return -1
+}
+
+func (m *ModFSM) Modulus() int {
+	return m.modulus
+}
+
+func (m *ModFSM) Base() int {
+	return m.base
+}
+
+func (m *ModFSM) Signed() bool {
+	return m.signed
+}
+
+func (m *ModFSM) LSBFirst() bool {
+	return m.lsbFirst
+}
+
+func reverse(input string) string {
+	runes := []rune(input)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func (m *ModFSM) GetAutomaton() *fsm.FiniteAutomaton {
+	return m.automaton
+}
+
+func (m *ModFSM) String() string {
+	return fmt.Sprintf("Mod %d (base %d) FSM:\n%s", m.modulus, m.base, m.automaton.String())
+}
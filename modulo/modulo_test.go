@@ -0,0 +1,430 @@
+package modulo
+
+import (
+	"bytes"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modthree"
+	"log/slog"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNewModFSM(t *testing.T) {
+	tests := []struct {
+		modulus          int
+		base             int
+		expectedStates   int
+		expectedAlphabet int
+	}{
+		{1, 2, 1, 2},
+		{3, 2, 3, 2},
+		{5, 10, 5, 10},
+		{7, 16, 7, 16},
+		{4, 36, 4, 36},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.modulus)+"_"+strconv.Itoa(test.base), func(t *testing.T) {
+			m, err := NewModFSM(test.modulus, test.base)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			automaton := m.GetAutomaton()
+			if len(automaton.GetStates()) != test.expectedStates {
+				t.Errorf("Expected %d states, got %d", test.expectedStates, len(automaton.GetStates()))
+			}
+			if len(automaton.GetAlphabet()) != test.expectedAlphabet {
+				t.Errorf("Expected %d symbols in alphabet, got %d", test.expectedAlphabet, len(automaton.GetAlphabet()))
+			}
+			if automaton.GetInitialState() != "S0" {
+				t.Errorf("Expected initial state S0, got %s", automaton.GetInitialState())
+			}
+			if m.Modulus() != test.modulus || m.Base() != test.base {
+				t.Errorf("Expected mod %d base %d, got mod %d base %d", test.modulus, test.base, m.Modulus(), m.Base())
+			}
+		})
+	}
+}
+
+func TestNewModFSM_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		modulus int
+		base    int
+	}{
+		{0, 2},
+		{-3, 2},
+		{3, 1},
+		{3, 37},
+	}
+
+	for _, test := range tests {
+		if _, err := NewModFSM(test.modulus, test.base); err == nil {
+			t.Errorf("Expected error for mod %d base %d, but got none", test.modulus, test.base)
+		}
+	}
+}
+
+func TestMod_ExampleCases(t *testing.T) {
+	tests := []struct {
+		modulus           int
+		base              int
+		input             string
+		expectedRemainder int
+	}{
+		{3, 2, "1101", 1},
+		{3, 2, "1111", 0},
+		{5, 2, "1010", 0},
+		{5, 2, "111", 2},
+		{7, 10, "100", 2},
+		{9, 10, "123456789", 0},
+		{16, 16, "ff", 15},
+		{1, 2, "1011", 0},
+		{11, 36, "zz", 1295 % 11},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			m, err := NewModFSM(test.modulus, test.base)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := m.Mod(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error for input '%s': %v", test.input, err)
+			}
+			if result.Remainder != test.expectedRemainder {
+				t.Errorf("For input '%s' mod %d (base %d): expected remainder %d, got %d",
+					test.input, test.modulus, test.base, test.expectedRemainder, result.Remainder)
+			}
+			if result.FinalState != stateFor(test.expectedRemainder) {
+				t.Errorf("Expected final state %s, got %s", stateFor(test.expectedRemainder), result.FinalState)
+			}
+		})
+	}
+}
+
+func TestMod_LongInput(t *testing.T) {
+	m, _ := NewModFSM(7, 2)
+
+	input := ""
+	for i := 0; i < 200; i++ {
+		input += "10"
+	}
+
+	result, err := m.Mod(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	value, _ := new(big.Int).SetString(input, 2)
+	expected := int(new(big.Int).Mod(value, big.NewInt(7)).Int64())
+	if result.Remainder != expected {
+		t.Errorf("Expected remainder %d, got %d", expected, result.Remainder)
+	}
+}
+
+func TestMod_MatchesModThree(t *testing.T) {
+	m, _ := NewModFSM(3, 2)
+	modThreeFSM := modthree.NewModThreeFSM()
+
+	for _, input := range modthree.SeedInputs() {
+		expected, err := modThreeFSM.ModThree(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		result, err := m.Mod(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.Remainder != expected.Remainder || result.FinalState != expected.FinalState {
+			t.Errorf("For input '%s': expected %d (%s), got %d (%s)",
+				input, expected.Remainder, expected.FinalState, result.Remainder, result.FinalState)
+		}
+	}
+}
+
+func TestMod_InvalidInput(t *testing.T) {
+	tests := []struct {
+		base  int
+		input string
+	}{
+		{2, ""},
+		{2, "2"},
+		{2, "01a"},
+		{10, "12a"},
+		{16, "FF"},
+		{16, "g"},
+		{10, " 1"},
+		{10, "-1"},
+		{10, "1é"},
+		{36, "z\x00"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			m, _ := NewModFSM(3, test.base)
+			if _, err := m.Mod(test.input); err == nil {
+				t.Errorf("Expected error for invalid base-%d input '%s', but got none", test.base, test.input)
+			}
+		})
+	}
+}
+
+func TestMod_CrossCheck(t *testing.T) {
+	plain, _ := NewModFSM(7, 10)
+	checked, _ := NewModFSM(7, 10, CrossCheck())
+	for _, m := range []*ModFSM{plain, checked} {
+		m.remainder[stateFor(3)] = 4
+	}
+
+	if _, err := plain.Mod("3"); err != nil {
+		t.Errorf("Expected no cross-check without the option, got %v", err)
+	}
+	if _, err := checked.Mod("3"); err == nil || !strings.Contains(err.Error(), "mismatch") {
+		t.Errorf("Expected a mismatch error, got %v", err)
+	}
+}
+
+func TestMod_TwosComplement(t *testing.T) {
+	tests := []struct {
+		modulus           int
+		input             string
+		expectedRemainder int
+	}{
+		{3, "0101", 2},
+		{3, "1111", 2},
+		{3, "1011", 1},
+		{3, "1000", 1},
+		{5, "11111011", 0},
+		{7, "10000000", 5},
+		{2, "1", 1},
+		{1, "1010", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("mod%d_%s", test.modulus, test.input), func(t *testing.T) {
+			m, err := NewModFSM(test.modulus, 2, TwosComplement())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			result, err := m.Mod(test.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Remainder != test.expectedRemainder {
+				t.Errorf("Expected remainder %d, got %d", test.expectedRemainder, result.Remainder)
+			}
+			if result.FinalState != stateFor(test.expectedRemainder) {
+				t.Errorf("Expected final state %s, got %s", stateFor(test.expectedRemainder), result.FinalState)
+			}
+		})
+	}
+}
+
+func TestMod_TwosComplementExhaustive(t *testing.T) {
+	for modulus := 1; modulus <= 9; modulus++ {
+		m, _ := NewModFSM(modulus, 2, TwosComplement())
+		for width := 1; width <= 8; width++ {
+			for value := -(1 << (width - 1)); value < 1<<(width-1); value++ {
+				input := fmt.Sprintf("%0*b", width, uint64(value)&(1<<width-1))
+				result, err := m.Mod(input)
+				if err != nil {
+					t.Fatalf("Unexpected error for %s: %v", input, err)
+				}
+				expected := ((value % modulus) + modulus) % modulus
+				if result.Remainder != expected {
+					t.Errorf("Mod %d of %s (%d): expected %d, got %d", modulus, input, value, expected, result.Remainder)
+				}
+			}
+		}
+	}
+}
+
+func TestNewModFSM_TwosComplementRequiresBinary(t *testing.T) {
+	if _, err := NewModFSM(3, 10, TwosComplement()); err == nil {
+		t.Error("Expected error for two's complement in base 10, but got none")
+	}
+
+	m, _ := NewModFSM(3, 2, TwosComplement())
+	if !m.Signed() {
+		t.Error("Expected machine to be signed")
+	}
+}
+
+func TestMod_LSBFirst(t *testing.T) {
+	tests := []struct {
+		modulus        int
+		base           int
+		expectedStates int
+	}{
+		{3, 2, 6},
+		{5, 2, 20},
+		{7, 10, 42},
+		{4, 2, 7},
+		{1, 2, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("mod%d_base%d", test.modulus, test.base), func(t *testing.T) {
+			lsb, err := NewModFSM(test.modulus, test.base, LSBFirst())
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			msb, _ := NewModFSM(test.modulus, test.base)
+
+			if states := len(lsb.GetAutomaton().GetStates()); states != test.expectedStates {
+				t.Errorf("Expected %d states, got %d", test.expectedStates, states)
+			}
+
+			for value := 0; value < 500; value++ {
+				input := strconv.FormatInt(int64(value), test.base)
+				expected, err := msb.Mod(input)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+
+				result, err := lsb.Mod(reverse(input))
+				if err != nil {
+					t.Fatalf("Unexpected error for %s: %v", reverse(input), err)
+				}
+				if result.Remainder != expected.Remainder {
+					t.Errorf("For LSB-first input %s: expected %d, got %d", reverse(input), expected.Remainder, result.Remainder)
+				}
+			}
+		})
+	}
+}
+
+func TestMod_LSBFirstExamples(t *testing.T) {
+	m, _ := NewModFSM(3, 2, LSBFirst())
+
+	tests := []struct {
+		input             string
+		expectedRemainder int
+		expectedState     fsm.State
+	}{
+		{"1011", 1, "S1_1"},
+		{"0111", 2, "S2_1"},
+		{"1111", 0, "S0_1"},
+		{"011", 0, "S0_2"},
+	}
+
+	for _, test := range tests {
+		result, err := m.Mod(test.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Remainder != test.expectedRemainder || result.FinalState != test.expectedState {
+			t.Errorf("For input %s: expected %d (%s), got %d (%s)",
+				test.input, test.expectedRemainder, test.expectedState, result.Remainder, result.FinalState)
+		}
+	}
+
+	if _, err := NewModFSM(3, 2, LSBFirst(), TwosComplement()); err == nil {
+		t.Error("Expected error combining LSB-first and two's complement, but got none")
+	}
+}
+
+func TestStateToRemainder(t *testing.T) {
+	m, _ := NewModFSM(5, 2)
+
+	tests := []struct {
+		state             fsm.State
+		expectedRemainder int
+	}{
+		{"S0", 0},
+		{"S4", 4},
+		{"S5", -1},
+		{"invalid", -1},
+	}
+
+	for _, test := range tests {
+		if remainder := m.stateToRemainder(test.state); remainder != test.expectedRemainder {
+			t.Errorf("For state %s: expected remainder %d, got %d", test.state, test.expectedRemainder, remainder)
+		}
+	}
+}
+
+func TestMod_LongBinaryInputUsesChunks(t *testing.T) {
+	for name, options := range map[string][]Option{
+		"msb_first":       nil,
+		"lsb_first":       {LSBFirst()},
+		"twos_complement": {TwosComplement()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			report, err := Differential(DifferentialConfig{
+				Moduli:    []int{3, 7, 10, 97},
+				Bases:     []int{2},
+				MinLength: chunkThreshold - 1,
+				MaxLength: chunkThreshold + 17,
+				Samples:   5,
+				Options:   options,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !report.OK() {
+				t.Errorf("Unexpected mismatches:\n%s", report)
+			}
+
+			m, err := NewModFSM(7, 2, options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, err := m.Mod(strings.Repeat("1101", 50)); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if m.chunker == nil {
+				t.Error("Expected a long base-2 input to build the chunk tables")
+			}
+		})
+	}
+}
+
+func TestMod_LongBinaryInputLogsTransitions(t *testing.T) {
+	m, err := NewModFSM(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var logs bytes.Buffer
+	m.GetAutomaton().Logger = slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	input := strings.Repeat("1101", chunkThreshold/4)
+	result, err := m.Mod(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	value, _ := new(big.Int).SetString(input, 2)
+	if expected := int(new(big.Int).Mod(value, big.NewInt(3)).Int64()); result.Remainder != expected {
+		t.Errorf("Expected remainder %d, got %d", expected, result.Remainder)
+	}
+	if lines := strings.Count(logs.String(), "\n"); lines < len(input) {
+		t.Errorf("Expected a debug record per digit of a %d-digit input, got %d lines", len(input), lines)
+	}
+	if m.chunker != nil {
+		t.Error("Expected debug logging to bypass the chunk tables")
+	}
+}
+
+func BenchmarkMod(b *testing.B) {
+	for _, base := range []int{2, 10} {
+		m, _ := NewModFSM(7, base)
+		input := strings.Repeat("1011", 1<<18)
+		b.Run(fmt.Sprintf("base%d", base), func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				if _, err := m.Mod(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package monitor
+
+import (
+	"sort"
+	"strings"
+)
+
+type kind int
+
+const (
+	kindTrue kind = iota
+	kindFalse
+	kindEvent
+	kindNot
+	kindAnd
+	kindOr
+	kindNext
+	kindAlways
+	kindEventually
+	kindUntil
+)
+
+// formula is kept in a normal form so that equal progressions get equal
+// names: conjunctions and disjunctions are flattened, sorted and deduplicated,
+// and constants are folded away.
+type formula struct {
+	kind     kind
+	event    string
+	children []*formula
+	name     string
+}
+
+var (
+	formulaTrue  = &formula{kind: kindTrue, name: "true"}
+	formulaFalse = &formula{kind: kindFalse, name: "false"}
+)
+
+func event(name string) *formula {
+	return &formula{kind: kindEvent, event: name, name: name}
+}
+
+func unary(k kind, operand *formula) *formula {
+	keyword := map[kind]string{kindNot: "not", kindNext: "next", kindAlways: "always", kindEventually: "eventually"}[k]
+	return &formula{kind: k, children: []*formula{operand}, name: keyword + " " + operand.operandName()}
+}
+
+func not(operand *formula) *formula {
+	switch operand.kind {
+	case kindTrue:
+		return formulaFalse
+	case kindFalse:
+		return formulaTrue
+	case kindNot:
+		return operand.children[0]
+	}
+	return unary(kindNot, operand)
+}
+
+func until(hold, goal *formula) *formula {
+	switch {
+	case goal.kind == kindTrue || goal.kind == kindFalse || hold.kind == kindFalse:
+		return goal
+	case hold.kind == kindTrue:
+		return unary(kindEventually, goal)
+	}
+	return &formula{kind: kindUntil, children: []*formula{hold, goal}, name: hold.operandName() + " until " + goal.operandName()}
+}
+
+func and(operands ...*formula) *formula {
+	return junction(kindAnd, formulaTrue, formulaFalse, " and ", operands)
+}
+
+func or(operands ...*formula) *formula {
+	return junction(kindOr, formulaFalse, formulaTrue, " or ", operands)
+}
+
+// junction builds an n-ary and/or: identity operands are dropped and any
+// absorbing operand absorbs the whole junction.
+func junction(k kind, identity, absorbing *formula, separator string, operands []*formula) *formula {
+	byName := map[string]*formula{}
+	var flatten func(operands []*formula) bool
+	flatten = func(operands []*formula) bool {
+		for _, operand := range operands {
+			switch {
+			case operand.kind == absorbing.kind:
+				return true
+			case operand.kind == identity.kind:
+			case operand.kind == k:
+				if flatten(operand.children) {
+					return true
+				}
+			default:
+				byName[operand.name] = operand
+			}
+		}
+		return false
+	}
+	if flatten(operands) {
+		return absorbing
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if byName["not "+byName[name].operandName()] != nil {
+			return absorbing
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return identity
+	case 1:
+		return byName[names[0]]
+	}
+	children := make([]*formula, len(names))
+	parts := make([]string, len(names))
+	for i, name := range names {
+		children[i] = byName[name]
+		parts[i] = children[i].operandName()
+	}
+	return &formula{kind: k, children: children, name: strings.Join(parts, separator)}
+}
+
+func (f *formula) operandName() string {
+	switch f.kind {
+	case kindTrue, kindFalse, kindEvent:
+		return f.name
+	}
+	return "(" + f.name + ")"
+}
+
+// progress rewrites f into the formula the rest of the trace must satisfy
+// after observing e.
+func (f *formula) progress(e string) *formula {
+	switch f.kind {
+	case kindEvent:
+		if f.event == e {
+			return formulaTrue
+		}
+		return formulaFalse
+	case kindNot:
+		return not(f.children[0].progress(e))
+	case kindAnd, kindOr:
+		operands := make([]*formula, len(f.children))
+		for i, child := range f.children {
+			operands[i] = child.progress(e)
+		}
+		if f.kind == kindAnd {
+			return and(operands...)
+		}
+		return or(operands...)
+	case kindNext:
+		return f.children[0]
+	case kindAlways:
+		return and(f.children[0].progress(e), f)
+	case kindEventually:
+		return or(f.children[0].progress(e), f)
+	case kindUntil:
+		return or(f.children[1].progress(e), and(f.children[0].progress(e), f))
+	}
+	return f
+}
+
+func (f *formula) events(into map[string]bool) {
+	if f.kind == kindEvent {
+		into[f.event] = true
+	}
+	for _, child := range f.children {
+		child.events(into)
+	}
+}
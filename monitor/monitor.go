@@ -0,0 +1,371 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+const (
+	maxSourceLength = 1024
+	maxDepth        = 64
+	maxStates       = 4096
+)
+
+// Other stands for every event the property does not mention.
+const Other fsm.Symbol = "*"
+
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+type Verdict int
+
+const (
+	Inconclusive Verdict = iota
+	Satisfied
+	Violated
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Satisfied:
+		return "satisfied"
+	case Violated:
+		return "violated"
+	}
+	return "inconclusive"
+}
+
+// Property is a compiled property over a stream of named events, one event
+// per step. Its automaton has a state for each formula that remains to be
+// satisfied; the states true and false are the final verdicts.
+type Property struct {
+	source    string
+	automaton *fsm.FiniteAutomaton
+}
+
+// Compile parses a property such as "always (request -> eventually grant)"
+// and builds its monitoring automaton by formula progression. Operators are
+// always, eventually, next, until, not (!), and (&&), or (||) and ->.
+func Compile(source string) (*Property, error) {
+	if len(source) > maxSourceLength {
+		return nil, &Error{Pos: 0, Msg: fmt.Sprintf("property longer than %d bytes", maxSourceLength)}
+	}
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseImplies(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+	}
+
+	automaton, err := build(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Property{source: source, automaton: automaton}, nil
+}
+
+func build(root *formula) (*fsm.FiniteAutomaton, error) {
+	mentioned := map[string]bool{}
+	root.events(mentioned)
+	alphabet := make([]fsm.Symbol, 0, len(mentioned)+1)
+	for name := range mentioned {
+		alphabet = append(alphabet, fsm.Symbol(name))
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	alphabet = append(alphabet, Other)
+
+	formulas := map[fsm.State]*formula{fsm.State(root.name): root}
+	states := []fsm.State{fsm.State(root.name)}
+	table := fsm.TransitionTable{}
+	for i := 0; i < len(states); i++ {
+		if len(states) > maxStates {
+			return nil, fmt.Errorf("property needs more than %d monitor states", maxStates)
+		}
+		current := formulas[states[i]]
+		for _, symbol := range alphabet {
+			next := current.progress(string(symbol))
+			name := fsm.State(next.name)
+			if _, seen := formulas[name]; !seen {
+				formulas[name] = next
+				states = append(states, name)
+			}
+			table.Set(states[i], symbol, name)
+		}
+	}
+
+	var accepting []fsm.State
+	if _, ok := formulas["true"]; ok {
+		accepting = append(accepting, "true")
+	}
+	return fsm.NewTableAutomaton(states, alphabet, states[0], accepting, table), nil
+}
+
+func (p *Property) String() string {
+	return p.source
+}
+
+// Automaton returns the monitoring automaton. Events the property does not
+// mention are the symbol Other.
+func (p *Property) Automaton() *fsm.FiniteAutomaton {
+	return p.automaton
+}
+
+func (p *Property) Monitor() *Monitor {
+	return &Monitor{property: p, state: p.automaton.InitialState}
+}
+
+// Monitor tracks one event stream against a property. Verdicts are final:
+// once satisfied or violated, later events do not change them.
+type Monitor struct {
+	property *Property
+	state    fsm.State
+}
+
+func (m *Monitor) Observe(event string) Verdict {
+	symbol := fsm.Symbol(event)
+	if next, ok := m.property.automaton.Table.Lookup(m.state, symbol); ok {
+		m.state = next
+	} else {
+		m.state = m.property.automaton.Table.Next(m.state, Other)
+	}
+	return m.Verdict()
+}
+
+func (m *Monitor) Verdict() Verdict {
+	switch m.state {
+	case "true":
+		return Satisfied
+	case "false":
+		return Violated
+	}
+	return Inconclusive
+}
+
+// Pending is the formula the rest of the stream must still satisfy.
+func (m *Monitor) Pending() string {
+	return string(m.state)
+}
+
+func (m *Monitor) Reset() {
+	m.state = m.property.automaton.InitialState
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	pos  int
+	text string
+}
+
+var keywords = map[string]string{
+	"not": "!", "and": "&&", "or": "||",
+	"always": "always", "eventually": "eventually", "next": "next", "until": "until",
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || c >= '0' && c <= '9' || c == '.' || c == '-' || c == ':' || c == '/'
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(source[i:], "->"):
+			tokens = append(tokens, token{kind: tokenOperator, pos: i, text: "->"})
+			i += 2
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && isIdentPart(source[i]) && !strings.HasPrefix(source[i:], "->") {
+				i++
+			}
+			word := source[start:i]
+			if operator, ok := keywords[word]; ok {
+				tokens = append(tokens, token{kind: tokenOperator, pos: start, text: operator})
+			} else {
+				tokens = append(tokens, token{kind: tokenIdent, pos: start, text: word})
+			}
+		default:
+			operator := ""
+			for _, candidate := range []string{"&&", "||", "!", "(", ")"} {
+				if strings.HasPrefix(source[i:], candidate) {
+					operator = candidate
+					break
+				}
+			}
+			if operator == "" {
+				return nil, &Error{Pos: i, Msg: fmt.Sprintf("unexpected character %q", c)}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, pos: i, text: operator})
+			i += len(operator)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(source), text: "end of property"}), nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(operator string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == operator {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) checkDepth(depth int) error {
+	if depth > maxDepth {
+		return &Error{Pos: p.peek().pos, Msg: fmt.Sprintf("property nested deeper than %d levels", maxDepth)}
+	}
+	return nil
+}
+
+// parseImplies parses the lowest-precedence level; -> and until associate to
+// the right, and and or to the left.
+func (p *parser) parseImplies(depth int) (*formula, error) {
+	if err := p.checkDepth(depth); err != nil {
+		return nil, err
+	}
+	left, err := p.parseOr(depth)
+	if err != nil || !p.accept("->") {
+		return left, err
+	}
+	right, err := p.parseImplies(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	return or(not(left), right), nil
+}
+
+func (p *parser) parseOr(depth int) (*formula, error) {
+	left, err := p.parseAnd(depth)
+	for err == nil && p.accept("||") {
+		var right *formula
+		if right, err = p.parseAnd(depth); err == nil {
+			left = or(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd(depth int) (*formula, error) {
+	left, err := p.parseUntil(depth)
+	for err == nil && p.accept("&&") {
+		var right *formula
+		if right, err = p.parseUntil(depth); err == nil {
+			left = and(left, right)
+		}
+	}
+	return left, err
+}
+
+func (p *parser) parseUntil(depth int) (*formula, error) {
+	left, err := p.parseUnary(depth)
+	if err != nil || !p.accept("until") {
+		return left, err
+	}
+	if err := p.checkDepth(depth + 1); err != nil {
+		return nil, err
+	}
+	right, err := p.parseUntil(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	return until(left, right), nil
+}
+
+func (p *parser) parseUnary(depth int) (*formula, error) {
+	if err := p.checkDepth(depth); err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	constructors := map[string]func(*formula) *formula{
+		"!":          not,
+		"next":       func(f *formula) *formula { return unaryUnlessConstant(kindNext, f) },
+		"always":     func(f *formula) *formula { return unaryUnlessConstant(kindAlways, f) },
+		"eventually": func(f *formula) *formula { return unaryUnlessConstant(kindEventually, f) },
+	}
+	if construct, ok := constructors[t.text]; ok && t.kind == tokenOperator {
+		p.next()
+		operand, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return construct(operand), nil
+	}
+	return p.parsePrimary(depth)
+}
+
+func unaryUnlessConstant(k kind, operand *formula) *formula {
+	if operand.kind == kindTrue || operand.kind == kindFalse {
+		return operand
+	}
+	return unary(k, operand)
+}
+
+func (p *parser) parsePrimary(depth int) (*formula, error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenIdent && t.text == "true":
+		return formulaTrue, nil
+	case t.kind == tokenIdent && t.text == "false":
+		return formulaFalse, nil
+	case t.kind == tokenIdent:
+		return event(t.text), nil
+	case t.kind == tokenOperator && t.text == "(":
+		inner, err := p.parseImplies(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenOperator || closing.text != ")" {
+			return nil, &Error{Pos: closing.pos, Msg: fmt.Sprintf("expected \")\", got %q", closing.text)}
+		}
+		return inner, nil
+	}
+	return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+)
+
+func observeAll(t *testing.T, property string, events ...string) Verdict {
+	t.Helper()
+
+	p, err := Compile(property)
+	if err != nil {
+		t.Fatalf("Unexpected error compiling %q: %v", property, err)
+	}
+	m := p.Monitor()
+	for _, event := range events {
+		m.Observe(event)
+	}
+	return m.Verdict()
+}
+
+func TestMonitor_Verdicts(t *testing.T) {
+	tests := []struct {
+		property string
+		events   []string
+		expected Verdict
+	}{
+		{"always !error", []string{"start", "tick", "tick"}, Inconclusive},
+		{"always !error", []string{"start", "error", "tick"}, Violated},
+		{"eventually done", []string{"start", "tick"}, Inconclusive},
+		{"eventually done", []string{"start", "done", "error"}, Satisfied},
+		{"login until logout", []string{"login", "login"}, Inconclusive},
+		{"login until logout", []string{"login", "logout"}, Satisfied},
+		{"login until logout", []string{"login", "browse"}, Violated},
+		{"next ack", []string{"syn", "ack"}, Satisfied},
+		{"next ack", []string{"syn", "syn"}, Violated},
+		{"always (request -> next grant)", []string{"request", "grant", "idle"}, Inconclusive},
+		{"always (request -> next grant)", []string{"request", "idle"}, Violated},
+		{"open -> (!close until ready)", []string{"open", "ready", "close"}, Satisfied},
+		{"open -> (!close until ready)", []string{"open", "close"}, Violated},
+		{"!open || eventually close", []string{"idle"}, Satisfied},
+		{"start and next (a or b)", []string{"start", "b"}, Satisfied},
+		{"true", nil, Satisfied},
+		{"always false", nil, Violated},
+	}
+
+	for _, test := range tests {
+		if verdict := observeAll(t, test.property, test.events...); verdict != test.expected {
+			t.Errorf("For %q on %v: expected %s, got %s", test.property, test.events, test.expected, verdict)
+		}
+	}
+}
+
+func TestMonitor_FinalVerdictsStick(t *testing.T) {
+	p, err := Compile("always (request -> eventually grant)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := p.Monitor()
+
+	for _, event := range []string{"request", "idle", "grant", "request"} {
+		if verdict := m.Observe(event); verdict != Inconclusive {
+			t.Errorf("Expected a liveness property to stay inconclusive, got %s", verdict)
+		}
+	}
+	if m.Pending() != "(always ((eventually grant) or (not request))) and (eventually grant)" {
+		t.Errorf("Unexpected pending formula %q", m.Pending())
+	}
+
+	m.Reset()
+	if m.Pending() != string(p.Automaton().InitialState) {
+		t.Errorf("Expected reset to return to %s, got %s", p.Automaton().InitialState, m.Pending())
+	}
+
+	bounded, _ := Compile("eventually done")
+	m = bounded.Monitor()
+	m.Observe("done")
+	if verdict := m.Observe("anything"); verdict != Satisfied {
+		t.Errorf("Expected satisfied to be final, got %s", verdict)
+	}
+}
+
+func TestProperty_Automaton(t *testing.T) {
+	p, err := Compile("always (request -> eventually grant)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fa := p.Automaton()
+	if len(fa.States) != 2 {
+		t.Errorf("Expected 2 monitor states, got %v", fa.States)
+	}
+	if len(fa.Alphabet) != 3 || fa.Alphabet[2] != Other {
+		t.Errorf("Expected alphabet [grant request *], got %v", fa.Alphabet)
+	}
+
+	accepted, err := fa.Accepts("*")
+	if err != nil || accepted {
+		t.Errorf("Expected no accepting state for a liveness property, got %v (%v)", accepted, err)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		property string
+		pos      int
+	}{
+		{"", 0},
+		{"always", 6},
+		{"(a until b", 10},
+		{"a and and b", 6},
+		{"a # b", 2},
+		{"a b", 2},
+	}
+
+	for _, test := range tests {
+		_, err := Compile(test.property)
+		var parseErr *Error
+		if !errors.As(err, &parseErr) {
+			t.Errorf("For %q: expected *Error, got %v", test.property, err)
+			continue
+		}
+		if parseErr.Pos != test.pos {
+			t.Errorf("For %q: expected error at position %d, got %v", test.property, test.pos, parseErr)
+		}
+	}
+}
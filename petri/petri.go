@@ -0,0 +1,253 @@
+package petri
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fsm-modulo-three/fsm"
+)
+
+const maxMarkings = 100000
+
+// DeadState is the sink that Reachability moves to when a transition fires
+// while it is not enabled.
+const DeadState fsm.State = "Dead"
+
+type Place string
+
+// Marking counts the tokens on each place; places that are absent hold none.
+type Marking map[Place]int
+
+// String lists the marked places in name order, e.g. {buffer:2,idle:1}.
+func (m Marking) String() string {
+	places := make([]string, 0, len(m))
+	for place, tokens := range m {
+		if tokens != 0 {
+			places = append(places, string(place))
+		}
+	}
+	sort.Strings(places)
+
+	parts := make([]string, len(places))
+	for i, place := range places {
+		parts[i] = fmt.Sprintf("%s:%d", place, m[Place(place)])
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// clone copies m without the places that hold no tokens.
+func (m Marking) clone() Marking {
+	copied := make(Marking, len(m))
+	for place, tokens := range m {
+		if tokens != 0 {
+			copied[place] = tokens
+		}
+	}
+	return copied
+}
+
+// Transition consumes Inputs and produces Outputs, as arc weights per place.
+type Transition struct {
+	Name    string
+	Inputs  map[Place]int
+	Outputs map[Place]int
+}
+
+type Net struct {
+	Places      []Place
+	Transitions []Transition
+
+	byName map[string]int
+}
+
+func New(places []Place, transitions []Transition) (*Net, error) {
+	known := make(map[Place]bool, len(places))
+	for _, place := range places {
+		if known[place] {
+			return nil, fmt.Errorf("duplicate place '%s'", place)
+		}
+		known[place] = true
+	}
+
+	byName := make(map[string]int, len(transitions))
+	for i, transition := range transitions {
+		if transition.Name == "" {
+			return nil, fmt.Errorf("transition %d has no name", i)
+		}
+		if _, ok := byName[transition.Name]; ok {
+			return nil, fmt.Errorf("duplicate transition '%s'", transition.Name)
+		}
+		byName[transition.Name] = i
+
+		for _, arcs := range []map[Place]int{transition.Inputs, transition.Outputs} {
+			for place, weight := range arcs {
+				if !known[place] {
+					return nil, fmt.Errorf("transition '%s' refers to undeclared place '%s'", transition.Name, place)
+				}
+				if weight < 1 {
+					return nil, fmt.Errorf("transition '%s' has weight %d on place '%s'", transition.Name, weight, place)
+				}
+			}
+		}
+	}
+
+	return &Net{Places: places, Transitions: transitions, byName: byName}, nil
+}
+
+func (n *Net) transition(name string) (Transition, error) {
+	i, ok := n.byName[name]
+	if !ok {
+		return Transition{}, fmt.Errorf("unknown transition '%s'", name)
+	}
+	return n.Transitions[i], nil
+}
+
+func (n *Net) Enabled(m Marking, name string) (bool, error) {
+	transition, err := n.transition(name)
+	if err != nil {
+		return false, err
+	}
+	return enabled(transition, m), nil
+}
+
+func enabled(transition Transition, m Marking) bool {
+	for place, weight := range transition.Inputs {
+		if m[place] < weight {
+			return false
+		}
+	}
+	return true
+}
+
+// EnabledTransitions returns the names of the transitions enabled in m, in
+// declaration order.
+func (n *Net) EnabledTransitions(m Marking) []string {
+	var names []string
+	for _, transition := range n.Transitions {
+		if enabled(transition, m) {
+			names = append(names, transition.Name)
+		}
+	}
+	return names
+}
+
+// Fire returns the marking after firing the named transition. m is left
+// unchanged.
+func (n *Net) Fire(m Marking, name string) (Marking, error) {
+	transition, err := n.transition(name)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled(transition, m) {
+		return nil, fmt.Errorf("transition '%s' is not enabled in marking %s", name, m)
+	}
+	return fire(transition, m), nil
+}
+
+func fire(transition Transition, m Marking) Marking {
+	next := m.clone()
+	for place, weight := range transition.Inputs {
+		next[place] -= weight
+	}
+	for place, weight := range transition.Outputs {
+		next[place] += weight
+	}
+	for place, tokens := range next {
+		if tokens == 0 {
+			delete(next, place)
+		}
+	}
+	return next
+}
+
+// covers reports whether m has at least as many tokens as other everywhere
+// and more somewhere.
+func covers(m, other Marking) bool {
+	strict := false
+	for place, tokens := range other {
+		if m[place] < tokens {
+			return false
+		}
+	}
+	for place, tokens := range m {
+		if tokens > other[place] {
+			strict = true
+		}
+	}
+	return strict
+}
+
+// Reachability builds the reachability graph of a bounded net as a DFA. Its
+// states are the reachable markings, named by Marking.String, and its symbols
+// are the transition names; firing a disabled transition moves to DeadState.
+// Markings are accepting if accepting returns true, or all of them if it is
+// nil, in which case the DFA accepts exactly the firing sequences of the net.
+// An unbounded net is reported as an error, detected when a marking covers
+// one of the markings it was reached from.
+func (n *Net) Reachability(initial Marking, accepting func(Marking) bool) (*fsm.FiniteAutomaton, error) {
+	for place, tokens := range initial {
+		if tokens < 0 {
+			return nil, fmt.Errorf("initial marking has %d tokens on place '%s'", tokens, place)
+		}
+	}
+
+	alphabet := make([]fsm.Symbol, len(n.Transitions))
+	for i, transition := range n.Transitions {
+		alphabet[i] = fsm.Symbol(transition.Name)
+	}
+
+	start := initial.clone()
+	markings := map[fsm.State]Marking{fsm.State(start.String()): start}
+	parent := map[fsm.State]fsm.State{}
+	states := []fsm.State{fsm.State(start.String())}
+	table := fsm.TransitionTable{}
+	dead := false
+
+	for i := 0; i < len(states); i++ {
+		state := states[i]
+		for _, transition := range n.Transitions {
+			symbol := fsm.Symbol(transition.Name)
+			if !enabled(transition, markings[state]) {
+				table.Set(state, symbol, DeadState)
+				dead = true
+				continue
+			}
+
+			next := fire(transition, markings[state])
+			name := fsm.State(next.String())
+			table.Set(state, symbol, name)
+			if _, seen := markings[name]; seen {
+				continue
+			}
+
+			for ancestor := state; ; ancestor = parent[ancestor] {
+				if covers(next, markings[ancestor]) {
+					return nil, fmt.Errorf("net is unbounded: %s is reachable from %s and covers it", next, markings[ancestor])
+				}
+				if ancestor == states[0] {
+					break
+				}
+			}
+			if len(states) >= maxMarkings {
+				return nil, fmt.Errorf("net has more than %d reachable markings", maxMarkings)
+			}
+			markings[name] = next
+			parent[name] = state
+			states = append(states, name)
+		}
+	}
+
+	var acceptingStates []fsm.State
+	for _, state := range states {
+		if accepting == nil || accepting(markings[state]) {
+			acceptingStates = append(acceptingStates, state)
+		}
+	}
+	if dead {
+		states = append(states, DeadState)
+		table.SetDefault(DeadState, DeadState)
+	}
+
+	return fsm.NewTableAutomaton(states, alphabet, states[0], acceptingStates, table), nil
+}
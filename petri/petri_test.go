@@ -0,0 +1,136 @@
+package petri
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/fsmtest"
+)
+
+// newProducerConsumer has a producer and a consumer sharing a buffer with
+// capacity free slots.
+func newProducerConsumer(t *testing.T) *Net {
+	t.Helper()
+
+	net, err := New(
+		[]Place{"ready", "buffer", "free"},
+		[]Transition{
+			{Name: "p", Inputs: map[Place]int{"ready": 1, "free": 1}, Outputs: map[Place]int{"ready": 1, "buffer": 1}},
+			{Name: "c", Inputs: map[Place]int{"buffer": 1}, Outputs: map[Place]int{"free": 1}},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return net
+}
+
+func TestNet_Fire(t *testing.T) {
+	net := newProducerConsumer(t)
+	initial := Marking{"ready": 1, "free": 2}
+
+	next, err := net.Fire(initial, "p")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := (Marking{"ready": 1, "free": 1, "buffer": 1}); !reflect.DeepEqual(next, expected) {
+		t.Errorf("Expected %s, got %s", expected, next)
+	}
+	if initial["free"] != 2 {
+		t.Errorf("Expected Fire to leave the marking unchanged, got %s", initial)
+	}
+	if next.String() != "{buffer:1,free:1,ready:1}" {
+		t.Errorf("Unexpected marking string %s", next)
+	}
+
+	if _, err := net.Fire(initial, "c"); err == nil {
+		t.Error("Expected error firing a disabled transition, but got none")
+	}
+	if _, err := net.Fire(initial, "x"); err == nil {
+		t.Error("Expected error firing an unknown transition, but got none")
+	}
+	if enabled := net.EnabledTransitions(next); !reflect.DeepEqual(enabled, []string{"p", "c"}) {
+		t.Errorf("Expected [p c] enabled, got %v", enabled)
+	}
+	if ok, err := net.Enabled(initial, "c"); ok || err != nil {
+		t.Errorf("Expected c disabled, got %v (%v)", ok, err)
+	}
+}
+
+func TestNet_Reachability(t *testing.T) {
+	net := newProducerConsumer(t)
+
+	fa, err := net.Reachability(Marking{"ready": 1, "free": 2}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(fa.States) != 4 {
+		t.Errorf("Expected 3 markings and Dead, got %v", fa.States)
+	}
+
+	// Firing sequences are exactly those that never overfill or underflow the buffer.
+	for _, input := range fsm.SeedCorpus(fa.Alphabet, 8) {
+		fill, valid := 0, true
+		for _, transition := range input {
+			if transition == 'p' {
+				fill++
+			} else {
+				fill--
+			}
+			valid = valid && fill >= 0 && fill <= 2
+		}
+		accepted, err := fa.Accepts(input)
+		if err != nil {
+			t.Fatalf("Unexpected error for input '%s': %v", input, err)
+		}
+		if accepted != valid {
+			t.Errorf("For input '%s': expected accepted=%v, got %v", input, valid, accepted)
+		}
+	}
+
+	full, err := net.Reachability(Marking{"ready": 1, "free": 2}, func(m Marking) bool { return m["free"] == 0 })
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	fsmtest.AssertAccepts(t, full, "pp", "pcpp")
+	fsmtest.AssertRejects(t, full, "", "p", "ppc")
+}
+
+func TestNet_ReachabilityUnbounded(t *testing.T) {
+	net, err := New(
+		[]Place{"ready", "buffer"},
+		[]Transition{{Name: "p", Inputs: map[Place]int{"ready": 1}, Outputs: map[Place]int{"ready": 1, "buffer": 1}}},
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	_, err = net.Reachability(Marking{"ready": 1}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unbounded") {
+		t.Errorf("Expected unbounded net error, got %v", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		description string
+		places      []Place
+		transitions []Transition
+	}{
+		{"duplicate place", []Place{"a", "a"}, nil},
+		{"unnamed transition", []Place{"a"}, []Transition{{}}},
+		{"duplicate transition", []Place{"a"}, []Transition{{Name: "t"}, {Name: "t"}}},
+		{"undeclared place", []Place{"a"}, []Transition{{Name: "t", Inputs: map[Place]int{"b": 1}}}},
+		{"zero weight", []Place{"a"}, []Transition{{Name: "t", Outputs: map[Place]int{"a": 0}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if _, err := New(test.places, test.transitions); err == nil {
+				t.Errorf("Expected error for %s, but got none", test.description)
+			}
+		})
+	}
+}
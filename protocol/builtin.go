@@ -0,0 +1,48 @@
+package protocol
+
+import "fsm-modulo-three/fsm"
+
+func SMTP() *Protocol {
+	return must(New("SMTP", "Connected", []fsm.State{"Closed"}, []Rule{
+		{"Connected", "HELO", "Greeted"},
+		{"Connected", "EHLO", "Greeted"},
+		{"Connected", "QUIT", "Closed"},
+		{"Greeted", "MAIL", "Sender"},
+		{"Greeted", "RSET", "Greeted"},
+		{"Greeted", "QUIT", "Closed"},
+		{"Sender", "RCPT", "Recipients"},
+		{"Sender", "RSET", "Greeted"},
+		{"Sender", "QUIT", "Closed"},
+		{"Recipients", "RCPT", "Recipients"},
+		{"Recipients", "DATA", "Data"},
+		{"Recipients", "RSET", "Greeted"},
+		{"Recipients", "QUIT", "Closed"},
+		{"Data", "END", "Greeted"},
+	}))
+}
+
+func TCPHandshake() *Protocol {
+	return must(New("TCP", "Listen", []fsm.State{"Closed"}, []Rule{
+		{"Listen", "SYN", "SynReceived"},
+		{"SynReceived", "SYN-ACK", "SynAckSent"},
+		{"SynReceived", "RST", "Closed"},
+		{"SynAckSent", "ACK", "Established"},
+		{"SynAckSent", "RST", "Closed"},
+		{"Established", "DATA", "Established"},
+		{"Established", "FIN", "FinWait"},
+		{"Established", "RST", "Closed"},
+		{"FinWait", "ACK", "HalfClosed"},
+		{"FinWait", "RST", "Closed"},
+		{"HalfClosed", "DATA", "HalfClosed"},
+		{"HalfClosed", "FIN", "LastAck"},
+		{"HalfClosed", "RST", "Closed"},
+		{"LastAck", "ACK", "Closed"},
+	}))
+}
+
+func must(p *Protocol, err error) *Protocol {
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,223 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/session"
+)
+
+const DefaultSessionHeader = "X-Session-ID"
+
+type MiddlewareOption func(*middleware)
+
+// WithStore keeps session states in store instead of in memory. Only the
+// snapshot's State is used.
+func WithStore(store session.Store) MiddlewareOption {
+	return func(m *middleware) {
+		m.store = store
+	}
+}
+
+func WithSessionID(sessionID func(*http.Request) string) MiddlewareOption {
+	return func(m *middleware) {
+		m.sessionID = sessionID
+	}
+}
+
+func WithEvent(event func(*http.Request) Event) MiddlewareOption {
+	return func(m *middleware) {
+		m.event = event
+	}
+}
+
+// WithLogger reports session states that could not be saved after the
+// wrapped handler had already answered.
+func WithLogger(logger *slog.Logger) MiddlewareOption {
+	return func(m *middleware) {
+		m.logger = logger
+	}
+}
+
+// WithCommit decides from the wrapped handler's status code whether the
+// session advances. The default commits 2xx and 3xx responses only.
+func WithCommit(commit func(status int) bool) MiddlewareOption {
+	return func(m *middleware) {
+		m.commit = commit
+	}
+}
+
+type middleware struct {
+	protocol  *Protocol
+	next      http.Handler
+	store     session.Store
+	sessionID func(*http.Request) string
+	event     func(*http.Request) Event
+	commit    func(status int) bool
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+// sessionLock serializes the requests of one session. refs counts the
+// requests holding or waiting for it, so idle sessions leave no entry behind.
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+type rejection struct {
+	Error    string    `json:"error"`
+	State    fsm.State `json:"state,omitempty"`
+	Event    Event     `json:"event,omitempty"`
+	Expected []Event   `json:"expected,omitempty"`
+}
+
+func Middleware(protocol *Protocol, next http.Handler, options ...MiddlewareOption) http.Handler {
+	m := &middleware{
+		protocol: protocol,
+		next:     next,
+		store:    session.NewMemoryStore(),
+		locks:    make(map[string]*sessionLock),
+		sessionID: func(r *http.Request) string {
+			return r.Header.Get(DefaultSessionHeader)
+		},
+		event: func(r *http.Request) Event {
+			return Event(r.Method + " " + r.URL.Path)
+		},
+		commit: func(status int) bool {
+			return status < http.StatusBadRequest
+		},
+	}
+
+	for _, option := range options {
+		option(m)
+	}
+
+	return m
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	event := m.event(r)
+	if !m.protocol.Tracks(event) {
+		m.next.ServeHTTP(w, r)
+		return
+	}
+
+	id := m.sessionID(r)
+	if id == "" {
+		writeRejection(w, http.StatusBadRequest, rejection{Error: "missing session identifier"})
+		return
+	}
+
+	unlock := m.lock(id)
+	defer unlock()
+
+	next, err := m.advance(r.Context(), id, event)
+	if err != nil {
+		var sequenceErr *SequenceError
+		if errors.As(err, &sequenceErr) {
+			writeRejection(w, http.StatusConflict, rejection{
+				Error:    err.Error(),
+				State:    sequenceErr.State,
+				Event:    sequenceErr.Event,
+				Expected: sequenceErr.Expected,
+			})
+			return
+		}
+		writeRejection(w, http.StatusInternalServerError, rejection{Error: "session store: " + err.Error()})
+		return
+	}
+
+	// The transition is committed only once the handler has returned without
+	// panicking and with a status the commit predicate accepts, 2xx or 3xx by
+	// default, so a rejected or failed request can be retried.
+	recorder := &statusRecorder{ResponseWriter: w}
+	m.next.ServeHTTP(recorder, r)
+	status := recorder.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if !m.commit(status) {
+		return
+	}
+	if err := m.store.Put(context.WithoutCancel(r.Context()), id, fsm.Snapshot{State: next}); err != nil && m.logger != nil {
+		m.logger.LogAttrs(r.Context(), slog.LevelError, "saving protocol session",
+			slog.String("session", id),
+			slog.String("state", string(next)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// lock holds the session's lock from reading its state until the new state
+// is saved. Requests for other sessions, and their store I/O, run in
+// parallel. The lock is per process: instances sharing a store do not
+// serialize against each other.
+func (m *middleware) lock(id string) (unlock func()) {
+	m.mu.Lock()
+	l := m.locks[id]
+	if l == nil {
+		l = &sessionLock{}
+		m.locks[id] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		m.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(m.locks, id)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// advance returns the state event leads the session to, without saving it.
+func (m *middleware) advance(ctx context.Context, id string, event Event) (fsm.State, error) {
+	snapshot, ok, err := m.store.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		snapshot.State = m.protocol.Initial()
+	}
+	return m.protocol.Next(snapshot.State, event)
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func writeRejection(w http.ResponseWriter, status int, body rejection) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,266 @@
+package protocol
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/session"
+)
+
+func newCheckoutProtocol(t *testing.T) *Protocol {
+	t.Helper()
+
+	p, err := New("checkout", "Browsing", []fsm.State{"Paid"}, []Rule{
+		{"Browsing", "POST /cart", "Cart"},
+		{"Cart", "POST /cart", "Cart"},
+		{"Cart", "POST /checkout", "Checkout"},
+		{"Checkout", "POST /pay", "Paid"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return p
+}
+
+var noContent = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+})
+
+func serve(handler http.Handler, method, path, session string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, path, nil)
+	if session != "" {
+		request.Header.Set(DefaultSessionHeader, session)
+	}
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(newCheckoutProtocol(t), noContent)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		session        string
+		expectedStatus int
+	}{
+		{"pay before cart", http.MethodPost, "/pay", "alice", http.StatusConflict},
+		{"add to cart", http.MethodPost, "/cart", "alice", http.StatusNoContent},
+		{"add again", http.MethodPost, "/cart", "alice", http.StatusNoContent},
+		{"pay before checkout", http.MethodPost, "/pay", "alice", http.StatusConflict},
+		{"other session unaffected", http.MethodPost, "/checkout", "bob", http.StatusConflict},
+		{"checkout", http.MethodPost, "/checkout", "alice", http.StatusNoContent},
+		{"pay", http.MethodPost, "/pay", "alice", http.StatusNoContent},
+		{"untracked request", http.MethodGet, "/products", "", http.StatusNoContent},
+		{"missing session", http.MethodPost, "/cart", "", http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		recorder := serve(handler, test.method, test.path, test.session)
+		if recorder.Code != test.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d (%s)", test.name, test.expectedStatus, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestMiddleware_RejectionBody(t *testing.T) {
+	handler := Middleware(newCheckoutProtocol(t), noContent)
+	serve(handler, http.MethodPost, "/cart", "carol")
+
+	recorder := serve(handler, http.MethodPost, "/pay", "carol")
+
+	var body rejection
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if body.State != "Cart" || body.Event != "POST /pay" {
+		t.Errorf("Expected rejection in state Cart for POST /pay, got %+v", body)
+	}
+	if len(body.Expected) != 2 || body.Expected[0] != "POST /cart" || body.Expected[1] != "POST /checkout" {
+		t.Errorf("Expected allowed events [POST /cart POST /checkout], got %v", body.Expected)
+	}
+}
+
+func TestMiddleware_Options(t *testing.T) {
+	store := session.NewMemoryStore()
+	handler := Middleware(newCheckoutProtocol(t), noContent,
+		WithStore(store),
+		WithSessionID(func(r *http.Request) string { return r.URL.Query().Get("sid") }),
+		WithEvent(func(r *http.Request) Event { return Event("POST " + r.URL.Path) }),
+	)
+
+	if recorder := serve(handler, http.MethodGet, "/cart?sid=dave", ""); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected request to reach the wrapped handler, got %d", recorder.Code)
+	}
+
+	snapshot, ok, err := store.Get(context.Background(), "dave")
+	if err != nil || !ok || snapshot.State != "Cart" {
+		t.Errorf("Expected stored state Cart, got %s (found %v, err %v)", snapshot.State, ok, err)
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(ctx context.Context, key string) (fsm.Snapshot, bool, error) {
+	return fsm.Snapshot{}, false, errors.New("unavailable")
+}
+
+func (failingStore) Put(ctx context.Context, key string, snapshot fsm.Snapshot) error {
+	return errors.New("unavailable")
+}
+
+func TestMiddleware_StoreError(t *testing.T) {
+	handler := Middleware(newCheckoutProtocol(t), http.NotFoundHandler(), WithStore(failingStore{}))
+
+	if recorder := serve(handler, http.MethodPost, "/cart", "erin"); recorder.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", recorder.Code)
+	}
+}
+
+func TestMiddleware_CommitsAfterHandler(t *testing.T) {
+	fail := true
+	handler := Middleware(newCheckoutProtocol(t), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/checkout" {
+			panic("checkout failed")
+		}
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	if recorder := serve(handler, http.MethodPost, "/cart", "frank"); recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", recorder.Code)
+	}
+	if recorder := serve(handler, http.MethodPost, "/checkout", "frank"); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected a failed request to leave the session in Browsing, got %d", recorder.Code)
+	}
+
+	fail = false
+	if recorder := serve(handler, http.MethodPost, "/cart", "frank"); recorder.Code != http.StatusNoContent {
+		t.Fatalf("Expected the retried request to succeed, got %d", recorder.Code)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the handler's panic to propagate")
+			}
+		}()
+		serve(handler, http.MethodPost, "/checkout", "frank")
+	}()
+	if recorder := serve(handler, http.MethodPost, "/pay", "frank"); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected a panicking request to leave the session in Cart, got %d", recorder.Code)
+	}
+	if recorder := serve(handler, http.MethodPost, "/cart", "frank"); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected the session lock to be released after a panic, got %d", recorder.Code)
+	}
+}
+
+func TestMiddleware_ClientErrorDoesNotCommit(t *testing.T) {
+	status := http.StatusUnprocessableEntity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	})
+	handler := Middleware(newCheckoutProtocol(t), next)
+
+	if recorder := serve(handler, http.MethodPost, "/cart", "gina"); recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status 422, got %d", recorder.Code)
+	}
+	if recorder := serve(handler, http.MethodPost, "/checkout", "gina"); recorder.Code != http.StatusConflict {
+		t.Errorf("Expected a 4xx response to leave the session in Browsing, got %d", recorder.Code)
+	}
+
+	status = http.StatusSeeOther
+	serve(handler, http.MethodPost, "/cart", "gina")
+	if recorder := serve(handler, http.MethodPost, "/checkout", "gina"); recorder.Code != http.StatusSeeOther {
+		t.Errorf("Expected a 3xx response to advance the session, got %d", recorder.Code)
+	}
+}
+
+func TestMiddleware_WithCommit(t *testing.T) {
+	handler := Middleware(newCheckoutProtocol(t), http.NotFoundHandler(),
+		WithCommit(func(status int) bool { return status != http.StatusInternalServerError }),
+	)
+
+	serve(handler, http.MethodPost, "/cart", "hank")
+	if recorder := serve(handler, http.MethodPost, "/checkout", "hank"); recorder.Code != http.StatusNotFound {
+		t.Errorf("Expected the custom predicate to commit a 404, got %d", recorder.Code)
+	}
+}
+
+type blockingStore struct {
+	session.Store
+	key     string
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) Get(ctx context.Context, key string) (fsm.Snapshot, bool, error) {
+	if key == s.key {
+		close(s.entered)
+		<-s.release
+	}
+	return s.Store.Get(ctx, key)
+}
+
+func TestMiddleware_SessionsRunInParallel(t *testing.T) {
+	store := &blockingStore{
+		Store:   session.NewMemoryStore(),
+		key:     "slow",
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	handler := Middleware(newCheckoutProtocol(t), noContent, WithStore(store))
+
+	done := make(chan struct{})
+	go func() {
+		serve(handler, http.MethodPost, "/cart", "slow")
+		close(done)
+	}()
+	<-store.entered
+
+	fast := make(chan int, 1)
+	go func() { fast <- serve(handler, http.MethodPost, "/cart", "fast").Code }()
+	select {
+	case code := <-fast:
+		if code != http.StatusNoContent {
+			t.Errorf("Expected the request to reach the handler, got %d", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected another session not to wait for a slow store read")
+	}
+
+	close(store.release)
+	<-done
+	if snapshot, ok, _ := store.Store.Get(context.Background(), "slow"); !ok || snapshot.State != "Cart" {
+		t.Errorf("Expected the slow session in Cart, got %+v", snapshot)
+	}
+}
+
+func TestProtocol_Next(t *testing.T) {
+	p := newCheckoutProtocol(t)
+
+	next, err := p.Next("Cart", "POST /checkout")
+	if err != nil || next != "Checkout" {
+		t.Errorf("Expected Checkout, got %s (err %v)", next, err)
+	}
+
+	state, err := p.Next("Browsing", "POST /pay")
+	if err == nil || state != "Browsing" {
+		t.Errorf("Expected rejection leaving state Browsing, got %s (err %v)", state, err)
+	}
+
+	if !p.Tracks("POST /pay") || p.Tracks("GET /pay") {
+		t.Error("Unexpected Tracks result")
+	}
+}
@@ -0,0 +1,185 @@
+package protocol
+
+import (
+	"fmt"
+	"sort"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Event string
+
+// AnyEvent in a Rule applies to every event the protocol tracks that has no
+// rule of its own in the rule's From state.
+const AnyEvent = Event(fsm.Wildcard)
+
+type Rule struct {
+	From  fsm.State
+	Event Event
+	To    fsm.State
+}
+
+type Protocol struct {
+	name      string
+	automaton *fsm.FiniteAutomaton
+}
+
+type SequenceError struct {
+	Protocol string
+	State    fsm.State
+	Event    Event
+	Expected []Event
+}
+
+func (e *SequenceError) Error() string {
+	return fmt.Sprintf("%s: event %q not allowed in state %s (expected one of %v)", e.Protocol, e.Event, e.State, e.Expected)
+}
+
+func New(name string, initial fsm.State, final []fsm.State, rules []Rule) (*Protocol, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("protocol %s has no rules", name)
+	}
+
+	states := []fsm.State{initial}
+	seenStates := map[fsm.State]bool{initial: true}
+	seenEvents := map[Event]bool{}
+	var alphabet []fsm.Symbol
+	table := fsm.TransitionTable{}
+
+	for _, rule := range rules {
+		if existing, ok := table[rule.From][fsm.Symbol(rule.Event)]; ok && existing != rule.To {
+			return nil, fmt.Errorf("protocol %s: conflicting rules for %q in state %s", name, rule.Event, rule.From)
+		}
+		table.Set(rule.From, fsm.Symbol(rule.Event), rule.To)
+
+		for _, state := range []fsm.State{rule.From, rule.To} {
+			if !seenStates[state] {
+				seenStates[state] = true
+				states = append(states, state)
+			}
+		}
+		if rule.Event != AnyEvent && !seenEvents[rule.Event] {
+			seenEvents[rule.Event] = true
+			alphabet = append(alphabet, fsm.Symbol(rule.Event))
+		}
+	}
+	if len(alphabet) == 0 {
+		return nil, fmt.Errorf("protocol %s has no events besides AnyEvent", name)
+	}
+
+	for _, state := range final {
+		if !seenStates[state] {
+			return nil, fmt.Errorf("protocol %s: final state %s does not appear in any rule", name, state)
+		}
+	}
+
+	return &Protocol{
+		name:      name,
+		automaton: fsm.NewTableAutomaton(states, alphabet, initial, final, table),
+	}, nil
+}
+
+func (p *Protocol) Name() string {
+	return p.name
+}
+
+func (p *Protocol) Automaton() *fsm.FiniteAutomaton {
+	return p.automaton
+}
+
+func (p *Protocol) Initial() fsm.State {
+	return p.automaton.InitialState
+}
+
+func (p *Protocol) Tracks(event Event) bool {
+	for _, symbol := range p.automaton.Alphabet {
+		if symbol == fsm.Symbol(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next returns the state event leads to from state, falling back to the
+// state's AnyEvent rule. Untracked events are always rejected.
+func (p *Protocol) Next(state fsm.State, event Event) (fsm.State, error) {
+	next, ok := p.automaton.Table.Lookup(state, fsm.Symbol(event))
+	if !ok || !p.Tracks(event) {
+		return state, &SequenceError{
+			Protocol: p.name,
+			State:    state,
+			Event:    event,
+			Expected: p.allowed(state),
+		}
+	}
+	return next, nil
+}
+
+func (p *Protocol) allowed(state fsm.State) []Event {
+	var events []Event
+	for _, symbol := range p.automaton.Alphabet {
+		if _, ok := p.automaton.Table.Lookup(state, symbol); ok {
+			events = append(events, Event(symbol))
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+type Validator struct {
+	protocol *Protocol
+	runner   *fsm.Runner
+}
+
+// NewValidator starts a sequence in the initial state. It keeps no history
+// unless options ask for it, e.g. fsm.WithHistoryLimit for a bounded one.
+func (p *Protocol) NewValidator(options ...fsm.RunnerOption) *Validator {
+	return &Validator{protocol: p, runner: fsm.NewRunner(p.automaton, options...)}
+}
+
+func (v *Validator) Observe(event Event) error {
+	if _, err := v.protocol.Next(v.runner.CurrentState(), event); err != nil {
+		return err
+	}
+
+	_, err := v.runner.Step(fsm.Symbol(event))
+	return err
+}
+
+func (v *Validator) ObserveAll(events ...Event) error {
+	for i, event := range events {
+		if err := v.Observe(event); err != nil {
+			return fmt.Errorf("event %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (v *Validator) State() fsm.State {
+	return v.runner.CurrentState()
+}
+
+func (v *Validator) Expected() []Event {
+	return v.protocol.allowed(v.runner.CurrentState())
+}
+
+func (v *Validator) Complete() bool {
+	return v.runner.IsAccepting()
+}
+
+func (v *Validator) Finish() error {
+	if v.Complete() {
+		return nil
+	}
+	return fmt.Errorf("%s: sequence ended in state %s (expected one of %v)", v.protocol.name, v.State(), v.Expected())
+}
+
+// History returns the observed transitions; it is empty unless the validator
+// was created with a history option.
+func (v *Validator) History() []fsm.Transition {
+	return v.runner.History()
+}
+
+func (v *Validator) Reset() {
+	v.runner.Reset()
+}
@@ -0,0 +1,227 @@
+package protocol
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func TestSMTP_ValidSessions(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []Event
+	}{
+		{"single message", []Event{"EHLO", "MAIL", "RCPT", "DATA", "END", "QUIT"}},
+		{"several recipients", []Event{"HELO", "MAIL", "RCPT", "RCPT", "RCPT", "DATA", "END", "QUIT"}},
+		{"two messages", []Event{"EHLO", "MAIL", "RCPT", "DATA", "END", "MAIL", "RCPT", "DATA", "END", "QUIT"}},
+		{"reset", []Event{"EHLO", "MAIL", "RSET", "MAIL", "RCPT", "DATA", "END", "QUIT"}},
+		{"immediate quit", []Event{"QUIT"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := SMTP().NewValidator(fsm.WithHistory())
+			if err := v.ObserveAll(test.events...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if err := v.Finish(); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if len(v.History()) != len(test.events) {
+				t.Errorf("Expected %d transitions in history, got %d", len(test.events), len(v.History()))
+			}
+		})
+	}
+}
+
+func TestSMTP_OutOfOrder(t *testing.T) {
+	tests := []struct {
+		name          string
+		events        []Event
+		expectedState fsm.State
+		rejected      Event
+	}{
+		{"mail before greeting", []Event{"MAIL"}, "Connected", "MAIL"},
+		{"data without recipients", []Event{"EHLO", "MAIL", "DATA"}, "Sender", "DATA"},
+		{"unknown command", []Event{"EHLO", "VRFY"}, "Greeted", "VRFY"},
+		{"quit during data", []Event{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"}, "Data", "QUIT"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := SMTP().NewValidator()
+			err := v.ObserveAll(test.events...)
+
+			var sequenceErr *SequenceError
+			if !errors.As(err, &sequenceErr) {
+				t.Fatalf("Expected a SequenceError, got %v", err)
+			}
+			if sequenceErr.Event != test.rejected || sequenceErr.State != test.expectedState {
+				t.Errorf("Expected %s rejected in %s, got %+v", test.rejected, test.expectedState, sequenceErr)
+			}
+			if v.State() != test.expectedState {
+				t.Errorf("Expected validator to stay in %s, got %s", test.expectedState, v.State())
+			}
+			if !strings.Contains(err.Error(), "event "+strconv.Itoa(len(test.events))) {
+				t.Errorf("Expected error to name the event position, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidator_Expected(t *testing.T) {
+	v := SMTP().NewValidator(fsm.WithHistory())
+	if err := v.ObserveAll("EHLO", "MAIL"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []Event{"QUIT", "RCPT", "RSET"}
+	if got := v.Expected(); strings.Join(eventStrings(got), ",") != strings.Join(eventStrings(expected), ",") {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+
+	if err := v.Finish(); err == nil {
+		t.Error("Expected error finishing an incomplete session, but got none")
+	}
+
+	v.Reset()
+	if v.State() != "Connected" || len(v.History()) != 0 {
+		t.Errorf("Expected reset validator, got state %s with %d transitions", v.State(), len(v.History()))
+	}
+}
+
+func TestValidator_History(t *testing.T) {
+	events := []Event{"EHLO", "MAIL", "RCPT", "DATA", "END", "MAIL", "RCPT", "DATA", "END", "QUIT"}
+
+	v := SMTP().NewValidator()
+	if err := v.ObserveAll(events...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(v.History()) != 0 {
+		t.Errorf("Expected no history by default, got %d transitions", len(v.History()))
+	}
+
+	v = SMTP().NewValidator(fsm.WithHistoryLimit(3))
+	if err := v.ObserveAll(events...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if history := v.History(); len(history) != 3 || history[2].Symbol != "QUIT" {
+		t.Errorf("Expected the last 3 transitions, got %v", history)
+	}
+}
+
+func TestProtocol_AnyEvent(t *testing.T) {
+	p, err := New("session", "Open", []fsm.State{"Closed"}, []Rule{
+		{From: "Open", Event: "PING", To: "Open"},
+		{From: "Open", Event: "CLOSE", To: "Closed"},
+		{From: "Open", Event: "ABORT", To: "Closed"},
+		{From: "Closed", Event: AnyEvent, To: "Closed"},
+		{From: "Closed", Event: "PING", To: "Open"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if next, err := p.Next("Closed", "ABORT"); err != nil || next != "Closed" {
+		t.Errorf("Expected AnyEvent to keep ABORT in Closed, got %s (err %v)", next, err)
+	}
+	if next, err := p.Next("Closed", "PING"); err != nil || next != "Open" {
+		t.Errorf("Expected the explicit rule to win, got %s (err %v)", next, err)
+	}
+	if _, err := p.Next("Closed", "UNKNOWN"); err == nil {
+		t.Error("Expected an untracked event to be rejected")
+	}
+	if p.Tracks(AnyEvent) {
+		t.Error("Expected AnyEvent not to be tracked as an event")
+	}
+
+	v := p.NewValidator()
+	if err := v.ObserveAll("CLOSE", "CLOSE", "ABORT", "PING"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if v.State() != "Open" {
+		t.Errorf("Expected Open, got %s", v.State())
+	}
+
+	v.Reset()
+	v.Observe("CLOSE")
+	if got := strings.Join(eventStrings(v.Expected()), ","); got != "ABORT,CLOSE,PING" {
+		t.Errorf("Expected every tracked event in Closed, got %s", got)
+	}
+
+	if _, err := New("empty", "A", nil, []Rule{{From: "A", Event: AnyEvent, To: "A"}}); err == nil {
+		t.Error("Expected an error for a protocol with only AnyEvent rules")
+	}
+}
+
+func eventStrings(events []Event) []string {
+	strs := make([]string, len(events))
+	for i, event := range events {
+		strs[i] = string(event)
+	}
+	return strs
+}
+
+func TestTCPHandshake(t *testing.T) {
+	tests := []struct {
+		events   []Event
+		valid    bool
+		complete bool
+	}{
+		{[]Event{"SYN", "SYN-ACK", "ACK", "DATA", "FIN", "ACK", "FIN", "ACK"}, true, true},
+		{[]Event{"SYN", "SYN-ACK", "ACK", "DATA", "RST"}, true, true},
+		{[]Event{"SYN", "SYN-ACK", "ACK", "DATA"}, true, false},
+		{[]Event{"SYN", "ACK"}, false, false},
+		{[]Event{"DATA"}, false, false},
+	}
+
+	for _, test := range tests {
+		name := strings.Join(eventStrings(test.events), " ")
+		t.Run(name, func(t *testing.T) {
+			v := TCPHandshake().NewValidator()
+			err := v.ObserveAll(test.events...)
+			if (err == nil) != test.valid {
+				t.Errorf("Expected valid=%v, got error %v", test.valid, err)
+			}
+			if v.Complete() != test.complete {
+				t.Errorf("Expected complete=%v in state %s", test.complete, v.State())
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		final []fsm.State
+		rules []Rule
+	}{
+		{"no rules", []fsm.State{"A"}, nil},
+		{"conflict", []fsm.State{"B"}, []Rule{{"A", "x", "B"}, {"A", "x", "C"}}},
+		{"unknown final", []fsm.State{"Z"}, []Rule{{"A", "x", "B"}}},
+	}
+
+	for _, test := range tests {
+		if _, err := New(test.name, "A", test.final, test.rules); err == nil {
+			t.Errorf("%s: expected error, but got none", test.name)
+		}
+	}
+}
+
+func TestProtocol_Automaton(t *testing.T) {
+	p := SMTP()
+	if p.Name() != "SMTP" {
+		t.Errorf("Expected name SMTP, got %s", p.Name())
+	}
+
+	automaton := p.Automaton()
+	if len(automaton.States) != 6 || len(automaton.Alphabet) != 8 {
+		t.Errorf("Expected 6 states and 8 events, got %d and %d", len(automaton.States), len(automaton.Alphabet))
+	}
+	if !strings.Contains(automaton.DOT(), "Recipients") {
+		t.Error("Expected DOT output to include protocol states")
+	}
+}
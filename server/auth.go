@@ -0,0 +1,218 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	DefaultAPIKeyHeader = "X-API-Key"
+
+	codeUnauthorized = "unauthorized"
+	codeForbidden    = "forbidden"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid credentials")
+	ErrForbidden       = errors.New("not allowed")
+)
+
+type Authenticator interface {
+	Authenticate(r *http.Request) (principal string, err error)
+}
+
+// NamespaceAuthorizer scopes an Authenticator's credentials to namespaces.
+// ctx is the authenticated request's context: it carries the principal and,
+// for APIKeyAuthenticator, the key that was presented.
+type NamespaceAuthorizer interface {
+	AuthorizeNamespace(ctx context.Context, namespace string) error
+}
+
+type AuthenticatorFunc func(r *http.Request) (string, error)
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (string, error) {
+	return f(r)
+}
+
+func WithAuthenticator(authenticator Authenticator) Option {
+	return func(s *Server) {
+		s.authenticator = authenticator
+	}
+}
+
+type APIKey struct {
+	Principal  string
+	Namespaces []string
+}
+
+type apiKeyAuthenticator struct {
+	header string
+	keys   map[string]APIKey
+}
+
+func APIKeyAuthenticator(header string, keys map[string]APIKey) Authenticator {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	copied := make(map[string]APIKey, len(keys))
+	for key, apiKey := range keys {
+		copied[key] = apiKey
+	}
+	return &apiKeyAuthenticator{header: header, keys: copied}
+}
+
+func (a *apiKeyAuthenticator) Authenticate(r *http.Request) (string, error) {
+	apiKey, err := a.authenticateKey(r)
+	return apiKey.Principal, err
+}
+
+func (a *apiKeyAuthenticator) authenticateKey(r *http.Request) (APIKey, error) {
+	presented := r.Header.Get(a.header)
+	if presented == "" {
+		return APIKey{}, ErrUnauthenticated
+	}
+
+	apiKey, found := a.lookup(presented)
+	if !found {
+		return APIKey{}, ErrUnauthenticated
+	}
+	return apiKey, nil
+}
+
+// AuthorizeNamespace checks the key presented with the request, not the
+// other keys its principal holds, so a scoped key stays scoped.
+func (a *apiKeyAuthenticator) AuthorizeNamespace(ctx context.Context, namespace string) error {
+	apiKey, ok := APIKeyFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("no API key presented for namespace '%s': %w", namespace, ErrForbidden)
+	}
+	if len(apiKey.Namespaces) == 0 {
+		return nil
+	}
+	for _, allowed := range apiKey.Namespaces {
+		if allowed == namespace {
+			return nil
+		}
+	}
+	return fmt.Errorf("this key for principal '%s' may not access namespace '%s': %w", apiKey.Principal, namespace, ErrForbidden)
+}
+
+func (a *apiKeyAuthenticator) lookup(presented string) (APIKey, bool) {
+	var match APIKey
+	found := false
+	for key, apiKey := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(key)) == 1 {
+			match, found = apiKey, true
+		}
+	}
+	return match, found
+}
+
+func ReadAPIKeys(r io.Reader) (map[string]APIKey, error) {
+	keys := make(map[string]APIKey)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected \"principal key [namespace,...]\", got %d fields", line, len(fields))
+		}
+		if _, ok := keys[fields[1]]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key", line)
+		}
+		apiKey := APIKey{Principal: fields[0]}
+		if len(fields) == 3 {
+			apiKey.Namespaces = strings.Split(fields[2], ",")
+		}
+		keys[fields[1]] = apiKey
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no API keys found")
+	}
+	return keys, nil
+}
+
+type (
+	principalKey struct{}
+	apiKeyKey    struct{}
+)
+
+func PrincipalFromContext(ctx context.Context) (string, bool) {
+	principal, ok := ctx.Value(principalKey{}).(string)
+	return principal, ok
+}
+
+// APIKeyFromContext returns the API key an APIKeyAuthenticator accepted for
+// the request.
+func APIKeyFromContext(ctx context.Context) (APIKey, bool) {
+	apiKey, ok := ctx.Value(apiKeyKey{}).(APIKey)
+	return apiKey, ok
+}
+
+// authenticate checks the request's credentials and returns the request with
+// the principal, and for API keys the presented key, in its context. Without
+// an authenticator every request is allowed.
+func (s *Server) authenticate(r *http.Request) (*http.Request, error) {
+	if s.authenticator == nil {
+		return r, nil
+	}
+
+	ctx := r.Context()
+	var principal string
+	var err error
+	if keys, ok := s.authenticator.(*apiKeyAuthenticator); ok {
+		var apiKey APIKey
+		apiKey, err = keys.authenticateKey(r)
+		principal = apiKey.Principal
+		ctx = context.WithValue(ctx, apiKeyKey{}, apiKey)
+	} else {
+		principal, err = s.authenticator.Authenticate(r)
+	}
+	if err != nil {
+		return r, err
+	}
+	return r.WithContext(context.WithValue(ctx, principalKey{}, principal)), nil
+}
+
+// authorizeNamespace checks an authenticated request against namespace.
+func (s *Server) authorizeNamespace(r *http.Request, namespace string) error {
+	if authorizer, ok := s.authenticator.(NamespaceAuthorizer); ok {
+		return authorizer.AuthorizeNamespace(r.Context(), namespace)
+	}
+	return nil
+}
+
+// authorize authenticates r and checks it may access namespace, writing the
+// 401 or 403 response when it may not.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request, namespace string) (*http.Request, bool) {
+	r, err := s.authenticate(r)
+	if err == nil {
+		err = s.authorizeNamespace(r, namespace)
+	}
+	if err != nil {
+		writeAuthError(w, err)
+		return r, false
+	}
+	return r, true
+}
+
+func writeAuthError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrForbidden) {
+		writeJSON(w, http.StatusForbidden, errorResponse{Error: err.Error(), Code: codeForbidden})
+		return
+	}
+	writeJSON(w, http.StatusUnauthorized, errorResponse{Error: err.Error(), Code: codeUnauthorized})
+}
@@ -0,0 +1,174 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator := APIKeyAuthenticator("", map[string]APIKey{
+		"secret-1": {Principal: "alice"},
+		"secret-2": {Principal: "bob", Namespaces: []string{"payments"}},
+	})
+
+	tests := []struct {
+		name              string
+		header            string
+		value             string
+		expectedPrincipal string
+		expectError       bool
+	}{
+		{"first key", DefaultAPIKeyHeader, "secret-1", "alice", false},
+		{"second key", DefaultAPIKeyHeader, "secret-2", "bob", false},
+		{"unknown key", DefaultAPIKeyHeader, "secret-3", "", true},
+		{"missing key", DefaultAPIKeyHeader, "", "", true},
+		{"wrong header", "Authorization", "secret-1", "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.value != "" {
+				request.Header.Set(test.header, test.value)
+			}
+
+			principal, err := authenticator.Authenticate(request)
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error %v, got %v", test.expectError, err)
+			}
+			if principal != test.expectedPrincipal {
+				t.Errorf("Expected principal %q, got %q", test.expectedPrincipal, principal)
+			}
+		})
+	}
+}
+
+func TestReadAPIKeys(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedKeys map[string]APIKey
+		expectError  bool
+	}{
+		{"keys and comments", "# ops team\nalice secret-1\n\nbob   secret-2\n", map[string]APIKey{"secret-1": {Principal: "alice"}, "secret-2": {Principal: "bob"}}, false},
+		{"namespaces", "team-a key-a payments,billing\n", map[string]APIKey{"key-a": {Principal: "team-a", Namespaces: []string{"payments", "billing"}}}, false},
+		{"too many fields", "alice secret ns extra\n", nil, true},
+		{"missing key", "alice\n", nil, true},
+		{"duplicate key", "alice secret\nbob secret\n", nil, true},
+		{"empty", "# nothing\n", nil, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keys, err := ReadAPIKeys(strings.NewReader(test.input))
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error %v, got %v", test.expectError, err)
+			}
+			if fmt.Sprint(keys) != fmt.Sprint(test.expectedKeys) && !test.expectError {
+				t.Errorf("Expected keys %v, got %v", test.expectedKeys, keys)
+			}
+		})
+	}
+}
+
+func TestServer_CustomAuthenticator(t *testing.T) {
+	authenticator := AuthenticatorFunc(func(r *http.Request) (string, error) {
+		switch r.Header.Get("Authorization") {
+		case "Bearer admin":
+			return "admin", nil
+		case "Bearer viewer":
+			return "", fmt.Errorf("viewer cannot modify machines: %w", ErrForbidden)
+		}
+		return "", ErrUnauthenticated
+	})
+
+	var seen string
+	s := newTestServer(t, WithAuthenticator(authenticator))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := s.authorize(w, r, DefaultNamespace)
+		if ok {
+			seen, _ = PrincipalFromContext(r.Context())
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	tests := []struct {
+		name              string
+		authorization     string
+		expectedStatus    int
+		expectedPrincipal string
+	}{
+		{"admin", "Bearer admin", http.StatusNoContent, "admin"},
+		{"viewer", "Bearer viewer", http.StatusForbidden, ""},
+		{"anonymous", "", http.StatusUnauthorized, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			seen = ""
+			request := httptest.NewRequest(http.MethodPut, "/", nil)
+			request.Header.Set("Authorization", test.authorization)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("Expected status %d, got %d", test.expectedStatus, recorder.Code)
+			}
+			if seen != test.expectedPrincipal {
+				t.Errorf("Expected principal %q, got %q", test.expectedPrincipal, seen)
+			}
+		})
+	}
+}
+
+func TestAPIKeyAuthenticator_Namespaces(t *testing.T) {
+	s := newTestServer(t, WithAuthenticator(APIKeyAuthenticator("", map[string]APIKey{
+		"admin-key":    {Principal: "admin"},
+		"payments-key": {Principal: "payments-team", Namespaces: []string{"payments", "billing"}},
+		"deploy-key":   {Principal: "payments-team"},
+	})))
+
+	tests := []struct {
+		name        string
+		key         string
+		namespace   string
+		expectError bool
+	}{
+		{"unscoped key", "admin-key", "payments", false},
+		{"own namespace", "payments-key", "payments", false},
+		{"second namespace", "payments-key", "billing", false},
+		{"other namespace", "payments-key", "search", true},
+		{"principal's unscoped key", "deploy-key", "search", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set(DefaultAPIKeyHeader, test.key)
+			request, err := s.authenticate(request)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if _, ok := APIKeyFromContext(request.Context()); !ok {
+				t.Fatal("Expected the presented key in the request context")
+			}
+
+			err = s.authorizeNamespace(request, test.namespace)
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error %v, got %v", test.expectError, err)
+			}
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				t.Errorf("Expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+
+	if err := s.authenticator.(NamespaceAuthorizer).AuthorizeNamespace(context.Background(), "payments"); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without a presented key, got %v", err)
+	}
+}
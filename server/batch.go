@@ -0,0 +1,161 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultBatchConcurrency = 8
+
+type BatchResult struct {
+	Line   int          `json:"line"`
+	Result *ModResponse `json:"result,omitempty"`
+	Error  string       `json:"error,omitempty"`
+}
+
+type batchJob struct {
+	line   int
+	input  string
+	err    error
+	result chan BatchResult
+}
+
+func WithBatchConcurrency(workers int) Option {
+	return func(s *Server) {
+		if workers > 0 {
+			s.batchWorkers = workers
+		}
+	}
+}
+
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	_, span := s.tracer.Start(ctx, "fsm.Batch")
+	defer span.End()
+
+	jobs := make(chan *batchJob, s.batchWorkers)
+	ordered := make(chan *batchJob, 4*s.batchWorkers)
+	done := ctx.Done()
+
+	for i := 0; i < s.batchWorkers; i++ {
+		go func() {
+			for job := range jobs {
+				job.result <- s.batchLine(job)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(ordered)
+		defer close(jobs)
+
+		scanner := bufio.NewScanner(r.Body)
+		maxLine := 1024 * 1024
+		if s.maxInputLength > 0 && s.maxInputLength+maxRequestOverhead < maxLine {
+			maxLine = s.maxInputLength + maxRequestOverhead
+		}
+		scanner.Buffer(make([]byte, 0, 4096), maxLine)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+
+			job := &batchJob{line: line, result: make(chan BatchResult, 1)}
+			var request ModRequest
+			if err := json.Unmarshal([]byte(text), &request); err != nil {
+				job.err = fmt.Errorf("invalid JSON: %w", err)
+			} else {
+				job.err = s.checkInputLength(request.Input)
+			}
+			job.input = request.Input
+
+			select {
+			case ordered <- job:
+			case <-done:
+				return
+			}
+			jobs <- job
+		}
+		if err := scanner.Err(); err != nil {
+			job := &batchJob{line: line + 1, err: fmt.Errorf("reading request: %w", err), result: make(chan BatchResult, 1)}
+			job.result <- BatchResult{Line: job.line, Error: job.err.Error()}
+			select {
+			case ordered <- job:
+			case <-done:
+			}
+		}
+	}()
+
+	controller := http.NewResponseController(w)
+	controller.EnableFullDuplex()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+
+	processed, invalid := 0, 0
+	for job := range ordered {
+		result := <-job.result
+		processed++
+		if result.Error != "" {
+			invalid++
+		}
+		if err := encoder.Encode(result); err != nil {
+			span.RecordError(err)
+			cancel()
+			break
+		}
+		controller.Flush()
+	}
+
+	span.SetAttributes(
+		slog.Int("fsm.batch_size", processed),
+		slog.Int("fsm.batch_invalid", invalid),
+	)
+	if s.logger != nil {
+		s.logger.LogAttrs(r.Context(), slog.LevelInfo, "processed batch",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("lines", processed),
+			slog.Int("invalid", invalid),
+		)
+	}
+}
+
+func (s *Server) batchLine(job *batchJob) BatchResult {
+	if job.err != nil {
+		return BatchResult{Line: job.line, Error: job.err.Error()}
+	}
+
+	start := time.Now()
+	result, err := s.machine.Mod(job.input)
+	elapsed := time.Since(start)
+	if err != nil {
+		s.metrics.Observe("", false, elapsed)
+		return BatchResult{Line: job.line, Error: err.Error()}
+	}
+	s.metrics.Observe(result.FinalState, true, elapsed)
+
+	return BatchResult{Line: job.line, Result: &ModResponse{
+		Input:      result.Input,
+		Modulus:    s.machine.Modulus(),
+		Base:       s.machine.Base(),
+		Remainder:  result.Remainder,
+		FinalState: result.FinalState,
+	}}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"fsm-modulo-three/modulo"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_Batch(t *testing.T) {
+	machine, err := modulo.NewModFSM(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	body := strings.Join([]string{
+		`{"input":"1101"}`,
+		`{"input":"012"}`,
+		``,
+		`{"input":`,
+		`{"input":"1110"}`,
+	}, "\n")
+
+	tests := []struct {
+		name    string
+		workers int
+	}{
+		{"sequential", 1},
+		{"concurrent", 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := New(machine, WithBatchConcurrency(test.workers))
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/process/batch", strings.NewReader(body)))
+
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != "application/x-ndjson" {
+				t.Errorf("Expected application/x-ndjson content type, got %q", contentType)
+			}
+
+			var results []BatchResult
+			decoder := json.NewDecoder(recorder.Body)
+			for decoder.More() {
+				var result BatchResult
+				if err := decoder.Decode(&result); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				results = append(results, result)
+			}
+
+			expected := []struct {
+				line      int
+				remainder int
+				invalid   bool
+			}{
+				{1, 1, false},
+				{2, 0, true},
+				{4, 0, true},
+				{5, 2, false},
+			}
+			if len(results) != len(expected) {
+				t.Fatalf("Expected %d results, got %d", len(expected), len(results))
+			}
+			for i, want := range expected {
+				got := results[i]
+				if got.Line != want.line {
+					t.Errorf("Expected result %d for line %d, got line %d", i, want.line, got.Line)
+				}
+				if want.invalid {
+					if got.Error == "" || got.Result != nil {
+						t.Errorf("Expected error for line %d, got %+v", want.line, got)
+					}
+					continue
+				}
+				if got.Result == nil || got.Result.Remainder != want.remainder {
+					t.Errorf("Expected remainder %d for line %d, got %+v", want.remainder, want.line, got)
+				}
+			}
+
+			var metrics strings.Builder
+			s.Metrics().WriteTo(&metrics)
+			if !strings.Contains(metrics.String(), "fsm_inputs_invalid_total 1") {
+				t.Errorf("Expected one invalid input in metrics, got:\n%s", metrics.String())
+			}
+		})
+	}
+}
+
+func TestServer_BatchMethod(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/process/batch", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status %d, got %d", http.StatusMethodNotAllowed, recorder.Code)
+	}
+	if allow := recorder.Header().Get("Allow"); allow != "POST" {
+		t.Errorf("Expected Allow: POST, got %q", allow)
+	}
+}
+
+func TestServer_BatchStreams(t *testing.T) {
+	httpServer := httptest.NewServer(newTestServer(t))
+	defer httpServer.Close()
+
+	reader, writer := io.Pipe()
+	defer writer.Close()
+
+	responses := make(chan *http.Response, 1)
+	errs := make(chan error, 1)
+	go func() {
+		response, err := http.Post(httpServer.URL+"/process/batch", "application/x-ndjson", reader)
+		if err != nil {
+			errs <- err
+			return
+		}
+		responses <- response
+	}()
+
+	fmt.Fprintln(writer, `{"input":"11"}`)
+
+	var response *http.Response
+	select {
+	case response = <-responses:
+	case err := <-errs:
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer response.Body.Close()
+
+	lines := bufio.NewScanner(response.Body)
+	if !lines.Scan() {
+		t.Fatalf("Expected a result before the request body was complete, got %v", lines.Err())
+	}
+	var result BatchResult
+	if err := json.Unmarshal(lines.Bytes(), &result); err != nil || result.Result == nil || result.Result.Remainder != 0 {
+		t.Errorf("Expected remainder 0 for the first line, got %s", lines.Text())
+	}
+
+	fmt.Fprintln(writer, `{"input":"100"}`)
+	writer.Close()
+	if !lines.Scan() {
+		t.Fatalf("Expected a second result, got %v", lines.Err())
+	}
+	if err := json.Unmarshal(lines.Bytes(), &result); err != nil || result.Result == nil || result.Result.Remainder != 1 {
+		t.Errorf("Expected remainder 1 for the second line, got %s", lines.Text())
+	}
+	if lines.Scan() {
+		t.Errorf("Expected end of stream, got %s", lines.Text())
+	}
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const rateLimitSweepInterval = time.Minute
+
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(s *Server) {
+		if perSecond > 0 {
+			s.limiter = newRateLimiter(perSecond, burst)
+		}
+	}
+}
+
+func WithMaxInputLength(length int) Option {
+	return func(s *Server) {
+		s.maxInputLength = length
+	}
+}
+
+// WithMaxDefinitionSize caps the body of a machine definition upload at size
+// bytes, independently of WithMaxInputLength. Zero or less means unlimited.
+func WithMaxDefinitionSize(size int64) Option {
+	return func(s *Server) {
+		s.maxDefinitionSize = size
+	}
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64
+	burst     float64
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func newRateLimiter(perSecond float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = int(math.Max(1, math.Ceil(perSecond)))
+	}
+	return &rateLimiter{
+		rate:    perSecond,
+		burst:   float64(burst),
+		clients: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		l.sweep(now)
+	}
+
+	bucket, ok := l.clients[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.clients[client] = bucket
+	}
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+		return false, wait
+	}
+	bucket.tokens--
+	return true, 0
+}
+
+func (l *rateLimiter) sweep(now time.Time) {
+	l.lastSweep = now
+	for client, bucket := range l.clients {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.clients, client)
+		}
+	}
+}
+
+func clientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func rateLimited(path string) bool {
+	return path != "/healthz" && path != "/metrics"
+}
+
+func writeRateLimited(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	writeJSON(w, http.StatusTooManyRequests, errorResponse{
+		Error: "rate limit exceeded",
+		Code:  codeRateLimited,
+	})
+}
+
+func (s *Server) checkInputLength(input string) error {
+	if s.maxInputLength > 0 && len(input) > s.maxInputLength {
+		return fmt.Errorf("input length %d exceeds the maximum of %d", len(input), s.maxInputLength)
+	}
+	return nil
+}
+
+func (s *Server) limitBody(w http.ResponseWriter, r *http.Request) {
+	if s.maxInputLength > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, int64(s.maxInputLength)+maxRequestOverhead)
+	}
+}
+
+func (s *Server) limitDefinitionBody(w http.ResponseWriter, r *http.Request) {
+	if s.maxDefinitionSize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxDefinitionSize)
+	}
+}
+
+func isTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
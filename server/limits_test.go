@@ -0,0 +1,171 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := newRateLimiter(2, 3)
+	limiter.now = func() time.Time { return now }
+
+	tests := []struct {
+		name          string
+		advance       time.Duration
+		client        string
+		expectedAllow bool
+		expectedWait  time.Duration
+	}{
+		{"burst 1", 0, "a", true, 0},
+		{"burst 2", 0, "a", true, 0},
+		{"burst 3", 0, "a", true, 0},
+		{"exhausted", 0, "a", false, 500 * time.Millisecond},
+		{"other client", 0, "b", true, 0},
+		{"partial refill", 250 * time.Millisecond, "a", false, 250 * time.Millisecond},
+		{"refilled", 250 * time.Millisecond, "a", true, 0},
+		{"empty again", 0, "a", false, 500 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			now = now.Add(test.advance)
+			allowed, wait := limiter.allow(test.client)
+			if allowed != test.expectedAllow {
+				t.Errorf("Expected allowed %v, got %v", test.expectedAllow, allowed)
+			}
+			if wait != test.expectedWait {
+				t.Errorf("Expected wait %v, got %v", test.expectedWait, wait)
+			}
+		})
+	}
+
+	now = now.Add(2 * rateLimitSweepInterval)
+	limiter.allow("c")
+	if _, ok := limiter.clients["a"]; ok {
+		t.Errorf("Expected idle client to be swept")
+	}
+	if len(limiter.clients) != 1 {
+		t.Errorf("Expected 1 tracked client after sweep, got %d", len(limiter.clients))
+	}
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	s := newTestServer(t, WithRateLimit(1, 2))
+
+	tests := []struct {
+		name           string
+		target         string
+		remoteAddr     string
+		expectedStatus int
+	}{
+		{"first", "/v1/mod?input=1", "10.0.0.1:1000", http.StatusOK},
+		{"second", "/v1/mod?input=1", "10.0.0.1:1001", http.StatusOK},
+		{"limited", "/v1/mod?input=1", "10.0.0.1:1002", http.StatusTooManyRequests},
+		{"other endpoint limited", "/v1/definition", "10.0.0.1:1003", http.StatusTooManyRequests},
+		{"health exempt", "/healthz", "10.0.0.1:1004", http.StatusOK},
+		{"metrics exempt", "/metrics", "10.0.0.1:1005", http.StatusOK},
+		{"other client", "/v1/mod?input=1", "10.0.0.2:1000", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, test.target, nil)
+			request.RemoteAddr = test.remoteAddr
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", test.expectedStatus, recorder.Code)
+			}
+			if test.expectedStatus != http.StatusTooManyRequests {
+				return
+			}
+			if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != "1" {
+				t.Errorf("Expected Retry-After 1, got %q", retryAfter)
+			}
+			var response errorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != codeRateLimited {
+				t.Errorf("Expected %s error, got %s", codeRateLimited, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_MaxInputLength(t *testing.T) {
+	s := newTestServer(t, WithMaxInputLength(4))
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+	}{
+		{"get within limit", http.MethodGet, "/v1/mod?input=1101", "", http.StatusOK},
+		{"get too long", http.MethodGet, "/v1/mod?input=11011", "", http.StatusRequestEntityTooLarge},
+		{"post within limit", http.MethodPost, "/v1/mod", `{"input":"1101"}`, http.StatusOK},
+		{"post too long", http.MethodPost, "/v1/mod", `{"input":"11011"}`, http.StatusRequestEntityTooLarge},
+		{"post body too large", http.MethodPost, "/v1/mod", `{"input":"` + strings.Repeat("1", 2000) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if test.expectedStatus != http.StatusRequestEntityTooLarge {
+				return
+			}
+			var response errorResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Code != codeInputTooLarge {
+				t.Errorf("Expected %s error, got %s", codeInputTooLarge, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestServer_MaxDefinitionSize(t *testing.T) {
+	s := newTestServer(t, WithMaxInputLength(4), WithMaxDefinitionSize(int64(len(evenOnesDefinition))))
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/v1/machines/even-ones", strings.NewReader(evenOnesDefinition)))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected a definition larger than the input limit to fit its own limit, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/v1/machines/even-ones", strings.NewReader(" "+evenOnesDefinition)))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusRequestEntityTooLarge, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_BatchMaxInputLength(t *testing.T) {
+	s := newTestServer(t, WithMaxInputLength(4))
+
+	body := `{"input":"11011"}` + "\n" + `{"input":"11"}` + "\n"
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/process/batch", strings.NewReader(body)))
+
+	lines := strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 result lines, got %d: %s", len(lines), recorder.Body.String())
+	}
+	var first, second BatchResult
+	json.Unmarshal([]byte(lines[0]), &first)
+	json.Unmarshal([]byte(lines[1]), &second)
+	if !strings.Contains(first.Error, "exceeds the maximum") {
+		t.Errorf("Expected length error for the first line, got %s", lines[0])
+	}
+	if second.Result == nil || second.Result.Remainder != 0 {
+		t.Errorf("Expected remainder 0 for the second line, got %s", lines[1])
+	}
+}
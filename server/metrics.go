@@ -0,0 +1,103 @@
+package server
+
+import (
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+var latencyBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1}
+
+type Metrics struct {
+	mu           sync.Mutex
+	processed    uint64
+	invalid      uint64
+	finalStates  map[fsm.State]uint64
+	bucketCounts []uint64
+	latencySum   float64
+	latencyCount uint64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		finalStates:  make(map[fsm.State]uint64),
+		bucketCounts: make([]uint64, len(latencyBuckets)),
+	}
+}
+
+func (m *Metrics) Observe(finalState fsm.State, valid bool, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.processed++
+	if valid {
+		m.finalStates[finalState]++
+	} else {
+		m.invalid++
+	}
+
+	seconds := duration.Seconds()
+	m.latencySum += seconds
+	m.latencyCount++
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			m.bucketCounts[i]++
+		}
+	}
+}
+
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+
+	fmt.Fprintln(cw, "# HELP fsm_inputs_processed_total Inputs processed, valid or not.")
+	fmt.Fprintln(cw, "# TYPE fsm_inputs_processed_total counter")
+	fmt.Fprintf(cw, "fsm_inputs_processed_total %d\n", m.processed)
+
+	fmt.Fprintln(cw, "# HELP fsm_inputs_invalid_total Inputs rejected by validation.")
+	fmt.Fprintln(cw, "# TYPE fsm_inputs_invalid_total counter")
+	fmt.Fprintf(cw, "fsm_inputs_invalid_total %d\n", m.invalid)
+
+	fmt.Fprintln(cw, "# HELP fsm_final_state_total Valid inputs by final state.")
+	fmt.Fprintln(cw, "# TYPE fsm_final_state_total counter")
+	states := make([]string, 0, len(m.finalStates))
+	for state := range m.finalStates {
+		states = append(states, string(state))
+	}
+	sort.Strings(states)
+	for _, state := range states {
+		fmt.Fprintf(cw, "fsm_final_state_total{state=%q} %d\n", state, m.finalStates[fsm.State(state)])
+	}
+
+	fmt.Fprintln(cw, "# HELP fsm_processing_duration_seconds Time spent processing a single input.")
+	fmt.Fprintln(cw, "# TYPE fsm_processing_duration_seconds histogram")
+	for i, bound := range latencyBuckets {
+		fmt.Fprintf(cw, "fsm_processing_duration_seconds_bucket{le=\"%g\"} %d\n", bound, m.bucketCounts[i])
+	}
+	fmt.Fprintf(cw, "fsm_processing_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.latencyCount)
+	fmt.Fprintf(cw, "fsm_processing_duration_seconds_sum %g\n", m.latencySum)
+	fmt.Fprintf(cw, "fsm_processing_duration_seconds_count %d\n", m.latencyCount)
+
+	return cw.n, cw.err
+}
+
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_Histogram(t *testing.T) {
+	m := NewMetrics()
+	m.Observe("S0", true, 20*time.Microsecond)
+	m.Observe("S1", true, 2*time.Millisecond)
+	m.Observe("", false, 2*time.Second)
+
+	var sb strings.Builder
+	n, err := m.WriteTo(&sb)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if int(n) != sb.Len() {
+		t.Errorf("Expected WriteTo to report %d bytes, got %d", sb.Len(), n)
+	}
+
+	expected := []string{
+		`fsm_processing_duration_seconds_bucket{le="1e-05"} 0`,
+		`fsm_processing_duration_seconds_bucket{le="5e-05"} 1`,
+		`fsm_processing_duration_seconds_bucket{le="0.001"} 1`,
+		`fsm_processing_duration_seconds_bucket{le="0.005"} 2`,
+		`fsm_processing_duration_seconds_bucket{le="1"} 2`,
+		`fsm_processing_duration_seconds_bucket{le="+Inf"} 3`,
+		"fsm_processing_duration_seconds_sum 2.00202",
+		"fsm_inputs_invalid_total 1",
+		"# TYPE fsm_processing_duration_seconds histogram",
+	}
+	for _, line := range expected {
+		if !strings.Contains(sb.String(), line+"\n") {
+			t.Errorf("Metrics should contain %q:\n%s", line, sb.String())
+		}
+	}
+
+	if strings.Contains(sb.String(), `state=""`) {
+		t.Error("Invalid inputs should not be counted under a final state")
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPI_Spec(t *testing.T) {
+	var spec struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components map[string]map[string]json.RawMessage `json:"components"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("Expected spec to be valid JSON, got %v", err)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		t.Errorf("Expected OpenAPI 3 document, got version %q", spec.OpenAPI)
+	}
+
+	for _, ref := range regexp.MustCompile(`"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(openAPISpec), -1) {
+		if _, ok := spec.Components[ref[1]][ref[2]]; !ok {
+			t.Errorf("Expected reference %s to resolve", ref[0])
+		}
+	}
+
+	s := newTestServer(t)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			if method == "parameters" {
+				continue
+			}
+			target := strings.NewReplacer("{namespace}", "example", "{name}", "example").Replace(path)
+			request := httptest.NewRequest(strings.ToUpper(method), target, strings.NewReader(`{"input":"1"}`))
+			if _, pattern := s.mux.Handler(request); pattern == "" {
+				t.Errorf("Expected %s to be routed", path)
+				continue
+			}
+
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, request)
+			if recorder.Code == http.StatusMethodNotAllowed {
+				t.Errorf("Expected %s %s to be allowed", strings.ToUpper(method), path)
+			}
+		}
+	}
+}
+
+func TestOpenAPI_Served(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Expected application/json content type, got %q", contentType)
+	}
+	if recorder.Body.String() != string(openAPISpec) {
+		t.Errorf("Expected served document to match the embedded spec")
+	}
+}
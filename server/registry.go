@@ -0,0 +1,336 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const DefaultNamespace = "default"
+
+var ErrQuotaExceeded = errors.New("namespace quota exceeded")
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+type RunRequest struct {
+	Input string `json:"input"`
+}
+
+type RunResponse struct {
+	Namespace  string    `json:"namespace"`
+	Machine    string    `json:"machine"`
+	Input      string    `json:"input"`
+	Accepted   bool      `json:"accepted"`
+	FinalState fsm.State `json:"final_state"`
+}
+
+type machineList struct {
+	Namespace string   `json:"namespace"`
+	Machines  []string `json:"machines"`
+}
+
+type NamespaceInfo struct {
+	Name     string `json:"name"`
+	Machines int    `json:"machines"`
+	Quota    int    `json:"quota,omitempty"`
+}
+
+type namespaceList struct {
+	Namespaces []NamespaceInfo `json:"namespaces"`
+}
+
+type registeredMachine struct {
+	definition *fsm.Definition
+	automaton  *fsm.FiniteAutomaton
+}
+
+type Registry struct {
+	mu           sync.RWMutex
+	namespaces   map[string]map[string]registeredMachine
+	quotas       map[string]int
+	defaultQuota int
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		namespaces: make(map[string]map[string]registeredMachine),
+		quotas:     make(map[string]int),
+	}
+}
+
+func WithRegistry(registry *Registry) Option {
+	return func(s *Server) {
+		s.registry = registry
+	}
+}
+
+func (r *Registry) SetDefaultQuota(limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.defaultQuota = limit
+}
+
+func (r *Registry) SetQuota(namespace string, limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.quotas[namespace] = limit
+}
+
+func (r *Registry) quota(namespace string) int {
+	if limit, ok := r.quotas[namespace]; ok {
+		return limit
+	}
+	return r.defaultQuota
+}
+
+func (r *Registry) Register(namespace, name string, definition *fsm.Definition) (created bool, err error) {
+	if !namePattern.MatchString(namespace) {
+		return false, fmt.Errorf("invalid namespace %q: use up to 64 letters, digits, '_', '.' or '-'", namespace)
+	}
+	if !namePattern.MatchString(name) {
+		return false, fmt.Errorf("invalid machine name %q: use up to 64 letters, digits, '_', '.' or '-'", name)
+	}
+	automaton, err := definition.Automaton()
+	if err != nil {
+		return false, err
+	}
+
+	stored := *definition
+	stored.Name = name
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	machines := r.namespaces[namespace]
+	_, exists := machines[name]
+	if limit := r.quota(namespace); !exists && limit > 0 && len(machines) >= limit {
+		return false, fmt.Errorf("%w: namespace '%s' already holds %d of %d machines", ErrQuotaExceeded, namespace, len(machines), limit)
+	}
+	if machines == nil {
+		machines = make(map[string]registeredMachine)
+		r.namespaces[namespace] = machines
+	}
+	machines[name] = registeredMachine{definition: &stored, automaton: automaton}
+	return !exists, nil
+}
+
+func (r *Registry) Definition(namespace, name string) (*fsm.Definition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	machine, ok := r.namespaces[namespace][name]
+	return machine.definition, ok
+}
+
+func (r *Registry) Automaton(namespace, name string) (*fsm.FiniteAutomaton, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	machine, ok := r.namespaces[namespace][name]
+	return machine.automaton, ok
+}
+
+func (r *Registry) Delete(namespace, name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	machines := r.namespaces[namespace]
+	if _, ok := machines[name]; !ok {
+		return false
+	}
+	delete(machines, name)
+	if len(machines) == 0 {
+		delete(r.namespaces, namespace)
+	}
+	return true
+}
+
+func (r *Registry) Names(namespace string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.namespaces[namespace]))
+	for name := range r.namespaces[namespace] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) Namespaces() []NamespaceInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	namespaces := make([]NamespaceInfo, 0, len(r.namespaces))
+	for namespace, machines := range r.namespaces {
+		namespaces = append(namespaces, NamespaceInfo{
+			Name:     namespace,
+			Machines: len(machines),
+			Quota:    r.quota(namespace),
+		})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	return namespaces
+}
+
+func (s *Server) handleMachines(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/v1/machines"), "/")
+	s.serveMachines(w, r, DefaultNamespace, rest)
+}
+
+func (s *Server) handleNamespaces(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/v1/namespaces"), "/")
+	if rest == "" {
+		if !allowGet(w, r) {
+			return
+		}
+		r, err := s.authenticate(r)
+		if err != nil {
+			writeAuthError(w, err)
+			return
+		}
+		visible := []NamespaceInfo{}
+		for _, namespace := range s.registry.Namespaces() {
+			if s.authorizeNamespace(r, namespace.Name) == nil {
+				visible = append(visible, namespace)
+			}
+		}
+		writeJSON(w, http.StatusOK, namespaceList{Namespaces: visible})
+		return
+	}
+
+	namespace, rest, _ := strings.Cut(rest, "/")
+	if rest != "machines" && !strings.HasPrefix(rest, "machines/") {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+		return
+	}
+	s.serveMachines(w, r, namespace, strings.TrimPrefix(strings.TrimPrefix(rest, "machines"), "/"))
+}
+
+// serveMachines authorizes every request, reads included, against namespace
+// before dispatching it.
+func (s *Server) serveMachines(w http.ResponseWriter, r *http.Request, namespace, rest string) {
+	name, action, _ := strings.Cut(rest, "/")
+
+	r, ok := s.authorize(w, r, namespace)
+	if !ok {
+		return
+	}
+
+	switch {
+	case name == "":
+		if !allowGet(w, r) {
+			return
+		}
+		writeJSON(w, http.StatusOK, machineList{Namespace: namespace, Machines: s.registry.Names(namespace)})
+	case action == "run":
+		s.handleRunMachine(w, r, namespace, name)
+	case action != "":
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "unknown machine action '" + action + "'"})
+	default:
+		s.handleMachine(w, r, namespace, name)
+	}
+}
+
+func (s *Server) handleMachine(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		definition, ok := s.registry.Definition(namespace, name)
+		if !ok {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "machine '" + name + "' not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, definition)
+	case http.MethodPut:
+		s.limitDefinitionBody(w, r)
+		definition, err := fsm.ReadDefinition(r.Body)
+		if err != nil {
+			if isTooLarge(err) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: "request body too large", Code: codeInputTooLarge})
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		created, err := s.registry.Register(namespace, name, definition)
+		if errors.Is(err, ErrQuotaExceeded) {
+			writeJSON(w, http.StatusForbidden, errorResponse{Error: err.Error(), Code: codeQuotaExceeded})
+			return
+		}
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+			return
+		}
+		s.logRegistryChange(r, "registered machine", namespace, name)
+		stored, _ := s.registry.Definition(namespace, name)
+		if created {
+			writeJSON(w, http.StatusCreated, stored)
+		} else {
+			writeJSON(w, http.StatusOK, stored)
+		}
+	case http.MethodDelete:
+		if !s.registry.Delete(namespace, name) {
+			writeJSON(w, http.StatusNotFound, errorResponse{Error: "machine '" + name + "' not found"})
+			return
+		}
+		s.logRegistryChange(r, "deleted machine", namespace, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, DELETE")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+	}
+}
+
+func (s *Server) handleRunMachine(w http.ResponseWriter, r *http.Request, namespace, name string) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+	automaton, ok := s.registry.Automaton(namespace, name)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, errorResponse{Error: "machine '" + name + "' not found"})
+		return
+	}
+
+	var request RunRequest
+	s.limitBody(w, r)
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		if isTooLarge(err) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: "request body too large", Code: codeInputTooLarge})
+			return
+		}
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON body: " + err.Error()})
+		return
+	}
+	if err := s.checkInputLength(request.Input); err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: err.Error(), Code: codeInputTooLarge})
+		return
+	}
+
+	state, err := automaton.ProcessInput(request.Input)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, RunResponse{
+		Namespace:  namespace,
+		Machine:    name,
+		Input:      request.Input,
+		Accepted:   automaton.IsAcceptingState(state),
+		FinalState: state,
+	})
+}
+
+func (s *Server) logRegistryChange(r *http.Request, message, namespace, name string) {
+	if s.logger == nil {
+		return
+	}
+	principal, _ := PrincipalFromContext(r.Context())
+	s.logger.LogAttrs(r.Context(), slog.LevelInfo, message,
+		slog.String("namespace", namespace),
+		slog.String("machine", name),
+		slog.String("principal", principal),
+	)
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fsm-modulo-three/fsm"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const evenOnesDefinition = `{
+  "states": ["even", "odd"],
+  "alphabet": ["0", "1"],
+  "initial": "even",
+  "accepting": ["even"],
+  "transitions": [
+    {"from": "even", "symbol": "0", "to": "even"},
+    {"from": "even", "symbol": "1", "to": "odd"},
+    {"from": "odd", "symbol": "0", "to": "odd"},
+    {"from": "odd", "symbol": "1", "to": "even"}
+  ]
+}`
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	definition, err := fsm.ReadDefinition(strings.NewReader(evenOnesDefinition))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		namespace       string
+		machine         string
+		expectedCreated bool
+		expectError     bool
+	}{
+		{"new", DefaultNamespace, "even-ones", true, false},
+		{"replace", DefaultNamespace, "even-ones", false, false},
+		{"second", DefaultNamespace, "parity.v2", true, false},
+		{"same name in another namespace", "payments", "even-ones", true, false},
+		{"invalid name", DefaultNamespace, "../etc", false, true},
+		{"empty name", DefaultNamespace, "", false, true},
+		{"invalid namespace", "a/b", "even-ones", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			created, err := registry.Register(test.namespace, test.machine, definition)
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error %v, got %v", test.expectError, err)
+			}
+			if created != test.expectedCreated {
+				t.Errorf("Expected created %v, got %v", test.expectedCreated, created)
+			}
+		})
+	}
+
+	if names := strings.Join(registry.Names(DefaultNamespace), ","); names != "even-ones,parity.v2" {
+		t.Errorf("Expected even-ones,parity.v2, got %s", names)
+	}
+	if names := strings.Join(registry.Names("payments"), ","); names != "even-ones" {
+		t.Errorf("Expected even-ones, got %s", names)
+	}
+	if stored, _ := registry.Definition(DefaultNamespace, "even-ones"); stored.Name != "even-ones" || definition.Name != "" {
+		t.Errorf("Expected stored copy to carry the registry name without modifying the input")
+	}
+	if !registry.Delete("payments", "even-ones") || registry.Delete("payments", "even-ones") {
+		t.Errorf("Expected delete to succeed once")
+	}
+	if _, ok := registry.Automaton(DefaultNamespace, "even-ones"); !ok {
+		t.Errorf("Expected delete in one namespace to leave the other untouched")
+	}
+	if namespaces := registry.Namespaces(); len(namespaces) != 1 || namespaces[0].Name != DefaultNamespace || namespaces[0].Machines != 2 {
+		t.Errorf("Expected only the default namespace with 2 machines, got %+v", namespaces)
+	}
+}
+
+func TestRegistry_Quota(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetDefaultQuota(2)
+	registry.SetQuota("large", 3)
+	definition, err := fsm.ReadDefinition(strings.NewReader(evenOnesDefinition))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		machine       string
+		expectedQuota bool
+	}{
+		{"first", "small", "a", false},
+		{"second", "small", "b", false},
+		{"over default quota", "small", "c", true},
+		{"replace at quota", "small", "b", false},
+		{"own quota 1", "large", "a", false},
+		{"own quota 2", "large", "b", false},
+		{"own quota 3", "large", "c", false},
+		{"over own quota", "large", "d", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := registry.Register(test.namespace, test.machine, definition)
+			if errors.Is(err, ErrQuotaExceeded) != test.expectedQuota {
+				t.Errorf("Expected quota error %v, got %v", test.expectedQuota, err)
+			}
+		})
+	}
+
+	registry.Delete("small", "a")
+	if _, err := registry.Register("small", "c", definition); err != nil {
+		t.Errorf("Expected room after delete, got %v", err)
+	}
+}
+
+func TestServer_Machines(t *testing.T) {
+	s := newTestServer(t, WithAuthenticator(APIKeyAuthenticator("", map[string]APIKey{"secret": {Principal: "alice"}})))
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		apiKey         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"list without key", http.MethodGet, "/v1/machines", "", "", http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"list empty", http.MethodGet, "/v1/machines", "secret", "", http.StatusOK, `"machines":[]`},
+		{"register without key", http.MethodPut, "/v1/machines/even-ones", "", evenOnesDefinition, http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"register with wrong key", http.MethodPut, "/v1/machines/even-ones", "nope", evenOnesDefinition, http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"register", http.MethodPut, "/v1/machines/even-ones", "secret", evenOnesDefinition, http.StatusCreated, `"name":"even-ones"`},
+		{"replace", http.MethodPut, "/v1/machines/even-ones", "secret", evenOnesDefinition, http.StatusOK, `"name":"even-ones"`},
+		{"register invalid", http.MethodPut, "/v1/machines/broken", "secret", `{"states":[]}`, http.StatusBadRequest, `"error"`},
+		{"register bad name", http.MethodPut, "/v1/machines/bad name", "secret", evenOnesDefinition, http.StatusBadRequest, `invalid machine name`},
+		{"list", http.MethodGet, "/v1/machines/", "secret", "", http.StatusOK, `"machines":["even-ones"]`},
+		{"get", http.MethodGet, "/v1/machines/even-ones", "secret", "", http.StatusOK, `"initial":"even"`},
+		{"get missing", http.MethodGet, "/v1/machines/missing", "secret", "", http.StatusNotFound, `not found`},
+		{"run accepted", http.MethodPost, "/v1/machines/even-ones/run", "secret", `{"input":"1001"}`, http.StatusOK, `"accepted":true,"final_state":"even"`},
+		{"run rejected", http.MethodPost, "/v1/machines/even-ones/run", "secret", `{"input":"1"}`, http.StatusOK, `"accepted":false,"final_state":"odd"`},
+		{"run invalid symbol", http.MethodPost, "/v1/machines/even-ones/run", "secret", `{"input":"12"}`, http.StatusBadRequest, `invalid symbol`},
+		{"run wrong method", http.MethodGet, "/v1/machines/even-ones/run", "secret", "", http.StatusMethodNotAllowed, `method not allowed`},
+		{"unknown action", http.MethodGet, "/v1/machines/even-ones/stop", "secret", "", http.StatusNotFound, `unknown machine action`},
+		{"run without key", http.MethodPost, "/v1/machines/even-ones/run", "", `{"input":"1001"}`, http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"get without key", http.MethodGet, "/v1/machines/even-ones", "", "", http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"delete without key", http.MethodDelete, "/v1/machines/even-ones", "", "", http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"delete", http.MethodDelete, "/v1/machines/even-ones", "secret", "", http.StatusNoContent, ``},
+		{"delete missing", http.MethodDelete, "/v1/machines/even-ones", "secret", "", http.StatusNotFound, `not found`},
+		{"wrong method", http.MethodPost, "/v1/machines/even-ones", "secret", "", http.StatusMethodNotAllowed, `method not allowed`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, "/", strings.NewReader(test.body))
+			request.URL.Path = test.target[:strings.IndexAny(test.target+"?", "?")]
+			if test.apiKey != "" {
+				request.Header.Set(DefaultAPIKeyHeader, test.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			body := recorder.Body.String()
+			if test.expectedBody != "" && !strings.Contains(compactJSON(body), test.expectedBody) {
+				t.Errorf("Expected body to contain %s, got %s", test.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestServer_MachinesOpenWithoutAuthenticator(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/v1/machines/even-ones", strings.NewReader(evenOnesDefinition)))
+
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusCreated, recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_Namespaces(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetQuota("payments", 1)
+	s := newTestServer(t, WithRegistry(registry), WithAuthenticator(APIKeyAuthenticator("", map[string]APIKey{
+		"admin-key":    {Principal: "admin"},
+		"payments-key": {Principal: "payments-team", Namespaces: []string{"payments"}},
+	})))
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		apiKey         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"register in own namespace", http.MethodPut, "/v1/namespaces/payments/machines/even-ones", "payments-key", evenOnesDefinition, http.StatusCreated, `"name":"even-ones"`},
+		{"over quota", http.MethodPut, "/v1/namespaces/payments/machines/second", "payments-key", evenOnesDefinition, http.StatusForbidden, `"code":"quota_exceeded"`},
+		{"other namespace forbidden", http.MethodPut, "/v1/namespaces/search/machines/even-ones", "payments-key", evenOnesDefinition, http.StatusForbidden, `"code":"forbidden"`},
+		{"default namespace forbidden", http.MethodPut, "/v1/machines/even-ones", "payments-key", evenOnesDefinition, http.StatusForbidden, `"code":"forbidden"`},
+		{"admin registers anywhere", http.MethodPut, "/v1/namespaces/search/machines/even-ones", "admin-key", evenOnesDefinition, http.StatusCreated, `"name":"even-ones"`},
+		{"list namespace", http.MethodGet, "/v1/namespaces/payments/machines", "payments-key", "", http.StatusOK, `"machines":["even-ones"],"namespace":"payments"`},
+		{"default namespace isolated", http.MethodGet, "/v1/machines", "admin-key", "", http.StatusOK, `"machines":[]`},
+		{"run in namespace", http.MethodPost, "/v1/namespaces/search/machines/even-ones/run", "admin-key", `{"input":"11"}`, http.StatusOK, `"namespace":"search"`},
+		{"run other namespace forbidden", http.MethodPost, "/v1/namespaces/search/machines/even-ones/run", "payments-key", `{"input":"11"}`, http.StatusForbidden, `"code":"forbidden"`},
+		{"get other namespace forbidden", http.MethodGet, "/v1/namespaces/search/machines/even-ones", "payments-key", "", http.StatusForbidden, `"code":"forbidden"`},
+		{"list other namespace forbidden", http.MethodGet, "/v1/namespaces/search/machines", "payments-key", "", http.StatusForbidden, `"code":"forbidden"`},
+		{"missing in namespace", http.MethodGet, "/v1/namespaces/search/machines/second", "admin-key", "", http.StatusNotFound, `not found`},
+		{"list namespaces", http.MethodGet, "/v1/namespaces", "admin-key", "", http.StatusOK, `{"namespaces":[{"machines":1,"name":"payments","quota":1},{"machines":1,"name":"search"}]}`},
+		{"list own namespaces", http.MethodGet, "/v1/namespaces", "payments-key", "", http.StatusOK, `{"namespaces":[{"machines":1,"name":"payments","quota":1}]}`},
+		{"list namespaces without key", http.MethodGet, "/v1/namespaces", "", "", http.StatusUnauthorized, `"code":"unauthorized"`},
+		{"unknown collection", http.MethodGet, "/v1/namespaces/payments/other", "", "", http.StatusNotFound, `not found`},
+		{"delete other namespace forbidden", http.MethodDelete, "/v1/namespaces/search/machines/even-ones", "payments-key", "", http.StatusForbidden, `"code":"forbidden"`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			if test.apiKey != "" {
+				request.Header.Set(DefaultAPIKeyHeader, test.apiKey)
+			}
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+			if body := recorder.Body.String(); !strings.Contains(compactJSON(body), test.expectedBody) {
+				t.Errorf("Expected body to contain %s, got %s", test.expectedBody, body)
+			}
+		})
+	}
+}
+
+func compactJSON(body string) string {
+	var value any
+	if err := json.Unmarshal([]byte(body), &value); err != nil {
+		return body
+	}
+	compact, _ := json.Marshal(value)
+	return string(compact)
+}
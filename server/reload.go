@@ -0,0 +1,272 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type MachineKey struct {
+	Namespace string
+	Name      string
+}
+
+func (k MachineKey) String() string {
+	return k.Namespace + "/" + k.Name
+}
+
+func (r *Registry) Apply(remove []MachineKey, add map[MachineKey]*fsm.Definition) error {
+	prepared := make(map[MachineKey]registeredMachine, len(add))
+	for key, definition := range add {
+		if !namePattern.MatchString(key.Namespace) {
+			return fmt.Errorf("invalid namespace %q", key.Namespace)
+		}
+		if !namePattern.MatchString(key.Name) {
+			return fmt.Errorf("invalid machine name %q", key.Name)
+		}
+		automaton, err := definition.Automaton()
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		stored := *definition
+		stored.Name = key.Name
+		prepared[key] = registeredMachine{definition: &stored, automaton: automaton}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkApplyQuotas(remove, prepared); err != nil {
+		return err
+	}
+	for _, key := range remove {
+		if machines := r.namespaces[key.Namespace]; machines != nil {
+			delete(machines, key.Name)
+			if len(machines) == 0 {
+				delete(r.namespaces, key.Namespace)
+			}
+		}
+	}
+	for key, machine := range prepared {
+		machines := r.namespaces[key.Namespace]
+		if machines == nil {
+			machines = make(map[string]registeredMachine)
+			r.namespaces[key.Namespace] = machines
+		}
+		machines[key.Name] = machine
+	}
+	return nil
+}
+
+// checkApplyQuotas rejects an Apply that would grow a namespace past its
+// quota. A namespace already over its quota may still shrink or be replaced
+// in place. The caller holds r.mu.
+func (r *Registry) checkApplyQuotas(remove []MachineKey, add map[MachineKey]registeredMachine) error {
+	counts := make(map[string]int)
+	removed := make(map[MachineKey]bool, len(remove))
+	for _, key := range remove {
+		if _, ok := r.namespaces[key.Namespace][key.Name]; ok && !removed[key] {
+			removed[key] = true
+			counts[key.Namespace]--
+		}
+	}
+	for key := range add {
+		if _, ok := r.namespaces[key.Namespace][key.Name]; !ok || removed[key] {
+			counts[key.Namespace]++
+		}
+	}
+
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		current := len(r.namespaces[namespace])
+		limit := r.quota(namespace)
+		if after := current + counts[namespace]; counts[namespace] > 0 && limit > 0 && after > limit {
+			return fmt.Errorf("%w: namespace '%s' would hold %d of %d machines", ErrQuotaExceeded, namespace, after, limit)
+		}
+	}
+	return nil
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+type Reloader struct {
+	registry *Registry
+	dir      string
+	logger   *slog.Logger
+
+	mu        sync.Mutex
+	stamps    map[string]fileStamp
+	managed   map[MachineKey]bool
+	lastError string
+}
+
+func NewReloader(registry *Registry, dir string, logger *slog.Logger) *Reloader {
+	return &Reloader{registry: registry, dir: dir, logger: logger}
+}
+
+func (l *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.Reload()
+		}
+	}
+}
+
+func (l *Reloader) Reload() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	files, stamps, err := l.scan()
+	if err != nil {
+		l.log(slog.LevelError, "definition reload failed", slog.String("error", err.Error()))
+		return false, err
+	}
+	// Stamps are recorded only once a reload applies, so a failed one is
+	// retried on the next tick, e.g. after a quota has been raised.
+	if l.stamps != nil && sameStamps(l.stamps, stamps) {
+		return false, nil
+	}
+
+	definitions := make(map[MachineKey]*fsm.Definition, len(files))
+	for key, path := range files {
+		definition, err := fsm.LoadDefinition(path)
+		if err != nil {
+			return false, l.fail(err)
+		}
+		definitions[key] = definition
+	}
+
+	var remove []MachineKey
+	for key := range l.managed {
+		if _, ok := definitions[key]; !ok {
+			remove = append(remove, key)
+		}
+	}
+	if err := l.registry.Apply(remove, definitions); err != nil {
+		return false, l.fail(err)
+	}
+
+	managed := make(map[MachineKey]bool, len(definitions))
+	for key := range definitions {
+		managed[key] = true
+	}
+	l.stamps = stamps
+	l.managed = managed
+	l.lastError = ""
+	l.log(slog.LevelInfo, "reloaded definitions",
+		slog.String("dir", l.dir),
+		slog.Int("machines", len(definitions)),
+		slog.Int("removed", len(remove)),
+	)
+	return true, nil
+}
+
+func (l *Reloader) Managed() []MachineKey {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	keys := make([]MachineKey, 0, len(l.managed))
+	for key := range l.managed {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+// fail logs err unless the previous attempt failed the same way, so content
+// retried on every tick does not repeat the error each time.
+func (l *Reloader) fail(err error) error {
+	if err.Error() == l.lastError {
+		return err
+	}
+	l.lastError = err.Error()
+	l.log(slog.LevelError, "definition reload failed, keeping previous machines",
+		slog.String("dir", l.dir),
+		slog.String("error", err.Error()),
+	)
+	return err
+}
+
+func (l *Reloader) scan() (map[MachineKey]string, map[string]fileStamp, error) {
+	files := make(map[MachineKey]string)
+	stamps := make(map[string]fileStamp)
+
+	add := func(namespace, path string, info os.FileInfo) {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		files[MachineKey{Namespace: namespace, Name: name}] = path
+		stamps[path] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(l.dir, entry.Name())
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !entry.IsDir() {
+			if filepath.Ext(path) == ".json" {
+				info, err := entry.Info()
+				if err != nil {
+					return nil, nil, err
+				}
+				add(DefaultNamespace, path, info)
+			}
+			continue
+		}
+
+		nested, err := os.ReadDir(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, file := range nested {
+			if file.IsDir() || strings.HasPrefix(file.Name(), ".") || filepath.Ext(file.Name()) != ".json" {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				return nil, nil, err
+			}
+			add(entry.Name(), filepath.Join(path, file.Name()), info)
+		}
+	}
+	return files, stamps, nil
+}
+
+func sameStamps(a, b map[string]fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, stamp := range a {
+		other, ok := b[path]
+		if !ok || !other.modTime.Equal(stamp.modTime) || other.size != stamp.size {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *Reloader) log(level slog.Level, message string, attrs ...slog.Attr) {
+	if l.logger != nil {
+		l.logger.LogAttrs(context.Background(), level, message, attrs...)
+	}
+}
@@ -0,0 +1,240 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const oddOnesDefinition = `{
+  "states": ["even", "odd"],
+  "alphabet": ["0", "1"],
+  "initial": "even",
+  "accepting": ["odd"],
+  "transitions": [
+    {"from": "even", "symbol": "0", "to": "even"},
+    {"from": "even", "symbol": "1", "to": "odd"},
+    {"from": "odd", "symbol": "0", "to": "odd"},
+    {"from": "odd", "symbol": "1", "to": "even"}
+  ]
+}`
+
+func writeDefinitionFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func acceptsOne(t *testing.T, registry *Registry, namespace, name string) string {
+	t.Helper()
+
+	automaton, ok := registry.Automaton(namespace, name)
+	if !ok {
+		return "missing"
+	}
+	accepted, err := automaton.Accepts("1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return fmt.Sprint(accepted)
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	registry := NewRegistry()
+	manual, _ := fsm.ReadDefinition(strings.NewReader(evenOnesDefinition))
+	registry.Register(DefaultNamespace, "manual", manual)
+	reloader := NewReloader(registry, dir, nil)
+	base := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name            string
+		change          func()
+		expectedChanged bool
+		expectError     bool
+		expected        map[MachineKey]string
+	}{
+		{
+			"initial load",
+			func() {
+				writeDefinitionFile(t, filepath.Join(dir, "parity.json"), evenOnesDefinition, base)
+				writeDefinitionFile(t, filepath.Join(dir, "payments", "odd.json"), oddOnesDefinition, base)
+				writeDefinitionFile(t, filepath.Join(dir, "notes.txt"), "ignored", base)
+				writeDefinitionFile(t, filepath.Join(dir, ".hidden", "skip.json"), "{", base)
+			},
+			true, false,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "false", {"payments", "odd"}: "true", {DefaultNamespace, "manual"}: "false"},
+		},
+		{
+			"unchanged",
+			func() {},
+			false, false,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "false"},
+		},
+		{
+			"updated file swapped",
+			func() {
+				writeDefinitionFile(t, filepath.Join(dir, "parity.json"), oddOnesDefinition, base.Add(time.Minute))
+			},
+			true, false,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "true", {"payments", "odd"}: "true"},
+		},
+		{
+			"invalid file keeps previous machines",
+			func() {
+				writeDefinitionFile(t, filepath.Join(dir, "parity.json"), `{"states":["a"],"alphabet":[],"initial":"b","accepting":[],"transitions":[]}`, base.Add(2*time.Minute))
+				writeDefinitionFile(t, filepath.Join(dir, "payments", "odd.json"), evenOnesDefinition, base.Add(2*time.Minute))
+			},
+			false, true,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "true", {"payments", "odd"}: "true"},
+		},
+		{
+			"failed content is retried",
+			func() {},
+			false, true,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "true"},
+		},
+		{
+			"fixed file applies pending changes",
+			func() {
+				writeDefinitionFile(t, filepath.Join(dir, "parity.json"), evenOnesDefinition, base.Add(3*time.Minute))
+			},
+			true, false,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "false", {"payments", "odd"}: "false"},
+		},
+		{
+			"removed file unregisters machine",
+			func() {
+				os.Remove(filepath.Join(dir, "payments", "odd.json"))
+			},
+			true, false,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "false", {"payments", "odd"}: "missing", {DefaultNamespace, "manual"}: "false"},
+		},
+		{
+			"invalid machine name",
+			func() {
+				writeDefinitionFile(t, filepath.Join(dir, "-bad.json"), evenOnesDefinition, base)
+			},
+			false, true,
+			map[MachineKey]string{{DefaultNamespace, "parity"}: "false"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.change()
+			changed, err := reloader.Reload()
+			if (err != nil) != test.expectError {
+				t.Fatalf("Expected error %v, got %v", test.expectError, err)
+			}
+			if changed != test.expectedChanged {
+				t.Errorf("Expected changed %v, got %v", test.expectedChanged, changed)
+			}
+			for key, expected := range test.expected {
+				if got := acceptsOne(t, registry, key.Namespace, key.Name); got != expected {
+					t.Errorf("Expected %s to accept \"1\": %s, got %s", key, expected, got)
+				}
+			}
+		})
+	}
+
+	if managed := fmt.Sprint(reloader.Managed()); managed != "[default/parity]" {
+		t.Errorf("Expected [default/parity] to be managed, got %s", managed)
+	}
+}
+
+func TestRegistry_ApplyIsAtomic(t *testing.T) {
+	registry := NewRegistry()
+	valid, _ := fsm.ReadDefinition(strings.NewReader(evenOnesDefinition))
+	invalid := &fsm.Definition{States: []fsm.State{"a"}, InitialState: "b"}
+	registry.Register(DefaultNamespace, "keep", valid)
+
+	err := registry.Apply([]MachineKey{{DefaultNamespace, "keep"}}, map[MachineKey]*fsm.Definition{
+		{DefaultNamespace, "new"}:    valid,
+		{DefaultNamespace, "broken"}: invalid,
+	})
+	if err == nil {
+		t.Fatalf("Expected error for invalid definition")
+	}
+	if names := fmt.Sprint(registry.Names(DefaultNamespace)); names != "[keep]" {
+		t.Errorf("Expected registry to be unchanged, got %s", names)
+	}
+}
+
+func TestRegistry_ApplyChecksQuotas(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetQuota("small", 2)
+	definition, _ := fsm.ReadDefinition(strings.NewReader(evenOnesDefinition))
+	registry.Register("small", "a", definition)
+
+	err := registry.Apply(nil, map[MachineKey]*fsm.Definition{
+		{"small", "b"}: definition,
+		{"small", "c"}: definition,
+	})
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if names := fmt.Sprint(registry.Names("small")); names != "[a]" {
+		t.Errorf("Expected registry to be unchanged, got %s", names)
+	}
+
+	err = registry.Apply([]MachineKey{{"small", "a"}}, map[MachineKey]*fsm.Definition{
+		{"small", "a"}: definition,
+		{"small", "b"}: definition,
+	})
+	if err != nil {
+		t.Fatalf("Expected replacing and adding within the quota to succeed, got %v", err)
+	}
+
+	registry.SetQuota("small", 1)
+	if err := registry.Apply(nil, map[MachineKey]*fsm.Definition{{"small", "b"}: definition}); err != nil {
+		t.Errorf("Expected replacing in an over-quota namespace to succeed, got %v", err)
+	}
+}
+
+func TestReloader_RetriesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	registry := NewRegistry()
+	registry.SetQuota("payments", 1)
+	var logs bytes.Buffer
+	reloader := NewReloader(registry, dir, slog.New(slog.NewTextHandler(&logs, nil)))
+	base := time.Now().Add(-time.Hour)
+	writeDefinitionFile(t, filepath.Join(dir, "payments", "odd.json"), oddOnesDefinition, base)
+	writeDefinitionFile(t, filepath.Join(dir, "payments", "even.json"), evenOnesDefinition, base)
+
+	for i := 0; i < 2; i++ {
+		if _, err := reloader.Reload(); !errors.Is(err, ErrQuotaExceeded) {
+			t.Fatalf("Expected ErrQuotaExceeded on attempt %d, got %v", i+1, err)
+		}
+	}
+	if count := strings.Count(logs.String(), "definition reload failed"); count != 1 {
+		t.Errorf("Expected a repeated failure to be logged once, got %d", count)
+	}
+
+	registry.SetQuota("payments", 2)
+	changed, err := reloader.Reload()
+	if err != nil || !changed {
+		t.Fatalf("Expected the unchanged files to apply once the quota allows, got %v, %v", changed, err)
+	}
+	if got := acceptsOne(t, registry, "payments", "odd"); got != "true" {
+		t.Errorf("Expected payments/odd to be registered, got %s", got)
+	}
+	if changed, err := reloader.Reload(); changed || err != nil {
+		t.Errorf("Expected no change after a successful reload, got %v, %v", changed, err)
+	}
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modulo"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type ModRequest struct {
+	Input string `json:"input"`
+}
+
+type ModResponse struct {
+	Input      string    `json:"input"`
+	Modulus    int       `json:"modulus"`
+	Base       int       `json:"base"`
+	Remainder  int       `json:"remainder"`
+	FinalState fsm.State `json:"final_state"`
+}
+
+const (
+	codeRateLimited    = "rate_limited"
+	codeInputTooLarge  = "input_too_large"
+	codeQuotaExceeded  = "quota_exceeded"
+	maxRequestOverhead = 1024
+
+	// DefaultMaxDefinitionSize bounds machine definition uploads unless
+	// WithMaxDefinitionSize says otherwise.
+	DefaultMaxDefinitionSize = 1 << 20
+)
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+type Option func(*Server)
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+type Server struct {
+	machine *modulo.ModFSM
+	metrics *Metrics
+	mux     *http.ServeMux
+	logger  *slog.Logger
+	tracer  Tracer
+
+	batchWorkers      int
+	limiter           *rateLimiter
+	maxInputLength    int
+	maxDefinitionSize int64
+	registry          *Registry
+	authenticator     Authenticator
+}
+
+func New(machine *modulo.ModFSM, options ...Option) *Server {
+	s := &Server{
+		machine: machine,
+		metrics: NewMetrics(),
+		mux:     http.NewServeMux(),
+		tracer:  noopTracer{},
+
+		batchWorkers:      defaultBatchConcurrency,
+		maxDefinitionSize: DefaultMaxDefinitionSize,
+		registry:          NewRegistry(),
+	}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	s.mux.HandleFunc("/v1/mod", s.handleMod)
+	s.mux.HandleFunc("/process/batch", s.handleBatch)
+	s.mux.HandleFunc("/v1/step", s.handleStep)
+	s.mux.HandleFunc("/v1/machines", s.handleMachines)
+	s.mux.HandleFunc("/v1/machines/", s.handleMachines)
+	s.mux.HandleFunc("/v1/namespaces", s.handleNamespaces)
+	s.mux.HandleFunc("/v1/namespaces/", s.handleNamespaces)
+	s.mux.HandleFunc("/v1/definition", s.handleDefinition)
+	s.mux.HandleFunc("/v1/diagram", s.handleDiagram)
+	s.mux.HandleFunc("/openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
+	s.mux.HandleFunc("/healthz", s.handleHealth)
+
+	return s
+}
+
+func (s *Server) Metrics() *Metrics {
+	return s.metrics
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = withTraceContext(r)
+	if s.limiter != nil && rateLimited(r.URL.Path) {
+		if ok, retryAfter := s.limiter.allow(clientKey(r)); !ok {
+			writeRateLimited(w, retryAfter)
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleMod(w http.ResponseWriter, r *http.Request) {
+	var request ModRequest
+	switch r.Method {
+	case http.MethodGet:
+		request.Input = r.URL.Query().Get("input")
+	case http.MethodPost:
+		s.limitBody(w, r)
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			if isTooLarge(err) {
+				writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: "request body too large", Code: codeInputTooLarge})
+				return
+			}
+			writeJSON(w, http.StatusBadRequest, errorResponse{Error: "invalid JSON body: " + err.Error()})
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+		return
+	}
+	if err := s.checkInputLength(request.Input); err != nil {
+		writeJSON(w, http.StatusRequestEntityTooLarge, errorResponse{Error: err.Error(), Code: codeInputTooLarge})
+		return
+	}
+
+	_, span := s.tracer.Start(r.Context(), "fsm.Mod")
+	defer span.End()
+	span.SetAttributes(
+		slog.Int("fsm.input_length", len(request.Input)),
+		slog.Int("fsm.modulus", s.machine.Modulus()),
+		slog.Int("fsm.base", s.machine.Base()),
+	)
+
+	start := time.Now()
+	result, err := s.machine.Mod(request.Input)
+	elapsed := time.Since(start)
+	if err != nil {
+		span.RecordError(err)
+		s.metrics.Observe("", false, elapsed)
+		s.logRequest(r, http.StatusBadRequest, elapsed, len(request.Input), slog.String("error", err.Error()))
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+	span.SetAttributes(
+		slog.String("fsm.final_state", string(result.FinalState)),
+		slog.Int("fsm.remainder", result.Remainder),
+	)
+	s.metrics.Observe(result.FinalState, true, elapsed)
+	s.logRequest(r, http.StatusOK, elapsed, len(request.Input),
+		slog.Int("remainder", result.Remainder),
+		slog.String("final_state", string(result.FinalState)),
+	)
+
+	writeJSON(w, http.StatusOK, ModResponse{
+		Input:      result.Input,
+		Modulus:    s.machine.Modulus(),
+		Base:       s.machine.Base(),
+		Remainder:  result.Remainder,
+		FinalState: result.FinalState,
+	})
+}
+
+func (s *Server) logRequest(r *http.Request, status int, elapsed time.Duration, inputLength int, attrs ...slog.Attr) {
+	if s.logger == nil {
+		return
+	}
+
+	attrs = append([]slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.Duration("duration", elapsed),
+		slog.Int("input_length", inputLength),
+	}, attrs...)
+	if sc, ok := SpanContextFromContext(r.Context()); ok {
+		attrs = append(attrs, slog.String("trace_id", fmt.Sprintf("%x", sc.TraceID)))
+	}
+	s.logger.LogAttrs(r.Context(), slog.LevelInfo, "processed request", attrs...)
+}
+
+func (s *Server) handleDefinition(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	definition := fsm.DefinitionOf(s.machine.GetAutomaton())
+	definition.Name = fmt.Sprintf("mod%d_base%d", s.machine.Modulus(), s.machine.Base())
+	writeJSON(w, http.StatusOK, definition)
+}
+
+func (s *Server) handleDiagram(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	automaton := s.machine.GetAutomaton()
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		fmt.Fprint(w, automaton.SVG())
+	case "dot":
+		w.Header().Set("Content-Type", "text/vnd.graphviz")
+		fmt.Fprint(w, automaton.DOT())
+	default:
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("unsupported diagram format %q, expected svg or dot", format)})
+	}
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if !allowGet(w, r) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+func allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+	w.Header().Set("Allow", "GET, HEAD")
+	writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+	return false
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.metrics.WriteTo(w)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"encoding/json"
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/modulo"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T, options ...Option) *Server {
+	t.Helper()
+
+	machine, err := modulo.NewModFSM(3, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return New(machine, options...)
+}
+
+func TestServer_Mod(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name              string
+		method            string
+		target            string
+		body              string
+		expectedStatus    int
+		expectedRemainder int
+	}{
+		{"get", http.MethodGet, "/v1/mod?input=1101", "", http.StatusOK, 1},
+		{"post", http.MethodPost, "/v1/mod", `{"input":"1110"}`, http.StatusOK, 2},
+		{"invalid input", http.MethodGet, "/v1/mod?input=012", "", http.StatusBadRequest, 0},
+		{"empty input", http.MethodGet, "/v1/mod", "", http.StatusBadRequest, 0},
+		{"bad json", http.MethodPost, "/v1/mod", `{"input":`, http.StatusBadRequest, 0},
+		{"wrong method", http.MethodDelete, "/v1/mod", "", http.StatusMethodNotAllowed, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, test.target, strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, request)
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", test.expectedStatus, recorder.Code, recorder.Body.String())
+			}
+
+			if test.expectedStatus != http.StatusOK {
+				var response errorResponse
+				if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error == "" {
+					t.Errorf("Expected JSON error body, got %q", recorder.Body.String())
+				}
+				return
+			}
+
+			var response ModResponse
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Unexpected error decoding response: %v", err)
+			}
+			if response.Remainder != test.expectedRemainder {
+				t.Errorf("Expected remainder %d, got %d", test.expectedRemainder, response.Remainder)
+			}
+			if response.Modulus != 3 || response.Base != 2 {
+				t.Errorf("Expected mod 3 base 2, got mod %d base %d", response.Modulus, response.Base)
+			}
+		})
+	}
+}
+
+func TestServer_MetricsEndpoint(t *testing.T) {
+	s := newTestServer(t)
+
+	for _, target := range []string{"/v1/mod?input=1101", "/v1/mod?input=1111", "/v1/mod?input=11", "/v1/mod?input=x"} {
+		s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("Expected text/plain content type, got %q", recorder.Header().Get("Content-Type"))
+	}
+
+	expected := []string{
+		"fsm_inputs_processed_total 4",
+		"fsm_inputs_invalid_total 1",
+		`fsm_final_state_total{state="S0"} 2`,
+		`fsm_final_state_total{state="S1"} 1`,
+		`fsm_processing_duration_seconds_bucket{le="+Inf"} 4`,
+		"fsm_processing_duration_seconds_count 4",
+	}
+	for _, line := range expected {
+		if !strings.Contains(recorder.Body.String(), line+"\n") {
+			t.Errorf("Metrics should contain %q:\n%s", line, recorder.Body.String())
+		}
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	newTestServer(t).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"ok"`) {
+		t.Errorf("Expected healthy response, got %d %q", recorder.Code, recorder.Body.String())
+	}
+}
+
+func TestServer_Logging(t *testing.T) {
+	machine, _ := modulo.NewModFSM(3, 2)
+
+	var buf strings.Builder
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	s := New(machine, WithLogger(logger))
+
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/mod?input=1101", nil))
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/mod?input=12", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	var entries []map[string]any
+	for _, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("Unexpected error decoding log line: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if entries[0]["msg"] != "processed request" || entries[0]["status"] != float64(200) ||
+		entries[0]["final_state"] != "S1" || entries[0]["input_length"] != float64(4) {
+		t.Errorf("Unexpected success log entry: %v", entries[0])
+	}
+	if entries[1]["status"] != float64(400) || entries[1]["error"] == nil {
+		t.Errorf("Unexpected failure log entry: %v", entries[1])
+	}
+}
+
+func TestServer_Definition(t *testing.T) {
+	s := newTestServer(t)
+
+	recorder := httptest.NewRecorder()
+	s.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/definition", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+
+	definition, err := fsm.ReadDefinition(recorder.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if definition.Name != "mod3_base2" {
+		t.Errorf("Expected name mod3_base2, got %q", definition.Name)
+	}
+
+	fa, err := definition.Automaton()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if state, _ := fa.ProcessInput("1101"); state != "S1" {
+		t.Errorf("Expected final state S1, got %s", state)
+	}
+}
+
+func TestServer_Diagram(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name                string
+		method              string
+		target              string
+		expectedStatus      int
+		expectedContentType string
+		expectedPrefix      string
+	}{
+		{"default", http.MethodGet, "/v1/diagram", http.StatusOK, "image/svg+xml", "<svg"},
+		{"svg", http.MethodGet, "/v1/diagram?format=svg", http.StatusOK, "image/svg+xml", "<svg"},
+		{"dot", http.MethodGet, "/v1/diagram?format=dot", http.StatusOK, "text/vnd.graphviz", "digraph"},
+		{"unknown format", http.MethodGet, "/v1/diagram?format=png", http.StatusBadRequest, "application/json", "{"},
+		{"wrong method", http.MethodPost, "/v1/diagram", http.StatusMethodNotAllowed, "application/json", "{"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, httptest.NewRequest(test.method, test.target, nil))
+
+			if recorder.Code != test.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", test.expectedStatus, recorder.Code)
+			}
+			if contentType := recorder.Header().Get("Content-Type"); contentType != test.expectedContentType {
+				t.Errorf("Expected content type %q, got %q", test.expectedContentType, contentType)
+			}
+			if body := strings.TrimSpace(recorder.Body.String()); !strings.HasPrefix(body, test.expectedPrefix) {
+				t.Errorf("Expected body to start with %q, got %.40q", test.expectedPrefix, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"fsm-modulo-three/fsm"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// stepUndoDepth bounds how many steps a session can undo, and so the
+	// history each open connection holds.
+	stepUndoDepth = 1000
+	// stepIdleTimeout closes sessions that send no message for this long.
+	stepIdleTimeout  = 5 * time.Minute
+	stepWriteTimeout = 10 * time.Second
+)
+
+// stepSession is one connection's runner. steps counts the steps taken since
+// the last reset, which can exceed the undo depth.
+type stepSession struct {
+	runner *fsm.Runner
+	steps  int
+}
+
+type StepCommand struct {
+	Symbol fsm.Symbol `json:"symbol,omitempty"`
+	Action string     `json:"action,omitempty"`
+}
+
+type StepEvent struct {
+	Transition *fsm.Transition `json:"transition,omitempty"`
+	State      fsm.State       `json:"state"`
+	Accepting  bool            `json:"accepting"`
+	Steps      int             `json:"steps"`
+	Error      string          `json:"error,omitempty"`
+}
+
+func (s *Server) handleStep(w http.ResponseWriter, r *http.Request) {
+	conn, err := upgradeWebSocket(w, r)
+	if err != nil {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+			return
+		}
+		w.Header().Set("Upgrade", "websocket")
+		writeJSON(w, http.StatusUpgradeRequired, errorResponse{Error: err.Error()})
+		return
+	}
+	defer conn.Close()
+	conn.maxMessageSize = s.maxStepMessageSize()
+	conn.readTimeout, conn.writeTimeout = stepIdleTimeout, stepWriteTimeout
+
+	session := &stepSession{runner: fsm.NewRunner(s.machine.GetAutomaton(), fsm.WithHistoryLimit(stepUndoDepth))}
+	if s.logger != nil {
+		s.logger.LogAttrs(r.Context(), slog.LevelInfo, "stepping session opened", slog.String("remote", r.RemoteAddr))
+	}
+
+	if err := sendStep(conn, session, nil, nil); err != nil {
+		return
+	}
+	for {
+		message, err := conn.ReadMessage()
+		if err != nil {
+			if s.logger != nil && !errors.Is(err, errWebSocketClosed) && !errors.Is(err, io.EOF) {
+				s.logger.LogAttrs(r.Context(), slog.LevelWarn, "stepping session failed", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		transition, err := s.applyStep(session, message)
+		if err := sendStep(conn, session, transition, err); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) applyStep(session *stepSession, message []byte) (*fsm.Transition, error) {
+	var command StepCommand
+	if err := json.Unmarshal(message, &command); err != nil {
+		return nil, errors.New("invalid JSON command: " + err.Error())
+	}
+
+	switch command.Action {
+	case "", "step":
+		if command.Symbol == "" {
+			return nil, errors.New("missing symbol")
+		}
+		if err := s.checkInputLength(string(command.Symbol)); err != nil {
+			return nil, err
+		}
+		if _, err := session.runner.Step(command.Symbol); err != nil {
+			return nil, err
+		}
+		session.steps++
+		transition, _ := session.runner.LastTransition()
+		return &transition, nil
+	case "undo":
+		if _, ok := session.runner.LastTransition(); !ok && session.steps > 0 {
+			return nil, fmt.Errorf("cannot undo more than %d steps", stepUndoDepth)
+		}
+		if err := session.runner.Rollback(1); err != nil {
+			return nil, err
+		}
+		session.steps--
+		return nil, nil
+	case "reset":
+		session.runner.Reset()
+		session.steps = 0
+		return nil, nil
+	}
+	return nil, errors.New("unknown action '" + command.Action + "', expected step, undo or reset")
+}
+
+// maxStepMessageSize applies the input length limit to each websocket frame.
+func (s *Server) maxStepMessageSize() int {
+	if s.maxInputLength > 0 && s.maxInputLength+maxRequestOverhead < maxWebSocketMessageSize {
+		return s.maxInputLength + maxRequestOverhead
+	}
+	return maxWebSocketMessageSize
+}
+
+func sendStep(conn *wsConn, session *stepSession, transition *fsm.Transition, stepErr error) error {
+	event := StepEvent{
+		Transition: transition,
+		State:      session.runner.CurrentState(),
+		Accepting:  session.runner.IsAccepting(),
+		Steps:      session.steps,
+	}
+	if stepErr != nil {
+		event.Error = stepErr.Error()
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return conn.WriteMessage(payload)
+}
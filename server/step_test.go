@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServer_Step(t *testing.T) {
+	httpServer := httptest.NewServer(newTestServer(t))
+	defer httpServer.Close()
+	ws := dialWebSocket(t, httpServer, "/v1/step")
+
+	receive := func(t *testing.T) StepEvent {
+		t.Helper()
+		_, payload := ws.readFrame(t)
+		var event StepEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return event
+	}
+
+	if event := receive(t); event.State != "S0" || !event.Accepting || event.Transition != nil {
+		t.Fatalf("Expected initial event in S0, got %+v", event)
+	}
+
+	tests := []struct {
+		name          string
+		command       string
+		expectedState string
+		expectedFrom  string
+		expectedSteps int
+		expectError   bool
+	}{
+		{"step 1", `{"symbol":"1"}`, "S1", "S0", 1, false},
+		{"step 0", `{"symbol":"0"}`, "S2", "S1", 2, false},
+		{"explicit step", `{"action":"step","symbol":"1"}`, "S2", "S2", 3, false},
+		{"invalid symbol", `{"symbol":"7"}`, "S2", "", 3, true},
+		{"undo", `{"action":"undo"}`, "S2", "", 2, false},
+		{"missing symbol", `{}`, "S2", "", 2, true},
+		{"bad json", `{"symbol":`, "S2", "", 2, true},
+		{"unknown action", `{"action":"jump"}`, "S2", "", 2, true},
+		{"reset", `{"action":"reset"}`, "S0", "", 0, false},
+		{"undo at start", `{"action":"undo"}`, "S0", "", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ws.send(t, test.command)
+			event := receive(t)
+
+			if string(event.State) != test.expectedState {
+				t.Errorf("Expected state %s, got %s", test.expectedState, event.State)
+			}
+			if event.Steps != test.expectedSteps {
+				t.Errorf("Expected %d steps, got %d", test.expectedSteps, event.Steps)
+			}
+			if (event.Error != "") != test.expectError {
+				t.Errorf("Expected error %v, got %q", test.expectError, event.Error)
+			}
+			if test.expectedFrom == "" {
+				if event.Transition != nil {
+					t.Errorf("Expected no transition, got %+v", event.Transition)
+				}
+				return
+			}
+			if event.Transition == nil || string(event.Transition.From) != test.expectedFrom || event.Transition.To != event.State {
+				t.Errorf("Expected transition from %s to %s, got %+v", test.expectedFrom, test.expectedState, event.Transition)
+			}
+		})
+	}
+}
+
+func TestServer_StepUndoDepth(t *testing.T) {
+	httpServer := httptest.NewServer(newTestServer(t))
+	defer httpServer.Close()
+	ws := dialWebSocket(t, httpServer, "/v1/step")
+	ws.readFrame(t)
+
+	var event StepEvent
+	send := func(command string) {
+		t.Helper()
+		ws.send(t, command)
+		_, payload := ws.readFrame(t)
+		event = StepEvent{}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	for i := 0; i < stepUndoDepth+5; i++ {
+		send(`{"symbol":"1"}`)
+	}
+	if event.Steps != stepUndoDepth+5 {
+		t.Fatalf("Expected %d steps, got %d", stepUndoDepth+5, event.Steps)
+	}
+	for i := 0; i < stepUndoDepth; i++ {
+		send(`{"action":"undo"}`)
+		if event.Error != "" {
+			t.Fatalf("Unexpected error on undo %d: %s", i+1, event.Error)
+		}
+	}
+	send(`{"action":"undo"}`)
+	if !strings.Contains(event.Error, "cannot undo more than") || event.Steps != 5 {
+		t.Errorf("Expected the undo depth error with 5 steps left, got %+v", event)
+	}
+}
+
+func TestServer_StepMaxInputLength(t *testing.T) {
+	httpServer := httptest.NewServer(newTestServer(t, WithMaxInputLength(4)))
+	defer httpServer.Close()
+	ws := dialWebSocket(t, httpServer, "/v1/step")
+	ws.readFrame(t)
+
+	ws.send(t, `{"symbol":"10101"}`)
+	_, payload := ws.readFrame(t)
+	var event StepEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(event.Error, "exceeds the maximum of 4") || event.Steps != 0 {
+		t.Errorf("Expected an input length error, got %+v", event)
+	}
+
+	ws.send(t, `{"symbol":"`+strings.Repeat("1", 2*maxRequestOverhead)+`"}`)
+	if opcode, payload := ws.readFrame(t); opcode != opClose || binary.BigEndian.Uint16(payload) != 1002 {
+		t.Errorf("Expected close 1002 for an oversized frame, got opcode %#x and %v", opcode, payload)
+	}
+}
+
+func TestServer_StepRequiresUpgrade(t *testing.T) {
+	s := newTestServer(t)
+
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+	}{
+		{"plain get", http.MethodGet, http.StatusUpgradeRequired},
+		{"post", http.MethodPost, http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			s.ServeHTTP(recorder, httptest.NewRequest(test.method, "/v1/step", nil))
+
+			if recorder.Code != test.expectedStatus {
+				t.Errorf("Expected status %d, got %d", test.expectedStatus, recorder.Code)
+			}
+		})
+	}
+}
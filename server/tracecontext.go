@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context headers, https://www.w3.org/TR/trace-context/.
+const (
+	TraceparentHeader = "traceparent"
+	TracestateHeader  = "tracestate"
+
+	maxTracestateLength = 512
+)
+
+// SpanContext identifies a span across process boundaries, as carried by the
+// traceparent and tracestate headers.
+type SpanContext struct {
+	TraceID    [16]byte
+	SpanID     [8]byte
+	Flags      byte
+	TraceState string
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+func (sc SpanContext) Sampled() bool {
+	return sc.Flags&0x01 != 0
+}
+
+// Traceparent formats sc as a version 00 traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, sc.Flags)
+}
+
+// ParseTraceparent parses a traceparent header value. Versions above 00 are
+// read by their 00 prefix, as the specification asks.
+func ParseTraceparent(value string) (SpanContext, error) {
+	var sc SpanContext
+	if len(value) < 55 {
+		return sc, errors.New("traceparent: too short")
+	}
+	version := value[:2]
+	if !isLowerHex(version) || version == "ff" {
+		return sc, fmt.Errorf("traceparent: invalid version %q", version)
+	}
+	if version == "00" && len(value) != 55 {
+		return sc, errors.New("traceparent: version 00 must be 55 characters")
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return sc, errors.New("traceparent: malformed trailing fields")
+	}
+	if value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, errors.New("traceparent: malformed separators")
+	}
+
+	traceID, spanID, flags := value[3:35], value[36:52], value[53:55]
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return sc, errors.New("traceparent: fields must be lowercase hex")
+	}
+	hex.Decode(sc.TraceID[:], []byte(traceID))
+	hex.Decode(sc.SpanID[:], []byte(spanID))
+	var flagByte [1]byte
+	hex.Decode(flagByte[:], []byte(flags))
+	sc.Flags = flagByte[0]
+	if !sc.IsValid() {
+		return SpanContext{}, errors.New("traceparent: trace and parent IDs must not be zero")
+	}
+	return sc, nil
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns ctx carrying sc. Tracer adapters call it with
+// the span they start, so InjectTraceContext forwards the child span.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the innermost span context in ctx: the
+// caller's, as extracted from the request headers, until a tracer starts a
+// span of its own.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok
+}
+
+// ExtractTraceContext reads the traceparent and tracestate headers. An
+// invalid or repeated traceparent is ignored, and so is tracestate then.
+func ExtractTraceContext(header http.Header) (SpanContext, bool) {
+	values := header.Values(TraceparentHeader)
+	if len(values) != 1 {
+		return SpanContext{}, false
+	}
+	sc, err := ParseTraceparent(strings.TrimSpace(values[0]))
+	if err != nil {
+		return SpanContext{}, false
+	}
+	if state := strings.Join(header.Values(TracestateHeader), ","); len(state) <= maxTracestateLength {
+		sc.TraceState = state
+	}
+	return sc, true
+}
+
+// InjectTraceContext writes the span context in ctx to header, for requests
+// made on behalf of the one being served.
+func InjectTraceContext(ctx context.Context, header http.Header) {
+	sc, ok := SpanContextFromContext(ctx)
+	if !ok || !sc.IsValid() {
+		return
+	}
+	header.Set(TraceparentHeader, sc.Traceparent())
+	if sc.TraceState != "" {
+		header.Set(TracestateHeader, sc.TraceState)
+	} else {
+		header.Del(TracestateHeader)
+	}
+}
+
+// withTraceContext moves the caller's trace context from r's headers into its
+// context, where tracers and InjectTraceContext find it.
+func withTraceContext(r *http.Request) *http.Request {
+	if r.Header.Get(TraceparentHeader) == "" {
+		return r
+	}
+	sc, ok := ExtractTraceContext(r.Header)
+	if !ok {
+		return r
+	}
+	return r.WithContext(ContextWithSpanContext(r.Context(), sc))
+}
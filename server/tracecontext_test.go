@@ -0,0 +1,112 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fsm-modulo-three/modulo"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"valid", testTraceparent, true},
+		{"unsampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"future version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"future version trailing garbage", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x", false},
+		{"version ff", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"version 00 too long", testTraceparent + "-extra", false},
+		{"uppercase", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"bad separator", "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"short", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sc, err := ParseTraceparent(test.value)
+			if (err == nil) != test.valid {
+				t.Fatalf("Expected valid %v, got %v", test.valid, err)
+			}
+			if test.valid && !strings.HasPrefix(sc.Traceparent(), "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-") {
+				t.Errorf("Expected the IDs to round-trip, got %s", sc.Traceparent())
+			}
+		})
+	}
+}
+
+func TestTraceContext_ExtractInject(t *testing.T) {
+	incoming := http.Header{}
+	incoming.Set(TraceparentHeader, testTraceparent)
+	incoming.Set(TracestateHeader, "congo=t61rcWkgMzE")
+
+	sc, ok := ExtractTraceContext(incoming)
+	if !ok || !sc.Sampled() || sc.TraceState != "congo=t61rcWkgMzE" {
+		t.Fatalf("Expected a sampled span context with tracestate, got %+v (%v)", sc, ok)
+	}
+
+	outgoing := http.Header{}
+	InjectTraceContext(ContextWithSpanContext(context.Background(), sc), outgoing)
+	if outgoing.Get(TraceparentHeader) != testTraceparent || outgoing.Get(TracestateHeader) != "congo=t61rcWkgMzE" {
+		t.Errorf("Expected the headers to round-trip, got %v", outgoing)
+	}
+
+	empty := http.Header{}
+	InjectTraceContext(context.Background(), empty)
+	if len(empty) != 0 {
+		t.Errorf("Expected nothing injected without a span context, got %v", empty)
+	}
+
+	incoming.Add(TraceparentHeader, testTraceparent)
+	if _, ok := ExtractTraceContext(incoming); ok {
+		t.Error("Expected a repeated traceparent to be ignored")
+	}
+}
+
+type contextTracer struct {
+	parents []SpanContext
+}
+
+func (t *contextTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	sc, _ := SpanContextFromContext(ctx)
+	t.parents = append(t.parents, sc)
+	return ctx, noopSpan{}
+}
+
+func TestServer_TraceContext(t *testing.T) {
+	machine, _ := modulo.NewModFSM(3, 2)
+	tracer := &contextTracer{}
+	var logs bytes.Buffer
+	s := New(machine, WithTracer(tracer), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/mod?input=1101", nil)
+	request.Header.Set(TraceparentHeader, testTraceparent)
+	s.ServeHTTP(httptest.NewRecorder(), request)
+
+	untraced := httptest.NewRequest(http.MethodGet, "/v1/mod?input=1101", nil)
+	untraced.Header.Set(TraceparentHeader, "garbage")
+	s.ServeHTTP(httptest.NewRecorder(), untraced)
+
+	if len(tracer.parents) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.parents))
+	}
+	if tracer.parents[0].Traceparent() != testTraceparent {
+		t.Errorf("Expected the span to start under the caller's trace, got %s", tracer.parents[0].Traceparent())
+	}
+	if tracer.parents[1].IsValid() {
+		t.Errorf("Expected an invalid traceparent to be ignored, got %s", tracer.parents[1].Traceparent())
+	}
+	if !strings.Contains(logs.String(), "trace_id=4bf92f3577b34da6a3ce929d0e0e4736") {
+		t.Errorf("Expected the request log to carry the trace ID, got %s", logs.String())
+	}
+}
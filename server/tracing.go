@@ -0,0 +1,36 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+)
+
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+	RecordError(err error)
+	End()
+}
+
+func WithTracer(tracer Tracer) Option {
+	return func(s *Server) {
+		s.tracer = tracer
+	}
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...slog.Attr) {}
+
+func (noopSpan) RecordError(err error) {}
+
+func (noopSpan) End() {}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"fsm-modulo-three/modulo"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type contextKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent any
+	attrs  map[string]slog.Value
+	err    error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *recordedSpan) End() {
+	s.ended = true
+}
+
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, parent: ctx.Value(contextKey{}), attrs: map[string]slog.Value{}}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, contextKey{}, span), span
+}
+
+func TestServer_Tracing(t *testing.T) {
+	machine, _ := modulo.NewModFSM(3, 2)
+	tracer := &recordingTracer{}
+	s := New(machine, WithTracer(tracer))
+
+	request := httptest.NewRequest(http.MethodGet, "/v1/mod?input=1101", nil)
+	request = request.WithContext(context.WithValue(request.Context(), contextKey{}, "caller"))
+	s.ServeHTTP(httptest.NewRecorder(), request)
+	s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/mod?input=12", nil))
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	success := tracer.spans[0]
+	if success.name != "fsm.Mod" || !success.ended || success.err != nil {
+		t.Errorf("Unexpected success span: %+v", success)
+	}
+	if success.parent != "caller" {
+		t.Errorf("Expected span to start from the request context, got parent %v", success.parent)
+	}
+
+	expected := map[string]string{
+		"fsm.input_length": "4",
+		"fsm.modulus":      "3",
+		"fsm.base":         "2",
+		"fsm.final_state":  "S1",
+		"fsm.remainder":    "1",
+	}
+	for key, value := range expected {
+		if got := success.attrs[key].String(); got != value {
+			t.Errorf("Expected attribute %s=%s, got %q", key, value, got)
+		}
+	}
+
+	failure := tracer.spans[1]
+	if !failure.ended || failure.err == nil {
+		t.Errorf("Expected failure span to record an error, got %+v", failure)
+	}
+	if _, ok := failure.attrs["fsm.final_state"]; ok {
+		t.Errorf("Expected no final state attribute on failure, got %v", failure.attrs)
+	}
+}
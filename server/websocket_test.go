@@ -0,0 +1,226 @@
+package server
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testWebSocket struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func dialWebSocket(t *testing.T, httpServer *httptest.Server, path string) *testWebSocket {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(httpServer.URL, "http://"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", path, key)
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status %d, got %d", http.StatusSwitchingProtocols, response.StatusCode)
+	}
+	if accept := response.Header.Get("Sec-WebSocket-Accept"); accept != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("Expected RFC 6455 accept key, got %q", accept)
+	}
+
+	return &testWebSocket{conn: conn, reader: reader}
+}
+
+func (ws *testWebSocket) writeFrame(t *testing.T, fin bool, opcode byte, payload []byte, masked bool) {
+	t.Helper()
+
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	frame := []byte{first}
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	if len(payload) <= 125 {
+		frame = append(frame, maskBit|byte(len(payload)))
+	} else {
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(len(payload)))
+	}
+	body := append([]byte(nil), payload...)
+	if masked {
+		mask := []byte{0x12, 0x34, 0x56, 0x78}
+		frame = append(frame, mask...)
+		for i := range body {
+			body[i] ^= mask[i%4]
+		}
+	}
+	if _, err := ws.conn.Write(append(frame, body...)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func (ws *testWebSocket) send(t *testing.T, message string) {
+	t.Helper()
+	ws.writeFrame(t, true, opText, []byte(message), true)
+}
+
+func (ws *testWebSocket) readFrame(t *testing.T) (byte, []byte) {
+	t.Helper()
+
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if header[1]&0x80 != 0 {
+		t.Fatalf("Expected unmasked server frame")
+	}
+	length := int(header[1] & 0x7F)
+	if length == 126 {
+		var extended [2]byte
+		io.ReadFull(ws.reader, extended[:])
+		length = int(binary.BigEndian.Uint16(extended[:]))
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return header[0] & 0x0F, payload
+}
+
+func newEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebSocket(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+		for {
+			message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(message)
+		}
+	}))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func TestWebSocket_Messages(t *testing.T) {
+	ws := dialWebSocket(t, newEchoServer(t), "/")
+
+	long := strings.Repeat("x", 300)
+	tests := []struct {
+		name   string
+		frames []string
+	}{
+		{"single frame", []string{"hello"}},
+		{"extended length", []string{long}},
+		{"fragmented", []string{"hel", "lo ", "world"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for i, part := range test.frames {
+				opcode := byte(opText)
+				if i > 0 {
+					opcode = opContinuation
+				}
+				ws.writeFrame(t, i == len(test.frames)-1, opcode, []byte(part), true)
+			}
+
+			opcode, payload := ws.readFrame(t)
+			if opcode != opText {
+				t.Errorf("Expected text frame, got opcode %#x", opcode)
+			}
+			if expected := strings.Join(test.frames, ""); string(payload) != expected {
+				t.Errorf("Expected %q, got %q", expected, payload)
+			}
+		})
+	}
+}
+
+func TestWebSocket_Control(t *testing.T) {
+	ws := dialWebSocket(t, newEchoServer(t), "/")
+
+	ws.writeFrame(t, true, opPing, []byte("ping"), true)
+	if opcode, payload := ws.readFrame(t); opcode != opPong || string(payload) != "ping" {
+		t.Errorf("Expected pong with ping payload, got opcode %#x and %q", opcode, payload)
+	}
+
+	ws.writeFrame(t, true, opClose, binary.BigEndian.AppendUint16(nil, 1000), true)
+	if opcode, payload := ws.readFrame(t); opcode != opClose || binary.BigEndian.Uint16(payload) != 1000 {
+		t.Errorf("Expected close 1000, got opcode %#x and %v", opcode, payload)
+	}
+}
+
+func TestWebSocket_RejectsUnmaskedFrames(t *testing.T) {
+	ws := dialWebSocket(t, newEchoServer(t), "/")
+
+	ws.writeFrame(t, true, opText, []byte("hello"), false)
+	if opcode, payload := ws.readFrame(t); opcode != opClose || binary.BigEndian.Uint16(payload) != 1002 {
+		t.Errorf("Expected close 1002, got opcode %#x and %v", opcode, payload)
+	}
+}
+
+func TestWebSocket_ReadTimeout(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := &wsConn{conn: server, reader: bufio.NewReader(server), readTimeout: 20 * time.Millisecond}
+	defer conn.Close()
+
+	// Send a frame header but never the rest, as a slow client would.
+	go client.Write([]byte{0x81, 0x85})
+
+	_, err := conn.ReadMessage()
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("Expected a timeout, got %v", err)
+	}
+}
+
+func TestWebSocket_Handshake(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		headers map[string]string
+	}{
+		{"not an upgrade", http.MethodGet, map[string]string{}},
+		{"wrong version", http.MethodGet, map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8", "Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ=="}},
+		{"bad key", http.MethodGet, map[string]string{"Connection": "keep-alive, Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "short"}},
+		{"wrong method", http.MethodPost, map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "dGhlIHNhbXBsZSBub25jZQ=="}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			request := httptest.NewRequest(test.method, "/", nil)
+			for name, value := range test.headers {
+				request.Header.Set(name, value)
+			}
+			if _, err := upgradeWebSocket(httptest.NewRecorder(), request); err == nil {
+				t.Errorf("Expected upgrade to fail")
+			}
+		})
+	}
+}
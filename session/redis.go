@@ -0,0 +1,233 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fsm-modulo-three/fsm"
+)
+
+type RedisOption func(*RedisStore)
+
+func WithPrefix(prefix string) RedisOption {
+	return func(s *RedisStore) {
+		s.prefix = prefix
+	}
+}
+
+func WithTTL(ttl time.Duration) RedisOption {
+	return func(s *RedisStore) {
+		s.ttl = ttl
+	}
+}
+
+func WithPassword(password string) RedisOption {
+	return func(s *RedisStore) {
+		s.password = password
+	}
+}
+
+// maxIdleRedisConns bounds the connections a RedisStore keeps open between
+// calls. Busier stores dial extra connections and close them afterwards.
+const maxIdleRedisConns = 8
+
+type RedisStore struct {
+	addr     string
+	prefix   string
+	ttl      time.Duration
+	password string
+
+	mu     sync.Mutex
+	idle   []*redisConn
+	closed bool
+}
+
+type redisConn struct {
+	conn net.Conn
+	rw   *bufio.ReadWriter
+}
+
+func NewRedisStore(addr string, options ...RedisOption) *RedisStore {
+	s := &RedisStore{addr: addr, prefix: "fsm:session:"}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (fsm.Snapshot, bool, error) {
+	reply, err := s.do(ctx, "GET", s.prefix+key)
+	if err != nil {
+		return fsm.Snapshot{}, false, err
+	}
+	if reply == nil {
+		return fsm.Snapshot{}, false, nil
+	}
+
+	var snapshot fsm.Snapshot
+	if err := json.Unmarshal(reply, &snapshot); err != nil {
+		return fsm.Snapshot{}, false, fmt.Errorf("decoding session %q: %w", key, err)
+	}
+	return snapshot, true, nil
+}
+
+func (s *RedisStore) Put(ctx context.Context, key string, snapshot fsm.Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"SET", s.prefix + key, string(data)}
+	if s.ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(s.ttl.Milliseconds(), 10))
+	}
+	_, err = s.do(ctx, args...)
+	return err
+}
+
+// Close closes the idle connections. Calls still in flight finish and close
+// their connection instead of returning it.
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	idle := s.idle
+	s.idle, s.closed = nil, true
+	s.mu.Unlock()
+
+	var errs []error
+	for _, c := range idle {
+		errs = append(errs, c.conn.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// do runs one command on a connection of its own, so a slow call does not
+// hold up the others. The context's deadline bounds the round trip, and
+// cancelling it interrupts a call that is blocked on the network.
+func (s *RedisStore) do(ctx context.Context, args ...string) ([]byte, error) {
+	c, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, _ := ctx.Deadline()
+	c.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() {
+		c.conn.SetDeadline(time.Unix(1, 0))
+	})
+
+	reply, err := c.roundTrip(args)
+	if !stop() {
+		// The context ended during the call and left the deadline in the
+		// past, so the connection cannot be reused.
+		c.conn.Close()
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", ctx.Err())
+		}
+		return reply, nil
+	}
+
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		c.conn.Close()
+		return nil, err
+	}
+	s.release(c)
+	return reply, err
+}
+
+func (s *RedisStore) acquire(ctx context.Context) (*redisConn, error) {
+	s.mu.Lock()
+	if n := len(s.idle); n > 0 {
+		c := s.idle[n-1]
+		s.idle = s.idle[:n-1]
+		s.mu.Unlock()
+		return c, nil
+	}
+	s.mu.Unlock()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to redis: %w", err)
+	}
+	c := &redisConn{conn: conn, rw: bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))}
+
+	if s.password != "" {
+		deadline, _ := ctx.Deadline()
+		conn.SetDeadline(deadline)
+		if _, err := c.roundTrip([]string{"AUTH", s.password}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("authenticating to redis: %w", err)
+		}
+	}
+	return c, nil
+}
+
+func (s *RedisStore) release(c *redisConn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || len(s.idle) >= maxIdleRedisConns {
+		c.conn.Close()
+		return
+	}
+	s.idle = append(s.idle, c)
+}
+
+func (c *redisConn) roundTrip(args []string) ([]byte, error) {
+	fmt.Fprintf(c.rw, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(c.rw, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if err := c.rw.Flush(); err != nil {
+		return nil, err
+	}
+	return readReply(c.rw.Reader)
+}
+
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+func readReply(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, redisError(line[1:])
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line[1:])
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:length], nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,228 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fsm-modulo-three/fsm"
+)
+
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	// stall, when set, holds every command on the key "fsm:session:stall"
+	// until it is closed.
+	stall chan struct{}
+
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f := &fakeRedis{listener: listener, password: password, data: make(map[string]string)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authenticated := f.password == ""
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if f.stall != nil && len(args) > 1 && args[1] == "fsm:session:stall" {
+			<-f.stall
+		}
+
+		f.mu.Lock()
+		f.commands = append(f.commands, strings.Join(args, " "))
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			if args[1] == f.password {
+				authenticated = true
+				reply = "+OK\r\n"
+			} else {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "GET":
+			if value, ok := f.data[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "SET":
+			f.data[args[1]] = args[2]
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+
+		io.WriteString(conn, reply)
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+
+	args := make([]string, count)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		length, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:length])
+	}
+	return args, nil
+}
+
+func TestRedisStore(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store := NewRedisStore(server.listener.Addr().String())
+	defer store.Close()
+
+	testStore(t, store)
+
+	if _, ok := server.data["fsm:session:doc-1"]; !ok {
+		t.Errorf("Expected snapshot under the default prefix, got keys %v", server.data)
+	}
+}
+
+func TestRedisStore_Options(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	store := NewRedisStore(server.listener.Addr().String(), WithPrefix("wf:"), WithTTL(90*time.Second), WithPassword("secret"))
+	defer store.Close()
+
+	runner, _ := Load(context.Background(), store, "a", newTestAutomaton())
+	runner.Step("submit")
+	if err := Save(context.Background(), store, "a", runner); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.commands[0] != "AUTH secret" {
+		t.Errorf("Expected AUTH first, got %v", server.commands)
+	}
+	set := server.commands[len(server.commands)-1]
+	if !strings.HasPrefix(set, "SET wf:a ") || !strings.HasSuffix(set, " PX 90000") {
+		t.Errorf("Expected SET with prefix and TTL, got %q", set)
+	}
+}
+
+func TestRedisStore_Errors(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	wrong := NewRedisStore(server.listener.Addr().String(), WithPassword("nope"))
+	if _, _, err := wrong.Get(context.Background(), "a"); err == nil {
+		t.Error("Expected authentication error, but got none")
+	}
+
+	server.data["fsm:session:corrupt"] = "{not json"
+	store := NewRedisStore(server.listener.Addr().String(), WithPassword("secret"))
+	defer store.Close()
+	if _, _, err := store.Get(context.Background(), "corrupt"); err == nil {
+		t.Error("Expected decoding error, but got none")
+	}
+
+	server.listener.Close()
+	unreachable := NewRedisStore(server.listener.Addr().String())
+	if _, _, err := unreachable.Get(context.Background(), "a"); err == nil {
+		t.Error("Expected connection error, but got none")
+	}
+}
+
+func TestRedisStore_ContextDeadline(t *testing.T) {
+	server := newFakeRedis(t, "")
+	server.stall = make(chan struct{})
+	defer close(server.stall)
+	store := NewRedisStore(server.listener.Addr().String())
+	defer store.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := store.Get(ctx, "stall"); err == nil {
+		t.Fatal("Expected the deadline to interrupt the call, but got no error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the call to return at the deadline, took %v", elapsed)
+	}
+
+	if err := store.Put(context.Background(), "a", fsm.Snapshot{State: "Draft"}); err != nil {
+		t.Errorf("Expected the store to recover after an interrupted call, got %v", err)
+	}
+}
+
+func TestRedisStore_Cancel(t *testing.T) {
+	server := newFakeRedis(t, "")
+	server.stall = make(chan struct{})
+	defer close(server.stall)
+	store := NewRedisStore(server.listener.Addr().String())
+	defer store.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := store.Get(ctx, "stall")
+		done <- err
+	}()
+
+	// A stalled call must not hold up calls on other keys.
+	if err := store.Put(context.Background(), "a", fsm.Snapshot{State: "Draft"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected cancellation to interrupt the stalled call")
+	}
+}
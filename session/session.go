@@ -0,0 +1,60 @@
+package session
+
+import (
+	"context"
+	"sync"
+
+	"fsm-modulo-three/fsm"
+)
+
+type Store interface {
+	Get(ctx context.Context, key string) (fsm.Snapshot, bool, error)
+	Put(ctx context.Context, key string, snapshot fsm.Snapshot) error
+}
+
+type MemoryStore struct {
+	mu        sync.Mutex
+	snapshots map[string]fsm.Snapshot
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: make(map[string]fsm.Snapshot)}
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (fsm.Snapshot, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, ok := s.snapshots[key]
+	if !ok {
+		return fsm.Snapshot{}, false, nil
+	}
+	snapshot.History = append([]fsm.Transition(nil), snapshot.History...)
+	return snapshot, true, nil
+}
+
+func (s *MemoryStore) Put(ctx context.Context, key string, snapshot fsm.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot.History = append([]fsm.Transition(nil), snapshot.History...)
+	s.snapshots[key] = snapshot
+	return nil
+}
+
+func Load(ctx context.Context, store Store, key string, automaton *fsm.FiniteAutomaton, options ...fsm.RunnerOption) (*fsm.Runner, error) {
+	runner := fsm.NewRunner(automaton, options...)
+
+	snapshot, ok, err := store.Get(ctx, key)
+	if err != nil || !ok {
+		return runner, err
+	}
+	if err := runner.Restore(snapshot); err != nil {
+		return nil, err
+	}
+	return runner, nil
+}
+
+func Save(ctx context.Context, store Store, key string, runner *fsm.Runner) error {
+	return store.Put(ctx, key, runner.Snapshot())
+}
@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+)
+
+func newTestAutomaton() *fsm.FiniteAutomaton {
+	table := fsm.TransitionTable{}
+	table.Set("Draft", "submit", "Review")
+	table.Set("Review", "approve", "Published")
+	table.Set("Review", "reject", "Draft")
+
+	return fsm.NewTableAutomaton(
+		[]fsm.State{"Draft", "Review", "Published"},
+		[]fsm.Symbol{"submit", "approve", "reject"},
+		"Draft",
+		[]fsm.State{"Published"},
+		table,
+	)
+}
+
+func testStore(t *testing.T, store Store) {
+	t.Helper()
+	ctx := context.Background()
+	automaton := newTestAutomaton()
+
+	runner, err := Load(ctx, store, "doc-1", automaton, fsm.WithHistory())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if runner.CurrentState() != "Draft" {
+		t.Errorf("Expected a new session to start in Draft, got %s", runner.CurrentState())
+	}
+
+	runner.Step("submit")
+	if err := Save(ctx, store, "doc-1", runner); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	restored, err := Load(ctx, store, "doc-1", automaton, fsm.WithHistory())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if restored.CurrentState() != "Review" {
+		t.Errorf("Expected restored state Review, got %s", restored.CurrentState())
+	}
+	if history := restored.History(); len(history) != 1 || history[0] != (fsm.Transition{From: "Draft", Symbol: "submit", To: "Review"}) {
+		t.Errorf("Expected restored history, got %v", history)
+	}
+
+	restored.Step("approve")
+	if !restored.IsAccepting() {
+		t.Error("Expected restored runner to continue the workflow")
+	}
+
+	if _, ok, err := store.Get(ctx, "doc-2"); ok || err != nil {
+		t.Errorf("Expected missing key, got ok=%v err=%v", ok, err)
+	}
+
+	if err := store.Put(ctx, "bad", fsm.Snapshot{State: "Archived"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := Load(ctx, store, "bad", automaton); err == nil {
+		t.Error("Expected error restoring an unknown state, but got none")
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
+
+func TestMemoryStore_CopiesHistory(t *testing.T) {
+	store := NewMemoryStore()
+	history := []fsm.Transition{{From: "Draft", Symbol: "submit", To: "Review"}}
+	store.Put(context.Background(), "k", fsm.Snapshot{State: "Review", History: history})
+	history[0].To = "Published"
+
+	snapshot, _, _ := store.Get(context.Background(), "k")
+	if snapshot.History[0].To != "Review" {
+		t.Error("Expected the store to keep its own copy of the history")
+	}
+}
@@ -0,0 +1,354 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/session"
+)
+
+type Event string
+
+type Guard func(ctx context.Context, payload any) error
+
+type Action func(ctx context.Context, payload any) error
+
+type Hook func(ctx context.Context, id string, transition Transition, payload any)
+
+type Transition struct {
+	Event    Event
+	From     fsm.State
+	To       fsm.State
+	Guard    Guard
+	Action   Action
+	Priority int
+}
+
+type ConflictPolicy int
+
+const (
+	FirstMatch ConflictPolicy = iota
+	HighestPriority
+	ErrorOnAmbiguity
+)
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case FirstMatch:
+		return "first-match"
+	case HighestPriority:
+		return "highest-priority"
+	case ErrorOnAmbiguity:
+		return "error-on-ambiguity"
+	}
+	return fmt.Sprintf("ConflictPolicy(%d)", int(p))
+}
+
+var (
+	ErrInvalidTransition   = errors.New("invalid transition")
+	ErrAmbiguousTransition = errors.New("ambiguous transition")
+)
+
+type TransitionError struct {
+	Workflow  string
+	State     fsm.State
+	Event     Event
+	Available []Event
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: event %q not available in step %s (available: %v)", e.Workflow, e.Event, e.State, e.Available)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+type AmbiguityError struct {
+	Workflow string
+	State    fsm.State
+	Event    Event
+	Targets  []fsm.State
+}
+
+func (e *AmbiguityError) Error() string {
+	return fmt.Sprintf("%s: event %q in step %s matches several transitions (targets: %v)", e.Workflow, e.Event, e.State, e.Targets)
+}
+
+func (e *AmbiguityError) Unwrap() error {
+	return ErrAmbiguousTransition
+}
+
+type GuardError struct {
+	Event Event
+	Err   error
+}
+
+func (e *GuardError) Error() string {
+	return fmt.Sprintf("guard for %q rejected the transition: %v", e.Event, e.Err)
+}
+
+func (e *GuardError) Unwrap() error {
+	return e.Err
+}
+
+type Option func(*Workflow)
+
+func WithStore(store session.Store) Option {
+	return func(w *Workflow) {
+		w.store = store
+	}
+}
+
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(w *Workflow) {
+		w.policy = policy
+	}
+}
+
+func OnTransition(hook Hook) Option {
+	return func(w *Workflow) {
+		w.hooks = append(w.hooks, hook)
+	}
+}
+
+type Workflow struct {
+	name        string
+	automaton   *fsm.FiniteAutomaton
+	transitions map[fsm.State]map[Event][]Transition
+	store       session.Store
+	hooks       []Hook
+	policy      ConflictPolicy
+}
+
+func New(name string, initial fsm.State, final []fsm.State, transitions []Transition, options ...Option) (*Workflow, error) {
+	if len(transitions) == 0 {
+		return nil, fmt.Errorf("workflow %s has no transitions", name)
+	}
+
+	w := &Workflow{
+		name:        name,
+		transitions: make(map[fsm.State]map[Event][]Transition),
+		store:       session.NewMemoryStore(),
+	}
+	for _, option := range options {
+		option(w)
+	}
+	if w.policy < FirstMatch || w.policy > ErrorOnAmbiguity {
+		return nil, fmt.Errorf("workflow %s: unknown conflict policy %v", name, w.policy)
+	}
+
+	candidates := map[fsm.State]map[Event]int{}
+	for _, t := range transitions {
+		if candidates[t.From] == nil {
+			candidates[t.From] = make(map[Event]int)
+		}
+		candidates[t.From][t.Event]++
+	}
+
+	states := []fsm.State{initial}
+	seenStates := map[fsm.State]bool{initial: true}
+	seenSymbols := map[fsm.Symbol]bool{}
+	var alphabet []fsm.Symbol
+	table := fsm.TransitionTable{}
+
+	for _, t := range transitions {
+		if t.Guard == nil {
+			for _, other := range w.transitions[t.From][t.Event] {
+				if other.Guard == nil {
+					return nil, fmt.Errorf("workflow %s: duplicate unguarded transition for %q in step %s", name, t.Event, t.From)
+				}
+			}
+		}
+		if w.transitions[t.From] == nil {
+			w.transitions[t.From] = make(map[Event][]Transition)
+		}
+		w.transitions[t.From][t.Event] = append(w.transitions[t.From][t.Event], t)
+
+		symbol := fsm.Symbol(t.Event)
+		if candidates[t.From][t.Event] > 1 {
+			symbol = conflictSymbol(t)
+		}
+		table.Set(t.From, symbol, t.To)
+		if !seenSymbols[symbol] {
+			seenSymbols[symbol] = true
+			alphabet = append(alphabet, symbol)
+		}
+
+		for _, state := range []fsm.State{t.From, t.To} {
+			if !seenStates[state] {
+				seenStates[state] = true
+				states = append(states, state)
+			}
+		}
+	}
+
+	for _, state := range final {
+		if !seenStates[state] {
+			return nil, fmt.Errorf("workflow %s: final step %s does not appear in any transition", name, state)
+		}
+	}
+
+	if w.policy == HighestPriority {
+		for _, events := range w.transitions {
+			for _, candidates := range events {
+				sort.SliceStable(candidates, func(i, j int) bool {
+					return candidates[i].Priority > candidates[j].Priority
+				})
+			}
+		}
+	}
+
+	w.automaton = fsm.NewTableAutomaton(states, alphabet, initial, final, table)
+	return w, nil
+}
+
+func conflictSymbol(t Transition) fsm.Symbol {
+	return fsm.Symbol(fmt.Sprintf("%s->%s", t.Event, t.To))
+}
+
+func (w *Workflow) Policy() ConflictPolicy {
+	return w.policy
+}
+
+func (w *Workflow) resolve(ctx context.Context, state fsm.State, event Event, payload any) (Transition, error) {
+	candidates := w.transitions[state][event]
+
+	var matched []Transition
+	var rejected []error
+	for _, t := range candidates {
+		if t.Guard != nil {
+			if err := t.Guard(ctx, payload); err != nil {
+				rejected = append(rejected, err)
+				continue
+			}
+		}
+		if w.policy != ErrorOnAmbiguity {
+			return t, nil
+		}
+		matched = append(matched, t)
+	}
+
+	switch len(matched) {
+	case 0:
+		if len(rejected) == 1 {
+			return Transition{}, &GuardError{Event: event, Err: rejected[0]}
+		}
+		return Transition{}, &GuardError{Event: event, Err: errors.Join(rejected...)}
+	case 1:
+		return matched[0], nil
+	}
+
+	targets := make([]fsm.State, len(matched))
+	for i, t := range matched {
+		targets[i] = t.To
+	}
+	return Transition{}, &AmbiguityError{Workflow: w.name, State: state, Event: event, Targets: targets}
+}
+
+func (w *Workflow) Name() string {
+	return w.name
+}
+
+func (w *Workflow) Automaton() *fsm.FiniteAutomaton {
+	return w.automaton
+}
+
+func (w *Workflow) available(state fsm.State) []Event {
+	var events []Event
+	for event := range w.transitions[state] {
+		events = append(events, event)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i] < events[j] })
+	return events
+}
+
+type Instance struct {
+	workflow *Workflow
+	id       string
+
+	mu     sync.Mutex
+	runner *fsm.Runner
+}
+
+func (w *Workflow) Start(ctx context.Context, id string) (*Instance, error) {
+	runner, err := session.Load(ctx, w.store, id, w.automaton, fsm.WithHistory())
+	if err != nil {
+		return nil, fmt.Errorf("loading %s instance %q: %w", w.name, id, err)
+	}
+	return &Instance{workflow: w, id: id, runner: runner}, nil
+}
+
+func (i *Instance) Trigger(ctx context.Context, event Event, payload any) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	state := i.runner.CurrentState()
+	if _, ok := i.workflow.transitions[state][event]; !ok {
+		return &TransitionError{
+			Workflow:  i.workflow.name,
+			State:     state,
+			Event:     event,
+			Available: i.workflow.available(state),
+		}
+	}
+
+	t, err := i.workflow.resolve(ctx, state, event, payload)
+	if err != nil {
+		return err
+	}
+	if t.Action != nil {
+		if err := t.Action(ctx, payload); err != nil {
+			return fmt.Errorf("action for %q failed: %w", event, err)
+		}
+	}
+
+	symbol := fsm.Symbol(event)
+	if len(i.workflow.transitions[state][event]) > 1 {
+		symbol = conflictSymbol(t)
+	}
+	if _, err := i.runner.Step(symbol); err != nil {
+		return err
+	}
+	if err := session.Save(ctx, i.workflow.store, i.id, i.runner); err != nil {
+		i.runner.Rollback(1)
+		return fmt.Errorf("saving %s instance %q: %w", i.workflow.name, i.id, err)
+	}
+
+	for _, hook := range i.workflow.hooks {
+		hook(ctx, i.id, t, payload)
+	}
+	return nil
+}
+
+func (i *Instance) ID() string {
+	return i.id
+}
+
+func (i *Instance) State() fsm.State {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.runner.CurrentState()
+}
+
+func (i *Instance) Done() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.runner.IsAccepting()
+}
+
+func (i *Instance) Available() []Event {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.workflow.available(i.runner.CurrentState())
+}
+
+func (i *Instance) History() []fsm.Transition {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.runner.History()
+}
@@ -0,0 +1,286 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"fsm-modulo-three/fsm"
+	"fsm-modulo-three/session"
+)
+
+type order struct {
+	Total float64
+	Paid  bool
+}
+
+var errUnpaid = errors.New("order is not paid")
+
+func newOrderWorkflow(t *testing.T, options ...Option) (*Workflow, *[]string) {
+	t.Helper()
+
+	var shipped []string
+	w, err := New("order", "Created", []fsm.State{"Delivered", "Cancelled"}, []Transition{
+		{Event: "pay", From: "Created", To: "Paid", Guard: func(ctx context.Context, payload any) error {
+			if payload.(*order).Total <= 0 {
+				return errors.New("total must be positive")
+			}
+			return nil
+		}, Action: func(ctx context.Context, payload any) error {
+			payload.(*order).Paid = true
+			return nil
+		}},
+		{Event: "cancel", From: "Created", To: "Cancelled"},
+		{Event: "ship", From: "Paid", To: "Shipped", Guard: func(ctx context.Context, payload any) error {
+			if !payload.(*order).Paid {
+				return errUnpaid
+			}
+			return nil
+		}, Action: func(ctx context.Context, payload any) error {
+			shipped = append(shipped, "shipped")
+			return nil
+		}},
+		{Event: "deliver", From: "Shipped", To: "Delivered"},
+	}, options...)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return w, &shipped
+}
+
+func TestInstance_Trigger(t *testing.T) {
+	w, shipped := newOrderWorkflow(t)
+	ctx := context.Background()
+
+	instance, err := w.Start(ctx, "order-1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	payload := &order{Total: 42}
+	for _, event := range []Event{"pay", "ship", "deliver"} {
+		if err := instance.Trigger(ctx, event, payload); err != nil {
+			t.Fatalf("Unexpected error on %s: %v", event, err)
+		}
+	}
+
+	if instance.State() != "Delivered" || !instance.Done() {
+		t.Errorf("Expected a delivered, finished order, got %s", instance.State())
+	}
+	if !payload.Paid || len(*shipped) != 1 {
+		t.Errorf("Expected actions to run, got paid=%v shipped=%v", payload.Paid, *shipped)
+	}
+	if len(instance.History()) != 3 {
+		t.Errorf("Expected 3 transitions in history, got %d", len(instance.History()))
+	}
+}
+
+func TestInstance_TriggerErrors(t *testing.T) {
+	w, _ := newOrderWorkflow(t)
+	ctx := context.Background()
+	instance, _ := w.Start(ctx, "order-2")
+
+	err := instance.Trigger(ctx, "ship", &order{Paid: true})
+	var transitionErr *TransitionError
+	if !errors.As(err, &transitionErr) || !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("Expected a TransitionError, got %v", err)
+	}
+	if len(transitionErr.Available) != 2 || transitionErr.Available[0] != "cancel" || transitionErr.Available[1] != "pay" {
+		t.Errorf("Expected available events [cancel pay], got %v", transitionErr.Available)
+	}
+
+	err = instance.Trigger(ctx, "pay", &order{Total: 0})
+	var guardErr *GuardError
+	if !errors.As(err, &guardErr) || guardErr.Event != "pay" {
+		t.Fatalf("Expected a GuardError, got %v", err)
+	}
+	if instance.State() != "Created" {
+		t.Errorf("Expected a rejected guard to keep the step, got %s", instance.State())
+	}
+
+	instance.Trigger(ctx, "pay", &order{Total: 10})
+	if err := instance.Trigger(ctx, "ship", &order{}); !errors.Is(err, errUnpaid) {
+		t.Errorf("Expected guard error to wrap errUnpaid, got %v", err)
+	}
+}
+
+func TestInstance_ActionFailure(t *testing.T) {
+	w, err := New("job", "Queued", []fsm.State{"Done"}, []Transition{
+		{Event: "run", From: "Queued", To: "Done", Action: func(ctx context.Context, payload any) error {
+			return errors.New("worker crashed")
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	instance, _ := w.Start(context.Background(), "job-1")
+	if err := instance.Trigger(context.Background(), "run", nil); err == nil {
+		t.Fatal("Expected action error, but got none")
+	}
+	if instance.State() != "Queued" {
+		t.Errorf("Expected a failed action to keep the step, got %s", instance.State())
+	}
+}
+
+type failingStore struct {
+	session.Store
+}
+
+func (failingStore) Put(ctx context.Context, key string, snapshot fsm.Snapshot) error {
+	return errors.New("store unavailable")
+}
+
+func TestWorkflow_Persistence(t *testing.T) {
+	store := session.NewMemoryStore()
+	var events []Event
+	w, _ := newOrderWorkflow(t, WithStore(store), OnTransition(func(ctx context.Context, id string, transition Transition, payload any) {
+		events = append(events, transition.Event)
+	}))
+	ctx := context.Background()
+
+	first, _ := w.Start(ctx, "order-3")
+	first.Trigger(ctx, "pay", &order{Total: 5})
+
+	second, err := w.Start(ctx, "order-3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second.State() != "Paid" || len(second.History()) != 1 {
+		t.Errorf("Expected restored instance in Paid, got %s", second.State())
+	}
+	if second.Available()[0] != "ship" {
+		t.Errorf("Expected ship to be available, got %v", second.Available())
+	}
+	if len(events) != 1 || events[0] != "pay" {
+		t.Errorf("Expected hook to observe pay, got %v", events)
+	}
+
+	failing, _ := newOrderWorkflow(t, WithStore(failingStore{session.NewMemoryStore()}))
+	instance, _ := failing.Start(ctx, "order-4")
+	if err := instance.Trigger(ctx, "cancel", nil); err == nil {
+		t.Fatal("Expected save error, but got none")
+	}
+	if instance.State() != "Created" || len(instance.History()) != 0 {
+		t.Errorf("Expected failed save to roll back, got %s", instance.State())
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		final       []fsm.State
+		transitions []Transition
+	}{
+		{"empty", []fsm.State{"A"}, nil},
+		{"duplicate", []fsm.State{"B"}, []Transition{{Event: "go", From: "A", To: "B"}, {Event: "go", From: "A", To: "C"}}},
+		{"unknown final", []fsm.State{"Z"}, []Transition{{Event: "go", From: "A", To: "B"}}},
+	}
+
+	for _, test := range tests {
+		if _, err := New(test.name, "A", test.final, test.transitions); err == nil {
+			t.Errorf("%s: expected error, but got none", test.name)
+		}
+	}
+}
+
+func TestWorkflow_Automaton(t *testing.T) {
+	w, _ := newOrderWorkflow(t)
+	if w.Name() != "order" || len(w.Automaton().States) != 5 {
+		t.Errorf("Unexpected workflow %s with %d steps", w.Name(), len(w.Automaton().States))
+	}
+}
+
+func newReviewWorkflow(t *testing.T, policy ConflictPolicy) *Workflow {
+	t.Helper()
+
+	above := func(limit float64) Guard {
+		return func(ctx context.Context, payload any) error {
+			if payload.(*order).Total <= limit {
+				return fmt.Errorf("total %.0f is not above %.0f", payload.(*order).Total, limit)
+			}
+			return nil
+		}
+	}
+
+	w, err := New("review", "Submitted", []fsm.State{"Approved", "Escalated", "Audited"}, []Transition{
+		{Event: "review", From: "Submitted", To: "Approved", Guard: above(0), Priority: 1},
+		{Event: "review", From: "Submitted", To: "Escalated", Guard: above(100), Priority: 5},
+		{Event: "review", From: "Submitted", To: "Audited", Guard: above(1000), Priority: 10},
+	}, WithConflictPolicy(policy))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return w
+}
+
+func TestWorkflow_ConflictPolicies(t *testing.T) {
+	tests := []struct {
+		policy        ConflictPolicy
+		total         float64
+		expectedState fsm.State
+		expectedErr   error
+	}{
+		{FirstMatch, 50, "Approved", nil},
+		{FirstMatch, 5000, "Approved", nil},
+		{HighestPriority, 50, "Approved", nil},
+		{HighestPriority, 500, "Escalated", nil},
+		{HighestPriority, 5000, "Audited", nil},
+		{ErrorOnAmbiguity, 50, "Approved", nil},
+		{ErrorOnAmbiguity, 500, "Submitted", ErrAmbiguousTransition},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%.0f", test.policy, test.total), func(t *testing.T) {
+			w := newReviewWorkflow(t, test.policy)
+			instance, _ := w.Start(context.Background(), "review-1")
+
+			err := instance.Trigger(context.Background(), "review", &order{Total: test.total})
+			if !errors.Is(err, test.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", test.expectedErr, err)
+			}
+			if instance.State() != test.expectedState {
+				t.Errorf("Expected step %s, got %s", test.expectedState, instance.State())
+			}
+		})
+	}
+}
+
+func TestWorkflow_ConflictResolutionDetails(t *testing.T) {
+	ctx := context.Background()
+
+	w := newReviewWorkflow(t, ErrorOnAmbiguity)
+	instance, _ := w.Start(ctx, "review-2")
+	err := instance.Trigger(ctx, "review", &order{Total: 5000})
+	var ambiguity *AmbiguityError
+	if !errors.As(err, &ambiguity) || len(ambiguity.Targets) != 3 {
+		t.Fatalf("Expected ambiguity between 3 targets, got %v", err)
+	}
+
+	err = instance.Trigger(ctx, "review", &order{Total: 0})
+	var guardErr *GuardError
+	if !errors.As(err, &guardErr) || !strings.Contains(err.Error(), "not above 1000") {
+		t.Errorf("Expected joined guard errors, got %v", err)
+	}
+
+	restored, _ := newReviewWorkflow(t, HighestPriority).Start(ctx, "review-3")
+	restored.Trigger(ctx, "review", &order{Total: 500})
+	history := restored.History()
+	if len(history) != 1 || history[0].Symbol != "review->Escalated" || history[0].To != "Escalated" {
+		t.Errorf("Expected history to name the chosen transition, got %v", history)
+	}
+	if err := restored.runner.Rollback(1); err != nil || restored.State() != "Submitted" {
+		t.Errorf("Expected rollback to Submitted, got %s (%v)", restored.State(), err)
+	}
+}
+
+func TestConflictPolicy_String(t *testing.T) {
+	if HighestPriority.String() != "highest-priority" || ConflictPolicy(9).String() != "ConflictPolicy(9)" {
+		t.Errorf("Unexpected policy names %s, %s", HighestPriority, ConflictPolicy(9))
+	}
+	if _, err := New("bad", "A", nil, []Transition{{Event: "go", From: "A", To: "B"}}, WithConflictPolicy(9)); err == nil {
+		t.Error("Expected error for unknown policy, but got none")
+	}
+}